@@ -0,0 +1,235 @@
+package storage
+
+import (
+	// Standard Library Imports
+	"context"
+	"encoding/json"
+	"errors"
+	"sort"
+	"time"
+
+	// External Imports
+	"github.com/ory/fosite"
+)
+
+// entityActiveSessions provides the name of the entity used when reporting
+// active session errors.
+const entityActiveSessions = "activeSessions"
+
+// ActiveSession provides a logical session, grouping the access and refresh
+// tokens issued under the same originating request. fosite retains the
+// request ID when refreshing tokens, so a session spans every refresh of the
+// tokens it was originally issued.
+type ActiveSession struct {
+	// ID is the ID of the originating request, used to revoke the session.
+	ID string `json:"id" xml:"id"`
+
+	// ClientID is the ID of the client the tokens were issued to.
+	ClientID string `json:"clientId" xml:"clientId"`
+
+	// ClientName contains the human-readable name of the client, if the
+	// client still exists.
+	ClientName string `json:"clientName" xml:"clientName"`
+
+	// UserID is the ID of the user the tokens were issued on behalf of, if
+	// any.
+	UserID string `json:"userId" xml:"userId"`
+
+	// GrantedScopes contains the scopes granted to the session.
+	GrantedScopes []string `json:"grantedScopes" xml:"grantedScopes"`
+
+	// GrantedAudience contains the audiences granted to the session.
+	GrantedAudience []string `json:"grantedAudience,omitempty" xml:"grantedAudience,omitempty"`
+
+	// FirstIssuedAt is when the session's tokens were first issued. For
+	// OpenID Connect sessions this is when the user was originally
+	// authorized, otherwise when the current tokens were issued, as rotating
+	// refresh tokens replaces the previously stored tokens.
+	FirstIssuedAt time.Time `json:"firstIssuedAt" xml:"firstIssuedAt"`
+
+	// LastRefreshedAt is when the session's current tokens were issued,
+	// either on first issue or on the most recent refresh.
+	LastRefreshedAt time.Time `json:"lastRefreshedAt" xml:"lastRefreshedAt"`
+
+	// ExpiresAt is when the session expires, being the refresh token's expiry
+	// if the session has a refresh token, otherwise the access token's
+	// expiry. Zero if the session doesn't record an expiry.
+	ExpiresAt time.Time `json:"expiresAt,omitempty" xml:"expiresAt,omitempty"`
+
+	// HasAccessToken reports whether the session holds an active access token.
+	HasAccessToken bool `json:"hasAccessToken" xml:"hasAccessToken"`
+
+	// HasRefreshToken reports whether the session holds an active refresh
+	// token.
+	HasRefreshToken bool `json:"hasRefreshToken" xml:"hasRefreshToken"`
+}
+
+// ListActiveSessionsRequest enables filtering active sessions.
+type ListActiveSessionsRequest struct {
+	// UserID filters sessions based on User ID.
+	UserID string `json:"userId" xml:"userId"`
+	// ClientID filters sessions based on Client ID.
+	ClientID string `json:"clientId" xml:"clientId"`
+}
+
+// RevokeActiveSessionsRequest specifies the sessions to revoke.
+type RevokeActiveSessionsRequest struct {
+	// UserID revokes the sessions issued on behalf of the user.
+	UserID string `json:"userId" xml:"userId"`
+	// ClientID revokes the sessions issued to the client.
+	ClientID string `json:"clientId" xml:"clientId"`
+	// ExceptID retains the session with the ID, for example, to sign out of
+	// all sessions except the current one.
+	ExceptID string `json:"exceptId" xml:"exceptId"`
+}
+
+// ListActiveSessions returns the active sessions matching the filter, most
+// recently issued first, by grouping the active access and refresh tokens by
+// their originating request. Sessions that have expired, but are yet to be
+// cleaned up, are not returned.
+func ListActiveSessions(ctx context.Context, requests RequestStorer, clients ClientStorer, filter ListActiveSessionsRequest) ([]ActiveSession, error) {
+	now := time.Now()
+	sessions := map[string]*ActiveSession{}
+	for _, entityName := range []string{EntityAccessTokens, EntityRefreshTokens} {
+		results, err := requests.List(ctx, entityName, ListRequestsRequest{
+			ClientID: filter.ClientID,
+			UserID:   filter.UserID,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		for _, request := range results {
+			if !request.Active {
+				continue
+			}
+
+			session, ok := sessions[request.ID]
+			if !ok {
+				session = &ActiveSession{
+					ID:              request.ID,
+					ClientID:        request.ClientID,
+					UserID:          request.UserID,
+					GrantedScopes:   request.GrantedScope,
+					GrantedAudience: request.GrantedAudience,
+				}
+				sessions[request.ID] = session
+			}
+			session.add(entityName, request)
+		}
+	}
+
+	names := map[string]string{}
+	results := make([]ActiveSession, 0, len(sessions))
+	for _, session := range sessions {
+		if session.IsExpired(now) {
+			continue
+		}
+
+		name, ok := names[session.ClientID]
+		if !ok && clients != nil {
+			client, err := clients.Get(ctx, session.ClientID)
+			if err != nil && !errors.Is(err, ErrNotFound) {
+				return nil, err
+			}
+			name = client.Name
+			names[session.ClientID] = name
+		}
+		session.ClientName = name
+		results = append(results, *session)
+	}
+
+	sort.Slice(results, func(i, j int) bool {
+		if results[i].FirstIssuedAt.Equal(results[j].FirstIssuedAt) {
+			return results[i].ID < results[j].ID
+		}
+		return results[i].FirstIssuedAt.After(results[j].FirstIssuedAt)
+	})
+
+	return results, nil
+}
+
+// RevokeActiveSession revokes the access and refresh tokens of the session.
+func RevokeActiveSession(ctx context.Context, requests RequestStorer, sessionID string) error {
+	if err := requests.RevokeAccessToken(ctx, sessionID); err != nil {
+		return err
+	}
+
+	return requests.RevokeRefreshToken(ctx, sessionID)
+}
+
+// RevokeActiveSessions revokes the sessions matching the request and returns
+// the number of sessions revoked. At least one of UserID or ClientID is
+// required, to guard against revoking every session.
+func RevokeActiveSessions(ctx context.Context, requests RequestStorer, revoke RevokeActiveSessionsRequest) (int, error) {
+	if revoke.UserID == "" && revoke.ClientID == "" {
+		return 0, NewInvalidArgumentError(entityActiveSessions, "a user id or client id is required", "userId", "clientId")
+	}
+
+	sessions, err := ListActiveSessions(ctx, requests, nil, ListActiveSessionsRequest{
+		UserID:   revoke.UserID,
+		ClientID: revoke.ClientID,
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	revoked := 0
+	for _, session := range sessions {
+		if session.ID == revoke.ExceptID {
+			continue
+		}
+
+		if err := RevokeActiveSession(ctx, requests, session.ID); err != nil {
+			return revoked, err
+		}
+		revoked++
+	}
+
+	return revoked, nil
+}
+
+// IsExpired returns whether the session has expired at the given time.
+// Sessions that don't record an expiry never expire.
+func (s ActiveSession) IsExpired(now time.Time) bool {
+	return !s.ExpiresAt.IsZero() && !s.ExpiresAt.After(now)
+}
+
+// sessionData provides the parts of a stored fosite session that describe
+// the session's lifetime. The fields match those of fosite's DefaultSession
+// and openid.DefaultSession, which custom sessions commonly embed.
+type sessionData struct {
+	ExpiresAt map[fosite.TokenType]time.Time
+	Claims    *struct {
+		RequestedAt time.Time
+	}
+}
+
+// add records the token's request against the session.
+func (s *ActiveSession) add(entityName string, request Request) {
+	var data sessionData
+	_ = json.Unmarshal(request.Session, &data)
+
+	issuedAt := request.RequestedAt
+	if data.Claims != nil && !data.Claims.RequestedAt.IsZero() && data.Claims.RequestedAt.Before(issuedAt) {
+		issuedAt = data.Claims.RequestedAt
+	}
+	if s.FirstIssuedAt.IsZero() || issuedAt.Before(s.FirstIssuedAt) {
+		s.FirstIssuedAt = issuedAt
+	}
+	if request.RequestedAt.After(s.LastRefreshedAt) {
+		s.LastRefreshedAt = request.RequestedAt
+	}
+
+	switch entityName {
+	case EntityAccessTokens:
+		s.HasAccessToken = true
+		if !s.HasRefreshToken {
+			s.ExpiresAt = data.ExpiresAt[fosite.AccessToken]
+		}
+
+	case EntityRefreshTokens:
+		s.HasRefreshToken = true
+		s.ExpiresAt = data.ExpiresAt[fosite.RefreshToken]
+	}
+}
@@ -0,0 +1,189 @@
+package storage_test
+
+import (
+	// Standard Library Imports
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	// External Imports
+	"github.com/ory/fosite"
+
+	// Internal Imports
+	"github.com/matthewhartstonge/storage"
+)
+
+// memoryTokens provides an in-memory store of access and refresh tokens.
+type memoryTokens struct {
+	storage.RequestStorer
+	tokens map[string][]storage.Request
+}
+
+func (m *memoryTokens) List(ctx context.Context, entityName string, filter storage.ListRequestsRequest) (results []storage.Request, err error) {
+	for _, request := range m.tokens[entityName] {
+		if filter.ClientID != "" && request.ClientID != filter.ClientID {
+			continue
+		}
+		if filter.UserID != "" && request.UserID != filter.UserID {
+			continue
+		}
+		results = append(results, request)
+	}
+	return results, nil
+}
+
+func (m *memoryTokens) revoke(entityName string, requestID string) error {
+	var retained []storage.Request
+	for _, request := range m.tokens[entityName] {
+		if request.ID != requestID {
+			retained = append(retained, request)
+		}
+	}
+	m.tokens[entityName] = retained
+	return nil
+}
+
+func (m *memoryTokens) RevokeAccessToken(ctx context.Context, requestID string) error {
+	return m.revoke(storage.EntityAccessTokens, requestID)
+}
+
+func (m *memoryTokens) RevokeRefreshToken(ctx context.Context, requestID string) error {
+	return m.revoke(storage.EntityRefreshTokens, requestID)
+}
+
+func newSessionTokens(t *testing.T, now time.Time) *memoryTokens {
+	session := func(access time.Time, refresh time.Time, requestedAt time.Time) []byte {
+		data := map[string]interface{}{
+			"ExpiresAt": map[fosite.TokenType]time.Time{
+				fosite.AccessToken:  access,
+				fosite.RefreshToken: refresh,
+			},
+		}
+		if !requestedAt.IsZero() {
+			data["Claims"] = map[string]interface{}{"RequestedAt": requestedAt}
+		}
+
+		b, err := json.Marshal(data)
+		if err != nil {
+			t.Fatalf("error marshaling session: %s", err)
+		}
+		return b
+	}
+
+	// session-1 was authorized a day ago and refreshed an hour ago.
+	refreshed := now.Add(-time.Hour)
+	session1 := session(refreshed.Add(time.Hour), refreshed.Add(30*24*time.Hour), now.Add(-24*time.Hour))
+
+	// session-2 only has an access token.
+	session2 := session(now.Add(time.Hour), time.Time{}, time.Time{})
+
+	// session-4 has expired, but is yet to be cleaned up.
+	session4 := session(now.Add(-time.Minute), time.Time{}, time.Time{})
+
+	return &memoryTokens{tokens: map[string][]storage.Request{
+		storage.EntityAccessTokens: {
+			{ID: "session-1", ClientID: "client-1", UserID: "user-1", GrantedScope: fosite.Arguments{"read"}, RequestedAt: refreshed, Active: true, Session: session1},
+			{ID: "session-2", ClientID: "client-2", UserID: "user-1", GrantedScope: fosite.Arguments{"read"}, RequestedAt: now, Active: true, Session: session2},
+			{ID: "session-3", ClientID: "client-1", UserID: "user-1", RequestedAt: now, Active: false},
+			{ID: "session-4", ClientID: "client-1", UserID: "user-1", RequestedAt: now.Add(-time.Hour), Active: true, Session: session4},
+		},
+		storage.EntityRefreshTokens: {
+			{ID: "session-1", ClientID: "client-1", UserID: "user-1", GrantedScope: fosite.Arguments{"read"}, RequestedAt: refreshed, Active: true, Session: session1},
+		},
+	}}
+}
+
+func TestListActiveSessions(t *testing.T) {
+	now := time.Now().Round(time.Second)
+	tokens := newSessionTokens(t, now)
+	clients, _ := newMemoryStores()
+	clients.clients["client-1"] = storage.Client{ID: "client-1", Name: "Kitteh TV"}
+
+	sessions, err := storage.ListActiveSessions(context.Background(), tokens, clients, storage.ListActiveSessionsRequest{UserID: "user-1"})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(sessions) != 2 {
+		t.Fatalf("expected 2 active sessions, got %d", len(sessions))
+	}
+
+	// Most recently issued first.
+	if sessions[0].ID != "session-2" || sessions[1].ID != "session-1" {
+		t.Fatalf("expected session-2 then session-1, got %s then %s", sessions[0].ID, sessions[1].ID)
+	}
+
+	session := sessions[1]
+	if session.ClientName != "Kitteh TV" {
+		t.Errorf("expected client name Kitteh TV, got %q", session.ClientName)
+	}
+	if !session.HasAccessToken || !session.HasRefreshToken {
+		t.Error("expected session to hold an access and refresh token")
+	}
+	if !session.FirstIssuedAt.Equal(now.Add(-24 * time.Hour)) {
+		t.Errorf("expected session to be first issued a day ago, got %s", session.FirstIssuedAt)
+	}
+	if !session.LastRefreshedAt.Equal(now.Add(-time.Hour)) {
+		t.Errorf("expected session to be refreshed an hour ago, got %s", session.LastRefreshedAt)
+	}
+	if expected := now.Add(-time.Hour).Add(30 * 24 * time.Hour); !session.ExpiresAt.Equal(expected) {
+		t.Errorf("expected session to expire with the refresh token at %s, got %s", expected, session.ExpiresAt)
+	}
+
+	session = sessions[0]
+	if session.HasRefreshToken {
+		t.Error("expected session to not hold a refresh token")
+	}
+	if !session.ExpiresAt.Equal(now.Add(time.Hour)) {
+		t.Errorf("expected session to expire with the access token, got %s", session.ExpiresAt)
+	}
+}
+
+func TestActiveSession_IsExpired(t *testing.T) {
+	now := time.Now()
+
+	if (storage.ActiveSession{}).IsExpired(now) {
+		t.Error("expected a session without an expiry to not expire")
+	}
+	if (storage.ActiveSession{ExpiresAt: now.Add(time.Minute)}).IsExpired(now) {
+		t.Error("expected a session expiring later to not be expired")
+	}
+	if !(storage.ActiveSession{ExpiresAt: now}).IsExpired(now) {
+		t.Error("expected a session expiring now to be expired")
+	}
+}
+
+func TestRevokeActiveSessions(t *testing.T) {
+	ctx := context.Background()
+	tokens := newSessionTokens(t, time.Now())
+
+	_, err := storage.RevokeActiveSessions(ctx, tokens, storage.RevokeActiveSessionsRequest{})
+	if !errors.Is(err, storage.ErrInvalidArgument) {
+		t.Errorf("expected revoking every session to be rejected, got %v", err)
+	}
+
+	revoked, err := storage.RevokeActiveSessions(ctx, tokens, storage.RevokeActiveSessionsRequest{
+		UserID:   "user-1",
+		ExceptID: "session-2",
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if revoked != 1 {
+		t.Errorf("expected 1 session to be revoked, got %d", revoked)
+	}
+
+	sessions, _ := storage.ListActiveSessions(ctx, tokens, nil, storage.ListActiveSessionsRequest{UserID: "user-1"})
+	if len(sessions) != 1 || sessions[0].ID != "session-2" {
+		t.Fatalf("expected only the current session to remain, got %v", sessions)
+	}
+
+	if err := storage.RevokeActiveSession(ctx, tokens, "session-2"); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	sessions, _ = storage.ListActiveSessions(ctx, tokens, nil, storage.ListActiveSessionsRequest{UserID: "user-1"})
+	if len(sessions) != 0 {
+		t.Errorf("expected no sessions to remain, got %d", len(sessions))
+	}
+}
@@ -0,0 +1,36 @@
+package storage
+
+// BulkResult provides the outcome of a single item of a bulk operation.
+// Results are returned in the order the items were provided, so a failing
+// item, for example, a conflict, is reported individually rather than
+// failing the batch.
+type BulkResult struct {
+	// ID contains the ID of the item.
+	ID string `json:"id" xml:"id"`
+
+	// Err contains the error that occurred processing the item, if any.
+	Err error `json:"-" xml:"-"`
+}
+
+// BulkFailures returns the number of items that failed in a bulk operation.
+func BulkFailures(results []BulkResult) (failures int) {
+	for _, result := range results {
+		if result.Err != nil {
+			failures++
+		}
+	}
+
+	return failures
+}
+
+// mergeBulkResults merges the results of the items passed on to a bulk
+// operation, listed by index, into results.
+func mergeBulkResults(results []BulkResult, passed []int, passedResults []BulkResult) []BulkResult {
+	for i, result := range passedResults {
+		if i < len(passed) {
+			results[passed[i]] = result
+		}
+	}
+
+	return results
+}
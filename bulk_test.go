@@ -0,0 +1,25 @@
+package storage_test
+
+import (
+	// Standard Library Imports
+	"errors"
+	"testing"
+
+	// Internal Imports
+	"github.com/matthewhartstonge/storage"
+)
+
+func TestBulkFailures(t *testing.T) {
+	results := []storage.BulkResult{
+		{ID: "client-1"},
+		{ID: "client-2", Err: errors.New("conflict")},
+		{ID: "client-3", Err: storage.NewNotFoundError(storage.EntityClients)},
+	}
+
+	if failures := storage.BulkFailures(results); failures != 2 {
+		t.Errorf("expected 2 failures, got %d", failures)
+	}
+	if failures := storage.BulkFailures(nil); failures != 0 {
+		t.Errorf("expected no failures, got %d", failures)
+	}
+}
@@ -31,6 +31,20 @@ type ClientStorer interface {
 	Authenticate(ctx context.Context, clientID string, secret string) (Client, error)
 	GrantScopes(ctx context.Context, clientID string, scopes []string) (Client, error)
 	RemoveScopes(ctx context.Context, clientID string, scopes []string) (Client, error)
+
+	// Bulk Functions
+	// BulkCreate, BulkUpdate and BulkDelete return a result per client, in
+	// the order given. An error is only returned if the batch as a whole
+	// failed.
+	BulkCreate(ctx context.Context, clients []Client) ([]BulkResult, error)
+	BulkUpdate(ctx context.Context, clients []Client) ([]BulkResult, error)
+	BulkDelete(ctx context.Context, clientIDs []string) ([]BulkResult, error)
+
+	// BulkGrantScopes and BulkRemoveScopes grant, or remove, scopes for the
+	// clients matching the filter, returning the number of clients modified.
+	// An empty filter is rejected, unless the filter sets All.
+	BulkGrantScopes(ctx context.Context, filter ListClientsRequest, scopes []string) (int64, error)
+	BulkRemoveScopes(ctx context.Context, filter ListClientsRequest, scopes []string) (int64, error)
 }
 
 // ListClientsRequest enables listing and filtering client records.
@@ -70,4 +84,33 @@ type ListClientsRequest struct {
 	Disabled bool `json:"disabled" xml:"disabled"`
 	// Published filters clients based on published status.
 	Published bool `json:"published" xml:"published"`
+	// All must be set in order to bulk grant, or remove, scopes for every
+	// client, if no other filter is set. All is ignored when listing.
+	All bool `json:"all" xml:"all"`
+}
+
+// IsZero returns true if the request doesn't filter clients.
+func (r ListClientsRequest) IsZero() bool {
+	return r.AllowedTenantAccess == "" &&
+		r.AllowedRegion == "" &&
+		r.RedirectURI == "" &&
+		r.GrantType == "" &&
+		r.ResponseType == "" &&
+		len(r.ScopesIntersection) == 0 &&
+		len(r.ScopesUnion) == 0 &&
+		r.Contact == "" &&
+		!r.Public &&
+		!r.Disabled &&
+		!r.Published
+}
+
+// ValidateBulk returns an invalid argument error if the request doesn't
+// filter clients and doesn't set All, so that a zero value request can't
+// modify every client.
+func (r ListClientsRequest) ValidateBulk() error {
+	if r.IsZero() && !r.All {
+		return NewInvalidArgumentError(EntityClients, "a filter, or all, is required to modify clients in bulk", "all")
+	}
+
+	return nil
 }
@@ -0,0 +1,305 @@
+package main
+
+import (
+	// Standard Library Imports
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	// Internal Imports
+	"github.com/matthewhartstonge/storage"
+)
+
+const (
+	// outputTable renders results as an aligned table.
+	outputTable = "table"
+
+	// outputJSON renders results as indented JSON.
+	outputJSON = "json"
+)
+
+// errUsage reports the command was invoked incorrectly, in which case the
+// command's usage is printed.
+var errUsage = errors.New("usage")
+
+// app provides the state shared by each command.
+type app struct {
+	stdout io.Writer
+	stderr io.Writer
+
+	// output specifies how results are rendered, either outputTable or
+	// outputJSON.
+	output string
+
+	// configFile specifies the configuration file to overlay over the
+	// environment, if any.
+	configFile string
+
+	// open opens the store. The returned function closes the store.
+	open func(configFile string) (*storage.Store, func(), error)
+
+	// store is the opened store, once connected.
+	store *storage.Store
+}
+
+// command provides a subcommand of a resource.
+type command struct {
+	name    string
+	args    string
+	summary string
+
+	// flags binds the command's flags to the flag set, returning a function
+	// that runs the command once the flags are parsed.
+	flags func(a *app, fs *flag.FlagSet) func(ctx context.Context, args []string) error
+}
+
+// resource groups the commands for a single kind of resource.
+type resource struct {
+	name     string
+	summary  string
+	commands []command
+}
+
+// resources returns the resources managed by storagectl.
+func resources() []resource {
+	return []resource{
+		clientResource(),
+		userResource(),
+		tokenResource(),
+		sessionResource(),
+		jtiResource(),
+	}
+}
+
+// run runs the command given by args, returning the process exit code.
+func (a *app) run(ctx context.Context, args []string) int {
+	fs := flag.NewFlagSet("storagectl", flag.ContinueOnError)
+	fs.SetOutput(a.stderr)
+	fs.StringVar(&a.configFile, "config", "", "path to a YAML or JSON config file, overlaid over the CONNECTIONS_MONGO_* environment")
+	fs.StringVar(&a.output, "output", outputTable, "output format, either table or json")
+	fs.Usage = a.usage(fs)
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+
+	if a.output != outputTable && a.output != outputJSON {
+		fmt.Fprintf(a.stderr, "unknown output format %q\n", a.output)
+		return 2
+	}
+
+	args = fs.Args()
+	if len(args) == 0 || args[0] == "help" {
+		fs.Usage()
+		return 2
+	}
+
+	res, ok := findResource(args[0])
+	if !ok {
+		fmt.Fprintf(a.stderr, "unknown resource %q\n\n", args[0])
+		fs.Usage()
+		return 2
+	}
+
+	if len(args) < 2 {
+		a.resourceUsage(res)
+		return 2
+	}
+
+	cmd, ok := res.find(args[1])
+	if !ok {
+		fmt.Fprintf(a.stderr, "unknown %s command %q\n\n", res.name, args[1])
+		a.resourceUsage(res)
+		return 2
+	}
+
+	err := a.runCommand(ctx, res, cmd, args[2:])
+	switch {
+	case err == nil:
+		return 0
+
+	case errors.Is(err, errUsage), errors.Is(err, flag.ErrHelp):
+		return 2
+
+	default:
+		fmt.Fprintf(a.stderr, "error: %v\n", err)
+		return 1
+	}
+}
+
+// runCommand parses the command's flags, opens the store and runs the
+// command.
+func (a *app) runCommand(ctx context.Context, res resource, cmd command, args []string) error {
+	fs := flag.NewFlagSet(res.name+" "+cmd.name, flag.ContinueOnError)
+	fs.SetOutput(a.stderr)
+	fs.Usage = func() {
+		fmt.Fprintf(a.stderr, "Usage: storagectl %s %s [flags] %s\n\n%s\n", res.name, cmd.name, cmd.args, cmd.summary)
+		fs.PrintDefaults()
+	}
+
+	runner := cmd.flags(a, fs)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if a.store == nil {
+		store, closeStore, err := a.open(a.configFile)
+		if err != nil {
+			return err
+		}
+		defer closeStore()
+		a.store = store
+	}
+
+	err := runner(ctx, fs.Args())
+	if errors.Is(err, errUsage) {
+		fs.Usage()
+	}
+
+	return err
+}
+
+// usage returns the top level usage.
+func (a *app) usage(fs *flag.FlagSet) func() {
+	return func() {
+		fmt.Fprintf(a.stderr, "Usage: storagectl [flags] <resource> <command> [flags] [args]\n\nFlags:\n")
+		fs.PrintDefaults()
+
+		fmt.Fprintf(a.stderr, "\nResources:\n")
+		w := tabwriter.NewWriter(a.stderr, 0, 4, 2, ' ', 0)
+		for _, res := range resources() {
+			for _, cmd := range res.commands {
+				fmt.Fprintf(w, "  %s %s %s\t%s\n", res.name, cmd.name, cmd.args, cmd.summary)
+			}
+		}
+		_ = w.Flush()
+	}
+}
+
+// resourceUsage prints the commands of a resource.
+func (a *app) resourceUsage(res resource) {
+	fmt.Fprintf(a.stderr, "Usage: storagectl %s <command> [flags] [args]\n\n%s\n\nCommands:\n", res.name, res.summary)
+	w := tabwriter.NewWriter(a.stderr, 0, 4, 2, ' ', 0)
+	for _, cmd := range res.commands {
+		fmt.Fprintf(w, "  %s %s\t%s\n", cmd.name, cmd.args, cmd.summary)
+	}
+	_ = w.Flush()
+}
+
+// findResource returns the named resource.
+func findResource(name string) (resource, bool) {
+	for _, res := range resources() {
+		if res.name == name {
+			return res, true
+		}
+	}
+
+	return resource{}, false
+}
+
+// find returns the named command.
+func (r resource) find(name string) (command, bool) {
+	for _, cmd := range r.commands {
+		if cmd.name == name {
+			return cmd, true
+		}
+	}
+
+	return command{}, false
+}
+
+// table provides the tabular rendering of a result.
+type table struct {
+	header []string
+	rows   [][]string
+}
+
+// render writes the result to stdout, either as the table, or as JSON.
+func (a *app) render(result interface{}, t table) error {
+	if a.output == outputJSON {
+		enc := json.NewEncoder(a.stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(result)
+	}
+
+	w := tabwriter.NewWriter(a.stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, strings.Join(t.header, "\t"))
+	for _, row := range t.rows {
+		fmt.Fprintln(w, strings.Join(row, "\t"))
+	}
+
+	return w.Flush()
+}
+
+// stringList provides a comma separated list flag.
+type stringList []string
+
+// String implements flag.Value.
+func (l *stringList) String() string {
+	if l == nil {
+		return ""
+	}
+
+	return strings.Join(*l, ",")
+}
+
+// Set implements flag.Value.
+func (l *stringList) Set(value string) error {
+	*l = splitList(value)
+	return nil
+}
+
+// splitList splits a comma separated list, dropping empty values.
+func splitList(value string) (list []string) {
+	for _, v := range strings.Split(value, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			list = append(list, v)
+		}
+	}
+
+	return list
+}
+
+// isSet returns true if the named flag was provided.
+func isSet(fs *flag.FlagSet, name string) (set bool) {
+	fs.Visit(func(f *flag.Flag) {
+		if f.Name == name {
+			set = true
+		}
+	})
+
+	return set
+}
+
+// requireArgs returns errUsage if the number of args doesn't match.
+func requireArgs(args []string, n int) error {
+	if len(args) != n {
+		return errUsage
+	}
+
+	return nil
+}
+
+// generateSecret returns a random, URL safe, secret.
+func generateSecret() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// join renders a list for a table cell.
+func join(list []string) string {
+	sorted := append([]string(nil), list...)
+	sort.Strings(sorted)
+	return strings.Join(sorted, ",")
+}
@@ -0,0 +1,321 @@
+package main
+
+import (
+	// Standard Library Imports
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	// Internal Imports
+	"github.com/matthewhartstonge/storage"
+)
+
+// fakeClients provides the client manager methods used by the commands.
+type fakeClients struct {
+	storage.ClientManager
+	clients map[string]storage.Client
+}
+
+func (f *fakeClients) Create(ctx context.Context, client storage.Client) (storage.Client, error) {
+	if client.ID == "" {
+		client.ID = "generated"
+	}
+	client.Secret = "hashed:" + client.Secret
+	f.clients[client.ID] = client
+	return client, nil
+}
+
+func (f *fakeClients) Get(ctx context.Context, clientID string) (storage.Client, error) {
+	client, ok := f.clients[clientID]
+	if !ok {
+		return client, storage.NewNotFoundError(storage.EntityClients)
+	}
+	return client, nil
+}
+
+func (f *fakeClients) Update(ctx context.Context, clientID string, client storage.Client) (storage.Client, error) {
+	if f.clients[clientID].Secret != client.Secret {
+		client.Secret = "hashed:" + client.Secret
+	}
+	f.clients[clientID] = client
+	return client, nil
+}
+
+// fakeUsers provides the user manager methods used by the commands.
+type fakeUsers struct {
+	storage.UserManager
+	users map[string]storage.User
+}
+
+func (f *fakeUsers) Get(ctx context.Context, userID string) (storage.User, error) {
+	user, ok := f.users[userID]
+	if !ok {
+		return user, storage.NewNotFoundError(storage.EntityUsers)
+	}
+	return user, nil
+}
+
+func (f *fakeUsers) Update(ctx context.Context, userID string, user storage.User) (storage.User, error) {
+	f.users[userID] = user
+	return user, nil
+}
+
+// fakeRequests provides the request manager methods used by the commands.
+type fakeRequests struct {
+	storage.RequestManager
+	requests map[string][]storage.Request
+	revoked  []string
+}
+
+func (f *fakeRequests) List(ctx context.Context, entityName string, filter storage.ListRequestsRequest) (results []storage.Request, err error) {
+	for _, request := range f.requests[entityName] {
+		if filter.ClientID != "" && request.ClientID != filter.ClientID {
+			continue
+		}
+		if filter.UserID != "" && request.UserID != filter.UserID {
+			continue
+		}
+		results = append(results, request)
+	}
+	return results, nil
+}
+
+func (f *fakeRequests) RevokeAccessToken(ctx context.Context, requestID string) error {
+	f.revoked = append(f.revoked, "access:"+requestID)
+	return nil
+}
+
+func (f *fakeRequests) RevokeRefreshToken(ctx context.Context, requestID string) error {
+	f.revoked = append(f.revoked, "refresh:"+requestID)
+	return nil
+}
+
+// newTestApp returns an app bound to fake managers, capturing its output.
+func newTestApp() (*app, *storage.Store, *bytes.Buffer, *bytes.Buffer) {
+	store := &storage.Store{
+		ClientManager: &fakeClients{clients: map[string]storage.Client{
+			"client-1": {ID: "client-1", Name: "Client", Secret: "hashed:secret"},
+		}},
+		UserManager: &fakeUsers{users: map[string]storage.User{
+			"user-1": {ID: "user-1", Username: "kevin"},
+		}},
+		RequestManager: &fakeRequests{requests: map[string][]storage.Request{
+			storage.EntityAccessTokens: {
+				{ID: "request-1", ClientID: "client-1", UserID: "user-1"},
+				{ID: "request-2", ClientID: "client-2", UserID: "user-2"},
+			},
+			storage.EntityRefreshTokens: {
+				{ID: "request-1", ClientID: "client-1", UserID: "user-1"},
+			},
+		}},
+	}
+
+	stdout, stderr := &bytes.Buffer{}, &bytes.Buffer{}
+	a := &app{
+		stdout: stdout,
+		stderr: stderr,
+		open: func(configFile string) (*storage.Store, func(), error) {
+			return store, func() {}, nil
+		},
+	}
+
+	return a, store, stdout, stderr
+}
+
+func TestApp_run_Usage(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+	}{
+		{name: "no args", args: nil},
+		{name: "help", args: []string{"help"}},
+		{name: "unknown resource", args: []string{"tenant", "list"}},
+		{name: "unknown command", args: []string{"client", "explode"}},
+		{name: "missing command", args: []string{"client"}},
+		{name: "unknown output", args: []string{"-output", "xml", "client", "list"}},
+		{name: "missing args", args: []string{"client", "get"}},
+		{name: "missing token selector", args: []string{"token", "list"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a, _, _, stderr := newTestApp()
+			if code := a.run(context.Background(), tt.args); code != 2 {
+				t.Errorf("expected exit code 2, got %d", code)
+			}
+			if !strings.Contains(stderr.String(), "Usage") && !strings.Contains(stderr.String(), "unknown") {
+				t.Errorf("expected usage to be printed, got %q", stderr.String())
+			}
+		})
+	}
+}
+
+func TestApp_run_ClientCreate(t *testing.T) {
+	a, store, stdout, _ := newTestApp()
+
+	code := a.run(context.Background(), []string{"-output", "json", "client", "create", "-id", "client-2", "-scopes", "read, write"})
+	if code != 0 {
+		t.Fatalf("expected exit code 0, got %d", code)
+	}
+
+	var got clientSecret
+	if err := json.Unmarshal(stdout.Bytes(), &got); err != nil {
+		t.Fatalf("expected json output, got %v", err)
+	}
+	if got.ID != "client-2" || got.Secret == "" {
+		t.Errorf("expected a generated secret for client-2, got %+v", got)
+	}
+
+	client := store.ClientManager.(*fakeClients).clients["client-2"]
+	if client.Secret != "hashed:"+got.Secret {
+		t.Errorf("expected the generated secret to be stored, got %q", client.Secret)
+	}
+	if strings.Join(client.Scopes, " ") != "read write" {
+		t.Errorf("expected scopes to be set, got %v", client.Scopes)
+	}
+}
+
+func TestApp_run_ClientRotateSecret(t *testing.T) {
+	a, store, stdout, _ := newTestApp()
+
+	if code := a.run(context.Background(), []string{"client", "rotate-secret", "client-1"}); code != 0 {
+		t.Fatalf("expected exit code 0, got %d", code)
+	}
+
+	client := store.ClientManager.(*fakeClients).clients["client-1"]
+	if client.Secret == "hashed:secret" {
+		t.Error("expected the secret to be rotated")
+	}
+	if !strings.Contains(stdout.String(), strings.TrimPrefix(client.Secret, "hashed:")) {
+		t.Errorf("expected the new secret to be shown, got %q", stdout.String())
+	}
+}
+
+func TestApp_run_ClientGet_ShouldRedactSecret(t *testing.T) {
+	a, _, stdout, _ := newTestApp()
+
+	if code := a.run(context.Background(), []string{"-output", "json", "client", "get", "client-1"}); code != 0 {
+		t.Fatalf("expected exit code 0, got %d", code)
+	}
+	if strings.Contains(stdout.String(), "hashed:secret") {
+		t.Errorf("expected secret hash to be omitted, got %q", stdout.String())
+	}
+}
+
+func TestApp_run_ClientGet_NotFound(t *testing.T) {
+	a, _, _, stderr := newTestApp()
+
+	if code := a.run(context.Background(), []string{"client", "get", "missing"}); code != 1 {
+		t.Errorf("expected exit code 1, got %d", code)
+	}
+	if !strings.Contains(stderr.String(), "not found") {
+		t.Errorf("expected not found error, got %q", stderr.String())
+	}
+}
+
+func TestApp_run_UserDisable(t *testing.T) {
+	a, store, _, _ := newTestApp()
+
+	if code := a.run(context.Background(), []string{"user", "disable", "user-1"}); code != 0 {
+		t.Fatalf("expected exit code 0, got %d", code)
+	}
+	if !store.UserManager.(*fakeUsers).users["user-1"].Disabled {
+		t.Error("expected user to be disabled")
+	}
+
+	if code := a.run(context.Background(), []string{"user", "enable", "user-1"}); code != 0 {
+		t.Fatalf("expected exit code 0, got %d", code)
+	}
+	if store.UserManager.(*fakeUsers).users["user-1"].Disabled {
+		t.Error("expected user to be enabled")
+	}
+}
+
+func TestApp_run_TokenRevoke(t *testing.T) {
+	a, store, stdout, _ := newTestApp()
+
+	if code := a.run(context.Background(), []string{"-output", "json", "token", "revoke", "-user", "user-1"}); code != 0 {
+		t.Fatalf("expected exit code 0, got %d", code)
+	}
+
+	var got revocation
+	if err := json.Unmarshal(stdout.Bytes(), &got); err != nil {
+		t.Fatalf("expected json output, got %v", err)
+	}
+	if got.AccessTokens != 1 || got.RefreshTokens != 1 {
+		t.Errorf("expected 1 access and 1 refresh token revoked, got %+v", got)
+	}
+
+	revoked := store.RequestManager.(*fakeRequests).revoked
+	if strings.Join(revoked, " ") != "access:request-1 refresh:request-1" {
+		t.Errorf("expected request-1 to be revoked, got %v", revoked)
+	}
+}
+
+func TestApp_run_SessionRevoke(t *testing.T) {
+	a, store, stdout, _ := newTestApp()
+	requests := store.RequestManager.(*fakeRequests)
+	for entityName, tokens := range requests.requests {
+		for i := range tokens {
+			tokens[i].Active = true
+		}
+		requests.requests[entityName] = tokens
+	}
+
+	if code := a.run(context.Background(), []string{"-output", "json", "session", "list", "-user", "user-1"}); code != 0 {
+		t.Fatalf("expected exit code 0, got %d", code)
+	}
+
+	var sessions []storage.ActiveSession
+	if err := json.Unmarshal(stdout.Bytes(), &sessions); err != nil {
+		t.Fatalf("expected json output, got %v", err)
+	}
+	if len(sessions) != 1 || sessions[0].ID != "request-1" || sessions[0].ClientName != "Client" {
+		t.Fatalf("expected the request-1 session of Client, got %+v", sessions)
+	}
+
+	if code := a.run(context.Background(), []string{"session", "revoke", "-user", "user-1", "-except", "request-1"}); code != 0 {
+		t.Fatalf("expected exit code 0, got %d", code)
+	}
+	if len(requests.revoked) != 0 {
+		t.Errorf("expected the excepted session to be retained, got %v", requests.revoked)
+	}
+
+	if code := a.run(context.Background(), []string{"session", "revoke", "-id", "request-1"}); code != 0 {
+		t.Fatalf("expected exit code 0, got %d", code)
+	}
+	if strings.Join(requests.revoked, " ") != "access:request-1 refresh:request-1" {
+		t.Errorf("expected request-1 to be revoked, got %v", requests.revoked)
+	}
+
+	if code := a.run(context.Background(), []string{"session", "revoke", "-id", "request-1", "-user", "user-1"}); code != 2 {
+		t.Errorf("expected a usage error combining selectors, got %d", code)
+	}
+}
+
+func TestApp_render_Table(t *testing.T) {
+	a, _, stdout, _ := newTestApp()
+	a.output = outputTable
+
+	err := a.render(nil, table{
+		header: []string{"ID", "NAME"},
+		rows:   [][]string{{"client-1", "Client"}},
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	expected := "ID        NAME\nclient-1  Client\n"
+	if stdout.String() != expected {
+		t.Errorf("expected %q, got %q", expected, stdout.String())
+	}
+}
+
+func TestSplitList(t *testing.T) {
+	got := splitList(" read, ,write,")
+	if strings.Join(got, " ") != "read write" {
+		t.Errorf("expected [read write], got %v", got)
+	}
+}
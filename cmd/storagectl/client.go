@@ -0,0 +1,323 @@
+package main
+
+import (
+	// Standard Library Imports
+	"context"
+	"flag"
+	"fmt"
+	"strconv"
+
+	// Internal Imports
+	"github.com/matthewhartstonge/storage"
+)
+
+// clientSecret provides the result of setting a client's secret. The secret
+// is only ever shown once.
+type clientSecret struct {
+	ID     string `json:"id"`
+	Secret string `json:"secret"`
+}
+
+// clientFields binds the flags used to create and update a client.
+type clientFields struct {
+	name          string
+	owner         string
+	public        bool
+	disabled      bool
+	scopes        stringList
+	audiences     stringList
+	tenants       stringList
+	regions       stringList
+	grantTypes    stringList
+	responseTypes stringList
+	redirectURIs  stringList
+}
+
+// bind binds the client flags to the flag set.
+func (f *clientFields) bind(fs *flag.FlagSet) {
+	fs.StringVar(&f.name, "name", "", "human-readable name of the client")
+	fs.StringVar(&f.owner, "owner", "", "owner of the client")
+	fs.BoolVar(&f.public, "public", false, "the client is public, and has no secret")
+	fs.BoolVar(&f.disabled, "disabled", false, "the client is disabled")
+	fs.Var(&f.scopes, "scopes", "comma separated scopes")
+	fs.Var(&f.audiences, "audiences", "comma separated allowed audiences")
+	fs.Var(&f.tenants, "tenants", "comma separated allowed tenants")
+	fs.Var(&f.regions, "regions", "comma separated allowed regions")
+	fs.Var(&f.grantTypes, "grant-types", "comma separated grant types")
+	fs.Var(&f.responseTypes, "response-types", "comma separated response types")
+	fs.Var(&f.redirectURIs, "redirect-uris", "comma separated redirect URIs")
+}
+
+// apply sets the provided flags on the client.
+func (f *clientFields) apply(fs *flag.FlagSet, client *storage.Client) {
+	if isSet(fs, "name") {
+		client.Name = f.name
+	}
+	if isSet(fs, "owner") {
+		client.Owner = f.owner
+	}
+	if isSet(fs, "public") {
+		client.Public = f.public
+	}
+	if isSet(fs, "disabled") {
+		client.Disabled = f.disabled
+	}
+	if isSet(fs, "scopes") {
+		client.Scopes = f.scopes
+	}
+	if isSet(fs, "audiences") {
+		client.AllowedAudiences = f.audiences
+	}
+	if isSet(fs, "tenants") {
+		client.AllowedTenantAccess = f.tenants
+	}
+	if isSet(fs, "regions") {
+		client.AllowedRegions = f.regions
+	}
+	if isSet(fs, "grant-types") {
+		client.GrantTypes = f.grantTypes
+	}
+	if isSet(fs, "response-types") {
+		client.ResponseTypes = f.responseTypes
+	}
+	if isSet(fs, "redirect-uris") {
+		client.RedirectURIs = f.redirectURIs
+	}
+}
+
+func clientResource() resource {
+	return resource{
+		name:    "client",
+		summary: "Manage OAuth 2.0 clients.",
+		commands: []command{
+			{
+				name:    "create",
+				summary: "Create a client. A secret is generated for confidential clients, and shown once.",
+				flags:   clientCreate,
+			},
+			{
+				name:    "list",
+				summary: "List clients.",
+				flags:   clientList,
+			},
+			{
+				name:    "get",
+				args:    "<client-id>",
+				summary: "Get a client.",
+				flags:   clientGet,
+			},
+			{
+				name:    "update",
+				args:    "<client-id>",
+				summary: "Update the provided fields of a client.",
+				flags:   clientUpdate,
+			},
+			{
+				name:    "delete",
+				args:    "<client-id>",
+				summary: "Delete a client.",
+				flags:   clientDelete,
+			},
+			{
+				name:    "rotate-secret",
+				args:    "<client-id>",
+				summary: "Generate a new secret for a client, shown once.",
+				flags:   clientRotateSecret,
+			},
+			{
+				name:    "grant-scopes",
+				args:    "<client-id> <scope>...",
+				summary: "Grant scopes to a client.",
+				flags:   clientGrantScopes,
+			},
+		},
+	}
+}
+
+func clientCreate(a *app, fs *flag.FlagSet) func(ctx context.Context, args []string) error {
+	id := fs.String("id", "", "client id, generated if empty")
+	secret := fs.String("secret", "", "client secret, generated if empty")
+	fields := &clientFields{}
+	fields.bind(fs)
+
+	return func(ctx context.Context, args []string) (err error) {
+		if err = requireArgs(args, 0); err != nil {
+			return err
+		}
+
+		client := storage.Client{ID: *id}
+		fields.apply(fs, &client)
+		if !client.Public {
+			client.Secret = *secret
+			if client.Secret == "" {
+				if client.Secret, err = generateSecret(); err != nil {
+					return err
+				}
+			}
+		}
+
+		plaintext := client.Secret
+		client, err = a.store.ClientManager.Create(ctx, client)
+		if err != nil {
+			return err
+		}
+
+		return a.renderSecret(clientSecret{ID: client.ID, Secret: plaintext})
+	}
+}
+
+func clientList(a *app, fs *flag.FlagSet) func(ctx context.Context, args []string) error {
+	filter := storage.ListClientsRequest{}
+	fs.StringVar(&filter.AllowedTenantAccess, "tenant", "", "filter by allowed tenant")
+	fs.StringVar(&filter.AllowedRegion, "region", "", "filter by allowed region")
+	fs.Var((*stringList)(&filter.ScopesIntersection), "scopes", "filter by clients with all the comma separated scopes")
+	fs.BoolVar(&filter.Disabled, "disabled", false, "only list disabled clients")
+	fs.BoolVar(&filter.Public, "public", false, "only list public clients")
+
+	return func(ctx context.Context, args []string) error {
+		if err := requireArgs(args, 0); err != nil {
+			return err
+		}
+
+		clients, err := a.store.ClientManager.List(ctx, filter)
+		if err != nil {
+			return err
+		}
+
+		return a.renderClients(clients)
+	}
+}
+
+func clientGet(a *app, fs *flag.FlagSet) func(ctx context.Context, args []string) error {
+	return func(ctx context.Context, args []string) error {
+		if err := requireArgs(args, 1); err != nil {
+			return err
+		}
+
+		client, err := a.store.ClientManager.Get(ctx, args[0])
+		if err != nil {
+			return err
+		}
+
+		return a.renderClients([]storage.Client{client})
+	}
+}
+
+func clientUpdate(a *app, fs *flag.FlagSet) func(ctx context.Context, args []string) error {
+	fields := &clientFields{}
+	fields.bind(fs)
+
+	return func(ctx context.Context, args []string) error {
+		if err := requireArgs(args, 1); err != nil {
+			return err
+		}
+
+		client, err := a.store.ClientManager.Get(ctx, args[0])
+		if err != nil {
+			return err
+		}
+
+		fields.apply(fs, &client)
+		client, err = a.store.ClientManager.Update(ctx, client.ID, client)
+		if err != nil {
+			return err
+		}
+
+		return a.renderClients([]storage.Client{client})
+	}
+}
+
+func clientDelete(a *app, fs *flag.FlagSet) func(ctx context.Context, args []string) error {
+	return func(ctx context.Context, args []string) error {
+		if err := requireArgs(args, 1); err != nil {
+			return err
+		}
+
+		if err := a.store.ClientManager.Delete(ctx, args[0]); err != nil {
+			return err
+		}
+
+		fmt.Fprintf(a.stderr, "deleted client %s\n", args[0])
+		return nil
+	}
+}
+
+func clientRotateSecret(a *app, fs *flag.FlagSet) func(ctx context.Context, args []string) error {
+	return func(ctx context.Context, args []string) error {
+		if err := requireArgs(args, 1); err != nil {
+			return err
+		}
+
+		client, err := a.store.ClientManager.Get(ctx, args[0])
+		if err != nil {
+			return err
+		}
+		if client.Public {
+			return fmt.Errorf("client %s is public, and has no secret", client.ID)
+		}
+
+		secret, err := generateSecret()
+		if err != nil {
+			return err
+		}
+
+		// The client manager hashes the secret on update.
+		client.Secret = secret
+		if _, err = a.store.ClientManager.Update(ctx, client.ID, client); err != nil {
+			return err
+		}
+
+		return a.renderSecret(clientSecret{ID: client.ID, Secret: secret})
+	}
+}
+
+func clientGrantScopes(a *app, fs *flag.FlagSet) func(ctx context.Context, args []string) error {
+	return func(ctx context.Context, args []string) error {
+		if len(args) < 2 {
+			return errUsage
+		}
+
+		client, err := a.store.ClientManager.GrantScopes(ctx, args[0], args[1:])
+		if err != nil {
+			return err
+		}
+
+		return a.renderClients([]storage.Client{client})
+	}
+}
+
+// renderClients renders clients, without their secret hashes.
+func (a *app) renderClients(clients []storage.Client) error {
+	t := table{
+		header: []string{"ID", "NAME", "OWNER", "PUBLIC", "DISABLED", "SCOPES", "GRANT TYPES", "TENANTS"},
+	}
+	for i := range clients {
+		clients[i].Secret = ""
+
+		client := clients[i]
+		t.rows = append(t.rows, []string{
+			client.ID,
+			client.Name,
+			client.Owner,
+			strconv.FormatBool(client.Public),
+			strconv.FormatBool(client.Disabled),
+			join(client.Scopes),
+			join(client.GrantTypes),
+			join(client.AllowedTenantAccess),
+		})
+	}
+
+	if clients == nil {
+		clients = []storage.Client{}
+	}
+
+	return a.render(clients, t)
+}
+
+// renderSecret renders a client's plaintext secret.
+func (a *app) renderSecret(secret clientSecret) error {
+	return a.render(secret, table{
+		header: []string{"ID", "SECRET"},
+		rows:   [][]string{{secret.ID, secret.Secret}},
+	})
+}
@@ -0,0 +1,100 @@
+package main
+
+import (
+	// Standard Library Imports
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"strconv"
+	"time"
+
+	// Internal Imports
+	"github.com/matthewhartstonge/storage"
+)
+
+// deniedJTI provides a denied JTI for output.
+type deniedJTI struct {
+	Signature string    `json:"signature"`
+	Expiry    time.Time `json:"exp"`
+	Expired   bool      `json:"expired"`
+}
+
+func jtiResource() resource {
+	return resource{
+		name:    "jti",
+		summary: "Inspect and purge denied JWT IDs.",
+		commands: []command{
+			{
+				name:    "list",
+				summary: "List denied JTIs, by signature.",
+				flags:   jtiList,
+			},
+			{
+				name:    "purge",
+				summary: "Remove expired denied JTIs.",
+				flags:   jtiPurge,
+			},
+		},
+	}
+}
+
+func jtiList(a *app, fs *flag.FlagSet) func(ctx context.Context, args []string) error {
+	expired := fs.Bool("expired", false, "only list expired JTIs")
+
+	return func(ctx context.Context, args []string) error {
+		if err := requireArgs(args, 0); err != nil {
+			return err
+		}
+
+		now := time.Now()
+		filter := storage.ListDeniedJTIsRequest{}
+		if *expired {
+			filter.ExpiresBefore = now.Unix()
+		}
+
+		results, err := a.store.DeniedJTIManager.List(ctx, filter)
+		if err != nil {
+			return err
+		}
+
+		jtis := make([]deniedJTI, 0, len(results))
+		t := table{
+			header: []string{"SIGNATURE", "EXPIRES", "EXPIRED"},
+		}
+		for _, result := range results {
+			jti := deniedJTI{
+				Signature: result.Signature,
+				Expiry:    time.Unix(result.Expiry, 0).UTC(),
+				Expired:   result.Expiry < now.Unix(),
+			}
+			jtis = append(jtis, jti)
+
+			t.rows = append(t.rows, []string{
+				jti.Signature,
+				jti.Expiry.Format(time.RFC3339),
+				strconv.FormatBool(jti.Expired),
+			})
+		}
+
+		return a.render(jtis, t)
+	}
+}
+
+func jtiPurge(a *app, fs *flag.FlagSet) func(ctx context.Context, args []string) error {
+	before := fs.Duration("older-than", 0, "only purge JTIs that expired at least this long ago")
+
+	return func(ctx context.Context, args []string) error {
+		if err := requireArgs(args, 0); err != nil {
+			return err
+		}
+
+		err := a.store.DeniedJTIManager.DeleteBefore(ctx, time.Now().Add(-*before).Unix())
+		if err != nil && !errors.Is(err, storage.ErrNotFound) {
+			return err
+		}
+
+		fmt.Fprintln(a.stderr, "purged expired JTIs")
+		return nil
+	}
+}
@@ -0,0 +1,68 @@
+// Command storagectl enables operators to manage the clients, users, tokens
+// and denied JTIs held in a mongo backed store.
+//
+// storagectl is configured using the same `CONNECTIONS_MONGO_*` environment
+// variables as mongo.Config, optionally overlaid with a YAML or JSON
+// configuration file via `-config`.
+//
+// Usage:
+//
+//	storagectl [-config file] [-output table|json] <resource> <command> [flags] [args]
+//
+// Run `storagectl help` for the list of commands.
+package main
+
+import (
+	// Standard Library Imports
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+
+	// Internal Imports
+	"github.com/matthewhartstonge/storage"
+	"github.com/matthewhartstonge/storage/mongo"
+)
+
+func main() {
+	ctx, cancel := context.WithCancel(context.Background())
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		<-signals
+		cancel()
+	}()
+
+	a := &app{
+		stdout: os.Stdout,
+		stderr: os.Stderr,
+		open:   openMongo,
+	}
+	code := a.run(ctx, os.Args[1:])
+
+	cancel()
+	os.Exit(code)
+}
+
+// openMongo connects to mongo, configured from the environment, overlaid with
+// the configuration file, if provided.
+func openMongo(configFile string) (*storage.Store, func(), error) {
+	var cfg *mongo.Config
+	var err error
+	if configFile != "" {
+		cfg, err = mongo.LoadConfigFile(configFile)
+	} else {
+		cfg, err = mongo.LoadConfig()
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("error loading config: %w", err)
+	}
+
+	store, err := mongo.New(cfg, nil)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error connecting to mongo: %w", err)
+	}
+
+	return &store.Store, store.Close, nil
+}
@@ -0,0 +1,114 @@
+package main
+
+import (
+	// Standard Library Imports
+	"context"
+	"flag"
+	"strconv"
+	"time"
+
+	// Internal Imports
+	"github.com/matthewhartstonge/storage"
+)
+
+func sessionResource() resource {
+	return resource{
+		name:    "session",
+		summary: "Inspect and revoke active sessions, grouping tokens by request.",
+		commands: []command{
+			{
+				name:    "list",
+				summary: "List active sessions, by client or user.",
+				flags:   sessionList,
+			},
+			{
+				name:    "revoke",
+				summary: "Revoke a session, or the sessions of a client or user.",
+				flags:   sessionRevoke,
+			},
+		},
+	}
+}
+
+// formatTime formats the time as RFC 3339, or empty if the time is zero.
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Format(time.RFC3339)
+}
+
+func sessionList(a *app, fs *flag.FlagSet) func(ctx context.Context, args []string) error {
+	filter := storage.ListActiveSessionsRequest{}
+	fs.StringVar(&filter.ClientID, "client", "", "select sessions of the client")
+	fs.StringVar(&filter.UserID, "user", "", "select sessions of the user")
+
+	return func(ctx context.Context, args []string) error {
+		if err := requireArgs(args, 0); err != nil {
+			return err
+		}
+		if filter.ClientID == "" && filter.UserID == "" {
+			return errUsage
+		}
+
+		sessions, err := storage.ListActiveSessions(ctx, a.store.RequestManager, a.store.ClientManager, filter)
+		if err != nil {
+			return err
+		}
+
+		t := table{
+			header: []string{"ID", "CLIENT", "CLIENT NAME", "USER", "SCOPES", "FIRST ISSUED", "LAST REFRESHED", "EXPIRES"},
+		}
+		for _, session := range sessions {
+			t.rows = append(t.rows, []string{
+				session.ID,
+				session.ClientID,
+				session.ClientName,
+				session.UserID,
+				join(session.GrantedScopes),
+				formatTime(session.FirstIssuedAt),
+				formatTime(session.LastRefreshedAt),
+				formatTime(session.ExpiresAt),
+			})
+		}
+
+		return a.render(sessions, t)
+	}
+}
+
+func sessionRevoke(a *app, fs *flag.FlagSet) func(ctx context.Context, args []string) error {
+	revoke := storage.RevokeActiveSessionsRequest{}
+	fs.StringVar(&revoke.ClientID, "client", "", "revoke the sessions of the client")
+	fs.StringVar(&revoke.UserID, "user", "", "revoke the sessions of the user")
+	fs.StringVar(&revoke.ExceptID, "except", "", "retain the session with the ID")
+	id := fs.String("id", "", "revoke the session with the ID")
+
+	return func(ctx context.Context, args []string) error {
+		if err := requireArgs(args, 0); err != nil {
+			return err
+		}
+
+		revoked := 1
+		switch {
+		case *id != "" && revoke.ClientID == "" && revoke.UserID == "" && revoke.ExceptID == "":
+			if err := storage.RevokeActiveSession(ctx, a.store.RequestManager, *id); err != nil {
+				return err
+			}
+
+		case *id == "" && (revoke.ClientID != "" || revoke.UserID != ""):
+			var err error
+			revoked, err = storage.RevokeActiveSessions(ctx, a.store.RequestManager, revoke)
+			if err != nil {
+				return err
+			}
+
+		default:
+			return errUsage
+		}
+
+		return a.render(map[string]int{"sessions": revoked}, table{
+			header: []string{"SESSIONS"},
+			rows:   [][]string{{strconv.Itoa(revoked)}},
+		})
+	}
+}
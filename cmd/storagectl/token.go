@@ -0,0 +1,212 @@
+package main
+
+import (
+	// Standard Library Imports
+	"context"
+	"flag"
+	"fmt"
+	"strconv"
+	"time"
+
+	// Internal Imports
+	"github.com/matthewhartstonge/storage"
+)
+
+const (
+	// tokenTypeAccess selects access tokens.
+	tokenTypeAccess = "access"
+
+	// tokenTypeRefresh selects refresh tokens.
+	tokenTypeRefresh = "refresh"
+
+	// tokenTypeAll selects access and refresh tokens.
+	tokenTypeAll = "all"
+)
+
+// token provides a summary of a stored token, omitting its signature and
+// session data.
+type token struct {
+	Type          string    `json:"type"`
+	RequestID     string    `json:"requestId"`
+	ClientID      string    `json:"clientId"`
+	UserID        string    `json:"userId,omitempty"`
+	GrantedScopes []string  `json:"grantedScopes"`
+	RequestedAt   time.Time `json:"requestedAt"`
+	Active        bool      `json:"active"`
+}
+
+// revocation provides the result of revoking tokens.
+type revocation struct {
+	AccessTokens  int `json:"accessTokens"`
+	RefreshTokens int `json:"refreshTokens"`
+}
+
+func tokenResource() resource {
+	return resource{
+		name:    "token",
+		summary: "Inspect and revoke access and refresh tokens.",
+		commands: []command{
+			{
+				name:    "list",
+				summary: "List tokens, by client or user.",
+				flags:   tokenList,
+			},
+			{
+				name:    "revoke",
+				summary: "Revoke the tokens of a client or user.",
+				flags:   tokenRevoke,
+			},
+		},
+	}
+}
+
+// tokenFilter binds the flags used to select tokens.
+type tokenFilter struct {
+	clientID  string
+	userID    string
+	tokenType string
+}
+
+// bind binds the token filter flags to the flag set.
+func (f *tokenFilter) bind(fs *flag.FlagSet) {
+	fs.StringVar(&f.clientID, "client", "", "select tokens issued to the client")
+	fs.StringVar(&f.userID, "user", "", "select tokens issued to the user")
+	fs.StringVar(&f.tokenType, "type", tokenTypeAll, "token type, either access, refresh or all")
+}
+
+// entities returns the request entities selected by the token type.
+func (f *tokenFilter) entities() (map[string]string, error) {
+	switch f.tokenType {
+	case tokenTypeAccess:
+		return map[string]string{tokenTypeAccess: storage.EntityAccessTokens}, nil
+
+	case tokenTypeRefresh:
+		return map[string]string{tokenTypeRefresh: storage.EntityRefreshTokens}, nil
+
+	case tokenTypeAll:
+		return map[string]string{
+			tokenTypeAccess:  storage.EntityAccessTokens,
+			tokenTypeRefresh: storage.EntityRefreshTokens,
+		}, nil
+	}
+
+	return nil, fmt.Errorf("unknown token type %q", f.tokenType)
+}
+
+// list returns the selected tokens, access tokens first.
+func (f *tokenFilter) list(ctx context.Context, requests storage.RequestStorer) (tokens []token, err error) {
+	if f.clientID == "" && f.userID == "" {
+		return nil, errUsage
+	}
+
+	entities, err := f.entities()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, tokenType := range []string{tokenTypeAccess, tokenTypeRefresh} {
+		entityName, ok := entities[tokenType]
+		if !ok {
+			continue
+		}
+
+		results, err := requests.List(ctx, entityName, storage.ListRequestsRequest{
+			ClientID: f.clientID,
+			UserID:   f.userID,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		for _, request := range results {
+			tokens = append(tokens, token{
+				Type:          tokenType,
+				RequestID:     request.ID,
+				ClientID:      request.ClientID,
+				UserID:        request.UserID,
+				GrantedScopes: request.GrantedScope,
+				RequestedAt:   request.RequestedAt,
+				Active:        request.Active,
+			})
+		}
+	}
+
+	return tokens, nil
+}
+
+func tokenList(a *app, fs *flag.FlagSet) func(ctx context.Context, args []string) error {
+	filter := &tokenFilter{}
+	filter.bind(fs)
+
+	return func(ctx context.Context, args []string) error {
+		if err := requireArgs(args, 0); err != nil {
+			return err
+		}
+
+		tokens, err := filter.list(ctx, a.store.RequestManager)
+		if err != nil {
+			return err
+		}
+
+		t := table{
+			header: []string{"TYPE", "REQUEST ID", "CLIENT", "USER", "SCOPES", "REQUESTED AT", "ACTIVE"},
+		}
+		for _, tok := range tokens {
+			t.rows = append(t.rows, []string{
+				tok.Type,
+				tok.RequestID,
+				tok.ClientID,
+				tok.UserID,
+				join(tok.GrantedScopes),
+				tok.RequestedAt.Format(time.RFC3339),
+				strconv.FormatBool(tok.Active),
+			})
+		}
+
+		if tokens == nil {
+			tokens = []token{}
+		}
+
+		return a.render(tokens, t)
+	}
+}
+
+func tokenRevoke(a *app, fs *flag.FlagSet) func(ctx context.Context, args []string) error {
+	filter := &tokenFilter{}
+	filter.bind(fs)
+
+	return func(ctx context.Context, args []string) error {
+		if err := requireArgs(args, 0); err != nil {
+			return err
+		}
+
+		tokens, err := filter.list(ctx, a.store.RequestManager)
+		if err != nil {
+			return err
+		}
+
+		result := revocation{}
+		for _, tok := range tokens {
+			switch tok.Type {
+			case tokenTypeAccess:
+				err = a.store.RequestManager.RevokeAccessToken(ctx, tok.RequestID)
+				result.AccessTokens++
+
+			case tokenTypeRefresh:
+				err = a.store.RequestManager.RevokeRefreshToken(ctx, tok.RequestID)
+				result.RefreshTokens++
+			}
+			if err != nil {
+				return err
+			}
+		}
+
+		return a.render(result, table{
+			header: []string{"ACCESS TOKENS", "REFRESH TOKENS"},
+			rows: [][]string{{
+				strconv.Itoa(result.AccessTokens),
+				strconv.Itoa(result.RefreshTokens),
+			}},
+		})
+	}
+}
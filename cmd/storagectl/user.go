@@ -0,0 +1,209 @@
+package main
+
+import (
+	// Standard Library Imports
+	"context"
+	"flag"
+	"strconv"
+
+	// Internal Imports
+	"github.com/matthewhartstonge/storage"
+)
+
+// userPassword provides the result of setting a user's password. A
+// generated password is only ever shown once.
+type userPassword struct {
+	ID       string `json:"id"`
+	Username string `json:"username"`
+	Password string `json:"password,omitempty"`
+}
+
+func userResource() resource {
+	return resource{
+		name:    "user",
+		summary: "Manage users.",
+		commands: []command{
+			{
+				name:    "create",
+				summary: "Create a user. If no password is provided, one is generated, and shown once.",
+				flags:   userCreate,
+			},
+			{
+				name:    "list",
+				summary: "List users.",
+				flags:   userList,
+			},
+			{
+				name:    "set-password",
+				args:    "<user-id>",
+				summary: "Set a user's password. If no password is provided, one is generated, and shown once.",
+				flags:   userSetPassword,
+			},
+			{
+				name:    "disable",
+				args:    "<user-id>",
+				summary: "Disable a user from signing in.",
+				flags:   userSetDisabled(true),
+			},
+			{
+				name:    "enable",
+				args:    "<user-id>",
+				summary: "Enable a disabled user to sign in.",
+				flags:   userSetDisabled(false),
+			},
+		},
+	}
+}
+
+func userCreate(a *app, fs *flag.FlagSet) func(ctx context.Context, args []string) error {
+	user := storage.User{}
+	fs.StringVar(&user.ID, "id", "", "user id, generated if empty")
+	fs.StringVar(&user.Username, "username", "", "username, required")
+	fs.StringVar(&user.Email, "email", "", "email address")
+	fs.StringVar(&user.Password, "password", "", "password, generated if empty")
+	fs.StringVar(&user.FirstName, "first-name", "", "first name")
+	fs.StringVar(&user.LastName, "last-name", "", "last name")
+	fs.StringVar(&user.PersonID, "person-id", "", "person id")
+	fs.Var((*stringList)(&user.Scopes), "scopes", "comma separated scopes")
+	fs.Var((*stringList)(&user.AllowedTenantAccess), "tenants", "comma separated allowed tenants")
+	fs.Var((*stringList)(&user.Groups), "groups", "comma separated group ids")
+
+	return func(ctx context.Context, args []string) (err error) {
+		if err = requireArgs(args, 0); err != nil {
+			return err
+		}
+		if user.Username == "" {
+			return errUsage
+		}
+
+		result := userPassword{}
+		if user.Password == "" {
+			if user.Password, err = generateSecret(); err != nil {
+				return err
+			}
+			result.Password = user.Password
+		}
+
+		user, err = a.store.UserManager.Create(ctx, user)
+		if err != nil {
+			return err
+		}
+		result.ID = user.ID
+		result.Username = user.Username
+
+		return a.renderPassword(result)
+	}
+}
+
+func userList(a *app, fs *flag.FlagSet) func(ctx context.Context, args []string) error {
+	filter := storage.ListUsersRequest{}
+	fs.StringVar(&filter.AllowedTenantAccess, "tenant", "", "filter by allowed tenant")
+	fs.StringVar(&filter.Username, "username", "", "filter by username")
+	fs.StringVar(&filter.Email, "email", "", "filter by email address")
+	fs.StringVar(&filter.PersonID, "person-id", "", "filter by person id")
+	fs.StringVar(&filter.Group, "group", "", "filter by group id")
+	fs.Var((*stringList)(&filter.ScopesIntersection), "scopes", "filter by users with all the comma separated scopes")
+	fs.BoolVar(&filter.Disabled, "disabled", false, "only list disabled users")
+
+	return func(ctx context.Context, args []string) error {
+		if err := requireArgs(args, 0); err != nil {
+			return err
+		}
+
+		users, err := a.store.UserManager.List(ctx, filter)
+		if err != nil {
+			return err
+		}
+
+		return a.renderUsers(users)
+	}
+}
+
+func userSetPassword(a *app, fs *flag.FlagSet) func(ctx context.Context, args []string) error {
+	password := fs.String("password", "", "password, generated if empty")
+
+	return func(ctx context.Context, args []string) (err error) {
+		if err = requireArgs(args, 1); err != nil {
+			return err
+		}
+
+		user, err := a.store.UserManager.Get(ctx, args[0])
+		if err != nil {
+			return err
+		}
+
+		result := userPassword{ID: user.ID, Username: user.Username}
+		user.Password = *password
+		if user.Password == "" {
+			if user.Password, err = generateSecret(); err != nil {
+				return err
+			}
+			result.Password = user.Password
+		}
+
+		// The user manager hashes the password on update.
+		if _, err = a.store.UserManager.Update(ctx, user.ID, user); err != nil {
+			return err
+		}
+
+		return a.renderPassword(result)
+	}
+}
+
+// userSetDisabled returns a command that disables, or enables, a user.
+func userSetDisabled(disabled bool) func(a *app, fs *flag.FlagSet) func(ctx context.Context, args []string) error {
+	return func(a *app, fs *flag.FlagSet) func(ctx context.Context, args []string) error {
+		return func(ctx context.Context, args []string) error {
+			if err := requireArgs(args, 1); err != nil {
+				return err
+			}
+
+			user, err := a.store.UserManager.Get(ctx, args[0])
+			if err != nil {
+				return err
+			}
+
+			user.Disabled = disabled
+			user, err = a.store.UserManager.Update(ctx, user.ID, user)
+			if err != nil {
+				return err
+			}
+
+			return a.renderUsers([]storage.User{user})
+		}
+	}
+}
+
+// renderUsers renders users, without their password hashes.
+func (a *app) renderUsers(users []storage.User) error {
+	t := table{
+		header: []string{"ID", "USERNAME", "NAME", "DISABLED", "SCOPES", "TENANTS"},
+	}
+	for i := range users {
+		users[i].Password = ""
+
+		user := users[i]
+		t.rows = append(t.rows, []string{
+			user.ID,
+			user.Username,
+			user.FullName(),
+			strconv.FormatBool(user.Disabled),
+			join(user.Scopes),
+			join(user.AllowedTenantAccess),
+		})
+	}
+
+	if users == nil {
+		users = []storage.User{}
+	}
+
+	return a.render(users, t)
+}
+
+// renderPassword renders the result of setting a user's password.
+func (a *app) renderPassword(result userPassword) error {
+	return a.render(result, table{
+		header: []string{"ID", "USERNAME", "PASSWORD"},
+		rows:   [][]string{{result.ID, result.Username, result.Password}},
+	})
+}
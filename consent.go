@@ -0,0 +1,57 @@
+package storage
+
+import (
+	// Standard Library Imports
+	"time"
+)
+
+// Consent provides the structure of a user's consent, granting a client
+// access to the listed scopes and audiences on the user's behalf.
+type Consent struct {
+	//// Consent Meta
+	// ID is the unique identifier of the consent.
+	ID string `bson:"id" json:"id" xml:"id"`
+
+	// CreateTime is when the resource was created in seconds from the epoch.
+	CreateTime int64 `bson:"createTime" json:"createTime" xml:"createTime"`
+
+	// UpdateTime is the last time the resource was modified in seconds from
+	// the epoch.
+	UpdateTime int64 `bson:"updateTime" json:"updateTime" xml:"updateTime"`
+
+	//// Consent Content
+	// UserID is the ID of the user who granted consent.
+	UserID string `bson:"userId" json:"userId" xml:"userId"`
+
+	// ClientID is the ID of the client consent was granted to.
+	ClientID string `bson:"clientId" json:"clientId" xml:"clientId"`
+
+	// GrantedScopes contains the scopes the user has consented to.
+	GrantedScopes []string `bson:"grantedScopes" json:"grantedScopes" xml:"grantedScopes"`
+
+	// GrantedAudience contains the audiences the user has consented to.
+	GrantedAudience []string `bson:"grantedAudience" json:"grantedAudience,omitempty" xml:"grantedAudience,omitempty"`
+
+	// GrantTime is when consent was last granted in seconds from the epoch.
+	GrantTime int64 `bson:"grantTime" json:"grantTime" xml:"grantTime"`
+
+	// RememberUntil is when the consent expires in seconds from the epoch,
+	// after which the user must be prompted again. If zero, the consent is
+	// remembered until revoked.
+	RememberUntil int64 `bson:"rememberUntil" json:"rememberUntil" xml:"rememberUntil"`
+}
+
+// IsExpired returns whether the consent is no longer remembered at the given
+// time.
+func (c Consent) IsExpired(now time.Time) bool {
+	return c.RememberUntil != 0 && c.RememberUntil <= now.Unix()
+}
+
+// Covers returns whether the consent is remembered at the given time and
+// grants all of the requested scopes and audiences, in which case the user
+// does not need to be prompted.
+func (c Consent) Covers(now time.Time, scopes []string, audience []string) bool {
+	return !c.IsExpired(now) &&
+		containsAll(c.GrantedScopes, scopes) &&
+		containsAll(c.GrantedAudience, audience)
+}
@@ -0,0 +1,46 @@
+package storage
+
+import (
+	// Standard Library Imports
+	"context"
+)
+
+// ConsentManager provides a generic interface to user consents in order to
+// build a Datastore backend.
+type ConsentManager interface {
+	Configurer
+	ConsentStorer
+}
+
+// ConsentStorer provides a definition of specific methods that are required to
+// store a user's Consent in a data store. A user has at most one consent per
+// client.
+type ConsentStorer interface {
+	List(ctx context.Context, filter ListConsentsRequest) ([]Consent, error)
+	Get(ctx context.Context, userID string, clientID string) (Consent, error)
+
+	// Grant records the user's consent to the client. If the user has
+	// already consented to the client, and the consent is yet to expire, the
+	// granted scopes and audiences are merged into the existing consent.
+	Grant(ctx context.Context, consent Consent) (Consent, error)
+
+	// Lookup returns the user's consent to the client if it is yet to expire
+	// and covers all of the requested scopes and audiences, otherwise not
+	// found, in which case the user should be prompted for consent.
+	Lookup(ctx context.Context, userID string, clientID string, scopes []string, audience []string) (Consent, error)
+
+	// Revoke removes the user's consent to the client. If revokeTokens is
+	// true, the access and refresh tokens issued to the client on behalf of
+	// the user are also revoked.
+	Revoke(ctx context.Context, userID string, clientID string, revokeTokens bool) error
+}
+
+// ListConsentsRequest enables filtering stored Consent entities.
+type ListConsentsRequest struct {
+	// UserID filters consents based on User ID.
+	UserID string `json:"userId" xml:"userId"`
+	// ClientID filters consents based on Client ID.
+	ClientID string `json:"clientId" xml:"clientId"`
+	// IncludeExpired includes consents that are no longer remembered.
+	IncludeExpired bool `json:"includeExpired" xml:"includeExpired"`
+}
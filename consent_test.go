@@ -0,0 +1,50 @@
+package storage_test
+
+import (
+	// Standard Library Imports
+	"testing"
+	"time"
+
+	// Internal Imports
+	"github.com/matthewhartstonge/storage"
+)
+
+func TestConsent_IsExpired(t *testing.T) {
+	now := time.Now()
+
+	if (storage.Consent{}).IsExpired(now) {
+		t.Error("expected a consent without an expiry to be remembered until revoked")
+	}
+	if (storage.Consent{RememberUntil: now.Add(time.Hour).Unix()}).IsExpired(now) {
+		t.Error("expected consent to be remembered")
+	}
+	if !(storage.Consent{RememberUntil: now.Unix()}).IsExpired(now) {
+		t.Error("expected consent to have expired")
+	}
+}
+
+func TestConsent_Covers(t *testing.T) {
+	now := time.Now()
+	consent := storage.Consent{
+		GrantedScopes:   []string{"openid", "cats:read"},
+		GrantedAudience: []string{"https://cats.example.com"},
+	}
+
+	if !consent.Covers(now, []string{"cats:read"}, nil) {
+		t.Error("expected a subset of the granted scopes to be covered")
+	}
+	if !consent.Covers(now, []string{"openid", "cats:read"}, []string{"https://cats.example.com"}) {
+		t.Error("expected the granted scopes and audience to be covered")
+	}
+	if consent.Covers(now, []string{"cats:delete"}, nil) {
+		t.Error("expected an ungranted scope to not be covered")
+	}
+	if consent.Covers(now, nil, []string{"https://dogs.example.com"}) {
+		t.Error("expected an ungranted audience to not be covered")
+	}
+
+	consent.RememberUntil = now.Add(-time.Minute).Unix()
+	if consent.Covers(now, []string{"cats:read"}, nil) {
+		t.Error("expected an expired consent to not be covered")
+	}
+}
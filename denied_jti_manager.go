@@ -15,6 +15,7 @@ type DeniedJTIManager interface {
 // DeniedJTIStorer enables storing denied JWT Tokens, by ID.
 type DeniedJTIStorer interface {
 	// Standard CRUD Storage API
+	List(ctx context.Context, filter ListDeniedJTIsRequest) ([]DeniedJTI, error)
 	Create(ctx context.Context, deniedJti DeniedJTI) (DeniedJTI, error)
 	Get(ctx context.Context, jti string) (DeniedJTI, error)
 	Delete(ctx context.Context, jti string) error
@@ -22,3 +23,13 @@ type DeniedJTIStorer interface {
 	// DeleteBefore removes all denied JTIs before the given unix time.
 	DeleteBefore(ctx context.Context, expBefore int64) error
 }
+
+// ListDeniedJTIsRequest enables filtering stored denied JTIs.
+type ListDeniedJTIsRequest struct {
+	// ExpiresBefore filters denied JTIs to those expiring before the given
+	// unix time.
+	ExpiresBefore int64 `json:"expiresBefore" xml:"expiresBefore"`
+	// ExpiresAfter filters denied JTIs to those expiring after the given unix
+	// time.
+	ExpiresAfter int64 `json:"expiresAfter" xml:"expiresAfter"`
+}
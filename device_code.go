@@ -0,0 +1,134 @@
+package storage
+
+import (
+	// Standard Library Imports
+	"errors"
+	"fmt"
+	"time"
+)
+
+const (
+	// DefaultDeviceCodeInterval provides the minimum number of seconds a
+	// device must wait between polling requests, if not specified, as
+	// defined by RFC 8628, section 3.2.
+	DefaultDeviceCodeInterval int64 = 5
+
+	// DeviceCodeSlowDownInterval provides the number of seconds the polling
+	// interval is increased by each time a device polls too quickly, as
+	// defined by RFC 8628, section 3.5.
+	DeviceCodeSlowDownInterval int64 = 5
+)
+
+// ErrSlowDown is wrapped by the error returned when a device polls for a
+// device code faster than its polling interval allows.
+var ErrSlowDown = errors.New("slow down")
+
+// DeviceCodeStatus provides the status of a device authorization request.
+type DeviceCodeStatus string
+
+const (
+	// DeviceCodeStatusPending reports the user is yet to approve or deny the
+	// device.
+	DeviceCodeStatusPending DeviceCodeStatus = "pending"
+
+	// DeviceCodeStatusApproved reports the user has approved the device.
+	DeviceCodeStatusApproved DeviceCodeStatus = "approved"
+
+	// DeviceCodeStatusDenied reports the user has denied the device.
+	DeviceCodeStatusDenied DeviceCodeStatus = "denied"
+
+	// DeviceCodeStatusExpired reports the device code expired before the user
+	// approved or denied the device.
+	DeviceCodeStatusExpired DeviceCodeStatus = "expired"
+)
+
+// IsValid returns whether the status is a known device code status.
+func (s DeviceCodeStatus) IsValid() bool {
+	switch s {
+	case DeviceCodeStatusPending,
+		DeviceCodeStatusApproved,
+		DeviceCodeStatusDenied,
+		DeviceCodeStatusExpired:
+		return true
+	}
+
+	return false
+}
+
+// DeviceCode provides the structure of an OAuth 2.0 Device Authorization
+// Grant (RFC 8628) session, which is polled by the device via the device code
+// and approved, or denied, by the user via the user code.
+type DeviceCode struct {
+	//// Device Code Meta
+	// ID is the unique identifier of the device code session.
+	ID string `bson:"id" json:"id" xml:"id"`
+
+	// CreateTime is when the resource was created in seconds from the epoch.
+	CreateTime int64 `bson:"createTime" json:"createTime" xml:"createTime"`
+
+	// UpdateTime is the last time the resource was modified in seconds from
+	// the epoch.
+	UpdateTime int64 `bson:"updateTime" json:"updateTime" xml:"updateTime"`
+
+	//// Device Code Content
+	// Signature contains the signature of the device code, which the device
+	// uses to poll the token endpoint.
+	Signature string `bson:"signature" json:"signature" xml:"signature"`
+
+	// UserCode contains the code the user enters to approve the device. The
+	// user code should be hashed, or otherwise normalised, by the caller
+	// before being stored.
+	UserCode string `bson:"userCode" json:"userCode" xml:"userCode"`
+
+	// Status contains the status of the device authorization request.
+	// Defaults to DeviceCodeStatusPending on create.
+	Status DeviceCodeStatus `bson:"status" json:"status" xml:"status"`
+
+	// ExpiresAt is when the device code expires in seconds from the epoch.
+	ExpiresAt int64 `bson:"expiresAt" json:"expiresAt" xml:"expiresAt"`
+
+	// Interval contains the minimum number of seconds the device must wait
+	// between polling requests. Defaults to DefaultDeviceCodeInterval on
+	// create, and increases by DeviceCodeSlowDownInterval each time the
+	// device polls too quickly.
+	Interval int64 `bson:"interval" json:"interval" xml:"interval"`
+
+	// LastPolledAt is when the device last polled in seconds from the epoch.
+	LastPolledAt int64 `bson:"lastPolledAt" json:"lastPolledAt" xml:"lastPolledAt"`
+
+	// Request contains the device authorization request. On approval, the
+	// approving user's subject is bound to the request's UserID.
+	Request Request `bson:"request" json:"request" xml:"request"`
+}
+
+// IsExpired returns whether the device code has expired at the given time.
+func (d DeviceCode) IsExpired(now time.Time) bool {
+	return d.ExpiresAt <= now.Unix()
+}
+
+// IsPending returns whether the device code is awaiting the user at the given
+// time.
+func (d DeviceCode) IsPending(now time.Time) bool {
+	return d.Status == DeviceCodeStatusPending && !d.IsExpired(now)
+}
+
+// Validate returns an invalid argument error if the device code is not valid.
+func (d DeviceCode) Validate() error {
+	if d.Signature == "" {
+		return NewInvalidArgumentError(EntityDeviceCodes, "signature is required", "signature")
+	}
+	if d.UserCode == "" {
+		return NewInvalidArgumentError(EntityDeviceCodes, "user code is required", "userCode")
+	}
+	if d.ExpiresAt == 0 {
+		return NewInvalidArgumentError(EntityDeviceCodes, "expiry is required", "expiresAt")
+	}
+	if d.Interval < 0 {
+		return NewInvalidArgumentError(EntityDeviceCodes, "interval must not be negative", "interval")
+	}
+	if !d.Status.IsValid() {
+		return NewInvalidArgumentError(EntityDeviceCodes, fmt.Sprintf("unknown status %q", d.Status), "status")
+	}
+
+	return nil
+}
@@ -0,0 +1,37 @@
+package storage
+
+import (
+	// Standard Library Imports
+	"context"
+)
+
+// DeviceCodeManager provides a generic interface to OAuth 2.0 Device
+// Authorization Grant (RFC 8628) sessions in order to build a Datastore
+// backend.
+type DeviceCodeManager interface {
+	Configurer
+	DeviceCodeStorer
+}
+
+// DeviceCodeStorer provides a definition of specific methods that are required
+// to store a DeviceCode in a data store.
+type DeviceCodeStorer interface {
+	CreateDeviceCodeSession(ctx context.Context, deviceCode DeviceCode) (DeviceCode, error)
+	GetDeviceCodeSession(ctx context.Context, signature string) (DeviceCode, error)
+	GetDeviceCodeSessionByUserCode(ctx context.Context, userCode string) (DeviceCode, error)
+	DeleteDeviceCodeSession(ctx context.Context, signature string) error
+
+	// PollDeviceCodeSession records the device polling for the device code.
+	// If the device polls faster than the device code's interval, the
+	// interval is increased and an error wrapping ErrSlowDown is returned.
+	// Pending device codes past their expiry are marked as expired.
+	PollDeviceCodeSession(ctx context.Context, signature string) (DeviceCode, error)
+
+	// ApproveDeviceCodeSession atomically approves the pending device code,
+	// binding the approving user's subject to the stored request. Device
+	// codes that are no longer pending, or have expired, can't be approved.
+	ApproveDeviceCodeSession(ctx context.Context, userCode string, subject string) (DeviceCode, error)
+
+	// DenyDeviceCodeSession atomically denies the pending device code.
+	DenyDeviceCodeSession(ctx context.Context, userCode string) (DeviceCode, error)
+}
@@ -0,0 +1,76 @@
+package storage_test
+
+import (
+	// Standard Library Imports
+	"errors"
+	"testing"
+	"time"
+
+	// Internal Imports
+	"github.com/matthewhartstonge/storage"
+)
+
+func TestDeviceCodeStatus_IsValid(t *testing.T) {
+	for _, status := range []storage.DeviceCodeStatus{
+		storage.DeviceCodeStatusPending,
+		storage.DeviceCodeStatusApproved,
+		storage.DeviceCodeStatusDenied,
+		storage.DeviceCodeStatusExpired,
+	} {
+		if !status.IsValid() {
+			t.Errorf("expected %q to be valid", status)
+		}
+	}
+
+	if storage.DeviceCodeStatus("polling").IsValid() {
+		t.Error("expected an unknown status to be invalid")
+	}
+}
+
+func TestDeviceCode_IsPending(t *testing.T) {
+	now := time.Now()
+	deviceCode := storage.DeviceCode{
+		Status:    storage.DeviceCodeStatusPending,
+		ExpiresAt: now.Add(time.Minute).Unix(),
+	}
+
+	if !deviceCode.IsPending(now) {
+		t.Error("expected device code to be pending")
+	}
+	if deviceCode.IsPending(now.Add(time.Hour)) {
+		t.Error("expected an expired device code to not be pending")
+	}
+
+	deviceCode.Status = storage.DeviceCodeStatusApproved
+	if deviceCode.IsPending(now) {
+		t.Error("expected an approved device code to not be pending")
+	}
+}
+
+func TestDeviceCode_Validate(t *testing.T) {
+	valid := storage.DeviceCode{
+		Signature: "device-code-signature",
+		UserCode:  "WDJB-MJHT",
+		Status:    storage.DeviceCodeStatusPending,
+		ExpiresAt: time.Now().Add(10 * time.Minute).Unix(),
+		Interval:  storage.DefaultDeviceCodeInterval,
+	}
+	if err := valid.Validate(); err != nil {
+		t.Errorf("expected device code to be valid, got %v", err)
+	}
+
+	invalid := map[string]func(d *storage.DeviceCode){
+		"signature": func(d *storage.DeviceCode) { d.Signature = "" },
+		"user code": func(d *storage.DeviceCode) { d.UserCode = "" },
+		"expiry":    func(d *storage.DeviceCode) { d.ExpiresAt = 0 },
+		"interval":  func(d *storage.DeviceCode) { d.Interval = -1 },
+		"status":    func(d *storage.DeviceCode) { d.Status = "polling" },
+	}
+	for name, invalidate := range invalid {
+		deviceCode := valid
+		invalidate(&deviceCode)
+		if err := deviceCode.Validate(); !errors.Is(err, storage.ErrInvalidArgument) {
+			t.Errorf("%s: expected invalid argument, got %v", name, err)
+		}
+	}
+}
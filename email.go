@@ -0,0 +1,15 @@
+package storage
+
+import (
+	// Standard Library Imports
+	"strings"
+)
+
+// NormalizeEmail returns the form email addresses are stored and compared
+// in. As with usernames, email addresses are NFKC normalized and case folded.
+// Although the local part of an address may be case sensitive, mail
+// providers treat it case insensitively, so "Peter@Example.com" and
+// "peter@example.com" are the same user.
+func NormalizeEmail(email string) string {
+	return NormalizeUsername(strings.TrimSpace(email))
+}
@@ -0,0 +1,15 @@
+package storage_test
+
+import (
+	// Standard Library Imports
+	"testing"
+
+	// Internal Imports
+	"github.com/matthewhartstonge/storage"
+)
+
+func TestNormalizeEmail(t *testing.T) {
+	if got := storage.NormalizeEmail(" Peter@Example.COM "); got != "peter@example.com" {
+		t.Errorf("expected peter@example.com, got %q", got)
+	}
+}
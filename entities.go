@@ -21,6 +21,14 @@ const (
 	// create, read, update and delete Proof Key for Code Exchange sessions.
 	EntityPKCESessions = "pkceSessions"
 
+	// EntityDeviceCodes provides the name of the entity to use in order to
+	// create, read, update and delete Device Authorization Grant sessions.
+	EntityDeviceCodes = "deviceCodes"
+
+	// EntityPARSessions provides the name of the entity to use in order to
+	// create, read, update and delete Pushed Authorization Request sessions.
+	EntityPARSessions = "parSessions"
+
 	// EntityJtiDenylist provides teh name of the entity to use in order to
 	// track and deny.
 	EntityJtiDenylist = "jtiDenylist"
@@ -32,4 +40,37 @@ const (
 	// EntityUsers provides the name of the entity to use in order to create,
 	// read, update and delete Users.
 	EntityUsers = "users"
+
+	// EntityGroups provides the name of the entity to use in order to create,
+	// read, update and delete user Groups.
+	EntityGroups = "groups"
+
+	// EntityIssuerTrusts provides the name of the entity to use in order to
+	// create, read, update and delete the trust relationships of JWT issuers.
+	EntityIssuerTrusts = "issuerTrusts"
+
+	// EntityLoginSessions provides the name of the entity to use in order to
+	// create, read, update and delete user Login Sessions.
+	EntityLoginSessions = "loginSessions"
+
+	// EntityScopes provides the name of the entity to use in order to create,
+	// read, update and delete registered Scopes.
+	EntityScopes = "scopes"
+
+	// EntityTenants provides the name of the entity to use in order to create,
+	// read, update and delete Tenants.
+	EntityTenants = "tenants"
+
+	// EntityConsents provides the name of the entity to use in order to
+	// create, read, update and delete user Consents.
+	EntityConsents = "consents"
+
+	// EntityMigrations provides the name of the entity to use in order to
+	// track applied schema migrations.
+	EntityMigrations = "migrations"
+
+	// EntityLeases provides the name of the entity to use in order to hold
+	// distributed leases, which ensure background work is only performed by
+	// one service instance at a time.
+	EntityLeases = "leases"
 )
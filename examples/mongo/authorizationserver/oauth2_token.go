@@ -36,6 +36,30 @@ func tokenEndpoint(rw http.ResponseWriter, req *http.Request) {
 		}
 	}
 
+	// If this is a password grant, grant the scopes the user is entitled to,
+	// including those inherited from the user's groups.
+	if accessRequest.GetGrantTypes().Exact("password") {
+		user, err := store.UserManager.GetByUsername(ctx, accessRequest.GetRequestForm().Get("username"))
+		if err != nil {
+			log.Printf("Error occurred in GetByUsername: %+v", err)
+			oauth2.WriteAccessError(rw, accessRequest, err)
+			return
+		}
+
+		permissions, err := store.EffectivePermissions(ctx, user)
+		if err != nil {
+			log.Printf("Error occurred in EffectivePermissions: %+v", err)
+			oauth2.WriteAccessError(rw, accessRequest, err)
+			return
+		}
+
+		for _, scope := range permissions.GrantableScopes(accessRequest.GetRequestedScopes()) {
+			if fosite.HierarchicScopeStrategy(accessRequest.GetClient().GetScopes(), scope) {
+				accessRequest.GrantScope(scope)
+			}
+		}
+	}
+
 	// Next we create a response for the access request. Again, we iterate through the TokenEndpointHandlers
 	// and aggregate the result in response.
 	response, err := oauth2.NewAccessResponse(ctx, accessRequest)
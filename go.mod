@@ -4,10 +4,17 @@ go 1.14
 
 require (
 	github.com/google/uuid v1.2.0
+	github.com/kelseyhightower/envconfig v1.4.0
 	github.com/opentracing/opentracing-go v1.1.0
 	github.com/ory/fosite v0.32.2
 	github.com/pkg/errors v0.9.1
 	github.com/sirupsen/logrus v1.4.2
-	github.com/stretchr/testify v1.6.1
+	github.com/stretchr/testify v1.7.0
 	go.mongodb.org/mongo-driver v1.5.2
+	go.opentelemetry.io/otel v1.0.1
+	go.opentelemetry.io/otel/sdk v1.0.1
+	go.opentelemetry.io/otel/trace v1.0.1
+	golang.org/x/text v0.3.5
+	gopkg.in/square/go-jose.v2 v2.5.0
+	gopkg.in/yaml.v2 v2.2.8
 )
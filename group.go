@@ -0,0 +1,123 @@
+package storage
+
+import (
+	// Standard Library Imports
+	"context"
+)
+
+// Group provides the structure of a group, or role, which bundles scopes,
+// tenant access and person access. Users inherit the access of the groups
+// they are a member of, as listed in User.Groups.
+type Group struct {
+	//// Group Meta
+	// ID is the unique identifier of the group.
+	ID string `bson:"id" json:"id" xml:"id"`
+
+	// CreateTime is when the resource was created in seconds from the epoch.
+	CreateTime int64 `bson:"createTime" json:"createTime" xml:"createTime"`
+
+	// UpdateTime is the last time the resource was modified in seconds from
+	// the epoch.
+	UpdateTime int64 `bson:"updateTime" json:"updateTime" xml:"updateTime"`
+
+	//// Group Content
+	// Name contains the unique name of the group, for example, staff.
+	Name string `bson:"name" json:"name" xml:"name"`
+
+	// Description contains a human-readable description of the group.
+	Description string `bson:"description" json:"description" xml:"description"`
+
+	// Scopes contains the scopes the members of the group are entitled to
+	// request.
+	Scopes []string `bson:"scopes" json:"scopes" xml:"scopes"`
+
+	// AllowedTenantAccess contains the Tenant IDs the members of the group
+	// have been given rights to access.
+	AllowedTenantAccess []string `bson:"allowedTenantAccess" json:"allowedTenantAccess,omitempty" xml:"allowedTenantAccess,omitempty"`
+
+	// AllowedPersonAccess contains the Person IDs the members of the group
+	// are allowed access to.
+	AllowedPersonAccess []string `bson:"allowedPersonAccess" json:"allowedPersonAccess,omitempty" xml:"allowedPersonAccess,omitempty"`
+}
+
+// Validate returns an invalid argument error if the group is not valid.
+func (g Group) Validate() error {
+	if g.Name == "" {
+		return NewInvalidArgumentError(EntityGroups, "name is required", "name")
+	}
+
+	return nil
+}
+
+// Permissions provides a user's effective permissions, being the user's own
+// scopes and access merged with those inherited from their groups.
+type Permissions struct {
+	// Scopes contains the scopes the user is entitled to request.
+	Scopes []string `json:"scopes" xml:"scopes"`
+
+	// AllowedTenantAccess contains the Tenant IDs the user has rights to
+	// access.
+	AllowedTenantAccess []string `json:"allowedTenantAccess,omitempty" xml:"allowedTenantAccess,omitempty"`
+
+	// AllowedPersonAccess contains the Person IDs the user is allowed access
+	// to.
+	AllowedPersonAccess []string `json:"allowedPersonAccess,omitempty" xml:"allowedPersonAccess,omitempty"`
+}
+
+// HasScopes returns whether the user is entitled to request all of the
+// scopes.
+func (p Permissions) HasScopes(scopes ...string) bool {
+	return containsAll(p.Scopes, scopes)
+}
+
+// GrantableScopes returns the requested scopes the user is entitled to,
+// for example, to grant in the resource owner password credentials flow.
+func (p Permissions) GrantableScopes(requested []string) (scopes []string) {
+	for _, scope := range requested {
+		if contains(p.Scopes, scope) {
+			scopes = append(scopes, scope)
+		}
+	}
+
+	return scopes
+}
+
+// EffectivePermissions returns the user's effective permissions, merging the
+// user's scopes, tenant access and person access with those of the groups
+// the user is a member of. Groups that no longer exist are skipped. If groups
+// is nil, only the user's own permissions are returned.
+func EffectivePermissions(ctx context.Context, groups GroupStorer, user User) (Permissions, error) {
+	permissions := Permissions{
+		Scopes:              appendUnique(nil, user.Scopes...),
+		AllowedTenantAccess: appendUnique(nil, user.AllowedTenantAccess...),
+		AllowedPersonAccess: appendUnique(nil, user.AllowedPersonAccess...),
+	}
+	if groups == nil || len(user.Groups) == 0 {
+		return permissions, nil
+	}
+
+	memberOf, err := groups.List(ctx, ListGroupsRequest{IDs: user.Groups})
+	if err != nil {
+		return Permissions{}, err
+	}
+
+	for _, group := range memberOf {
+		permissions.Scopes = appendUnique(permissions.Scopes, group.Scopes...)
+		permissions.AllowedTenantAccess = appendUnique(permissions.AllowedTenantAccess, group.AllowedTenantAccess...)
+		permissions.AllowedPersonAccess = appendUnique(permissions.AllowedPersonAccess, group.AllowedPersonAccess...)
+	}
+
+	return permissions, nil
+}
+
+// appendUnique appends the values to the list that it doesn't already
+// contain.
+func appendUnique(list []string, values ...string) []string {
+	for _, value := range values {
+		if !contains(list, value) {
+			list = append(list, value)
+		}
+	}
+
+	return list
+}
@@ -0,0 +1,39 @@
+package storage
+
+import (
+	// Standard Library Imports
+	"context"
+)
+
+// GroupManager provides a generic interface to groups in order to build a
+// Datastore backend.
+type GroupManager interface {
+	Configurer
+	GroupStorer
+}
+
+// GroupStorer provides a definition of specific methods that are required to
+// store a Group in a data store. User membership of groups is stored on the
+// user, via User.Groups.
+type GroupStorer interface {
+	List(ctx context.Context, filter ListGroupsRequest) ([]Group, error)
+	Create(ctx context.Context, group Group) (Group, error)
+	Get(ctx context.Context, groupID string) (Group, error)
+	Update(ctx context.Context, groupID string, group Group) (Group, error)
+
+	// Delete deletes the group, removing its members from the group.
+	Delete(ctx context.Context, groupID string) error
+}
+
+// ListGroupsRequest enables filtering stored Group entities.
+type ListGroupsRequest struct {
+	// IDs filters groups to those with one of the listed IDs.
+	IDs []string `json:"ids" xml:"ids"`
+	// Name filters groups based on name.
+	Name string `json:"name" xml:"name"`
+	// Scope filters groups to those that grant the scope.
+	Scope string `json:"scope" xml:"scope"`
+	// AllowedTenantAccess filters groups to those that grant access to the
+	// tenant.
+	AllowedTenantAccess string `json:"allowedTenantAccess" xml:"allowedTenantAccess"`
+}
@@ -0,0 +1,149 @@
+package storage_test
+
+import (
+	// Standard Library Imports
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	// Internal Imports
+	"github.com/matthewhartstonge/storage"
+)
+
+// memoryGroups provides an in-memory group storer, for calculating
+// effective permissions.
+type memoryGroups struct {
+	storage.GroupManager
+	groups map[string]storage.Group
+}
+
+func (m *memoryGroups) List(ctx context.Context, filter storage.ListGroupsRequest) (results []storage.Group, err error) {
+	for _, group := range m.groups {
+		if len(filter.IDs) > 0 && !containsString(filter.IDs, group.ID) {
+			continue
+		}
+		if filter.AllowedTenantAccess != "" && !containsString(group.AllowedTenantAccess, filter.AllowedTenantAccess) {
+			continue
+		}
+		results = append(results, group)
+	}
+
+	return results, nil
+}
+
+func (m *memoryGroups) Get(ctx context.Context, groupID string) (storage.Group, error) {
+	group, ok := m.groups[groupID]
+	if !ok {
+		return storage.Group{}, storage.NewNotFoundError(storage.EntityGroups)
+	}
+	return group, nil
+}
+
+func (m *memoryGroups) Create(ctx context.Context, group storage.Group) (storage.Group, error) {
+	m.groups[group.ID] = group
+	return group, nil
+}
+
+// containsString returns true if the list contains the value.
+func containsString(list []string, value string) bool {
+	for _, item := range list {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
+
+func TestGroup_Validate(t *testing.T) {
+	if err := (storage.Group{Name: "staff"}).Validate(); err != nil {
+		t.Errorf("expected no error, got %v", err)
+	}
+	if err := (storage.Group{}).Validate(); !errors.Is(err, storage.ErrInvalidArgument) {
+		t.Errorf("expected invalid argument error, got %v", err)
+	}
+}
+
+func TestUser_GroupMembership(t *testing.T) {
+	user := storage.User{}
+	user.EnableGroupMembership("staff", "admins", "staff")
+	if !reflect.DeepEqual(user.Groups, []string{"staff", "admins"}) {
+		t.Errorf("expected unique groups, got %v", user.Groups)
+	}
+
+	user.DisableGroupMembership("staff", "unknown")
+	if !reflect.DeepEqual(user.Groups, []string{"admins"}) {
+		t.Errorf("expected user to have left staff, got %v", user.Groups)
+	}
+}
+
+func TestEffectivePermissions(t *testing.T) {
+	ctx := context.Background()
+	groups := &memoryGroups{groups: map[string]storage.Group{
+		"staff": {
+			ID:                  "staff",
+			Name:                "Staff",
+			Scopes:              []string{"openid", "cats:read"},
+			AllowedTenantAccess: []string{"tenant-1"},
+		},
+		"vets": {
+			ID:                  "vets",
+			Name:                "Vets",
+			Scopes:              []string{"cats:read", "cats:treat"},
+			AllowedPersonAccess: []string{"person-1"},
+		},
+	}}
+	user := storage.User{
+		Scopes:              []string{"openid", "profile"},
+		AllowedTenantAccess: []string{"tenant-2"},
+		Groups:              []string{"staff", "vets", "deleted"},
+	}
+
+	permissions, err := storage.EffectivePermissions(ctx, groups, user)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	expected := storage.Permissions{
+		Scopes:              []string{"openid", "profile", "cats:read", "cats:treat"},
+		AllowedTenantAccess: []string{"tenant-2", "tenant-1"},
+		AllowedPersonAccess: []string{"person-1"},
+	}
+	if !reflect.DeepEqual(permissions, expected) {
+		t.Errorf("expected %+v, got %+v", expected, permissions)
+	}
+
+	if !permissions.HasScopes("profile", "cats:treat") {
+		t.Error("expected inherited scopes to be effective")
+	}
+	if permissions.HasScopes("cats:delete") {
+		t.Error("expected ungranted scopes to not be effective")
+	}
+	if granted := permissions.GrantableScopes([]string{"openid", "cats:delete", "cats:treat"}); !reflect.DeepEqual(granted, []string{"openid", "cats:treat"}) {
+		t.Errorf("expected only entitled scopes to be grantable, got %v", granted)
+	}
+}
+
+func TestEffectivePermissions_WithoutGroups(t *testing.T) {
+	user := storage.User{Scopes: []string{"openid"}, Groups: []string{"staff"}}
+
+	permissions, err := storage.EffectivePermissions(context.Background(), nil, user)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if !reflect.DeepEqual(permissions.Scopes, []string{"openid"}) {
+		t.Errorf("expected only the user's scopes, got %v", permissions.Scopes)
+	}
+}
+
+func TestStore_EffectivePermissions_WithoutGroupManager(t *testing.T) {
+	store := &storage.Store{}
+
+	permissions, err := store.EffectivePermissions(context.Background(), storage.User{Scopes: []string{"openid"}})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if !permissions.HasScopes("openid") {
+		t.Errorf("expected the user's scopes, got %v", permissions.Scopes)
+	}
+}
@@ -0,0 +1,163 @@
+package storage
+
+import (
+	// Standard Library Imports
+	"context"
+	"encoding/json"
+	"time"
+
+	// External Imports
+	"gopkg.in/square/go-jose.v2"
+)
+
+// RFC7523KeyStorage provides storage for the keys of issuers trusted to
+// assert the JWT Profile for OAuth 2.0 Authorization Grants (RFC 7523), and
+// replay protection for the assertions they issue.
+//
+// RFC7523KeyStorage mirrors the interface expected by fosite's rfc7523
+// handler, which is yet to be released in the version of fosite this module
+// depends on.
+type RFC7523KeyStorage interface {
+	// GetPublicKey returns the public key, identified by key ID, the issuer
+	// is trusted to sign assertions for the subject with.
+	GetPublicKey(ctx context.Context, issuer string, subject string, keyID string) (*jose.JSONWebKey, error)
+
+	// GetPublicKeys returns the public keys the issuer is trusted to sign
+	// assertions for the subject with.
+	GetPublicKeys(ctx context.Context, issuer string, subject string) (*jose.JSONWebKeySet, error)
+
+	// GetPublicKeyScopes returns the scopes the issuer is allowed to request
+	// for the subject, when signing with the identified key.
+	GetPublicKeyScopes(ctx context.Context, issuer string, subject string, keyID string) ([]string, error)
+
+	// IsJWTUsed returns whether an unexpired assertion has already been used
+	// with the given JTI.
+	IsJWTUsed(ctx context.Context, jti string) (bool, error)
+
+	// MarkJWTUsedForTime marks the assertion's JTI as used until it expires.
+	MarkJWTUsedForTime(ctx context.Context, jti string, exp time.Time) error
+}
+
+// IssuerTrust provides the structure of a trust relationship with a JWT
+// issuer, allowing the issuer to exchange assertions, signed with the trusted
+// public key, for access tokens via the JWT bearer grant (RFC 7523).
+type IssuerTrust struct {
+	//// Issuer Trust Meta
+	// ID is the unique identifier of the trust relationship.
+	ID string `bson:"id" json:"id" xml:"id"`
+
+	// CreateTime is when the resource was created in seconds from the epoch.
+	CreateTime int64 `bson:"createTime" json:"createTime" xml:"createTime"`
+
+	// UpdateTime is the last time the resource was modified in seconds from
+	// the epoch.
+	UpdateTime int64 `bson:"updateTime" json:"updateTime" xml:"updateTime"`
+
+	//// Issuer Trust Content
+	// Issuer is the `iss` of the assertions the issuer signs.
+	Issuer string `bson:"issuer" json:"issuer" xml:"issuer"`
+
+	// Subject is the `sub` the issuer is trusted to assert. Must be empty if
+	// AllowAnySubject is set.
+	Subject string `bson:"subject" json:"subject,omitempty" xml:"subject,omitempty"`
+
+	// AllowAnySubject trusts the issuer to assert any subject.
+	AllowAnySubject bool `bson:"allowAnySubject" json:"allowAnySubject" xml:"allowAnySubject"`
+
+	// KeyID is the `kid` of the trusted public key.
+	KeyID string `bson:"keyId" json:"keyId" xml:"keyId"`
+
+	// PublicKey contains the trusted public key as a JSON Web Key (JWK).
+	PublicKey json.RawMessage `bson:"publicKey" json:"publicKey" xml:"-"`
+
+	// Scopes contains the scopes the issuer is allowed to request.
+	Scopes []string `bson:"scopes" json:"scopes" xml:"scopes"`
+
+	// ExpiresAt is when the trust relationship expires in seconds from the
+	// epoch.
+	ExpiresAt int64 `bson:"expiresAt" json:"expiresAt" xml:"expiresAt"`
+}
+
+// NewIssuerTrust returns a new trust relationship with the issuer for the
+// public key. If subject is empty, the issuer is trusted to assert any
+// subject.
+func NewIssuerTrust(issuer string, subject string, key jose.JSONWebKey, scopes []string, expiresAt time.Time) (IssuerTrust, error) {
+	trust := IssuerTrust{
+		Issuer:          issuer,
+		Subject:         subject,
+		AllowAnySubject: subject == "",
+		Scopes:          scopes,
+		ExpiresAt:       expiresAt.Unix(),
+	}
+	if err := trust.SetJSONWebKey(key); err != nil {
+		return IssuerTrust{}, err
+	}
+
+	return trust, nil
+}
+
+// JSONWebKey decodes the trusted public key.
+func (t IssuerTrust) JSONWebKey() (*jose.JSONWebKey, error) {
+	key := &jose.JSONWebKey{}
+	if err := key.UnmarshalJSON(t.PublicKey); err != nil {
+		return nil, NewInvalidArgumentError(EntityIssuerTrusts, "public key must be a valid jwk", "publicKey")
+	}
+
+	return key, nil
+}
+
+// SetJSONWebKey sets the trusted public key, and its key ID. Only public keys
+// can be trusted.
+func (t *IssuerTrust) SetJSONWebKey(key jose.JSONWebKey) error {
+	if !key.Valid() || !key.IsPublic() {
+		return NewInvalidArgumentError(EntityIssuerTrusts, "public key must be a valid public jwk", "publicKey")
+	}
+
+	publicKey, err := key.MarshalJSON()
+	if err != nil {
+		return NewInvalidArgumentError(EntityIssuerTrusts, "public key must be a valid public jwk", "publicKey")
+	}
+	t.KeyID = key.KeyID
+	t.PublicKey = publicKey
+
+	return nil
+}
+
+// IsExpired returns whether the trust relationship has expired at the given
+// time.
+func (t IssuerTrust) IsExpired(now time.Time) bool {
+	return t.ExpiresAt <= now.Unix()
+}
+
+// Validate returns an invalid argument error if the trust relationship is not
+// valid.
+func (t IssuerTrust) Validate() error {
+	if t.Issuer == "" {
+		return NewInvalidArgumentError(EntityIssuerTrusts, "issuer is required", "issuer")
+	}
+	if t.Subject == "" && !t.AllowAnySubject {
+		return NewInvalidArgumentError(EntityIssuerTrusts, "subject is required unless any subject is allowed", "subject", "allowAnySubject")
+	}
+	if t.Subject != "" && t.AllowAnySubject {
+		return NewInvalidArgumentError(EntityIssuerTrusts, "subject must be empty if any subject is allowed", "subject", "allowAnySubject")
+	}
+	if t.KeyID == "" {
+		return NewInvalidArgumentError(EntityIssuerTrusts, "key id is required", "keyId")
+	}
+	if t.ExpiresAt == 0 {
+		return NewInvalidArgumentError(EntityIssuerTrusts, "expiry is required", "expiresAt")
+	}
+
+	key, err := t.JSONWebKey()
+	if err != nil {
+		return err
+	}
+	if !key.Valid() || !key.IsPublic() {
+		return NewInvalidArgumentError(EntityIssuerTrusts, "public key must be a valid public jwk", "publicKey")
+	}
+	if key.KeyID != t.KeyID {
+		return NewInvalidArgumentError(EntityIssuerTrusts, "key id must match the public key's kid", "keyId")
+	}
+
+	return nil
+}
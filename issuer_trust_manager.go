@@ -0,0 +1,38 @@
+package storage
+
+import (
+	// Standard Library Imports
+	"context"
+)
+
+// IssuerTrustManager provides a generic interface to trusted JWT issuers in
+// order to build a Datastore backend.
+type IssuerTrustManager interface {
+	Configurer
+	IssuerTrustStorer
+}
+
+// IssuerTrustStorer provides a definition of specific methods that are
+// required to store the trust relationships of JWT issuers in a data store.
+// An issuer has at most one trust relationship per subject and key.
+type IssuerTrustStorer interface {
+	// RFC7523KeyStorage provides the keys fosite's rfc7523 handler verifies
+	// assertions with.
+	RFC7523KeyStorage
+
+	List(ctx context.Context, filter ListIssuerTrustsRequest) ([]IssuerTrust, error)
+	Create(ctx context.Context, trust IssuerTrust) (IssuerTrust, error)
+	Get(ctx context.Context, trustID string) (IssuerTrust, error)
+	Delete(ctx context.Context, trustID string) error
+}
+
+// ListIssuerTrustsRequest enables filtering stored IssuerTrust entities.
+type ListIssuerTrustsRequest struct {
+	// Issuer filters trust relationships based on issuer.
+	Issuer string `json:"issuer" xml:"issuer"`
+	// Subject filters trust relationships based on the trusted subject,
+	// including those trusted to assert any subject.
+	Subject string `json:"subject" xml:"subject"`
+	// IncludeExpired includes trust relationships that have expired.
+	IncludeExpired bool `json:"includeExpired" xml:"includeExpired"`
+}
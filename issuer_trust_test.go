@@ -0,0 +1,136 @@
+package storage_test
+
+import (
+	// Standard Library Imports
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"errors"
+	"testing"
+	"time"
+
+	// External Imports
+	"gopkg.in/square/go-jose.v2"
+
+	// Internal Imports
+	"github.com/matthewhartstonge/storage"
+)
+
+func newTestJWK(t *testing.T, keyID string) (private jose.JSONWebKey, public jose.JSONWebKey) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("error generating key: %v", err)
+	}
+
+	private = jose.JSONWebKey{Key: key, KeyID: keyID, Algorithm: string(jose.ES256), Use: "sig"}
+	return private, private.Public()
+}
+
+func TestNewIssuerTrust(t *testing.T) {
+	_, public := newTestJWK(t, "key-1")
+	expiresAt := time.Now().Add(time.Hour)
+
+	trust, err := storage.NewIssuerTrust("https://idp.example.com", "", public, []string{"cats:read"}, expiresAt)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if !trust.AllowAnySubject {
+		t.Error("expected an empty subject to allow any subject")
+	}
+	if trust.KeyID != "key-1" {
+		t.Errorf("expected key id key-1, got %q", trust.KeyID)
+	}
+	if err := trust.Validate(); err != nil {
+		t.Errorf("expected a valid trust, got %v", err)
+	}
+
+	key, err := trust.JSONWebKey()
+	if err != nil {
+		t.Fatalf("expected no error decoding the key, got %v", err)
+	}
+	if key.KeyID != "key-1" || !key.IsPublic() {
+		t.Errorf("expected the public key to round trip, got %+v", key)
+	}
+}
+
+func TestNewIssuerTrust_ShouldRejectPrivateKeys(t *testing.T) {
+	private, _ := newTestJWK(t, "key-1")
+
+	_, err := storage.NewIssuerTrust("https://idp.example.com", "alice", private, nil, time.Now().Add(time.Hour))
+	if !errors.Is(err, storage.ErrInvalidArgument) {
+		t.Errorf("expected invalid argument error, got %v", err)
+	}
+}
+
+func TestIssuerTrust_IsExpired(t *testing.T) {
+	now := time.Now()
+
+	if (storage.IssuerTrust{ExpiresAt: now.Add(time.Hour).Unix()}).IsExpired(now) {
+		t.Error("expected trust to be active")
+	}
+	if !(storage.IssuerTrust{ExpiresAt: now.Unix()}).IsExpired(now) {
+		t.Error("expected trust to have expired")
+	}
+}
+
+func TestIssuerTrust_Validate(t *testing.T) {
+	_, public := newTestJWK(t, "key-1")
+	expiresAt := time.Now().Add(time.Hour)
+
+	tests := []struct {
+		name   string
+		mutate func(trust *storage.IssuerTrust)
+		valid  bool
+	}{
+		{
+			name:   "valid",
+			mutate: func(trust *storage.IssuerTrust) {},
+			valid:  true,
+		},
+		{
+			name:   "missing issuer",
+			mutate: func(trust *storage.IssuerTrust) { trust.Issuer = "" },
+		},
+		{
+			name:   "missing subject",
+			mutate: func(trust *storage.IssuerTrust) { trust.Subject = "" },
+		},
+		{
+			name:   "subject and any subject",
+			mutate: func(trust *storage.IssuerTrust) { trust.AllowAnySubject = true },
+		},
+		{
+			name:   "missing expiry",
+			mutate: func(trust *storage.IssuerTrust) { trust.ExpiresAt = 0 },
+		},
+		{
+			name:   "mismatched key id",
+			mutate: func(trust *storage.IssuerTrust) { trust.KeyID = "key-2" },
+		},
+		{
+			name:   "invalid public key",
+			mutate: func(trust *storage.IssuerTrust) { trust.PublicKey = []byte("{}") },
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			trust, err := storage.NewIssuerTrust("https://idp.example.com", "alice", public, nil, expiresAt)
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+			tt.mutate(&trust)
+
+			err = trust.Validate()
+			if tt.valid {
+				if err != nil {
+					t.Errorf("expected no error, got %v", err)
+				}
+				return
+			}
+			if !errors.Is(err, storage.ErrInvalidArgument) {
+				t.Errorf("expected invalid argument error, got %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,83 @@
+package storage
+
+import (
+	// Standard Library Imports
+	"crypto/sha256"
+	"encoding/hex"
+	"time"
+)
+
+// HashLoginSessionID returns the hash of a login session ID, as stored. Login
+// session IDs are bearer credentials, held in the user's browser, so only
+// their hash is stored.
+func HashLoginSessionID(sessionID string) string {
+	hash := sha256.Sum256([]byte(sessionID))
+	return hex.EncodeToString(hash[:])
+}
+
+// LoginSession provides the structure of an authenticated browser session,
+// enabling the authorization endpoint to skip prompting for credentials, and
+// to honour OpenID Connect's `prompt=none` and `max_age`.
+type LoginSession struct {
+	//// Login Session Meta
+	// ID is the hash of the login session ID, as returned by
+	// HashLoginSessionID.
+	ID string `bson:"id" json:"id" xml:"id"`
+
+	// CreateTime is when the resource was created in seconds from the epoch.
+	CreateTime int64 `bson:"createTime" json:"createTime" xml:"createTime"`
+
+	// UpdateTime is the last time the resource was modified in seconds from
+	// the epoch.
+	UpdateTime int64 `bson:"updateTime" json:"updateTime" xml:"updateTime"`
+
+	//// Login Session Content
+	// UserID is the ID of the authenticated user.
+	UserID string `bson:"userId" json:"userId" xml:"userId"`
+
+	// AuthTime is when the user authenticated in seconds from the epoch,
+	// used as the OpenID Connect `auth_time` claim.
+	AuthTime int64 `bson:"authTime" json:"authTime" xml:"authTime"`
+
+	// AMR contains the authentication methods used, used as the OpenID
+	// Connect `amr` claim, for example, pwd and otp.
+	AMR []string `bson:"amr" json:"amr,omitempty" xml:"amr,omitempty"`
+
+	// ACR contains the authentication context class satisfied, used as the
+	// OpenID Connect `acr` claim.
+	ACR string `bson:"acr" json:"acr,omitempty" xml:"acr,omitempty"`
+
+	// Remember reports whether the user asked to be remembered, in which
+	// case the session should outlive the browser session.
+	Remember bool `bson:"remember" json:"remember" xml:"remember"`
+
+	// ExpiresAt is when the login session expires in seconds from the epoch.
+	ExpiresAt int64 `bson:"expiresAt" json:"expiresAt" xml:"expiresAt"`
+}
+
+// IsExpired returns whether the login session has expired at the given time.
+func (s LoginSession) IsExpired(now time.Time) bool {
+	return s.ExpiresAt <= now.Unix()
+}
+
+// AuthenticatedWithin returns whether the user authenticated within the
+// maximum authentication age, as requested via OpenID Connect's `max_age`.
+func (s LoginSession) AuthenticatedWithin(maxAge time.Duration, now time.Time) bool {
+	return now.Sub(time.Unix(s.AuthTime, 0)) <= maxAge
+}
+
+// Validate returns an invalid argument error if the login session is not
+// valid.
+func (s LoginSession) Validate() error {
+	if s.UserID == "" {
+		return NewInvalidArgumentError(EntityLoginSessions, "user id is required", "userId")
+	}
+	if s.AuthTime == 0 {
+		return NewInvalidArgumentError(EntityLoginSessions, "auth time is required", "authTime")
+	}
+	if s.ExpiresAt == 0 {
+		return NewInvalidArgumentError(EntityLoginSessions, "expiry is required", "expiresAt")
+	}
+
+	return nil
+}
@@ -0,0 +1,45 @@
+package storage
+
+import (
+	// Standard Library Imports
+	"context"
+)
+
+// LoginSessionManager provides a generic interface to login sessions in order
+// to build a Datastore backend.
+type LoginSessionManager interface {
+	Configurer
+	LoginSessionStorer
+}
+
+// LoginSessionStorer provides a definition of specific methods that are
+// required to store a LoginSession in a data store.
+//
+// Methods accepting a session ID expect the raw session ID, as held by the
+// user's browser, which is hashed via HashLoginSessionID before use.
+type LoginSessionStorer interface {
+	List(ctx context.Context, filter ListLoginSessionsRequest) ([]LoginSession, error)
+	Create(ctx context.Context, sessionID string, session LoginSession) (LoginSession, error)
+
+	// Get returns the login session, or not found if the session has
+	// expired.
+	Get(ctx context.Context, sessionID string) (LoginSession, error)
+
+	// Extend extends the unexpired login session until the given expiry.
+	Extend(ctx context.Context, sessionID string, expiresAt int64) (LoginSession, error)
+
+	// Delete logs out of the login session.
+	Delete(ctx context.Context, sessionID string) error
+
+	// DeleteByUser logs the user out of every login session, returning the
+	// number of sessions logged out.
+	DeleteByUser(ctx context.Context, userID string) (int64, error)
+}
+
+// ListLoginSessionsRequest enables filtering stored LoginSession entities.
+type ListLoginSessionsRequest struct {
+	// UserID filters login sessions based on User ID.
+	UserID string `json:"userId" xml:"userId"`
+	// IncludeExpired includes expired login sessions.
+	IncludeExpired bool `json:"includeExpired" xml:"includeExpired"`
+}
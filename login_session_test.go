@@ -0,0 +1,92 @@
+package storage_test
+
+import (
+	// Standard Library Imports
+	"errors"
+	"testing"
+	"time"
+
+	// Internal Imports
+	"github.com/matthewhartstonge/storage"
+)
+
+func TestHashLoginSessionID(t *testing.T) {
+	hash := storage.HashLoginSessionID("c2Vzc2lvbg")
+	if hash == "c2Vzc2lvbg" {
+		t.Error("expected the login session id to be hashed")
+	}
+	if len(hash) != 64 {
+		t.Errorf("expected a hex encoded sha256 hash, got %q", hash)
+	}
+	if hash != storage.HashLoginSessionID("c2Vzc2lvbg") {
+		t.Error("expected hashing to be deterministic")
+	}
+	if hash == storage.HashLoginSessionID("b3RoZXI") {
+		t.Error("expected different session ids to hash differently")
+	}
+}
+
+func TestLoginSession_IsExpired(t *testing.T) {
+	now := time.Now()
+
+	if (storage.LoginSession{ExpiresAt: now.Add(time.Hour).Unix()}).IsExpired(now) {
+		t.Error("expected login session to be active")
+	}
+	if !(storage.LoginSession{ExpiresAt: now.Unix()}).IsExpired(now) {
+		t.Error("expected login session to have expired")
+	}
+}
+
+func TestLoginSession_AuthenticatedWithin(t *testing.T) {
+	now := time.Now()
+	session := storage.LoginSession{AuthTime: now.Add(-10 * time.Minute).Unix()}
+
+	if !session.AuthenticatedWithin(time.Hour, now) {
+		t.Error("expected user to have authenticated within the max age")
+	}
+	if session.AuthenticatedWithin(time.Minute, now) {
+		t.Error("expected user to require re-authentication")
+	}
+}
+
+func TestLoginSession_Validate(t *testing.T) {
+	now := time.Now().Unix()
+	tests := []struct {
+		name    string
+		session storage.LoginSession
+		valid   bool
+	}{
+		{
+			name:    "valid",
+			session: storage.LoginSession{UserID: "1", AuthTime: now, ExpiresAt: now + 60},
+			valid:   true,
+		},
+		{
+			name:    "missing user",
+			session: storage.LoginSession{AuthTime: now, ExpiresAt: now + 60},
+		},
+		{
+			name:    "missing auth time",
+			session: storage.LoginSession{UserID: "1", ExpiresAt: now + 60},
+		},
+		{
+			name:    "missing expiry",
+			session: storage.LoginSession{UserID: "1", AuthTime: now},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.session.Validate()
+			if tt.valid {
+				if err != nil {
+					t.Errorf("expected no error, got %v", err)
+				}
+				return
+			}
+			if !errors.Is(err, storage.ErrInvalidArgument) {
+				t.Errorf("expected invalid argument error, got %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,24 @@
+package storage
+
+// Migration provides the state of a versioned schema migration.
+type Migration struct {
+	// Version orders migrations. Migrations are applied in ascending version
+	// order.
+	Version int `bson:"version" json:"version" xml:"version"`
+
+	// Name uniquely identifies the migration.
+	Name string `bson:"id" json:"name" xml:"name"`
+
+	// Description provides a human readable explanation of what the
+	// migration changes.
+	Description string `bson:"description" json:"description,omitempty" xml:"description,omitempty"`
+
+	// AppliedAt is when the migration was applied in seconds from the epoch.
+	// AppliedAt is zero if the migration is pending.
+	AppliedAt int64 `bson:"appliedAt" json:"appliedAt,omitempty" xml:"appliedAt,omitempty"`
+}
+
+// IsApplied returns whether the migration has been applied.
+func (m Migration) IsApplied() bool {
+	return m.AppliedAt != 0
+}
@@ -0,0 +1,38 @@
+package storage
+
+import (
+	// Standard Library Imports
+	"context"
+)
+
+// MigrationManager provides a generic interface to versioned schema
+// migrations in order to evolve the shape of stored documents over time.
+//
+// Configure is expected to apply any pending migrations, or if running in a
+// dry-run mode, report the pending migrations without applying them.
+type MigrationManager interface {
+	Configurer
+	MigrationStorer
+}
+
+// MigrationStorer enables inspecting and applying schema migrations.
+//
+// Implementations must ensure migrations are applied in version order, are
+// only applied once, and that concurrent service starts don't race to apply
+// the same migration.
+type MigrationStorer interface {
+	// List returns all known migrations, applied or not, in version order.
+	List(ctx context.Context) ([]Migration, error)
+
+	// Pending returns the migrations that have not yet been applied, in
+	// version order.
+	Pending(ctx context.Context) ([]Migration, error)
+
+	// UpToDate returns true if there are no pending migrations.
+	UpToDate(ctx context.Context) (bool, error)
+
+	// Migrate applies any pending migrations, returning the migrations
+	// that were applied. If dryRun is true, pending migrations are returned
+	// without being applied.
+	Migrate(ctx context.Context, dryRun bool) ([]Migration, error)
+}
@@ -0,0 +1,162 @@
+package mongo_test
+
+import (
+	// Standard Library Imports
+	"context"
+	"testing"
+	"time"
+
+	// External Imports
+	"github.com/google/uuid"
+	"github.com/ory/fosite"
+
+	// Internal Imports
+	"github.com/matthewhartstonge/storage"
+	"github.com/matthewhartstonge/storage/mongo"
+)
+
+// createActiveSession issues an access token, and if refreshExpiresAt is
+// set, a refresh token, under a new originating request, returning the
+// request ID.
+func createActiveSession(ctx context.Context, t *testing.T, store *mongo.Store, client storage.Client, subject string, accessExpiresAt time.Time, refreshExpiresAt time.Time) string {
+	session := &fosite.DefaultSession{
+		Subject: subject,
+		ExpiresAt: map[fosite.TokenType]time.Time{
+			fosite.AccessToken: accessExpiresAt,
+		},
+	}
+	if !refreshExpiresAt.IsZero() {
+		session.ExpiresAt[fosite.RefreshToken] = refreshExpiresAt
+	}
+
+	request := fosite.NewRequest()
+	request.ID = uuid.NewString()
+	request.Client = &client
+	request.Session = session
+	request.GrantedScope = fosite.Arguments{"urn:test:cats:read"}
+
+	err := store.RequestManager.CreateAccessTokenSession(ctx, uuid.NewString(), request)
+	if err != nil {
+		AssertFatal(t, err, nil, "create access token should return no database errors")
+	}
+
+	if !refreshExpiresAt.IsZero() {
+		err = store.RequestManager.CreateRefreshTokenSession(ctx, uuid.NewString(), request)
+		if err != nil {
+			AssertFatal(t, err, nil, "create refresh token should return no database errors")
+		}
+	}
+
+	return request.ID
+}
+
+func TestListActiveSessions(t *testing.T) {
+	store, ctx, teardown := setup(t)
+	defer teardown()
+
+	client := createClient(ctx, t, store)
+	subject := uuid.NewString()
+	now := time.Now()
+
+	refreshExpiresAt := now.Add(24 * time.Hour)
+	active := createActiveSession(ctx, t, store, client, subject, now.Add(time.Hour), refreshExpiresAt)
+
+	// The access token has expired, and there is no refresh token, so the
+	// session has expired, but is yet to be cleaned up.
+	createActiveSession(ctx, t, store, client, subject, now.Add(-time.Minute), time.Time{})
+
+	// Sessions of other users are filtered out.
+	createActiveSession(ctx, t, store, client, uuid.NewString(), now.Add(time.Hour), time.Time{})
+
+	sessions, err := storage.ListActiveSessions(ctx, store.RequestManager, store.ClientManager, storage.ListActiveSessionsRequest{
+		UserID: subject,
+	})
+	if err != nil {
+		AssertFatal(t, err, nil, "list should return no database errors")
+	}
+	if len(sessions) != 1 {
+		AssertFatal(t, len(sessions), 1, "only the unexpired session should be listed")
+	}
+
+	session := sessions[0]
+	if session.ID != active {
+		AssertError(t, session.ID, active, "session should be identified by the originating request")
+	}
+	if session.ClientID != client.ID || session.ClientName != client.Name {
+		AssertError(t, session.ClientName, client.Name, "session should name the client")
+	}
+	if !session.HasAccessToken || !session.HasRefreshToken {
+		AssertError(t, session, "access and refresh token", "session should hold an access and refresh token")
+	}
+	if !session.ExpiresAt.Equal(refreshExpiresAt) {
+		AssertError(t, session.ExpiresAt, refreshExpiresAt, "session should expire with the refresh token")
+	}
+}
+
+func TestListActiveSessions_ShouldIncludeRefreshableSessions(t *testing.T) {
+	store, ctx, teardown := setup(t)
+	defer teardown()
+
+	client := createClient(ctx, t, store)
+	subject := uuid.NewString()
+	now := time.Now()
+
+	// The access token has expired, but the session can still be refreshed.
+	expected := createActiveSession(ctx, t, store, client, subject, now.Add(-time.Minute), now.Add(time.Hour))
+
+	sessions, err := storage.ListActiveSessions(ctx, store.RequestManager, store.ClientManager, storage.ListActiveSessionsRequest{
+		UserID: subject,
+	})
+	if err != nil {
+		AssertFatal(t, err, nil, "list should return no database errors")
+	}
+	if len(sessions) != 1 || sessions[0].ID != expected {
+		AssertError(t, sessions, expected, "refreshable session should be listed")
+	}
+}
+
+func TestRevokeActiveSessions(t *testing.T) {
+	store, ctx, teardown := setup(t)
+	defer teardown()
+
+	client := createClient(ctx, t, store)
+	subject := uuid.NewString()
+	now := time.Now()
+
+	current := createActiveSession(ctx, t, store, client, subject, now.Add(time.Hour), now.Add(24*time.Hour))
+	createActiveSession(ctx, t, store, client, subject, now.Add(time.Hour), now.Add(24*time.Hour))
+	createActiveSession(ctx, t, store, client, subject, now.Add(time.Hour), time.Time{})
+
+	revoked, err := storage.RevokeActiveSessions(ctx, store.RequestManager, storage.RevokeActiveSessionsRequest{
+		UserID:   subject,
+		ExceptID: current,
+	})
+	if err != nil {
+		AssertFatal(t, err, nil, "revoke should return no database errors")
+	}
+	if revoked != 2 {
+		AssertError(t, revoked, 2, "every session except the current session should be revoked")
+	}
+
+	sessions, err := storage.ListActiveSessions(ctx, store.RequestManager, nil, storage.ListActiveSessionsRequest{
+		UserID: subject,
+	})
+	if err != nil {
+		AssertFatal(t, err, nil, "list should return no database errors")
+	}
+	if len(sessions) != 1 || sessions[0].ID != current {
+		AssertError(t, sessions, current, "only the current session should remain")
+	}
+
+	for _, entityName := range []string{storage.EntityAccessTokens, storage.EntityRefreshTokens} {
+		tokens, err := store.RequestManager.List(ctx, entityName, storage.ListRequestsRequest{UserID: subject})
+		if err != nil {
+			AssertFatal(t, err, nil, "list should return no database errors")
+		}
+		for _, token := range tokens {
+			if token.ID != current {
+				AssertError(t, token.ID, current, "revoked session tokens should be deleted from "+entityName)
+			}
+		}
+	}
+}
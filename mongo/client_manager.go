@@ -3,12 +3,12 @@ package mongo
 import (
 	// Standard Library imports
 	"context"
+	"errors"
 	"time"
 
 	// External Imports
 	"github.com/google/uuid"
 	"github.com/ory/fosite"
-	"github.com/sirupsen/logrus"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
@@ -28,13 +28,31 @@ import (
 type ClientManager struct {
 	DB     *DB
 	Hasher fosite.Hasher
+	Logger Logger
 
 	DeniedJTIs storage.DeniedJTIStorer
+
+	// Tenants, if set, validates the tenants referenced by the client's
+	// AllowedTenantAccess exist on create and update.
+	Tenants storage.TenantStorer
+
+	// Scopes, if set, enables strict scopes, validating the scopes granted
+	// to the client are registered on create, update and GrantScopes.
+	Scopes storage.ScopeStorer
+
+	// Region provides the region the store is serving. If set, or if a region
+	// is bound to the context via storage.RegionToContext, GetClient and
+	// Authenticate deny clients whose AllowedRegions exclude it.
+	Region string
+
+	// HashWorkers provides the number of workers to hash secrets with in
+	// bulk operations. If zero, a worker is started per CPU.
+	HashWorkers int
 }
 
 // Configure sets up the Mongo collection for OAuth 2.0 client resources.
 func (c *ClientManager) Configure(ctx context.Context) (err error) {
-	log := logger.WithFields(logrus.Fields{
+	log := newLogger(ctx, c.Logger, Fields{
 		"package":    "mongo",
 		"collection": storage.EntityClients,
 		"method":     "Configure",
@@ -57,11 +75,10 @@ func (c *ClientManager) Configure(ctx context.Context) (err error) {
 		},
 	}
 
-	collection := c.DB.Collection(storage.EntityClients)
-	_, err = collection.Indexes().CreateMany(ctx, indices)
+	err = c.DB.createIndexes(ctx, storage.EntityClients, indices)
 	if err != nil {
 		log.WithError(err).Error(logError)
-		return err
+		return toStorageError(storage.EntityClients, err)
 	}
 
 	return nil
@@ -69,7 +86,7 @@ func (c *ClientManager) Configure(ctx context.Context) (err error) {
 
 // getConcrete returns an OAuth 2.0 Client resource.
 func (c *ClientManager) getConcrete(ctx context.Context, clientID string) (result storage.Client, err error) {
-	log := logger.WithFields(logrus.Fields{
+	log := newLogger(ctx, c.Logger, Fields{
 		"package":    "mongo",
 		"collection": storage.EntityClients,
 		"method":     "getConcrete",
@@ -82,10 +99,12 @@ func (c *ClientManager) getConcrete(ctx context.Context, clientID string) (resul
 	}
 
 	// Trace how long the Mongo operation takes to complete.
-	span, _ := traceMongoCall(ctx, dbTrace{
-		Manager: "ClientManager",
-		Method:  "getConcrete",
-		Query:   query,
+	span, ctx := c.DB.traceMongoCall(ctx, DBTrace{
+		Manager:    "ClientManager",
+		Method:     "getConcrete",
+		Collection: storage.EntityClients,
+		Operation:  "find",
+		Query:      query,
 	})
 	defer span.Finish()
 
@@ -95,14 +114,14 @@ func (c *ClientManager) getConcrete(ctx context.Context, clientID string) (resul
 	if err != nil {
 		if err == mongo.ErrNoDocuments {
 			log.WithError(err).Debug(logNotFound)
-			return result, fosite.ErrNotFound
+			return result, storage.NewNotFoundError(storage.EntityClients)
 		}
 
 		// Log to StdOut
 		log.WithError(err).Error(logError)
-		// Log to OpenTracing
-		otLogErr(span, err)
-		return result, err
+		// Log to Tracer
+		span.RecordError(err)
+		return result, toStorageError(storage.EntityClients, err)
 	}
 
 	return storageClient, nil
@@ -111,13 +130,110 @@ func (c *ClientManager) getConcrete(ctx context.Context, clientID string) (resul
 // List filters resources to return a list of OAuth 2.0 client resources.
 func (c *ClientManager) List(ctx context.Context, filter storage.ListClientsRequest) (results []storage.Client, err error) {
 	// Initialize contextual method logger
-	log := logger.WithFields(logrus.Fields{
+	log := newLogger(ctx, c.Logger, Fields{
 		"package":    "mongo",
 		"collection": storage.EntityClients,
 		"method":     "List",
 	})
 
 	// Build Query
+	query := listClientsQuery(filter)
+
+	// Trace how long the Mongo operation takes to complete.
+	span, ctx := c.DB.traceMongoCall(ctx, DBTrace{
+		Manager:    "ClientManager",
+		Method:     "List",
+		Collection: storage.EntityClients,
+		Operation:  "find",
+		Query:      query,
+	})
+	defer span.Finish()
+
+	collection := c.DB.Collection(storage.EntityClients)
+	cursor, err := collection.Find(ctx, query)
+	if err != nil {
+		// Log to StdOut
+		log.WithError(err).Error(logError)
+		// Log to Tracer
+		span.RecordError(err)
+		return results, toStorageError(storage.EntityClients, err)
+	}
+
+	var clients []storage.Client
+	err = cursor.All(ctx, &clients)
+	if err != nil {
+		// Log to StdOut
+		log.WithError(err).Error(logError)
+		// Log to Tracer
+		span.RecordError(err)
+		return results, toStorageError(storage.EntityClients, err)
+	}
+
+	return clients, nil
+}
+
+// Each calls fn with each client matching the filter, decoding clients from the
+// cursor one at a time rather than reading them all into memory. Each stops
+// at, and returns, the first error returned by fn.
+func (c *ClientManager) Each(ctx context.Context, filter storage.ListClientsRequest, fn func(client storage.Client) error) (err error) {
+	// Initialize contextual method logger
+	log := newLogger(ctx, c.Logger, Fields{
+		"package":    "mongo",
+		"collection": storage.EntityClients,
+		"method":     "Each",
+	})
+
+	// Build Query
+	query := listClientsQuery(filter)
+
+	// Trace how long the Mongo operation takes to complete.
+	span, ctx := c.DB.traceMongoCall(ctx, DBTrace{
+		Manager:    "ClientManager",
+		Method:     "Each",
+		Collection: storage.EntityClients,
+		Operation:  "find",
+		Query:      query,
+	})
+	defer span.Finish()
+
+	collection := c.DB.Collection(storage.EntityClients)
+	cursor, err := collection.Find(ctx, query)
+	if err != nil {
+		// Log to StdOut
+		log.WithError(err).Error(logError)
+		// Log to Tracer
+		span.RecordError(err)
+		return toStorageError(storage.EntityClients, err)
+	}
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		var client storage.Client
+		if err := cursor.Decode(&client); err != nil {
+			// Log to StdOut
+			log.WithError(err).Error(logError)
+			// Log to Tracer
+			span.RecordError(err)
+			return toStorageError(storage.EntityClients, err)
+		}
+
+		if err := fn(client); err != nil {
+			return err
+		}
+	}
+	if err := cursor.Err(); err != nil {
+		// Log to StdOut
+		log.WithError(err).Error(logError)
+		// Log to Tracer
+		span.RecordError(err)
+		return toStorageError(storage.EntityClients, err)
+	}
+
+	return nil
+}
+
+// listClientsQuery returns the query matching the clients that match the provided inputs.
+func listClientsQuery(filter storage.ListClientsRequest) bson.M {
 	query := bson.M{}
 	if filter.AllowedTenantAccess != "" {
 		query["allowedTenantAccess"] = filter.AllowedTenantAccess
@@ -153,41 +269,13 @@ func (c *ClientManager) List(ctx context.Context, filter storage.ListClientsRequ
 		query["published"] = filter.Published
 	}
 
-	// Trace how long the Mongo operation takes to complete.
-	span, _ := traceMongoCall(ctx, dbTrace{
-		Manager: "ClientManager",
-		Method:  "List",
-		Query:   query,
-	})
-	defer span.Finish()
-
-	collection := c.DB.Collection(storage.EntityClients)
-	cursor, err := collection.Find(ctx, query)
-	if err != nil {
-		// Log to StdOut
-		log.WithError(err).Error(logError)
-		// Log to OpenTracing
-		otLogErr(span, err)
-		return results, err
-	}
-
-	var clients []storage.Client
-	err = cursor.All(ctx, &clients)
-	if err != nil {
-		// Log to StdOut
-		log.WithError(err).Error(logError)
-		// Log to OpenTracing
-		otLogErr(span, err)
-		return results, err
-	}
-
-	return clients, nil
+	return query
 }
 
 // Create stores a new OAuth2.0 Client resource.
 func (c *ClientManager) Create(ctx context.Context, client storage.Client) (result storage.Client, err error) {
 	// Initialize contextual method logger
-	log := logger.WithFields(logrus.Fields{
+	log := newLogger(ctx, c.Logger, Fields{
 		"package":    "mongo",
 		"collection": storage.EntityClients,
 		"method":     "Create",
@@ -201,6 +289,18 @@ func (c *ClientManager) Create(ctx context.Context, client storage.Client) (resu
 		client.CreateTime = time.Now().Unix()
 	}
 
+	err = storage.ValidateTenantReferences(ctx, c.Tenants, storage.EntityClients, client.AllowedTenantAccess)
+	if err != nil {
+		log.WithError(err).Debug(logInvalid)
+		return result, err
+	}
+
+	err = storage.ValidateScopes(ctx, c.Scopes, storage.EntityClients, client.Scopes)
+	if err != nil {
+		log.WithError(err).Debug(logInvalid)
+		return result, err
+	}
+
 	// Hash incoming secret
 	hash, err := c.Hasher.Hash(ctx, []byte(client.Secret))
 	if err != nil {
@@ -210,9 +310,11 @@ func (c *ClientManager) Create(ctx context.Context, client storage.Client) (resu
 	client.Secret = string(hash)
 
 	// Trace how long the Mongo operation takes to complete.
-	span, _ := traceMongoCall(ctx, dbTrace{
-		Manager: "ClientManager",
-		Method:  "Create",
+	span, ctx := c.DB.traceMongoCall(ctx, DBTrace{
+		Manager:    "ClientManager",
+		Method:     "Create",
+		Collection: storage.EntityClients,
+		Operation:  "insert",
 	})
 	defer span.Finish()
 
@@ -223,18 +325,18 @@ func (c *ClientManager) Create(ctx context.Context, client storage.Client) (resu
 		if isDup(err) {
 			// Log to StdOut
 			log.WithError(err).Debug(logConflict)
-			// Log to OpenTracing
-			otLogErr(span, err)
-			return result, storage.ErrResourceExists
+			// Log to Tracer
+			span.RecordError(err)
+			return result, toStorageError(storage.EntityClients, err)
 		}
 
 		// Log to StdOut
 		log.WithError(err).Error(logError)
-		// Log to OpenTracing
+		// Log to Tracer
 		client.Secret = "REDACTED"
-		otLogQuery(span, client)
-		otLogErr(span, err)
-		return result, err
+		span.SetQuery(client)
+		span.RecordError(err)
+		return result, toStorageError(storage.EntityClients, err)
 	}
 
 	return client, nil
@@ -255,14 +357,38 @@ func (c *ClientManager) GetClient(ctx context.Context, clientID string) (fosite.
 	if err != nil {
 		return nil, err
 	}
+
+	if err = c.allowedRegion(ctx, client); err != nil {
+		return nil, err
+	}
+
 	return &client, nil
 }
 
+// allowedRegion returns not found if the client is not permitted to operate
+// in the serving region, as if the client didn't exist.
+func (c *ClientManager) allowedRegion(ctx context.Context, client storage.Client) error {
+	region := storage.ServingRegion(ctx, c.Region)
+	if region == "" || client.IsRegionAllowed(region) {
+		return nil
+	}
+
+	newLogger(ctx, c.Logger, Fields{
+		"package":    "mongo",
+		"collection": storage.EntityClients,
+		"method":     "allowedRegion",
+		"id":         client.ID,
+		"region":     region,
+	}).Debug("client denied access in region")
+
+	return storage.NewNotFoundError(storage.EntityClients)
+}
+
 // ClientAssertionJWTValid returns an error if the JTI is known or the DB check
 // failed and nil if the JTI is not known.
 func (c *ClientManager) ClientAssertionJWTValid(ctx context.Context, jti string) error {
 	// Initialize contextual method logger
-	log := logger.WithFields(logrus.Fields{
+	log := newLogger(ctx, c.Logger, Fields{
 		"package":    "mongo",
 		"collection": storage.EntityJtiDenylist,
 		"method":     "ClientAssertionJWTValid",
@@ -271,32 +397,31 @@ func (c *ClientManager) ClientAssertionJWTValid(ctx context.Context, jti string)
 
 	deniedJti, err := c.DeniedJTIs.Get(ctx, jti)
 	if err != nil {
-		switch err {
-		case fosite.ErrNotFound:
+		if errors.Is(err, storage.ErrNotFound) {
 			// the jti is not known => valid
 			return nil
-
-		default:
-			// Unknown error...
-			log.WithError(err).Debug("error asserting jwt validity")
-			return err
 		}
+
+		// Unknown error...
+		log.WithError(err).Debug("error asserting jwt validity")
+		return err
 	}
 
 	if time.Unix(deniedJti.Expiry, 0).After(time.Now()) {
 		// the jti is not expired yet => invalid
-		return fosite.ErrJTIKnown
+		return storage.NewError(storage.ErrResourceExists, storage.EntityJtiDenylist, fosite.ErrJTIKnown)
 	}
 
 	return nil
 }
 
 // SetClientAssertionJWT marks a JTI as known for the given expiry time.
-// Before inserting the new JTI, it will clean up any existing JTIs that have
-// expired as those tokens can not be replayed due to the expiry.
+// If the JTI is already known, but has expired, it is replaced, as expired
+// tokens can not be replayed. Other expired JTIs are cleaned up in the
+// background by the Janitor.
 func (c *ClientManager) SetClientAssertionJWT(ctx context.Context, jti string, exp time.Time) (err error) {
 	// Initialize contextual method logger
-	log := logger.WithFields(logrus.Fields{
+	log := newLogger(ctx, c.Logger, Fields{
 		"package":    "mongo",
 		"collection": storage.EntityJtiDenylist,
 		"method":     "SetClientAssertionJWT",
@@ -315,27 +440,30 @@ func (c *ClientManager) SetClientAssertionJWT(ctx context.Context, jti string, e
 		defer closeSession()
 	}
 
-	// delete expired JTIs
-	err = c.DeniedJTIs.DeleteBefore(ctx, time.Now().Unix())
-	if err != nil {
-		switch err {
-		case fosite.ErrNotFound:
-			// we don't care!
-			log.WithError(err).Debug("expired tokens not found, none removed")
+	_, err = c.DeniedJTIs.Create(ctx, storage.NewDeniedJTI(jti, exp))
+	if err != nil && errors.Is(err, storage.ErrResourceExists) {
+		// The JTI is known, but can be replaced if it has expired and is
+		// yet to be cleaned up.
+		if err = c.ClientAssertionJWTValid(ctx, jti); err != nil {
+			return err
 		}
-	}
 
-	_, err = c.DeniedJTIs.Create(ctx, storage.NewDeniedJTI(jti, exp))
+		err = c.DeniedJTIs.Delete(ctx, jti)
+		if err != nil && !errors.Is(err, storage.ErrNotFound) {
+			log.WithError(err).Error("error removing expired denied jti")
+			return err
+		}
+
+		_, err = c.DeniedJTIs.Create(ctx, storage.NewDeniedJTI(jti, exp))
+	}
 	if err != nil {
-		switch err {
-		case storage.ErrResourceExists:
+		if errors.Is(err, storage.ErrResourceExists) {
 			// found a DeniedJTIs
-			return fosite.ErrJTIKnown
-
-		default:
-			log.WithError(err).Error("error creating denied jti")
-			return err
+			return storage.NewError(storage.ErrResourceExists, storage.EntityJtiDenylist, fosite.ErrJTIKnown)
 		}
+
+		log.WithError(err).Error("error creating denied jti")
+		return err
 	}
 
 	return nil
@@ -344,7 +472,7 @@ func (c *ClientManager) SetClientAssertionJWT(ctx context.Context, jti string, e
 // Update updates an OAuth 2.0 client resource.
 func (c *ClientManager) Update(ctx context.Context, clientID string, updatedClient storage.Client) (result storage.Client, err error) {
 	// Initialize contextual method logger
-	log := logger.WithFields(logrus.Fields{
+	log := newLogger(ctx, c.Logger, Fields{
 		"package":    "mongo",
 		"collection": storage.EntityClients,
 		"method":     "Update",
@@ -365,7 +493,7 @@ func (c *ClientManager) Update(ctx context.Context, clientID string, updatedClie
 
 	currentResource, err := c.getConcrete(ctx, clientID)
 	if err != nil {
-		if err == fosite.ErrNotFound {
+		if errors.Is(err, storage.ErrNotFound) {
 			log.Debug(logNotFound)
 			return result, err
 		}
@@ -379,6 +507,23 @@ func (c *ClientManager) Update(ctx context.Context, clientID string, updatedClie
 	// Update modified time
 	updatedClient.UpdateTime = time.Now().Unix()
 
+	// Only validate newly referenced tenants, so that clients and users
+	// referencing tenants created before tenants were tracked can be updated.
+	addedTenants := difference(updatedClient.AllowedTenantAccess, currentResource.AllowedTenantAccess)
+	err = storage.ValidateTenantReferences(ctx, c.Tenants, storage.EntityClients, addedTenants)
+	if err != nil {
+		log.WithError(err).Debug(logInvalid)
+		return result, err
+	}
+
+	// Likewise, only validate newly granted scopes.
+	addedScopes := difference(updatedClient.Scopes, currentResource.Scopes)
+	err = storage.ValidateScopes(ctx, c.Scopes, storage.EntityClients, addedScopes)
+	if err != nil {
+		log.WithError(err).Debug(logInvalid)
+		return result, err
+	}
+
 	if currentResource.Secret == updatedClient.Secret || updatedClient.Secret == "" {
 		// If the password/hash is blank or hash matches, set using old hash.
 		updatedClient.Secret = currentResource.Secret
@@ -397,10 +542,12 @@ func (c *ClientManager) Update(ctx context.Context, clientID string, updatedClie
 	}
 
 	// Trace how long the Mongo operation takes to complete.
-	span, _ := traceMongoCall(ctx, dbTrace{
-		Manager:  "ClientManager",
-		Method:   "Update",
-		Selector: selector,
+	span, ctx := c.DB.traceMongoCall(ctx, DBTrace{
+		Manager:    "ClientManager",
+		Method:     "Update",
+		Collection: storage.EntityClients,
+		Operation:  "update",
+		Selector:   selector,
 	})
 	defer span.Finish()
 
@@ -410,25 +557,25 @@ func (c *ClientManager) Update(ctx context.Context, clientID string, updatedClie
 		if isDup(err) {
 			// Log to StdOut
 			log.WithError(err).Debug(logConflict)
-			// Log to OpenTracing
-			otLogErr(span, err)
-			return result, storage.ErrResourceExists
+			// Log to Tracer
+			span.RecordError(err)
+			return result, toStorageError(storage.EntityClients, err)
 		}
 
 		// Log to StdOut
 		log.WithError(err).Error(logError)
-		// Log to OpenTracing
-		otLogQuery(span, updatedClient)
-		otLogErr(span, err)
-		return result, err
+		// Log to Tracer
+		span.SetQuery(updatedClient)
+		span.RecordError(err)
+		return result, toStorageError(storage.EntityClients, err)
 	}
 
 	if res.MatchedCount == 0 {
 		// Log to StdOut
 		log.WithError(err).Debug(logNotFound)
-		// Log to OpenTracing
-		otLogErr(span, err)
-		return result, fosite.ErrNotFound
+		// Log to Tracer
+		span.RecordError(err)
+		return result, storage.NewNotFoundError(storage.EntityClients)
 	}
 
 	return updatedClient, nil
@@ -440,7 +587,7 @@ func (c *ClientManager) Update(ctx context.Context, clientID string, updatedClie
 // newly provided full record. Use with caution, be secure, don't be dumb.
 func (c *ClientManager) Migrate(ctx context.Context, migratedClient storage.Client) (result storage.Client, err error) {
 	// Initialize contextual method logger
-	log := logger.WithFields(logrus.Fields{
+	log := newLogger(ctx, c.Logger, Fields{
 		"package":    "mongo",
 		"collection": storage.EntityClients,
 		"method":     "Migrate",
@@ -464,10 +611,12 @@ func (c *ClientManager) Migrate(ctx context.Context, migratedClient storage.Clie
 	}
 
 	// Trace how long the Mongo operation takes to complete.
-	span, _ := traceMongoCall(ctx, dbTrace{
-		Manager:  "ClientManager",
-		Method:   "Migrate",
-		Selector: selector,
+	span, ctx := c.DB.traceMongoCall(ctx, DBTrace{
+		Manager:    "ClientManager",
+		Method:     "Migrate",
+		Collection: storage.EntityClients,
+		Operation:  "update",
+		Selector:   selector,
 	})
 	defer span.Finish()
 
@@ -478,25 +627,25 @@ func (c *ClientManager) Migrate(ctx context.Context, migratedClient storage.Clie
 		if isDup(err) {
 			// Log to StdOut
 			log.WithError(err).Debug(logConflict)
-			// Log to OpenTracing
-			otLogErr(span, err)
-			return result, storage.ErrResourceExists
+			// Log to Tracer
+			span.RecordError(err)
+			return result, toStorageError(storage.EntityClients, err)
 		}
 
 		// Log to StdOut
 		log.WithError(err).Error(logError)
-		// Log to OpenTracing
-		otLogQuery(span, migratedClient)
-		otLogErr(span, err)
-		return result, err
+		// Log to Tracer
+		span.SetQuery(migratedClient)
+		span.RecordError(err)
+		return result, toStorageError(storage.EntityClients, err)
 	}
 
-	if res.MatchedCount == 0 {
+	if res.MatchedCount == 0 && res.UpsertedCount == 0 {
 		// Log to StdOut
 		log.WithError(err).Debug(logNotFound)
-		// Log to OpenTracing
-		otLogErr(span, err)
-		return result, fosite.ErrNotFound
+		// Log to Tracer
+		span.RecordError(err)
+		return result, storage.NewNotFoundError(storage.EntityClients)
 	}
 
 	return migratedClient, nil
@@ -505,7 +654,7 @@ func (c *ClientManager) Migrate(ctx context.Context, migratedClient storage.Clie
 // Delete removes an OAuth 2.0 Client resource.
 func (c *ClientManager) Delete(ctx context.Context, clientID string) (err error) {
 	// Initialize contextual method logger
-	log := logger.WithFields(logrus.Fields{
+	log := newLogger(ctx, c.Logger, Fields{
 		"package":    "mongo",
 		"collection": storage.EntityClients,
 		"method":     "Delete",
@@ -518,10 +667,12 @@ func (c *ClientManager) Delete(ctx context.Context, clientID string) (err error)
 	}
 
 	// Trace how long the Mongo operation takes to complete.
-	span, _ := traceMongoCall(ctx, dbTrace{
-		Manager: "ClientManager",
-		Method:  "Delete",
-		Query:   query,
+	span, ctx := c.DB.traceMongoCall(ctx, DBTrace{
+		Manager:    "ClientManager",
+		Method:     "Delete",
+		Collection: storage.EntityClients,
+		Operation:  "delete",
+		Query:      query,
 	})
 	defer span.Finish()
 
@@ -530,17 +681,17 @@ func (c *ClientManager) Delete(ctx context.Context, clientID string) (err error)
 	if err != nil {
 		// Log to StdOut
 		log.WithError(err).Error(logError)
-		// Log to OpenTracing
-		otLogErr(span, err)
-		return err
+		// Log to Tracer
+		span.RecordError(err)
+		return toStorageError(storage.EntityClients, err)
 	}
 
 	if res.DeletedCount == 0 {
 		// Log to StdOut
 		log.WithError(err).Debug(logNotFound)
-		// Log to OpenTracing
-		otLogErr(span, err)
-		return fosite.ErrNotFound
+		// Log to Tracer
+		span.RecordError(err)
+		return storage.NewNotFoundError(storage.EntityClients)
 	}
 
 	return nil
@@ -549,7 +700,7 @@ func (c *ClientManager) Delete(ctx context.Context, clientID string) (err error)
 // Authenticate verifies the identity of a client resource.
 func (c *ClientManager) Authenticate(ctx context.Context, clientID string, secret string) (result storage.Client, err error) {
 	// Initialize contextual method logger
-	log := logger.WithFields(logrus.Fields{
+	log := newLogger(ctx, c.Logger, Fields{
 		"package":    "mongo",
 		"collection": storage.EntityClients,
 		"method":     "Authenticate",
@@ -557,15 +708,16 @@ func (c *ClientManager) Authenticate(ctx context.Context, clientID string, secre
 	})
 
 	// Trace how long the Mongo operation takes to complete.
-	span, ctx := traceMongoCall(ctx, dbTrace{
-		Manager: "ClientManager",
-		Method:  "Authenticate",
+	span, ctx := c.DB.traceMongoCall(ctx, DBTrace{
+		Manager:    "ClientManager",
+		Method:     "Authenticate",
+		Collection: storage.EntityClients,
 	})
 	defer span.Finish()
 
 	client, err := c.getConcrete(ctx, clientID)
 	if err != nil {
-		if err == fosite.ErrNotFound {
+		if errors.Is(err, storage.ErrNotFound) {
 			log.Debug(logNotFound)
 			return result, err
 		}
@@ -574,6 +726,10 @@ func (c *ClientManager) Authenticate(ctx context.Context, clientID string, secre
 		return result, err
 	}
 
+	if err = c.allowedRegion(ctx, client); err != nil {
+		return result, err
+	}
+
 	if client.Public {
 		// The client doesn't have a secret, therefore is authenticated
 		// implicitly.
@@ -583,13 +739,13 @@ func (c *ClientManager) Authenticate(ctx context.Context, clientID string, secre
 
 	if client.Disabled {
 		log.Debug("disabled client denied access")
-		return result, fosite.ErrAccessDenied
+		return result, storage.NewError(storage.ErrAuthenticationFailed, storage.EntityClients, fosite.ErrAccessDenied)
 	}
 
 	err = c.Hasher.Compare(ctx, client.GetHashedSecret(), []byte(secret))
 	if err != nil {
 		log.WithError(err).Warn("failed to authenticate client secret")
-		return result, err
+		return result, storage.NewAuthenticationFailedError(storage.EntityClients, err)
 	}
 
 	return client, nil
@@ -603,7 +759,7 @@ func (c *ClientManager) Authenticate(ctx context.Context, clientID string, secre
 // fosite.hasher.
 func (c *ClientManager) AuthenticateMigration(ctx context.Context, currentAuth storage.AuthClientFunc, clientID string, secret string) (result storage.Client, err error) {
 	// Initialize contextual method logger
-	log := logger.WithFields(logrus.Fields{
+	log := newLogger(ctx, c.Logger, Fields{
 		"package":    "mongo",
 		"collection": storage.EntityClients,
 		"method":     "AuthenticateMigration",
@@ -623,9 +779,10 @@ func (c *ClientManager) AuthenticateMigration(ctx context.Context, currentAuth s
 	}
 
 	// Trace how long the Mongo operation takes to complete.
-	span, ctx := traceMongoCall(ctx, dbTrace{
-		Manager: "ClientManager",
-		Method:  "AuthenticateMigration",
+	span, ctx := c.DB.traceMongoCall(ctx, DBTrace{
+		Manager:    "ClientManager",
+		Method:     "AuthenticateMigration",
+		Collection: storage.EntityClients,
 	})
 	defer span.Finish()
 
@@ -635,7 +792,7 @@ func (c *ClientManager) AuthenticateMigration(ctx context.Context, currentAuth s
 	// Check for client not found
 	if client.IsEmpty() && !authenticated {
 		log.Debug(logNotFound)
-		return result, fosite.ErrNotFound
+		return result, storage.NewNotFoundError(storage.EntityClients)
 	}
 
 	if client.Public {
@@ -647,7 +804,7 @@ func (c *ClientManager) AuthenticateMigration(ctx context.Context, currentAuth s
 
 	if client.Disabled {
 		log.Debug("disabled client denied access")
-		return result, fosite.ErrAccessDenied
+		return result, storage.NewError(storage.ErrAuthenticationFailed, storage.EntityClients, fosite.ErrAccessDenied)
 	}
 
 	if !authenticated {
@@ -655,7 +812,7 @@ func (c *ClientManager) AuthenticateMigration(ctx context.Context, currentAuth s
 		err := c.Hasher.Compare(ctx, client.GetHashedSecret(), []byte(secret))
 		if err != nil {
 			log.WithError(err).Warn("failed to authenticate client secret")
-			return result, err
+			return result, storage.NewAuthenticationFailedError(storage.EntityClients, err)
 		}
 		return client, nil
 	}
@@ -678,7 +835,7 @@ func (c *ClientManager) AuthenticateMigration(ctx context.Context, currentAuth s
 // GrantScopes grants the provided scopes to the specified Client resource.
 func (c *ClientManager) GrantScopes(ctx context.Context, clientID string, scopes []string) (result storage.Client, err error) {
 	// Initialize contextual method logger
-	log := logger.WithFields(logrus.Fields{
+	log := newLogger(ctx, c.Logger, Fields{
 		"package":    "mongo",
 		"collection": storage.EntityClients,
 		"method":     "GrantScopes",
@@ -698,15 +855,16 @@ func (c *ClientManager) GrantScopes(ctx context.Context, clientID string, scopes
 	}
 
 	// Trace how long the Mongo operation takes to complete.
-	span, ctx := traceMongoCall(ctx, dbTrace{
-		Manager: "ClientManager",
-		Method:  "GrantScopes",
+	span, ctx := c.DB.traceMongoCall(ctx, DBTrace{
+		Manager:    "ClientManager",
+		Method:     "GrantScopes",
+		Collection: storage.EntityClients,
 	})
 	defer span.Finish()
 
 	client, err := c.getConcrete(ctx, clientID)
 	if err != nil {
-		if err == fosite.ErrNotFound {
+		if errors.Is(err, storage.ErrNotFound) {
 			log.Debug(logNotFound)
 			return result, err
 		}
@@ -724,7 +882,7 @@ func (c *ClientManager) GrantScopes(ctx context.Context, clientID string, scopes
 // RemoveScopes revokes the provided scopes from the specified Client resource.
 func (c *ClientManager) RemoveScopes(ctx context.Context, clientID string, scopes []string) (result storage.Client, err error) {
 	// Initialize contextual method logger
-	log := logger.WithFields(logrus.Fields{
+	log := newLogger(ctx, c.Logger, Fields{
 		"package":    "mongo",
 		"collection": storage.EntityClients,
 		"method":     "RemoveScopes",
@@ -744,15 +902,16 @@ func (c *ClientManager) RemoveScopes(ctx context.Context, clientID string, scope
 	}
 
 	// Trace how long the Mongo operation takes to complete.
-	span, ctx := traceMongoCall(ctx, dbTrace{
-		Manager: "ClientManager",
-		Method:  "RemoveScopes",
+	span, ctx := c.DB.traceMongoCall(ctx, DBTrace{
+		Manager:    "ClientManager",
+		Method:     "RemoveScopes",
+		Collection: storage.EntityClients,
 	})
 	defer span.Finish()
 
 	client, err := c.getConcrete(ctx, clientID)
 	if err != nil {
-		if err == fosite.ErrNotFound {
+		if errors.Is(err, storage.ErrNotFound) {
 			log.Debug(logNotFound)
 			return result, err
 		}
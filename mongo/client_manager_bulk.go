@@ -0,0 +1,340 @@
+package mongo
+
+import (
+	// Standard Library Imports
+	"context"
+	"time"
+
+	// External Imports
+	"github.com/google/uuid"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	// Internal Imports
+	"github.com/matthewhartstonge/storage"
+)
+
+// BulkCreate creates the clients, returning a result per client in the order
+// given. Secrets are hashed in a bounded pool of workers and the clients are
+// inserted with a single unordered bulk write, so that invalid, or
+// conflicting, clients are reported individually rather than failing the
+// batch.
+func (c *ClientManager) BulkCreate(ctx context.Context, clients []storage.Client) (results []storage.BulkResult, err error) {
+	// Work on a copy, so that the caller's clients aren't modified.
+	clients = append([]storage.Client(nil), clients...)
+
+	now := time.Now().Unix()
+	results = make([]storage.BulkResult, len(clients))
+	tenants := make([][]string, len(clients))
+	scopes := make([][]string, len(clients))
+	for i := range clients {
+		// Enable developers to provide their own IDs
+		if clients[i].ID == "" {
+			clients[i].ID = uuid.NewString()
+		}
+		if clients[i].CreateTime == 0 {
+			clients[i].CreateTime = now
+		}
+
+		results[i].ID = clients[i].ID
+		tenants[i] = clients[i].AllowedTenantAccess
+		scopes[i] = clients[i].Scopes
+	}
+	c.validateBulk(ctx, results, tenants, scopes)
+
+	var jobs []hashJob
+	for i := range clients {
+		if results[i].Err == nil {
+			jobs = append(jobs, hashJob{index: i, secret: clients[i].Secret})
+		}
+	}
+
+	models := make(map[int]mongo.WriteModel, len(jobs))
+	for _, hashed := range hashSecrets(ctx, c.Hasher, jobs, c.HashWorkers) {
+		if hashed.err != nil {
+			results[hashed.index].Err = hashed.err
+			continue
+		}
+
+		client := clients[hashed.index]
+		client.Secret = hashed.hash
+		models[hashed.index] = mongo.NewInsertOneModel().SetDocument(client)
+	}
+
+	err = c.bulkWrite(ctx, "BulkCreate", results, models)
+	if err != nil {
+		return nil, err
+	}
+
+	return results, nil
+}
+
+// BulkUpdate updates the clients by ID, returning a result per client in the
+// order given. As with Update, a blank secret retains the current secret.
+func (c *ClientManager) BulkUpdate(ctx context.Context, clients []storage.Client) (results []storage.BulkResult, err error) {
+	// Work on a copy, so that the caller's clients aren't modified.
+	clients = append([]storage.Client(nil), clients...)
+
+	ids := make([]string, len(clients))
+	for i := range clients {
+		ids[i] = clients[i].ID
+	}
+
+	current, err := c.findByIDs(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().Unix()
+	results = make([]storage.BulkResult, len(clients))
+	addedTenants := make([][]string, len(clients))
+	addedScopes := make([][]string, len(clients))
+	for i := range clients {
+		results[i].ID = clients[i].ID
+
+		currentResource, ok := current[clients[i].ID]
+		if !ok {
+			results[i].Err = storage.NewNotFoundError(storage.EntityClients)
+			continue
+		}
+
+		// Update modified time
+		clients[i].UpdateTime = now
+
+		// Only validate newly referenced tenants and scopes, as with Update.
+		addedTenants[i] = difference(clients[i].AllowedTenantAccess, currentResource.AllowedTenantAccess)
+		addedScopes[i] = difference(clients[i].Scopes, currentResource.Scopes)
+	}
+	c.validateBulk(ctx, results, addedTenants, addedScopes)
+
+	var jobs []hashJob
+	models := make(map[int]mongo.WriteModel, len(clients))
+	for i := range clients {
+		if results[i].Err != nil {
+			continue
+		}
+
+		currentResource := current[clients[i].ID]
+		if currentResource.Secret == clients[i].Secret || clients[i].Secret == "" {
+			// If the password/hash is blank or hash matches, set using old hash.
+			clients[i].Secret = currentResource.Secret
+			models[i] = replaceByID(clients[i].ID, clients[i])
+			continue
+		}
+
+		jobs = append(jobs, hashJob{index: i, secret: clients[i].Secret})
+	}
+
+	for _, hashed := range hashSecrets(ctx, c.Hasher, jobs, c.HashWorkers) {
+		if hashed.err != nil {
+			results[hashed.index].Err = hashed.err
+			continue
+		}
+
+		client := clients[hashed.index]
+		client.Secret = hashed.hash
+		models[hashed.index] = replaceByID(client.ID, client)
+	}
+
+	err = c.bulkWrite(ctx, "BulkUpdate", results, models)
+	if err != nil {
+		return nil, err
+	}
+
+	return results, nil
+}
+
+// BulkDelete deletes the clients by ID, returning a result per client in the
+// order given.
+func (c *ClientManager) BulkDelete(ctx context.Context, clientIDs []string) (results []storage.BulkResult, err error) {
+	// Initialize contextual method logger
+	log := newLogger(ctx, c.Logger, Fields{
+		"package":    "mongo",
+		"collection": storage.EntityClients,
+		"method":     "BulkDelete",
+	})
+
+	// Trace how long the Mongo operation takes to complete.
+	span, ctx := c.DB.traceMongoCall(ctx, DBTrace{
+		Manager:    "ClientManager",
+		Method:     "BulkDelete",
+		Collection: storage.EntityClients,
+		Operation:  "bulkWrite",
+	})
+	defer span.Finish()
+
+	collection := c.DB.Collection(storage.EntityClients)
+	results, err = bulkDelete(ctx, collection, storage.EntityClients, clientIDs)
+	if err != nil {
+		// Log to StdOut
+		log.WithError(err).Error(logError)
+		// Log to Tracer
+		span.RecordError(err)
+		return nil, err
+	}
+
+	return results, nil
+}
+
+// BulkGrantScopes grants the scopes to the clients matching the filter and
+// returns the number of clients modified.
+func (c *ClientManager) BulkGrantScopes(ctx context.Context, filter storage.ListClientsRequest, scopes []string) (modified int64, err error) {
+	if err = filter.ValidateBulk(); err != nil {
+		return 0, err
+	}
+	if len(scopes) == 0 {
+		return 0, nil
+	}
+
+	err = storage.ValidateScopes(ctx, c.Scopes, storage.EntityClients, scopes)
+	if err != nil {
+		return 0, err
+	}
+
+	// Only match the clients missing a scope, so that the update time of
+	// clients that already have the scopes is retained.
+	query := bson.M{
+		"$and": []bson.M{
+			listClientsQuery(filter),
+			{"scopes": bson.M{"$not": bson.M{"$all": scopes}}},
+		},
+	}
+	update := bson.M{
+		"$addToSet": bson.M{"scopes": bson.M{"$each": scopes}},
+		"$set":      bson.M{"updateTime": time.Now().Unix()},
+	}
+
+	return c.updateMany(ctx, "BulkGrantScopes", query, update)
+}
+
+// BulkRemoveScopes removes the scopes from the clients matching the filter
+// and returns the number of clients modified.
+func (c *ClientManager) BulkRemoveScopes(ctx context.Context, filter storage.ListClientsRequest, scopes []string) (modified int64, err error) {
+	if err = filter.ValidateBulk(); err != nil {
+		return 0, err
+	}
+	if len(scopes) == 0 {
+		return 0, nil
+	}
+
+	query := bson.M{
+		"$and": []bson.M{
+			listClientsQuery(filter),
+			{"scopes": bson.M{"$in": scopes}},
+		},
+	}
+	update := bson.M{
+		"$pull": bson.M{"scopes": bson.M{"$in": scopes}},
+		"$set":  bson.M{"updateTime": time.Now().Unix()},
+	}
+
+	return c.updateMany(ctx, "BulkRemoveScopes", query, update)
+}
+
+// validateBulk validates the tenants and scopes referenced by each client,
+// recording a validation error as the client's result.
+func (c *ClientManager) validateBulk(ctx context.Context, results []storage.BulkResult, tenants [][]string, scopes [][]string) {
+	tenantErrs := validateEach(tenants, func(tenantIDs []string) error {
+		return storage.ValidateTenantReferences(ctx, c.Tenants, storage.EntityClients, tenantIDs)
+	})
+	scopeErrs := validateEach(scopes, func(scopeNames []string) error {
+		return storage.ValidateScopes(ctx, c.Scopes, storage.EntityClients, scopeNames)
+	})
+
+	for i := range results {
+		if results[i].Err != nil {
+			continue
+		}
+		if tenantErrs[i] != nil {
+			results[i].Err = tenantErrs[i]
+			continue
+		}
+		results[i].Err = scopeErrs[i]
+	}
+}
+
+// findByIDs returns the clients that exist out of the given IDs, by ID.
+func (c *ClientManager) findByIDs(ctx context.Context, clientIDs []string) (map[string]storage.Client, error) {
+	collection := c.DB.Collection(storage.EntityClients)
+	cursor, err := collection.Find(ctx, bson.M{"id": bson.M{"$in": clientIDs}})
+	if err != nil {
+		return nil, toStorageError(storage.EntityClients, err)
+	}
+
+	var clients []storage.Client
+	if err = cursor.All(ctx, &clients); err != nil {
+		return nil, toStorageError(storage.EntityClients, err)
+	}
+
+	found := make(map[string]storage.Client, len(clients))
+	for _, client := range clients {
+		found[client.ID] = client
+	}
+
+	return found, nil
+}
+
+// bulkWrite writes the models, keyed by the index of the client they write,
+// recording the error of each failed write as the client's result.
+func (c *ClientManager) bulkWrite(ctx context.Context, method string, results []storage.BulkResult, models map[int]mongo.WriteModel) error {
+	// Initialize contextual method logger
+	log := newLogger(ctx, c.Logger, Fields{
+		"package":    "mongo",
+		"collection": storage.EntityClients,
+		"method":     method,
+	})
+
+	// Trace how long the Mongo operation takes to complete.
+	span, ctx := c.DB.traceMongoCall(ctx, DBTrace{
+		Manager:    "ClientManager",
+		Method:     method,
+		Collection: storage.EntityClients,
+		Operation:  "bulkWrite",
+	})
+	defer span.Finish()
+
+	collection := c.DB.Collection(storage.EntityClients)
+	err := writeIndexed(ctx, collection, storage.EntityClients, results, models)
+	if err != nil {
+		// Log to StdOut
+		log.WithError(err).Error(logError)
+		// Log to Tracer
+		span.RecordError(err)
+		return err
+	}
+
+	return nil
+}
+
+// updateMany applies the update to the clients matching the query and
+// returns the number of clients modified.
+func (c *ClientManager) updateMany(ctx context.Context, method string, query bson.M, update bson.M) (int64, error) {
+	// Initialize contextual method logger
+	log := newLogger(ctx, c.Logger, Fields{
+		"package":    "mongo",
+		"collection": storage.EntityClients,
+		"method":     method,
+	})
+
+	// Trace how long the Mongo operation takes to complete.
+	span, ctx := c.DB.traceMongoCall(ctx, DBTrace{
+		Manager:    "ClientManager",
+		Method:     method,
+		Collection: storage.EntityClients,
+		Operation:  "updateMany",
+		Query:      query,
+	})
+	defer span.Finish()
+
+	collection := c.DB.Collection(storage.EntityClients)
+	res, err := collection.UpdateMany(ctx, query, update)
+	if err != nil {
+		// Log to StdOut
+		log.WithError(err).Error(logError)
+		// Log to Tracer
+		span.RecordError(err)
+		return 0, toStorageError(storage.EntityClients, err)
+	}
+
+	return res.ModifiedCount, nil
+}
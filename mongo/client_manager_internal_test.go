@@ -64,3 +64,12 @@ func TestClientMongoManager_ImplementsStorageClientManager(t *testing.T) {
 		t.Error("ClientManager does not implement interface storage.ClientManager")
 	}
 }
+
+func TestClientMongoManager_ImplementsStorageClientIterator(t *testing.T) {
+	c := &ClientManager{}
+
+	var i interface{} = c
+	if _, ok := i.(storage.ClientIterator); !ok {
+		t.Error("ClientManager does not implement interface storage.ClientIterator")
+	}
+}
@@ -3,6 +3,7 @@ package mongo_test
 import (
 	// Standard Library Imports
 	"context"
+	"errors"
 	"reflect"
 	"testing"
 	"time"
@@ -492,7 +493,7 @@ func TestClientManager_Create_ShouldConflict(t *testing.T) {
 	if err == nil {
 		AssertError(t, err, nil, "create should return an error on conflict")
 	}
-	if err != storage.ErrResourceExists {
+	if !errors.Is(err, storage.ErrResourceExists) {
 		AssertError(t, err, nil, "create should return conflict")
 	}
 }
@@ -515,9 +516,9 @@ func TestClientManager_Get_ShouldReturnNotFound(t *testing.T) {
 	store, ctx, teardown := setup(t)
 	defer teardown()
 
-	expected := fosite.ErrNotFound
+	expected := storage.ErrNotFound
 	got, err := store.ClientManager.Get(ctx, "lolNotFound")
-	if err != expected {
+	if !errors.Is(err, expected) {
 		AssertError(t, got, expected, "get should return not found")
 	}
 }
@@ -607,7 +608,7 @@ func TestClientManager_Update_ShouldReturnNotFound(t *testing.T) {
 	if err == nil {
 		AssertError(t, err, nil, "update should return an error on not found")
 	}
-	if err != fosite.ErrNotFound {
+	if !errors.Is(err, fosite.ErrNotFound) {
 		AssertError(t, err, nil, "update should return not found")
 	}
 }
@@ -624,9 +625,9 @@ func TestClientManager_Delete(t *testing.T) {
 	}
 
 	// Double check that the original reference was deleted
-	expectedErr := fosite.ErrNotFound
+	expectedErr := storage.ErrNotFound
 	got, err := store.ClientManager.Get(ctx, expected.ID)
-	if err != expectedErr {
+	if !errors.Is(err, expectedErr) {
 		AssertError(t, got, expectedErr, "get should return not found")
 	}
 }
@@ -639,7 +640,51 @@ func TestClientManager_Delete_ShouldReturnNotFound(t *testing.T) {
 	if err == nil {
 		AssertError(t, err, nil, "delete should return an error on not found")
 	}
-	if err != fosite.ErrNotFound {
+	if !errors.Is(err, fosite.ErrNotFound) {
 		AssertError(t, err, nil, "delete should return not found")
 	}
 }
+
+func TestClientManager_BulkCreate_ShouldNotModifyInput(t *testing.T) {
+	store, ctx, teardown := setup(t)
+	defer teardown()
+
+	client := expectedClient()
+	client.ID = ""
+	client.CreateTime = 0
+	clients := []storage.Client{client}
+
+	results, err := store.ClientManager.BulkCreate(ctx, clients)
+	if err != nil {
+		AssertFatal(t, err, nil, "bulk create should return no database errors")
+	}
+	if results[0].Err != nil {
+		AssertFatal(t, results[0].Err, nil, "bulk create should create the client")
+	}
+	if !reflect.DeepEqual(clients[0], client) {
+		AssertError(t, clients[0], client, "bulk create should not modify the given clients")
+	}
+}
+
+func TestClientManager_BulkUpdate_ShouldNotModifyInput(t *testing.T) {
+	store, ctx, teardown := setup(t)
+	defer teardown()
+
+	expected := createClient(ctx, t, store)
+
+	update := expected
+	update.Secret = "foobaz"
+	update.UpdateTime = 0
+	clients := []storage.Client{update}
+
+	results, err := store.ClientManager.BulkUpdate(ctx, clients)
+	if err != nil {
+		AssertFatal(t, err, nil, "bulk update should return no database errors")
+	}
+	if results[0].Err != nil {
+		AssertFatal(t, results[0].Err, nil, "bulk update should update the client")
+	}
+	if !reflect.DeepEqual(clients[0], update) {
+		AssertError(t, clients[0], update, "bulk update should not modify the given clients")
+	}
+}
@@ -0,0 +1,409 @@
+package mongo
+
+import (
+	// Standard Library Imports
+	"context"
+	"errors"
+	"time"
+
+	// External Imports
+	"github.com/google/uuid"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	// Internal Imports
+	"github.com/matthewhartstonge/storage"
+)
+
+// ConsentManager provides a mongo backed implementation for user consents.
+//
+// Implements:
+// - storage.Configurer
+// - storage.ConsentStorer
+// - storage.ConsentManager
+type ConsentManager struct {
+	DB     *DB
+	Logger Logger
+
+	// Requests provides access to the request collections in order to revoke
+	// the tokens issued under a consent when it is revoked.
+	Requests storage.RequestStorer
+}
+
+// Configure implements storage.Configurer.
+func (c *ConsentManager) Configure(ctx context.Context) (err error) {
+	log := newLogger(ctx, c.Logger, Fields{
+		"package":    "mongo",
+		"collection": storage.EntityConsents,
+		"method":     "Configure",
+	})
+
+	indices := []mongo.IndexModel{
+		{
+			Keys: bson.D{
+				{
+					Key:   "id",
+					Value: int32(1),
+				},
+			},
+			Options: options.Index().
+				SetName(IdxConsentID).
+				SetBackground(true).
+				SetSparse(true).
+				SetUnique(true),
+		},
+		{
+			Keys: bson.D{
+				{
+					Key:   "userId",
+					Value: int32(1),
+				},
+				{
+					Key:   "clientId",
+					Value: int32(1),
+				},
+			},
+			Options: options.Index().
+				SetName(IdxCompoundConsent).
+				SetBackground(true).
+				SetUnique(true),
+		},
+	}
+
+	err = c.DB.createIndexes(ctx, storage.EntityConsents, indices)
+	if err != nil {
+		log.WithError(err).Error(logError)
+		return toStorageError(storage.EntityConsents, err)
+	}
+
+	return nil
+}
+
+// getConcrete returns the user's Consent resource for the client.
+func (c *ConsentManager) getConcrete(ctx context.Context, userID string, clientID string) (result storage.Consent, err error) {
+	log := newLogger(ctx, c.Logger, Fields{
+		"package":    "mongo",
+		"collection": storage.EntityConsents,
+		"method":     "getConcrete",
+		"userID":     userID,
+		"clientID":   clientID,
+	})
+
+	// Build Query
+	query := bson.M{
+		"userId":   userID,
+		"clientId": clientID,
+	}
+
+	// Trace how long the Mongo operation takes to complete.
+	span, ctx := c.DB.traceMongoCall(ctx, DBTrace{
+		Manager:    "ConsentManager",
+		Method:     "getConcrete",
+		Collection: storage.EntityConsents,
+		Operation:  "find",
+		Query:      query,
+	})
+	defer span.Finish()
+
+	var consent storage.Consent
+	collection := c.DB.Collection(storage.EntityConsents)
+	err = collection.FindOne(ctx, query).Decode(&consent)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			log.WithError(err).Debug(logNotFound)
+			return result, storage.NewNotFoundError(storage.EntityConsents)
+		}
+
+		// Log to StdOut
+		log.WithError(err).Error(logError)
+		// Log to Tracer
+		span.RecordError(err)
+		return result, toStorageError(storage.EntityConsents, err)
+	}
+
+	return consent, nil
+}
+
+// List returns a list of Consent resources that match the provided inputs.
+func (c *ConsentManager) List(ctx context.Context, filter storage.ListConsentsRequest) (results []storage.Consent, err error) {
+	// Initialize contextual method logger
+	log := newLogger(ctx, c.Logger, Fields{
+		"package":    "mongo",
+		"collection": storage.EntityConsents,
+		"method":     "List",
+	})
+
+	// Build Query
+	query := bson.M{}
+	if filter.UserID != "" {
+		query["userId"] = filter.UserID
+	}
+	if filter.ClientID != "" {
+		query["clientId"] = filter.ClientID
+	}
+	if !filter.IncludeExpired {
+		query["$or"] = bson.A{
+			bson.M{"rememberUntil": 0},
+			bson.M{"rememberUntil": bson.M{"$gt": time.Now().Unix()}},
+		}
+	}
+
+	// Trace how long the Mongo operation takes to complete.
+	span, ctx := c.DB.traceMongoCall(ctx, DBTrace{
+		Manager:    "ConsentManager",
+		Method:     "List",
+		Collection: storage.EntityConsents,
+		Operation:  "find",
+		Query:      query,
+	})
+	defer span.Finish()
+
+	collection := c.DB.Collection(storage.EntityConsents)
+	cursor, err := collection.Find(ctx, query)
+	if err != nil {
+		// Log to StdOut
+		log.WithError(err).Error(logError)
+		// Log to Tracer
+		span.RecordError(err)
+		return results, toStorageError(storage.EntityConsents, err)
+	}
+
+	var consents []storage.Consent
+	err = cursor.All(ctx, &consents)
+	if err != nil {
+		// Log to StdOut
+		log.WithError(err).Error(logError)
+		// Log to Tracer
+		span.RecordError(err)
+		return results, toStorageError(storage.EntityConsents, err)
+	}
+
+	return consents, nil
+}
+
+// Get returns the user's Consent resource for the client.
+func (c *ConsentManager) Get(ctx context.Context, userID string, clientID string) (result storage.Consent, err error) {
+	return c.getConcrete(ctx, userID, clientID)
+}
+
+// Grant records the user's consent to the client, merging the granted scopes
+// and audiences into the existing consent if it is yet to expire, and returns
+// the granted Consent resource.
+func (c *ConsentManager) Grant(ctx context.Context, consent storage.Consent) (result storage.Consent, err error) {
+	// Initialize contextual method logger
+	log := newLogger(ctx, c.Logger, Fields{
+		"package":    "mongo",
+		"collection": storage.EntityConsents,
+		"method":     "Grant",
+		"userID":     consent.UserID,
+		"clientID":   consent.ClientID,
+	})
+
+	if consent.UserID == "" {
+		log.Debug(logInvalid)
+		return result, storage.NewInvalidArgumentError(storage.EntityConsents, "user id is required", "userId")
+	}
+	if consent.ClientID == "" {
+		log.Debug(logInvalid)
+		return result, storage.NewInvalidArgumentError(storage.EntityConsents, "client id is required", "clientId")
+	}
+
+	now := time.Now()
+	currentResource, err := c.getConcrete(ctx, consent.UserID, consent.ClientID)
+	switch {
+	case err == nil:
+		// Retain the identity of the existing consent
+		consent.ID = currentResource.ID
+		consent.CreateTime = currentResource.CreateTime
+		consent.UpdateTime = now.Unix()
+		if !currentResource.IsExpired(now) {
+			consent.GrantedScopes = union(currentResource.GrantedScopes, consent.GrantedScopes)
+			consent.GrantedAudience = union(currentResource.GrantedAudience, consent.GrantedAudience)
+		}
+
+	case errors.Is(err, storage.ErrNotFound):
+		// Enable developers to provide their own IDs
+		if consent.ID == "" {
+			consent.ID = uuid.NewString()
+		}
+		if consent.CreateTime == 0 {
+			consent.CreateTime = now.Unix()
+		}
+
+	default:
+		log.WithError(err).Error(logError)
+		return result, err
+	}
+	consent.GrantTime = now.Unix()
+
+	// Build Query
+	selector := bson.M{
+		"userId":   consent.UserID,
+		"clientId": consent.ClientID,
+	}
+
+	// Trace how long the Mongo operation takes to complete.
+	span, ctx := c.DB.traceMongoCall(ctx, DBTrace{
+		Manager:    "ConsentManager",
+		Method:     "Grant",
+		Collection: storage.EntityConsents,
+		Operation:  "update",
+		Selector:   selector,
+	})
+	defer span.Finish()
+
+	collection := c.DB.Collection(storage.EntityConsents)
+	opts := options.Replace().SetUpsert(true)
+	_, err = collection.ReplaceOne(ctx, selector, consent, opts)
+	if err != nil {
+		if isDup(err) {
+			// Log to StdOut
+			log.WithError(err).Debug(logConflict)
+			// Log to Tracer
+			span.RecordError(err)
+			return result, toStorageError(storage.EntityConsents, err)
+		}
+
+		// Log to StdOut
+		log.WithError(err).Error(logError)
+		// Log to Tracer
+		span.SetQuery(consent)
+		span.RecordError(err)
+		return result, toStorageError(storage.EntityConsents, err)
+	}
+
+	return consent, nil
+}
+
+// Lookup returns the user's Consent resource for the client if it is yet to
+// expire and covers the requested scopes and audiences, otherwise not found.
+func (c *ConsentManager) Lookup(ctx context.Context, userID string, clientID string, scopes []string, audience []string) (result storage.Consent, err error) {
+	// Initialize contextual method logger
+	log := newLogger(ctx, c.Logger, Fields{
+		"package":    "mongo",
+		"collection": storage.EntityConsents,
+		"method":     "Lookup",
+		"userID":     userID,
+		"clientID":   clientID,
+	})
+
+	consent, err := c.getConcrete(ctx, userID, clientID)
+	if err != nil {
+		return result, err
+	}
+
+	if !consent.Covers(time.Now(), scopes, audience) {
+		log.Debug("consent does not cover the request")
+		return result, storage.NewNotFoundError(storage.EntityConsents)
+	}
+
+	return consent, nil
+}
+
+// Revoke deletes the user's Consent resource for the client, and optionally
+// revokes the tokens issued to the client on behalf of the user. Tokens are
+// revoked before the consent is deleted, so if revoking them fails, the
+// consent remains to be revoked again.
+func (c *ConsentManager) Revoke(ctx context.Context, userID string, clientID string, revokeTokens bool) (err error) {
+	// Initialize contextual method logger
+	log := newLogger(ctx, c.Logger, Fields{
+		"package":    "mongo",
+		"collection": storage.EntityConsents,
+		"method":     "Revoke",
+		"userID":     userID,
+		"clientID":   clientID,
+	})
+
+	if revokeTokens {
+		if _, err = c.getConcrete(ctx, userID, clientID); err != nil {
+			return err
+		}
+		if err = c.revokeTokens(ctx, userID, clientID); err != nil {
+			return err
+		}
+	}
+
+	// Build Query
+	query := bson.M{
+		"userId":   userID,
+		"clientId": clientID,
+	}
+
+	// Trace how long the Mongo operation takes to complete.
+	span, ctx := c.DB.traceMongoCall(ctx, DBTrace{
+		Manager:    "ConsentManager",
+		Method:     "Revoke",
+		Collection: storage.EntityConsents,
+		Operation:  "delete",
+		Query:      query,
+	})
+	defer span.Finish()
+
+	collection := c.DB.Collection(storage.EntityConsents)
+	res, err := collection.DeleteOne(ctx, query)
+	if err != nil {
+		// Log to StdOut
+		log.WithError(err).Error(logError)
+		// Log to Tracer
+		span.RecordError(err)
+		return toStorageError(storage.EntityConsents, err)
+	}
+
+	if res.DeletedCount == 0 {
+		// Log to StdOut
+		log.WithError(err).Debug(logNotFound)
+		// Log to Tracer
+		span.RecordError(err)
+		return storage.NewNotFoundError(storage.EntityConsents)
+	}
+
+	return nil
+}
+
+// revokeTokens revokes the access and refresh tokens issued to the client on
+// behalf of the user.
+func (c *ConsentManager) revokeTokens(ctx context.Context, userID string, clientID string) (err error) {
+	log := newLogger(ctx, c.Logger, Fields{
+		"package":  "mongo",
+		"method":   "revokeTokens",
+		"userID":   userID,
+		"clientID": clientID,
+	})
+
+	if c.Requests == nil {
+		err = errors.New("requests are required to revoke tokens")
+		log.WithError(err).Error(logError)
+		return storage.NewError(storage.ErrPreconditionFailed, storage.EntityConsents, err)
+	}
+
+	revokers := map[string]func(ctx context.Context, requestID string) error{
+		storage.EntityAccessTokens:  c.Requests.RevokeAccessToken,
+		storage.EntityRefreshTokens: c.Requests.RevokeRefreshToken,
+	}
+	for entityName, revoke := range revokers {
+		requests, err := c.Requests.List(ctx, entityName, storage.ListRequestsRequest{
+			ClientID: clientID,
+			UserID:   userID,
+		})
+		if err != nil {
+			log.WithError(err).Error(logError)
+			return err
+		}
+
+		for _, request := range requests {
+			if err = revoke(ctx, request.ID); err != nil {
+				log.WithError(err).Error(logError)
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// union returns the values in a, followed by the values in b that are not in
+// a.
+func union(a []string, b []string) []string {
+	return append(append([]string{}, a...), difference(b, a)...)
+}
@@ -0,0 +1,105 @@
+package mongo
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/matthewhartstonge/storage"
+)
+
+func TestConsentMongoManager_ImplementsStorageConfigurer(t *testing.T) {
+	c := &ConsentManager{}
+
+	var i interface{} = c
+	if _, ok := i.(storage.Configurer); !ok {
+		t.Error("ConsentManager does not implement interface storage.Configurer")
+	}
+}
+
+func TestConsentMongoManager_ImplementsStorageConsentStorer(t *testing.T) {
+	c := &ConsentManager{}
+
+	var i interface{} = c
+	if _, ok := i.(storage.ConsentStorer); !ok {
+		t.Error("ConsentManager does not implement interface storage.ConsentStorer")
+	}
+}
+
+func TestConsentMongoManager_ImplementsStorageConsentManager(t *testing.T) {
+	c := &ConsentManager{}
+
+	var i interface{} = c
+	if _, ok := i.(storage.ConsentManager); !ok {
+		t.Error("ConsentManager does not implement interface storage.ConsentManager")
+	}
+}
+
+// consentRequests provides an in-memory request storer, recording revoked
+// tokens.
+type consentRequests struct {
+	storage.RequestStorer
+	requests map[string][]storage.Request
+	revoked  []string
+}
+
+func (r *consentRequests) List(ctx context.Context, entityName string, filter storage.ListRequestsRequest) (results []storage.Request, err error) {
+	for _, request := range r.requests[entityName] {
+		if request.ClientID == filter.ClientID && request.UserID == filter.UserID {
+			results = append(results, request)
+		}
+	}
+	return results, nil
+}
+
+func (r *consentRequests) RevokeAccessToken(ctx context.Context, requestID string) error {
+	r.revoked = append(r.revoked, storage.EntityAccessTokens+":"+requestID)
+	return nil
+}
+
+func (r *consentRequests) RevokeRefreshToken(ctx context.Context, requestID string) error {
+	r.revoked = append(r.revoked, storage.EntityRefreshTokens+":"+requestID)
+	return nil
+}
+
+func TestConsentManager_RevokeTokens(t *testing.T) {
+	requests := &consentRequests{requests: map[string][]storage.Request{
+		storage.EntityAccessTokens: {
+			{ID: "request-1", ClientID: "client-1", UserID: "user-1"},
+			{ID: "request-2", ClientID: "client-2", UserID: "user-1"},
+		},
+		storage.EntityRefreshTokens: {
+			{ID: "request-1", ClientID: "client-1", UserID: "user-1"},
+		},
+	}}
+	c := &ConsentManager{Requests: requests}
+
+	if err := c.revokeTokens(context.Background(), "user-1", "client-1"); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	sort.Strings(requests.revoked)
+	expected := []string{
+		storage.EntityAccessTokens + ":request-1",
+		storage.EntityRefreshTokens + ":request-1",
+	}
+	if !reflect.DeepEqual(requests.revoked, expected) {
+		t.Errorf("expected %v to be revoked, got %v", expected, requests.revoked)
+	}
+}
+
+func TestConsentManager_RevokeTokens_RequiresRequests(t *testing.T) {
+	err := (&ConsentManager{}).revokeTokens(context.Background(), "user-1", "client-1")
+	if !errors.Is(err, storage.ErrPreconditionFailed) {
+		t.Errorf("expected precondition failed, got %v", err)
+	}
+}
+
+func TestUnion(t *testing.T) {
+	got := union([]string{"a", "b"}, []string{"b", "c"})
+	if expected := []string{"a", "b", "c"}; !reflect.DeepEqual(got, expected) {
+		t.Errorf("expected %v, got %v", expected, got)
+	}
+}
@@ -0,0 +1,235 @@
+package mongo_test
+
+import (
+	// Standard Library Imports
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	// External Imports
+	"github.com/google/uuid"
+
+	// Internal Imports
+	"github.com/matthewhartstonge/storage"
+	"github.com/matthewhartstonge/storage/mongo"
+)
+
+func expectedConsent(userID string, clientID string) storage.Consent {
+	return storage.Consent{
+		UserID:          userID,
+		ClientID:        clientID,
+		GrantedScopes:   []string{"urn:test:cats:read", "urn:test:cats:write"},
+		GrantedAudience: []string{"https://cats.example.com"},
+		RememberUntil:   time.Now().Add(time.Hour).Unix(),
+	}
+}
+
+func grantConsent(ctx context.Context, t *testing.T, store *mongo.Store, consent storage.Consent) storage.Consent {
+	got, err := store.ConsentManager.Grant(ctx, consent)
+	if err != nil {
+		AssertFatal(t, err, nil, "grant should return no database errors")
+	}
+
+	return got
+}
+
+func TestConsentManager_Grant(t *testing.T) {
+	store, ctx, teardown := setup(t)
+	defer teardown()
+
+	expected := grantConsent(ctx, t, store, expectedConsent(uuid.NewString(), uuid.NewString()))
+	if expected.ID == "" || expected.CreateTime == 0 || expected.GrantTime == 0 {
+		AssertError(t, expected, "id, createTime and grantTime", "grant should default the consent metadata")
+	}
+
+	got, err := store.ConsentManager.Get(ctx, expected.UserID, expected.ClientID)
+	if err != nil {
+		AssertFatal(t, err, nil, "get should return no database errors")
+	}
+	if !reflect.DeepEqual(got, expected) {
+		AssertError(t, got, expected, "consent not equal")
+	}
+}
+
+func TestConsentManager_Grant_ShouldMergeScopes(t *testing.T) {
+	store, ctx, teardown := setup(t)
+	defer teardown()
+
+	consent := expectedConsent(uuid.NewString(), uuid.NewString())
+	consent.GrantedScopes = []string{"urn:test:cats:read"}
+	expected := grantConsent(ctx, t, store, consent)
+
+	consent.GrantedScopes = []string{"urn:test:dogs:read"}
+	got := grantConsent(ctx, t, store, consent)
+	if got.ID != expected.ID {
+		AssertError(t, got.ID, expected.ID, "grant should retain the identity of the existing consent")
+	}
+
+	scopes := []string{"urn:test:cats:read", "urn:test:dogs:read"}
+	if !reflect.DeepEqual(got.GrantedScopes, scopes) {
+		AssertError(t, got.GrantedScopes, scopes, "grant should merge the granted scopes")
+	}
+}
+
+func TestConsentManager_Grant_ShouldValidate(t *testing.T) {
+	store, ctx, teardown := setup(t)
+	defer teardown()
+
+	_, err := store.ConsentManager.Grant(ctx, expectedConsent("", uuid.NewString()))
+	if !errors.Is(err, storage.ErrInvalidArgument) {
+		AssertError(t, err, storage.ErrInvalidArgument, "grant should require a user id")
+	}
+
+	_, err = store.ConsentManager.Grant(ctx, expectedConsent(uuid.NewString(), ""))
+	if !errors.Is(err, storage.ErrInvalidArgument) {
+		AssertError(t, err, storage.ErrInvalidArgument, "grant should require a client id")
+	}
+}
+
+func TestConsentManager_Lookup(t *testing.T) {
+	store, ctx, teardown := setup(t)
+	defer teardown()
+
+	expected := grantConsent(ctx, t, store, expectedConsent(uuid.NewString(), uuid.NewString()))
+
+	got, err := store.ConsentManager.Lookup(ctx, expected.UserID, expected.ClientID, []string{"urn:test:cats:read"}, nil)
+	if err != nil {
+		AssertFatal(t, err, nil, "lookup should match a subset of the granted scopes")
+	}
+	if got.ID != expected.ID {
+		AssertError(t, got.ID, expected.ID, "lookup should return the consent")
+	}
+
+	_, err = store.ConsentManager.Lookup(ctx, expected.UserID, expected.ClientID, []string{"urn:test:cats:read", "urn:test:dogs:read"}, nil)
+	if !errors.Is(err, storage.ErrNotFound) {
+		AssertError(t, err, storage.ErrNotFound, "lookup should not match scopes that haven't been granted")
+	}
+
+	_, err = store.ConsentManager.Lookup(ctx, expected.UserID, expected.ClientID, nil, []string{"https://dogs.example.com"})
+	if !errors.Is(err, storage.ErrNotFound) {
+		AssertError(t, err, storage.ErrNotFound, "lookup should not match audiences that haven't been granted")
+	}
+}
+
+func TestConsentManager_Lookup_ShouldNotMatchExpired(t *testing.T) {
+	store, ctx, teardown := setup(t)
+	defer teardown()
+
+	consent := expectedConsent(uuid.NewString(), uuid.NewString())
+	consent.RememberUntil = time.Now().Add(-time.Minute).Unix()
+	expected := grantConsent(ctx, t, store, consent)
+
+	_, err := store.ConsentManager.Lookup(ctx, expected.UserID, expected.ClientID, []string{"urn:test:cats:read"}, nil)
+	if !errors.Is(err, storage.ErrNotFound) {
+		AssertError(t, err, storage.ErrNotFound, "lookup should not match an expired consent")
+	}
+}
+
+func TestConsentManager_List(t *testing.T) {
+	store, ctx, teardown := setup(t)
+	defer teardown()
+
+	userID := uuid.NewString()
+	expected := grantConsent(ctx, t, store, expectedConsent(userID, uuid.NewString()))
+
+	expired := expectedConsent(userID, uuid.NewString())
+	expired.RememberUntil = time.Now().Add(-time.Minute).Unix()
+	expired = grantConsent(ctx, t, store, expired)
+
+	// Consents of other users are filtered out.
+	grantConsent(ctx, t, store, expectedConsent(uuid.NewString(), expected.ClientID))
+
+	got, err := store.ConsentManager.List(ctx, storage.ListConsentsRequest{UserID: userID})
+	if err != nil {
+		AssertFatal(t, err, nil, "list should return no database errors")
+	}
+	if len(got) != 1 || got[0].ID != expected.ID {
+		AssertError(t, got, expected, "list should only return the user's unexpired consents")
+	}
+
+	got, err = store.ConsentManager.List(ctx, storage.ListConsentsRequest{
+		UserID:         userID,
+		IncludeExpired: true,
+	})
+	if err != nil {
+		AssertFatal(t, err, nil, "list should return no database errors")
+	}
+	if len(got) != 2 {
+		AssertError(t, len(got), 2, "list should include expired consents if requested")
+	}
+
+	got, err = store.ConsentManager.List(ctx, storage.ListConsentsRequest{ClientID: expected.ClientID})
+	if err != nil {
+		AssertFatal(t, err, nil, "list should return no database errors")
+	}
+	if len(got) != 2 {
+		AssertError(t, len(got), 2, "list should filter by client")
+	}
+}
+
+func TestConsentManager_Revoke(t *testing.T) {
+	store, ctx, teardown := setup(t)
+	defer teardown()
+
+	expected := grantConsent(ctx, t, store, expectedConsent(uuid.NewString(), uuid.NewString()))
+
+	err := store.ConsentManager.Revoke(ctx, expected.UserID, expected.ClientID, false)
+	if err != nil {
+		AssertFatal(t, err, nil, "revoke should return no database errors")
+	}
+
+	_, err = store.ConsentManager.Get(ctx, expected.UserID, expected.ClientID)
+	if !errors.Is(err, storage.ErrNotFound) {
+		AssertError(t, err, storage.ErrNotFound, "revoked consent should be deleted")
+	}
+
+	err = store.ConsentManager.Revoke(ctx, expected.UserID, expected.ClientID, false)
+	if !errors.Is(err, storage.ErrNotFound) {
+		AssertError(t, err, storage.ErrNotFound, "revoking a missing consent should return not found")
+	}
+}
+
+func TestConsentManager_Revoke_ShouldRevokeTokens(t *testing.T) {
+	store, ctx, teardown := setup(t)
+	defer teardown()
+
+	client := createClient(ctx, t, store)
+	subject := uuid.NewString()
+	now := time.Now()
+	createActiveSession(ctx, t, store, client, subject, now.Add(time.Hour), now.Add(24*time.Hour))
+
+	// Tokens of other users are retained.
+	other := uuid.NewString()
+	createActiveSession(ctx, t, store, client, other, now.Add(time.Hour), now.Add(24*time.Hour))
+
+	expected := grantConsent(ctx, t, store, expectedConsent(subject, client.ID))
+	err := store.ConsentManager.Revoke(ctx, expected.UserID, expected.ClientID, true)
+	if err != nil {
+		AssertFatal(t, err, nil, "revoke should return no database errors")
+	}
+
+	for _, entityName := range []string{storage.EntityAccessTokens, storage.EntityRefreshTokens} {
+		tokens, err := store.RequestManager.List(ctx, entityName, storage.ListRequestsRequest{ClientID: client.ID, UserID: subject})
+		if err != nil {
+			AssertFatal(t, err, nil, "list should return no database errors")
+		}
+		if len(tokens) != 0 {
+			AssertError(t, len(tokens), 0, "revoke should delete the user's tokens from "+entityName)
+		}
+
+		tokens, err = store.RequestManager.List(ctx, entityName, storage.ListRequestsRequest{ClientID: client.ID, UserID: other})
+		if err != nil {
+			AssertFatal(t, err, nil, "list should return no database errors")
+		}
+		if len(tokens) != 1 {
+			AssertError(t, len(tokens), 1, "revoke should retain other users' tokens in "+entityName)
+		}
+	}
+
+	_, err = store.ConsentManager.Get(ctx, expected.UserID, expected.ClientID)
+	if !errors.Is(err, storage.ErrNotFound) {
+		AssertError(t, err, storage.ErrNotFound, "revoked consent should be deleted")
+	}
+}
@@ -0,0 +1,471 @@
+package mongo
+
+import (
+	// Standard Library Imports
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	// External Imports
+	"github.com/google/uuid"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	// Internal Imports
+	"github.com/matthewhartstonge/storage"
+)
+
+// DeviceCodeManager provides a mongo backed implementation for OAuth 2.0
+// Device Authorization Grant (RFC 8628) sessions.
+//
+// Implements:
+// - storage.Configurer
+// - storage.DeviceCodeStorer
+// - storage.DeviceCodeManager
+type DeviceCodeManager struct {
+	DB     *DB
+	Logger Logger
+}
+
+// Configure implements storage.Configurer.
+func (d *DeviceCodeManager) Configure(ctx context.Context) (err error) {
+	log := newLogger(ctx, d.Logger, Fields{
+		"package":    "mongo",
+		"collection": storage.EntityDeviceCodes,
+		"method":     "Configure",
+	})
+
+	indices := []mongo.IndexModel{
+		{
+			Keys: bson.D{
+				{
+					Key:   "id",
+					Value: int32(1),
+				},
+			},
+			Options: options.Index().
+				SetName(IdxDeviceCodeID).
+				SetBackground(true).
+				SetSparse(true).
+				SetUnique(true),
+		},
+		{
+			Keys: bson.D{
+				{
+					Key:   "signature",
+					Value: int32(1),
+				},
+			},
+			Options: options.Index().
+				SetName(IdxSignatureID).
+				SetBackground(true).
+				SetSparse(true).
+				SetUnique(true),
+		},
+		{
+			// User codes are short, so are only required to be unique while
+			// the device code is pending.
+			Keys: bson.D{
+				{
+					Key:   "userCode",
+					Value: int32(1),
+				},
+			},
+			Options: options.Index().
+				SetName(IdxUserCode).
+				SetBackground(true).
+				SetUnique(true).
+				SetPartialFilterExpression(bson.M{
+					"status": storage.DeviceCodeStatusPending,
+				}),
+		},
+	}
+
+	err = d.DB.createIndexes(ctx, storage.EntityDeviceCodes, indices)
+	if err != nil {
+		log.WithError(err).Error(logError)
+		return toStorageError(storage.EntityDeviceCodes, err)
+	}
+
+	return nil
+}
+
+// getConcrete returns the device code matching the query.
+func (d *DeviceCodeManager) getConcrete(ctx context.Context, method string, query bson.M) (result storage.DeviceCode, err error) {
+	log := newLogger(ctx, d.Logger, Fields{
+		"package":    "mongo",
+		"collection": storage.EntityDeviceCodes,
+		"method":     method,
+	})
+
+	// Trace how long the Mongo operation takes to complete.
+	span, ctx := d.DB.traceMongoCall(ctx, DBTrace{
+		Manager:    "DeviceCodeManager",
+		Method:     method,
+		Collection: storage.EntityDeviceCodes,
+		Operation:  "find",
+		Query:      query,
+	})
+	defer span.Finish()
+
+	// Prefer the latest device code, as user codes may be reused once a
+	// device code is no longer pending.
+	opts := options.FindOne().SetSort(bson.D{{Key: "createTime", Value: -1}})
+
+	var deviceCode storage.DeviceCode
+	collection := d.DB.Collection(storage.EntityDeviceCodes)
+	err = collection.FindOne(ctx, query, opts).Decode(&deviceCode)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			log.WithError(err).Debug(logNotFound)
+			return result, storage.NewNotFoundError(storage.EntityDeviceCodes)
+		}
+
+		// Log to StdOut
+		log.WithError(err).Error(logError)
+		// Log to Tracer
+		span.RecordError(err)
+		return result, toStorageError(storage.EntityDeviceCodes, err)
+	}
+
+	return deviceCode, nil
+}
+
+// findOneAndUpdate atomically updates the device code matching the query and
+// returns the updated device code.
+func (d *DeviceCodeManager) findOneAndUpdate(ctx context.Context, method string, query bson.M, update bson.M) (result storage.DeviceCode, err error) {
+	log := newLogger(ctx, d.Logger, Fields{
+		"package":    "mongo",
+		"collection": storage.EntityDeviceCodes,
+		"method":     method,
+	})
+
+	// Trace how long the Mongo operation takes to complete.
+	span, ctx := d.DB.traceMongoCall(ctx, DBTrace{
+		Manager:    "DeviceCodeManager",
+		Method:     method,
+		Collection: storage.EntityDeviceCodes,
+		Operation:  "update",
+		Selector:   query,
+	})
+	defer span.Finish()
+
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+
+	var deviceCode storage.DeviceCode
+	collection := d.DB.Collection(storage.EntityDeviceCodes)
+	err = collection.FindOneAndUpdate(ctx, query, update, opts).Decode(&deviceCode)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			log.WithError(err).Debug(logNotFound)
+			return result, storage.NewNotFoundError(storage.EntityDeviceCodes)
+		}
+
+		// Log to StdOut
+		log.WithError(err).Error(logError)
+		// Log to Tracer
+		span.SetQuery(update)
+		span.RecordError(err)
+		return result, toStorageError(storage.EntityDeviceCodes, err)
+	}
+
+	return deviceCode, nil
+}
+
+// CreateDeviceCodeSession creates a new pending device code and returns the
+// newly created device code. A pending device code that has expired no
+// longer holds its user code.
+func (d *DeviceCodeManager) CreateDeviceCodeSession(ctx context.Context, deviceCode storage.DeviceCode) (result storage.DeviceCode, err error) {
+	// Initialize contextual method logger
+	log := newLogger(ctx, d.Logger, Fields{
+		"package":    "mongo",
+		"collection": storage.EntityDeviceCodes,
+		"method":     "CreateDeviceCodeSession",
+	})
+
+	// Enable developers to provide their own IDs
+	if deviceCode.ID == "" {
+		deviceCode.ID = uuid.NewString()
+	}
+	if deviceCode.CreateTime == 0 {
+		deviceCode.CreateTime = time.Now().Unix()
+	}
+	if deviceCode.Status == "" {
+		deviceCode.Status = storage.DeviceCodeStatusPending
+	}
+	if deviceCode.Interval == 0 {
+		deviceCode.Interval = storage.DefaultDeviceCodeInterval
+	}
+	if deviceCode.Request.RequestedAt.IsZero() {
+		deviceCode.Request.RequestedAt = time.Now()
+	}
+
+	if err = deviceCode.Validate(); err != nil {
+		log.WithError(err).Debug(logInvalid)
+		return result, err
+	}
+
+	// User codes are only unique while pending, so release the user code if
+	// the device code holding it expired without being polled.
+	if err = d.expireUserCode(ctx, deviceCode.UserCode); err != nil {
+		return result, err
+	}
+
+	// Trace how long the Mongo operation takes to complete.
+	span, ctx := d.DB.traceMongoCall(ctx, DBTrace{
+		Manager:    "DeviceCodeManager",
+		Method:     "CreateDeviceCodeSession",
+		Collection: storage.EntityDeviceCodes,
+		Operation:  "insert",
+	})
+	defer span.Finish()
+
+	// Create resource
+	collection := d.DB.Collection(storage.EntityDeviceCodes)
+	_, err = collection.InsertOne(ctx, deviceCode)
+	if err != nil {
+		if isDup(err) {
+			// Log to StdOut
+			log.WithError(err).Debug(logConflict)
+			// Log to Tracer
+			span.RecordError(err)
+			return result, toStorageError(storage.EntityDeviceCodes, err)
+		}
+
+		// Log to StdOut
+		log.WithError(err).Error(logError)
+		// Log to Tracer
+		span.SetQuery(deviceCode)
+		span.RecordError(err)
+		return result, toStorageError(storage.EntityDeviceCodes, err)
+	}
+
+	return deviceCode, nil
+}
+
+// expireUserCode marks pending device codes issued with the user code as
+// expired, if they have expired.
+func (d *DeviceCodeManager) expireUserCode(ctx context.Context, userCode string) (err error) {
+	log := newLogger(ctx, d.Logger, Fields{
+		"package":    "mongo",
+		"collection": storage.EntityDeviceCodes,
+		"method":     "expireUserCode",
+	})
+
+	now := time.Now().Unix()
+
+	// Build Query
+	query := bson.M{
+		"userCode":  userCode,
+		"status":    storage.DeviceCodeStatusPending,
+		"expiresAt": bson.M{"$lte": now},
+	}
+
+	// Trace how long the Mongo operation takes to complete.
+	span, ctx := d.DB.traceMongoCall(ctx, DBTrace{
+		Manager:    "DeviceCodeManager",
+		Method:     "expireUserCode",
+		Collection: storage.EntityDeviceCodes,
+		Operation:  "update",
+		Selector:   query,
+	})
+	defer span.Finish()
+
+	collection := d.DB.Collection(storage.EntityDeviceCodes)
+	_, err = collection.UpdateMany(ctx, query, bson.M{
+		"$set": bson.M{
+			"status":     storage.DeviceCodeStatusExpired,
+			"updateTime": now,
+		},
+	})
+	if err != nil {
+		// Log to StdOut
+		log.WithError(err).Error(logError)
+		// Log to Tracer
+		span.RecordError(err)
+		return toStorageError(storage.EntityDeviceCodes, err)
+	}
+
+	return nil
+}
+
+// GetDeviceCodeSession returns the device code by signature.
+func (d *DeviceCodeManager) GetDeviceCodeSession(ctx context.Context, signature string) (result storage.DeviceCode, err error) {
+	return d.getConcrete(ctx, "GetDeviceCodeSession", bson.M{
+		"signature": signature,
+	})
+}
+
+// GetDeviceCodeSessionByUserCode returns the latest device code issued with
+// the user code.
+func (d *DeviceCodeManager) GetDeviceCodeSessionByUserCode(ctx context.Context, userCode string) (result storage.DeviceCode, err error) {
+	return d.getConcrete(ctx, "GetDeviceCodeSessionByUserCode", bson.M{
+		"userCode": userCode,
+	})
+}
+
+// PollDeviceCodeSession records the device polling for the device code and
+// returns the device code, so the device can be told whether the request is
+// still pending, approved, denied or expired. If the device polls too
+// quickly, the polling interval is increased and an error wrapping
+// storage.ErrSlowDown is returned.
+func (d *DeviceCodeManager) PollDeviceCodeSession(ctx context.Context, signature string) (result storage.DeviceCode, err error) {
+	// Initialize contextual method logger
+	log := newLogger(ctx, d.Logger, Fields{
+		"package":    "mongo",
+		"collection": storage.EntityDeviceCodes,
+		"method":     "PollDeviceCodeSession",
+	})
+
+	now := time.Now().Unix()
+
+	// Record the poll, only if the device has waited for the interval.
+	deviceCode, err := d.findOneAndUpdate(ctx, "PollDeviceCodeSession", bson.M{
+		"signature": signature,
+		"$expr": bson.M{
+			"$lte": bson.A{bson.M{"$add": bson.A{"$lastPolledAt", "$interval"}}, now},
+		},
+	}, bson.M{
+		"$set": bson.M{"lastPolledAt": now},
+	})
+	if err != nil {
+		if !errors.Is(err, storage.ErrNotFound) {
+			return result, err
+		}
+
+		// Either the device code doesn't exist, or the device polled too
+		// quickly, in which case it must slow down.
+		deviceCode, err = d.findOneAndUpdate(ctx, "PollDeviceCodeSession", bson.M{
+			"signature": signature,
+		}, bson.M{
+			"$set": bson.M{"lastPolledAt": now},
+			"$inc": bson.M{"interval": storage.DeviceCodeSlowDownInterval},
+		})
+		if err != nil {
+			return result, err
+		}
+
+		log.Debug("device polling too quickly")
+		return deviceCode, storage.NewError(storage.ErrPreconditionFailed, storage.EntityDeviceCodes, storage.ErrSlowDown)
+	}
+
+	if deviceCode.Status == storage.DeviceCodeStatusPending && deviceCode.ExpiresAt <= now {
+		return d.findOneAndUpdate(ctx, "PollDeviceCodeSession", bson.M{
+			"signature": signature,
+			"status":    storage.DeviceCodeStatusPending,
+		}, bson.M{
+			"$set": bson.M{
+				"status":     storage.DeviceCodeStatusExpired,
+				"updateTime": now,
+			},
+		})
+	}
+
+	return deviceCode, nil
+}
+
+// ApproveDeviceCodeSession atomically approves the pending device code issued
+// with the user code, binding the subject to the stored request's UserID.
+func (d *DeviceCodeManager) ApproveDeviceCodeSession(ctx context.Context, userCode string, subject string) (result storage.DeviceCode, err error) {
+	if subject == "" {
+		return result, storage.NewInvalidArgumentError(storage.EntityDeviceCodes, "subject is required", "subject")
+	}
+
+	return d.decide(ctx, "ApproveDeviceCodeSession", userCode, bson.M{
+		"status":         storage.DeviceCodeStatusApproved,
+		"request.userId": subject,
+	})
+}
+
+// DenyDeviceCodeSession atomically denies the pending device code issued with
+// the user code.
+func (d *DeviceCodeManager) DenyDeviceCodeSession(ctx context.Context, userCode string) (result storage.DeviceCode, err error) {
+	return d.decide(ctx, "DenyDeviceCodeSession", userCode, bson.M{
+		"status": storage.DeviceCodeStatusDenied,
+	})
+}
+
+// decide atomically applies the user's decision to the pending device code.
+// If there is no pending, unexpired, device code for the user code, not found
+// is returned if the user code is unknown, otherwise precondition failed.
+func (d *DeviceCodeManager) decide(ctx context.Context, method string, userCode string, set bson.M) (result storage.DeviceCode, err error) {
+	// Initialize contextual method logger
+	log := newLogger(ctx, d.Logger, Fields{
+		"package":    "mongo",
+		"collection": storage.EntityDeviceCodes,
+		"method":     method,
+	})
+
+	now := time.Now().Unix()
+	set["updateTime"] = now
+
+	deviceCode, err := d.findOneAndUpdate(ctx, method, bson.M{
+		"userCode":  userCode,
+		"status":    storage.DeviceCodeStatusPending,
+		"expiresAt": bson.M{"$gt": now},
+	}, bson.M{
+		"$set": set,
+	})
+	if err == nil || !errors.Is(err, storage.ErrNotFound) {
+		return deviceCode, err
+	}
+
+	current, err := d.GetDeviceCodeSessionByUserCode(ctx, userCode)
+	if err != nil {
+		return result, err
+	}
+
+	err = fmt.Errorf("device code is %s", current.Status)
+	if current.Status == storage.DeviceCodeStatusPending {
+		err = errors.New("device code has expired")
+	}
+	log.WithError(err).Debug("device code is no longer pending")
+	return result, storage.NewError(storage.ErrPreconditionFailed, storage.EntityDeviceCodes, err, "status")
+}
+
+// DeleteDeviceCodeSession deletes the device code by signature.
+func (d *DeviceCodeManager) DeleteDeviceCodeSession(ctx context.Context, signature string) (err error) {
+	// Initialize contextual method logger
+	log := newLogger(ctx, d.Logger, Fields{
+		"package":    "mongo",
+		"collection": storage.EntityDeviceCodes,
+		"method":     "DeleteDeviceCodeSession",
+	})
+
+	// Build Query
+	query := bson.M{
+		"signature": signature,
+	}
+
+	// Trace how long the Mongo operation takes to complete.
+	span, ctx := d.DB.traceMongoCall(ctx, DBTrace{
+		Manager:    "DeviceCodeManager",
+		Method:     "DeleteDeviceCodeSession",
+		Collection: storage.EntityDeviceCodes,
+		Operation:  "delete",
+		Query:      query,
+	})
+	defer span.Finish()
+
+	collection := d.DB.Collection(storage.EntityDeviceCodes)
+	res, err := collection.DeleteOne(ctx, query)
+	if err != nil {
+		// Log to StdOut
+		log.WithError(err).Error(logError)
+		// Log to Tracer
+		span.RecordError(err)
+		return toStorageError(storage.EntityDeviceCodes, err)
+	}
+
+	if res.DeletedCount == 0 {
+		// Log to StdOut
+		log.WithError(err).Debug(logNotFound)
+		// Log to Tracer
+		span.RecordError(err)
+		return storage.NewNotFoundError(storage.EntityDeviceCodes)
+	}
+
+	return nil
+}
@@ -0,0 +1,34 @@
+package mongo
+
+import (
+	"testing"
+
+	"github.com/matthewhartstonge/storage"
+)
+
+func TestDeviceCodeMongoManager_ImplementsStorageConfigurer(t *testing.T) {
+	d := &DeviceCodeManager{}
+
+	var i interface{} = d
+	if _, ok := i.(storage.Configurer); !ok {
+		t.Error("DeviceCodeManager does not implement interface storage.Configurer")
+	}
+}
+
+func TestDeviceCodeMongoManager_ImplementsStorageDeviceCodeStorer(t *testing.T) {
+	d := &DeviceCodeManager{}
+
+	var i interface{} = d
+	if _, ok := i.(storage.DeviceCodeStorer); !ok {
+		t.Error("DeviceCodeManager does not implement interface storage.DeviceCodeStorer")
+	}
+}
+
+func TestDeviceCodeMongoManager_ImplementsStorageDeviceCodeManager(t *testing.T) {
+	d := &DeviceCodeManager{}
+
+	var i interface{} = d
+	if _, ok := i.(storage.DeviceCodeManager); !ok {
+		t.Error("DeviceCodeManager does not implement interface storage.DeviceCodeManager")
+	}
+}
@@ -0,0 +1,380 @@
+package mongo_test
+
+import (
+	// Standard Library Imports
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	// External Imports
+	"github.com/google/uuid"
+
+	// Internal Imports
+	"github.com/matthewhartstonge/storage"
+	"github.com/matthewhartstonge/storage/mongo"
+)
+
+func expectedDeviceCode() storage.DeviceCode {
+	request := storage.NewRequest()
+	request.ClientID = uuid.NewString()
+	request.RequestedScope = []string{"urn:test:cats:read"}
+
+	return storage.DeviceCode{
+		Signature: uuid.NewString(),
+		UserCode:  uuid.NewString()[:8],
+		ExpiresAt: time.Now().Add(10 * time.Minute).Unix(),
+		Request:   request,
+	}
+}
+
+func createDeviceCode(ctx context.Context, t *testing.T, store *mongo.Store, deviceCode storage.DeviceCode) storage.DeviceCode {
+	got, err := store.DeviceCodeManager.CreateDeviceCodeSession(ctx, deviceCode)
+	if err != nil {
+		AssertFatal(t, err, nil, "create should return no database errors")
+	}
+
+	return got
+}
+
+func TestDeviceCodeManager_Create(t *testing.T) {
+	store, ctx, teardown := setup(t)
+	defer teardown()
+
+	expected := createDeviceCode(ctx, t, store, expectedDeviceCode())
+	if expected.Status != storage.DeviceCodeStatusPending {
+		AssertError(t, expected.Status, storage.DeviceCodeStatusPending, "create should default the device code to pending")
+	}
+	if expected.Interval != storage.DefaultDeviceCodeInterval {
+		AssertError(t, expected.Interval, storage.DefaultDeviceCodeInterval, "create should default the polling interval")
+	}
+
+	got, err := store.DeviceCodeManager.GetDeviceCodeSession(ctx, expected.Signature)
+	if err != nil {
+		AssertFatal(t, err, nil, "get should return no database errors")
+	}
+	if got.ID != expected.ID || got.UserCode != expected.UserCode || got.Request.ClientID != expected.Request.ClientID {
+		AssertError(t, got, expected, "device code not equal")
+	}
+
+	got, err = store.DeviceCodeManager.GetDeviceCodeSessionByUserCode(ctx, expected.UserCode)
+	if err != nil {
+		AssertFatal(t, err, nil, "get by user code should return no database errors")
+	}
+	if got.ID != expected.ID {
+		AssertError(t, got.ID, expected.ID, "get by user code should return the device code")
+	}
+}
+
+func TestDeviceCodeManager_Create_ShouldConflictOnPendingUserCode(t *testing.T) {
+	store, ctx, teardown := setup(t)
+	defer teardown()
+
+	expected := createDeviceCode(ctx, t, store, expectedDeviceCode())
+
+	duplicate := expectedDeviceCode()
+	duplicate.UserCode = expected.UserCode
+	_, err := store.DeviceCodeManager.CreateDeviceCodeSession(ctx, duplicate)
+	if !errors.Is(err, storage.ErrResourceExists) {
+		AssertError(t, err, storage.ErrResourceExists, "create should conflict on a pending user code")
+	}
+}
+
+func TestDeviceCodeManager_Create_ShouldReuseExpiredUserCode(t *testing.T) {
+	store, ctx, teardown := setup(t)
+	defer teardown()
+
+	deviceCode := expectedDeviceCode()
+	deviceCode.CreateTime = time.Now().Add(-11 * time.Minute).Unix()
+	deviceCode.ExpiresAt = time.Now().Add(-time.Minute).Unix()
+	expired := createDeviceCode(ctx, t, store, deviceCode)
+
+	reissued := expectedDeviceCode()
+	reissued.UserCode = expired.UserCode
+	expected := createDeviceCode(ctx, t, store, reissued)
+
+	got, err := store.DeviceCodeManager.GetDeviceCodeSessionByUserCode(ctx, expected.UserCode)
+	if err != nil {
+		AssertFatal(t, err, nil, "get by user code should return no database errors")
+	}
+	if got.ID != expected.ID {
+		AssertError(t, got.ID, expected.ID, "get by user code should return the reissued device code")
+	}
+
+	got, err = store.DeviceCodeManager.GetDeviceCodeSession(ctx, expired.Signature)
+	if err != nil {
+		AssertFatal(t, err, nil, "get should return no database errors")
+	}
+	if got.Status != storage.DeviceCodeStatusExpired {
+		AssertError(t, got.Status, storage.DeviceCodeStatusExpired, "create should expire the device code holding the user code")
+	}
+}
+
+func TestDeviceCodeManager_ApproveDeviceCodeSession(t *testing.T) {
+	store, ctx, teardown := setup(t)
+	defer teardown()
+
+	expected := createDeviceCode(ctx, t, store, expectedDeviceCode())
+	subject := uuid.NewString()
+
+	got, err := store.DeviceCodeManager.ApproveDeviceCodeSession(ctx, expected.UserCode, subject)
+	if err != nil {
+		AssertFatal(t, err, nil, "approve should return no database errors")
+	}
+	if got.Status != storage.DeviceCodeStatusApproved {
+		AssertError(t, got.Status, storage.DeviceCodeStatusApproved, "approve should approve the device code")
+	}
+	if got.Request.UserID != subject {
+		AssertError(t, got.Request.UserID, subject, "approve should bind the subject to the request")
+	}
+
+	stored, err := store.DeviceCodeManager.GetDeviceCodeSession(ctx, expected.Signature)
+	if err != nil {
+		AssertFatal(t, err, nil, "get should return no database errors")
+	}
+	if stored.Status != storage.DeviceCodeStatusApproved || stored.Request.UserID != subject {
+		AssertError(t, stored, got, "approval should be stored")
+	}
+
+	_, err = store.DeviceCodeManager.ApproveDeviceCodeSession(ctx, expected.UserCode, uuid.NewString())
+	if !errors.Is(err, storage.ErrPreconditionFailed) {
+		AssertError(t, err, storage.ErrPreconditionFailed, "approving an approved device code should fail")
+	}
+
+	_, err = store.DeviceCodeManager.DenyDeviceCodeSession(ctx, expected.UserCode)
+	if !errors.Is(err, storage.ErrPreconditionFailed) {
+		AssertError(t, err, storage.ErrPreconditionFailed, "denying an approved device code should fail")
+	}
+
+	stored, err = store.DeviceCodeManager.GetDeviceCodeSession(ctx, expected.Signature)
+	if err != nil {
+		AssertFatal(t, err, nil, "get should return no database errors")
+	}
+	if stored.Status != storage.DeviceCodeStatusApproved || stored.Request.UserID != subject {
+		AssertError(t, stored, got, "approval should not be overwritten")
+	}
+}
+
+func TestDeviceCodeManager_ApproveDeviceCodeSession_ShouldDecideOnce(t *testing.T) {
+	store, ctx, teardown := setup(t)
+	defer teardown()
+
+	expected := createDeviceCode(ctx, t, store, expectedDeviceCode())
+
+	// Race approvals and denials, of which exactly one should be applied.
+	const deciders = 10
+	var wg sync.WaitGroup
+	errs := make([]error, deciders)
+	for i := 0; i < deciders; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+
+			// Sessions can't be used concurrently, so decide outside of the
+			// test session.
+			if i%2 == 0 {
+				_, errs[i] = store.DeviceCodeManager.ApproveDeviceCodeSession(context.Background(), expected.UserCode, uuid.NewString())
+			} else {
+				_, errs[i] = store.DeviceCodeManager.DenyDeviceCodeSession(context.Background(), expected.UserCode)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	decided := 0
+	for _, err := range errs {
+		switch {
+		case err == nil:
+			decided++
+		case !errors.Is(err, storage.ErrPreconditionFailed):
+			AssertError(t, err, storage.ErrPreconditionFailed, "losing decisions should fail the precondition")
+		}
+	}
+	if decided != 1 {
+		AssertError(t, decided, 1, "exactly one decision should be applied")
+	}
+}
+
+func TestDeviceCodeManager_ApproveDeviceCodeSession_ShouldRejectExpired(t *testing.T) {
+	store, ctx, teardown := setup(t)
+	defer teardown()
+
+	deviceCode := expectedDeviceCode()
+	deviceCode.ExpiresAt = time.Now().Add(-time.Minute).Unix()
+	expected := createDeviceCode(ctx, t, store, deviceCode)
+
+	_, err := store.DeviceCodeManager.ApproveDeviceCodeSession(ctx, expected.UserCode, uuid.NewString())
+	if !errors.Is(err, storage.ErrPreconditionFailed) {
+		AssertError(t, err, storage.ErrPreconditionFailed, "approving an expired device code should fail")
+	}
+
+	stored, err := store.DeviceCodeManager.GetDeviceCodeSession(ctx, expected.Signature)
+	if err != nil {
+		AssertFatal(t, err, nil, "get should return no database errors")
+	}
+	if stored.Status != storage.DeviceCodeStatusPending || stored.Request.UserID != "" {
+		AssertError(t, stored.Status, storage.DeviceCodeStatusPending, "expired device code should not be approved")
+	}
+}
+
+func TestDeviceCodeManager_ApproveDeviceCodeSession_ShouldValidate(t *testing.T) {
+	store, ctx, teardown := setup(t)
+	defer teardown()
+
+	expected := createDeviceCode(ctx, t, store, expectedDeviceCode())
+
+	_, err := store.DeviceCodeManager.ApproveDeviceCodeSession(ctx, expected.UserCode, "")
+	if !errors.Is(err, storage.ErrInvalidArgument) {
+		AssertError(t, err, storage.ErrInvalidArgument, "approve should require a subject")
+	}
+
+	_, err = store.DeviceCodeManager.ApproveDeviceCodeSession(ctx, uuid.NewString(), uuid.NewString())
+	if !errors.Is(err, storage.ErrNotFound) {
+		AssertError(t, err, storage.ErrNotFound, "approving an unknown user code should return not found")
+	}
+}
+
+func TestDeviceCodeManager_DenyDeviceCodeSession(t *testing.T) {
+	store, ctx, teardown := setup(t)
+	defer teardown()
+
+	expected := createDeviceCode(ctx, t, store, expectedDeviceCode())
+
+	got, err := store.DeviceCodeManager.DenyDeviceCodeSession(ctx, expected.UserCode)
+	if err != nil {
+		AssertFatal(t, err, nil, "deny should return no database errors")
+	}
+	if got.Status != storage.DeviceCodeStatusDenied {
+		AssertError(t, got.Status, storage.DeviceCodeStatusDenied, "deny should deny the device code")
+	}
+
+	_, err = store.DeviceCodeManager.ApproveDeviceCodeSession(ctx, expected.UserCode, uuid.NewString())
+	if !errors.Is(err, storage.ErrPreconditionFailed) {
+		AssertError(t, err, storage.ErrPreconditionFailed, "approving a denied device code should fail")
+	}
+}
+
+func TestDeviceCodeManager_PollDeviceCodeSession(t *testing.T) {
+	store, ctx, teardown := setup(t)
+	defer teardown()
+
+	// The device last polled an interval ago, so is free to poll again.
+	deviceCode := expectedDeviceCode()
+	deviceCode.LastPolledAt = time.Now().Unix() - storage.DefaultDeviceCodeInterval
+	expected := createDeviceCode(ctx, t, store, deviceCode)
+
+	got, err := store.DeviceCodeManager.PollDeviceCodeSession(ctx, expected.Signature)
+	if err != nil {
+		AssertFatal(t, err, nil, "poll should return no errors")
+	}
+	if got.Status != storage.DeviceCodeStatusPending {
+		AssertError(t, got.Status, storage.DeviceCodeStatusPending, "poll should report the device code is pending")
+	}
+	if got.Interval != storage.DefaultDeviceCodeInterval {
+		AssertError(t, got.Interval, storage.DefaultDeviceCodeInterval, "poll should not increase the interval")
+	}
+	if got.LastPolledAt <= expected.LastPolledAt {
+		AssertError(t, got.LastPolledAt, "now", "poll should record when the device polled")
+	}
+}
+
+func TestDeviceCodeManager_PollDeviceCodeSession_ShouldSlowDown(t *testing.T) {
+	store, ctx, teardown := setup(t)
+	defer teardown()
+
+	expected := createDeviceCode(ctx, t, store, expectedDeviceCode())
+
+	// The first poll is always permitted.
+	_, err := store.DeviceCodeManager.PollDeviceCodeSession(ctx, expected.Signature)
+	if err != nil {
+		AssertFatal(t, err, nil, "first poll should return no errors")
+	}
+
+	// Each poll within the interval must slow down further.
+	for i := int64(1); i <= 2; i++ {
+		got, err := store.DeviceCodeManager.PollDeviceCodeSession(ctx, expected.Signature)
+		if !errors.Is(err, storage.ErrSlowDown) || !errors.Is(err, storage.ErrPreconditionFailed) {
+			AssertFatal(t, err, storage.ErrSlowDown, "polling within the interval should slow down")
+		}
+
+		interval := storage.DefaultDeviceCodeInterval + i*storage.DeviceCodeSlowDownInterval
+		if got.Interval != interval {
+			AssertError(t, got.Interval, interval, "slow down should increase the interval")
+		}
+	}
+
+	stored, err := store.DeviceCodeManager.GetDeviceCodeSession(ctx, expected.Signature)
+	if err != nil {
+		AssertFatal(t, err, nil, "get should return no database errors")
+	}
+	if interval := storage.DefaultDeviceCodeInterval + 2*storage.DeviceCodeSlowDownInterval; stored.Interval != interval {
+		AssertError(t, stored.Interval, interval, "increased interval should be stored")
+	}
+}
+
+func TestDeviceCodeManager_PollDeviceCodeSession_ShouldExpire(t *testing.T) {
+	store, ctx, teardown := setup(t)
+	defer teardown()
+
+	deviceCode := expectedDeviceCode()
+	deviceCode.ExpiresAt = time.Now().Add(-time.Minute).Unix()
+	expected := createDeviceCode(ctx, t, store, deviceCode)
+
+	got, err := store.DeviceCodeManager.PollDeviceCodeSession(ctx, expected.Signature)
+	if err != nil {
+		AssertFatal(t, err, nil, "poll should return no errors")
+	}
+	if got.Status != storage.DeviceCodeStatusExpired {
+		AssertError(t, got.Status, storage.DeviceCodeStatusExpired, "poll should expire the device code")
+	}
+
+	stored, err := store.DeviceCodeManager.GetDeviceCodeSession(ctx, expected.Signature)
+	if err != nil {
+		AssertFatal(t, err, nil, "get should return no database errors")
+	}
+	if stored.Status != storage.DeviceCodeStatusExpired {
+		AssertError(t, stored.Status, storage.DeviceCodeStatusExpired, "expiry should be stored")
+	}
+
+	// The user code is free to be reused once the device code is no longer
+	// pending.
+	reissued := expectedDeviceCode()
+	reissued.UserCode = expected.UserCode
+	reissued = createDeviceCode(ctx, t, store, reissued)
+
+	got, err = store.DeviceCodeManager.GetDeviceCodeSessionByUserCode(ctx, expected.UserCode)
+	if err != nil {
+		AssertFatal(t, err, nil, "get by user code should return no database errors")
+	}
+	if got.ID != reissued.ID {
+		AssertError(t, got.ID, reissued.ID, "get by user code should return the latest device code")
+	}
+}
+
+func TestDeviceCodeManager_PollDeviceCodeSession_ShouldReturnNotFound(t *testing.T) {
+	store, ctx, teardown := setup(t)
+	defer teardown()
+
+	_, err := store.DeviceCodeManager.PollDeviceCodeSession(ctx, uuid.NewString())
+	if !errors.Is(err, storage.ErrNotFound) {
+		AssertError(t, err, storage.ErrNotFound, "polling an unknown device code should return not found")
+	}
+}
+
+func TestDeviceCodeManager_DeleteDeviceCodeSession(t *testing.T) {
+	store, ctx, teardown := setup(t)
+	defer teardown()
+
+	expected := createDeviceCode(ctx, t, store, expectedDeviceCode())
+
+	err := store.DeviceCodeManager.DeleteDeviceCodeSession(ctx, expected.Signature)
+	if err != nil {
+		AssertFatal(t, err, nil, "delete should return no database errors")
+	}
+
+	_, err = store.DeviceCodeManager.GetDeviceCodeSession(ctx, expected.Signature)
+	if !errors.Is(err, storage.ErrNotFound) {
+		AssertError(t, err, storage.ErrNotFound, "deleted device code should not be found")
+	}
+}
@@ -0,0 +1,386 @@
+package mongo
+
+import (
+	// Standard Library Imports
+	"context"
+	"errors"
+	"time"
+
+	// External Imports
+	"github.com/google/uuid"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	// Internal Imports
+	"github.com/matthewhartstonge/storage"
+)
+
+// GroupManager provides a mongo backed implementation for group resources,
+// with user membership stored on the user.
+//
+// Implements:
+// - storage.Configurer
+// - storage.GroupStorer
+// - storage.GroupManager
+type GroupManager struct {
+	DB     *DB
+	Logger Logger
+
+	// Scopes, if set, enables strict scopes, validating the scopes granted
+	// to the group are registered on create and update.
+	Scopes storage.ScopeStorer
+}
+
+// Configure implements storage.Configurer.
+func (g *GroupManager) Configure(ctx context.Context) (err error) {
+	log := newLogger(ctx, g.Logger, Fields{
+		"package":    "mongo",
+		"collection": storage.EntityGroups,
+		"method":     "Configure",
+	})
+
+	indices := []mongo.IndexModel{
+		{
+			Keys: bson.D{
+				{
+					Key:   "id",
+					Value: int32(1),
+				},
+			},
+			Options: options.Index().
+				SetName(IdxGroupID).
+				SetBackground(true).
+				SetSparse(true).
+				SetUnique(true),
+		},
+		{
+			Keys: bson.D{
+				{
+					Key:   "name",
+					Value: int32(1),
+				},
+			},
+			Options: options.Index().
+				SetName(IdxGroupName).
+				SetBackground(true).
+				SetSparse(true).
+				SetUnique(true),
+		},
+	}
+
+	err = g.DB.createIndexes(ctx, storage.EntityGroups, indices)
+	if err != nil {
+		log.WithError(err).Error(logError)
+		return toStorageError(storage.EntityGroups, err)
+	}
+
+	return nil
+}
+
+// getConcrete returns a Group resource.
+func (g *GroupManager) getConcrete(ctx context.Context, groupID string) (result storage.Group, err error) {
+	log := newLogger(ctx, g.Logger, Fields{
+		"package":    "mongo",
+		"collection": storage.EntityGroups,
+		"method":     "getConcrete",
+		"groupID":    groupID,
+	})
+
+	// Build Query
+	query := bson.M{
+		"id": groupID,
+	}
+
+	// Trace how long the Mongo operation takes to complete.
+	span, ctx := g.DB.traceMongoCall(ctx, DBTrace{
+		Manager:    "GroupManager",
+		Method:     "getConcrete",
+		Collection: storage.EntityGroups,
+		Operation:  "find",
+		Query:      query,
+	})
+	defer span.Finish()
+
+	var group storage.Group
+	collection := g.DB.Collection(storage.EntityGroups)
+	err = collection.FindOne(ctx, query).Decode(&group)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			log.WithError(err).Debug(logNotFound)
+			return result, storage.NewNotFoundError(storage.EntityGroups)
+		}
+
+		// Log to StdOut
+		log.WithError(err).Error(logError)
+		// Log to Tracer
+		span.RecordError(err)
+		return result, toStorageError(storage.EntityGroups, err)
+	}
+
+	return group, nil
+}
+
+// List returns a list of Group resources that match the provided inputs.
+func (g *GroupManager) List(ctx context.Context, filter storage.ListGroupsRequest) (results []storage.Group, err error) {
+	// Initialize contextual method logger
+	log := newLogger(ctx, g.Logger, Fields{
+		"package":    "mongo",
+		"collection": storage.EntityGroups,
+		"method":     "List",
+	})
+
+	// Build Query
+	query := bson.M{}
+	if len(filter.IDs) > 0 {
+		query["id"] = bson.M{"$in": filter.IDs}
+	}
+	if filter.Name != "" {
+		query["name"] = filter.Name
+	}
+	if filter.Scope != "" {
+		query["scopes"] = filter.Scope
+	}
+	if filter.AllowedTenantAccess != "" {
+		query["allowedTenantAccess"] = filter.AllowedTenantAccess
+	}
+
+	// Trace how long the Mongo operation takes to complete.
+	span, ctx := g.DB.traceMongoCall(ctx, DBTrace{
+		Manager:    "GroupManager",
+		Method:     "List",
+		Collection: storage.EntityGroups,
+		Operation:  "find",
+		Query:      query,
+	})
+	defer span.Finish()
+
+	collection := g.DB.Collection(storage.EntityGroups)
+	cursor, err := collection.Find(ctx, query)
+	if err != nil {
+		// Log to StdOut
+		log.WithError(err).Error(logError)
+		// Log to Tracer
+		span.RecordError(err)
+		return results, toStorageError(storage.EntityGroups, err)
+	}
+
+	var groups []storage.Group
+	err = cursor.All(ctx, &groups)
+	if err != nil {
+		// Log to StdOut
+		log.WithError(err).Error(logError)
+		// Log to Tracer
+		span.RecordError(err)
+		return results, toStorageError(storage.EntityGroups, err)
+	}
+
+	return groups, nil
+}
+
+// Create creates a new Group resource and returns the newly created Group
+// resource.
+func (g *GroupManager) Create(ctx context.Context, group storage.Group) (result storage.Group, err error) {
+	// Initialize contextual method logger
+	log := newLogger(ctx, g.Logger, Fields{
+		"package":    "mongo",
+		"collection": storage.EntityGroups,
+		"method":     "Create",
+	})
+
+	// Enable developers to provide their own IDs
+	if group.ID == "" {
+		group.ID = uuid.NewString()
+	}
+	if group.CreateTime == 0 {
+		group.CreateTime = time.Now().Unix()
+	}
+
+	if err = group.Validate(); err != nil {
+		log.WithError(err).Debug(logInvalid)
+		return result, err
+	}
+
+	err = storage.ValidateScopes(ctx, g.Scopes, storage.EntityGroups, group.Scopes)
+	if err != nil {
+		log.WithError(err).Debug(logInvalid)
+		return result, err
+	}
+
+	// Trace how long the Mongo operation takes to complete.
+	span, ctx := g.DB.traceMongoCall(ctx, DBTrace{
+		Manager:    "GroupManager",
+		Method:     "Create",
+		Collection: storage.EntityGroups,
+		Operation:  "insert",
+	})
+	defer span.Finish()
+
+	// Create resource
+	collection := g.DB.Collection(storage.EntityGroups)
+	_, err = collection.InsertOne(ctx, group)
+	if err != nil {
+		if isDup(err) {
+			// Log to StdOut
+			log.WithError(err).Debug(logConflict)
+			// Log to Tracer
+			span.RecordError(err)
+			return result, toStorageError(storage.EntityGroups, err)
+		}
+
+		// Log to StdOut
+		log.WithError(err).Error(logError)
+		// Log to Tracer
+		span.SetQuery(group)
+		span.RecordError(err)
+		return result, toStorageError(storage.EntityGroups, err)
+	}
+
+	return group, nil
+}
+
+// Get returns the specified Group resource.
+func (g *GroupManager) Get(ctx context.Context, groupID string) (result storage.Group, err error) {
+	return g.getConcrete(ctx, groupID)
+}
+
+// Update updates the Group resource and attributes and returns the updated
+// Group resource.
+func (g *GroupManager) Update(ctx context.Context, groupID string, updatedGroup storage.Group) (result storage.Group, err error) {
+	// Initialize contextual method logger
+	log := newLogger(ctx, g.Logger, Fields{
+		"package":    "mongo",
+		"collection": storage.EntityGroups,
+		"method":     "Update",
+		"id":         groupID,
+	})
+
+	currentResource, err := g.getConcrete(ctx, groupID)
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			log.Debug(logNotFound)
+			return result, err
+		}
+
+		log.WithError(err).Error(logError)
+		return result, err
+	}
+
+	// Deny updating the entity Id
+	updatedGroup.ID = groupID
+	// Retain the create time
+	updatedGroup.CreateTime = currentResource.CreateTime
+	// Update modified time
+	updatedGroup.UpdateTime = time.Now().Unix()
+
+	if err = updatedGroup.Validate(); err != nil {
+		log.WithError(err).Debug(logInvalid)
+		return result, err
+	}
+
+	// Only validate newly granted scopes, so that groups granting scopes
+	// registered before strict scopes was enabled can be updated.
+	addedScopes := difference(updatedGroup.Scopes, currentResource.Scopes)
+	err = storage.ValidateScopes(ctx, g.Scopes, storage.EntityGroups, addedScopes)
+	if err != nil {
+		log.WithError(err).Debug(logInvalid)
+		return result, err
+	}
+
+	// Build Query
+	selector := bson.M{
+		"id": groupID,
+	}
+
+	// Trace how long the Mongo operation takes to complete.
+	span, ctx := g.DB.traceMongoCall(ctx, DBTrace{
+		Manager:    "GroupManager",
+		Method:     "Update",
+		Collection: storage.EntityGroups,
+		Operation:  "update",
+		Selector:   selector,
+	})
+	defer span.Finish()
+
+	collection := g.DB.Collection(storage.EntityGroups)
+	res, err := collection.ReplaceOne(ctx, selector, updatedGroup)
+	if err != nil {
+		// Log to StdOut
+		log.WithError(err).Error(logError)
+		// Log to Tracer
+		span.SetQuery(updatedGroup)
+		span.RecordError(err)
+		return result, toStorageError(storage.EntityGroups, err)
+	}
+
+	if res.MatchedCount == 0 {
+		// Log to StdOut
+		log.WithError(err).Debug(logNotFound)
+		// Log to Tracer
+		span.RecordError(err)
+		return result, storage.NewNotFoundError(storage.EntityGroups)
+	}
+
+	return updatedGroup, nil
+}
+
+// Delete deletes the specified Group resource, removing its members from the
+// group.
+func (g *GroupManager) Delete(ctx context.Context, groupID string) (err error) {
+	// Initialize contextual method logger
+	log := newLogger(ctx, g.Logger, Fields{
+		"package":    "mongo",
+		"collection": storage.EntityGroups,
+		"method":     "Delete",
+		"id":         groupID,
+	})
+
+	// Build Query
+	query := bson.M{
+		"id": groupID,
+	}
+
+	// Trace how long the Mongo operation takes to complete.
+	span, ctx := g.DB.traceMongoCall(ctx, DBTrace{
+		Manager:    "GroupManager",
+		Method:     "Delete",
+		Collection: storage.EntityGroups,
+		Operation:  "delete",
+		Query:      query,
+	})
+	defer span.Finish()
+
+	collection := g.DB.Collection(storage.EntityGroups)
+	res, err := collection.DeleteOne(ctx, query)
+	if err != nil {
+		// Log to StdOut
+		log.WithError(err).Error(logError)
+		// Log to Tracer
+		span.RecordError(err)
+		return toStorageError(storage.EntityGroups, err)
+	}
+
+	if res.DeletedCount == 0 {
+		// Log to StdOut
+		log.WithError(err).Debug(logNotFound)
+		// Log to Tracer
+		span.RecordError(err)
+		return storage.NewNotFoundError(storage.EntityGroups)
+	}
+
+	// Remove the members from the group, so that a group created later with
+	// the same ID isn't inherited.
+	users := g.DB.Collection(storage.EntityUsers)
+	_, err = users.UpdateMany(ctx, bson.M{"groups": groupID}, bson.M{
+		"$pull": bson.M{"groups": groupID},
+	})
+	if err != nil {
+		// Log to StdOut
+		log.WithError(err).Error(logError)
+		// Log to Tracer
+		span.RecordError(err)
+		return toStorageError(storage.EntityUsers, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,34 @@
+package mongo
+
+import (
+	"testing"
+
+	"github.com/matthewhartstonge/storage"
+)
+
+func TestGroupMongoManager_ImplementsStorageConfigurer(t *testing.T) {
+	m := &GroupManager{}
+
+	var i interface{} = m
+	if _, ok := i.(storage.Configurer); !ok {
+		t.Error("GroupManager does not implement interface storage.Configurer")
+	}
+}
+
+func TestGroupMongoManager_ImplementsStorageGroupStorer(t *testing.T) {
+	m := &GroupManager{}
+
+	var i interface{} = m
+	if _, ok := i.(storage.GroupStorer); !ok {
+		t.Error("GroupManager does not implement interface storage.GroupStorer")
+	}
+}
+
+func TestGroupMongoManager_ImplementsStorageGroupManager(t *testing.T) {
+	m := &GroupManager{}
+
+	var i interface{} = m
+	if _, ok := i.(storage.GroupManager); !ok {
+		t.Error("GroupManager does not implement interface storage.GroupManager")
+	}
+}
@@ -0,0 +1,384 @@
+package mongo
+
+import (
+	// Standard Library Imports
+	"context"
+	"errors"
+	"time"
+
+	// External Imports
+	"github.com/google/uuid"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"gopkg.in/square/go-jose.v2"
+
+	// Internal Imports
+	"github.com/matthewhartstonge/storage"
+)
+
+// IssuerTrustManager provides a mongo backed implementation for the trust
+// relationships of JWT issuers, as used by the JWT bearer grant (RFC 7523).
+//
+// Implements:
+// - storage.Configurer
+// - storage.IssuerTrustStorer
+// - storage.IssuerTrustManager
+// - storage.RFC7523KeyStorage
+type IssuerTrustManager struct {
+	DB     *DB
+	Logger Logger
+
+	// Clients provides access to the denied JTI collection, in order to
+	// protect against assertions being replayed, in the same way client
+	// assertions are.
+	Clients storage.ClientStorer
+}
+
+// Configure implements storage.Configurer.
+func (t *IssuerTrustManager) Configure(ctx context.Context) (err error) {
+	log := newLogger(ctx, t.Logger, Fields{
+		"package":    "mongo",
+		"collection": storage.EntityIssuerTrusts,
+		"method":     "Configure",
+	})
+
+	indices := []mongo.IndexModel{
+		{
+			Keys: bson.D{
+				{
+					Key:   "id",
+					Value: int32(1),
+				},
+			},
+			Options: options.Index().
+				SetName(IdxIssuerTrustID).
+				SetBackground(true).
+				SetSparse(true).
+				SetUnique(true),
+		},
+		{
+			Keys: bson.D{
+				{
+					Key:   "issuer",
+					Value: int32(1),
+				},
+				{
+					Key:   "subject",
+					Value: int32(1),
+				},
+				{
+					Key:   "keyId",
+					Value: int32(1),
+				},
+			},
+			Options: options.Index().
+				SetName(IdxCompoundIssuerTrust).
+				SetBackground(true).
+				SetUnique(true),
+		},
+	}
+
+	err = t.DB.createIndexes(ctx, storage.EntityIssuerTrusts, indices)
+	if err != nil {
+		log.WithError(err).Error(logError)
+		return toStorageError(storage.EntityIssuerTrusts, err)
+	}
+
+	return nil
+}
+
+// subjectQuery returns the query matching trust relationships for the
+// subject, including those trusted to assert any subject.
+func subjectQuery(subject string) []bson.M {
+	return []bson.M{
+		{"subject": subject},
+		{"allowAnySubject": true},
+	}
+}
+
+// List returns a list of IssuerTrust resources that match the provided
+// inputs.
+func (t *IssuerTrustManager) List(ctx context.Context, filter storage.ListIssuerTrustsRequest) (results []storage.IssuerTrust, err error) {
+	// Initialize contextual method logger
+	log := newLogger(ctx, t.Logger, Fields{
+		"package":    "mongo",
+		"collection": storage.EntityIssuerTrusts,
+		"method":     "List",
+	})
+
+	// Build Query
+	query := bson.M{}
+	if filter.Issuer != "" {
+		query["issuer"] = filter.Issuer
+	}
+	if filter.Subject != "" {
+		query["$or"] = subjectQuery(filter.Subject)
+	}
+	if !filter.IncludeExpired {
+		query["expiresAt"] = bson.M{"$gt": time.Now().Unix()}
+	}
+
+	// Trace how long the Mongo operation takes to complete.
+	span, ctx := t.DB.traceMongoCall(ctx, DBTrace{
+		Manager:    "IssuerTrustManager",
+		Method:     "List",
+		Collection: storage.EntityIssuerTrusts,
+		Operation:  "find",
+		Query:      query,
+	})
+	defer span.Finish()
+
+	collection := t.DB.Collection(storage.EntityIssuerTrusts)
+	cursor, err := collection.Find(ctx, query)
+	if err != nil {
+		// Log to StdOut
+		log.WithError(err).Error(logError)
+		// Log to Tracer
+		span.RecordError(err)
+		return results, toStorageError(storage.EntityIssuerTrusts, err)
+	}
+
+	var trusts []storage.IssuerTrust
+	err = cursor.All(ctx, &trusts)
+	if err != nil {
+		// Log to StdOut
+		log.WithError(err).Error(logError)
+		// Log to Tracer
+		span.RecordError(err)
+		return results, toStorageError(storage.EntityIssuerTrusts, err)
+	}
+
+	return trusts, nil
+}
+
+// Create creates a new IssuerTrust resource and returns the newly created
+// IssuerTrust resource.
+func (t *IssuerTrustManager) Create(ctx context.Context, trust storage.IssuerTrust) (result storage.IssuerTrust, err error) {
+	// Initialize contextual method logger
+	log := newLogger(ctx, t.Logger, Fields{
+		"package":    "mongo",
+		"collection": storage.EntityIssuerTrusts,
+		"method":     "Create",
+		"issuer":     trust.Issuer,
+		"subject":    trust.Subject,
+	})
+
+	// Enable developers to provide their own IDs
+	if trust.ID == "" {
+		trust.ID = uuid.NewString()
+	}
+	if trust.CreateTime == 0 {
+		trust.CreateTime = time.Now().Unix()
+	}
+
+	if err = trust.Validate(); err != nil {
+		log.WithError(err).Debug(logInvalid)
+		return result, err
+	}
+
+	// Trace how long the Mongo operation takes to complete.
+	span, ctx := t.DB.traceMongoCall(ctx, DBTrace{
+		Manager:    "IssuerTrustManager",
+		Method:     "Create",
+		Collection: storage.EntityIssuerTrusts,
+		Operation:  "insert",
+	})
+	defer span.Finish()
+
+	// Create resource
+	collection := t.DB.Collection(storage.EntityIssuerTrusts)
+	_, err = collection.InsertOne(ctx, trust)
+	if err != nil {
+		if isDup(err) {
+			// Log to StdOut
+			log.WithError(err).Debug(logConflict)
+			// Log to Tracer
+			span.RecordError(err)
+			return result, toStorageError(storage.EntityIssuerTrusts, err)
+		}
+
+		// Log to StdOut
+		log.WithError(err).Error(logError)
+		// Log to Tracer
+		span.RecordError(err)
+		return result, toStorageError(storage.EntityIssuerTrusts, err)
+	}
+
+	return trust, nil
+}
+
+// Get returns the specified IssuerTrust resource.
+func (t *IssuerTrustManager) Get(ctx context.Context, trustID string) (result storage.IssuerTrust, err error) {
+	return t.findOne(ctx, "Get", bson.M{"id": trustID})
+}
+
+// Delete deletes the specified IssuerTrust resource, revoking the issuer's
+// trust.
+func (t *IssuerTrustManager) Delete(ctx context.Context, trustID string) (err error) {
+	// Initialize contextual method logger
+	log := newLogger(ctx, t.Logger, Fields{
+		"package":    "mongo",
+		"collection": storage.EntityIssuerTrusts,
+		"method":     "Delete",
+		"id":         trustID,
+	})
+
+	// Build Query
+	query := bson.M{
+		"id": trustID,
+	}
+
+	// Trace how long the Mongo operation takes to complete.
+	span, ctx := t.DB.traceMongoCall(ctx, DBTrace{
+		Manager:    "IssuerTrustManager",
+		Method:     "Delete",
+		Collection: storage.EntityIssuerTrusts,
+		Operation:  "delete",
+		Query:      query,
+	})
+	defer span.Finish()
+
+	collection := t.DB.Collection(storage.EntityIssuerTrusts)
+	res, err := collection.DeleteOne(ctx, query)
+	if err != nil {
+		// Log to StdOut
+		log.WithError(err).Error(logError)
+		// Log to Tracer
+		span.RecordError(err)
+		return toStorageError(storage.EntityIssuerTrusts, err)
+	}
+
+	if res.DeletedCount == 0 {
+		// Log to StdOut
+		log.WithError(err).Debug(logNotFound)
+		// Log to Tracer
+		span.RecordError(err)
+		return storage.NewNotFoundError(storage.EntityIssuerTrusts)
+	}
+
+	return nil
+}
+
+// GetPublicKey returns the public key, identified by key ID, the issuer is
+// trusted to sign assertions for the subject with.
+func (t *IssuerTrustManager) GetPublicKey(ctx context.Context, issuer string, subject string, keyID string) (*jose.JSONWebKey, error) {
+	trust, err := t.findOne(ctx, "GetPublicKey", trustedKeyQuery(issuer, subject, keyID))
+	if err != nil {
+		return nil, err
+	}
+
+	return trust.JSONWebKey()
+}
+
+// GetPublicKeys returns the public keys the issuer is trusted to sign
+// assertions for the subject with.
+func (t *IssuerTrustManager) GetPublicKeys(ctx context.Context, issuer string, subject string) (*jose.JSONWebKeySet, error) {
+	trusts, err := t.List(ctx, storage.ListIssuerTrustsRequest{
+		Issuer:  issuer,
+		Subject: subject,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(trusts) == 0 {
+		return nil, storage.NewNotFoundError(storage.EntityIssuerTrusts)
+	}
+
+	keys := &jose.JSONWebKeySet{}
+	for _, trust := range trusts {
+		key, err := trust.JSONWebKey()
+		if err != nil {
+			return nil, err
+		}
+		keys.Keys = append(keys.Keys, *key)
+	}
+
+	return keys, nil
+}
+
+// GetPublicKeyScopes returns the scopes the issuer is allowed to request for
+// the subject, when signing with the identified key.
+func (t *IssuerTrustManager) GetPublicKeyScopes(ctx context.Context, issuer string, subject string, keyID string) ([]string, error) {
+	trust, err := t.findOne(ctx, "GetPublicKeyScopes", trustedKeyQuery(issuer, subject, keyID))
+	if err != nil {
+		return nil, err
+	}
+
+	return trust.Scopes, nil
+}
+
+// IsJWTUsed returns whether an unexpired assertion has already been used with
+// the given JTI. Assertion JTIs are stored alongside denied client assertion
+// JTIs.
+func (t *IssuerTrustManager) IsJWTUsed(ctx context.Context, jti string) (bool, error) {
+	err := t.Clients.ClientAssertionJWTValid(ctx, jti)
+	if err != nil {
+		if errors.Is(err, storage.ErrResourceExists) {
+			return true, nil
+		}
+
+		return false, err
+	}
+
+	return false, nil
+}
+
+// MarkJWTUsedForTime marks the assertion's JTI as used until it expires.
+// Expired JTIs are cleaned up in the background by the Janitor.
+func (t *IssuerTrustManager) MarkJWTUsedForTime(ctx context.Context, jti string, exp time.Time) error {
+	return t.Clients.SetClientAssertionJWT(ctx, jti, exp)
+}
+
+// trustedKeyQuery returns the query matching the unexpired trust relationship
+// of the issuer for the subject and key.
+func trustedKeyQuery(issuer string, subject string, keyID string) bson.M {
+	return bson.M{
+		"issuer":    issuer,
+		"keyId":     keyID,
+		"$or":       subjectQuery(subject),
+		"expiresAt": bson.M{"$gt": time.Now().Unix()},
+	}
+}
+
+// findOne returns the IssuerTrust resource matching the query. Where the
+// issuer is trusted for both the subject and any subject, the subject's trust
+// relationship is preferred.
+func (t *IssuerTrustManager) findOne(ctx context.Context, method string, query bson.M) (result storage.IssuerTrust, err error) {
+	// Initialize contextual method logger
+	log := newLogger(ctx, t.Logger, Fields{
+		"package":    "mongo",
+		"collection": storage.EntityIssuerTrusts,
+		"method":     method,
+	})
+
+	// Trace how long the Mongo operation takes to complete.
+	span, ctx := t.DB.traceMongoCall(ctx, DBTrace{
+		Manager:    "IssuerTrustManager",
+		Method:     method,
+		Collection: storage.EntityIssuerTrusts,
+		Operation:  "find",
+		Query:      query,
+	})
+	defer span.Finish()
+
+	opts := options.FindOne().SetSort(bson.D{{Key: "allowAnySubject", Value: 1}})
+
+	var trust storage.IssuerTrust
+	collection := t.DB.Collection(storage.EntityIssuerTrusts)
+	err = collection.FindOne(ctx, query, opts).Decode(&trust)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			log.WithError(err).Debug(logNotFound)
+			return result, storage.NewNotFoundError(storage.EntityIssuerTrusts)
+		}
+
+		// Log to StdOut
+		log.WithError(err).Error(logError)
+		// Log to Tracer
+		span.RecordError(err)
+		return result, toStorageError(storage.EntityIssuerTrusts, err)
+	}
+
+	return trust, nil
+}
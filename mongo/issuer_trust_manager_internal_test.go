@@ -0,0 +1,88 @@
+package mongo
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/matthewhartstonge/storage"
+)
+
+func TestIssuerTrustMongoManager_ImplementsStorageConfigurer(t *testing.T) {
+	m := &IssuerTrustManager{}
+
+	var i interface{} = m
+	if _, ok := i.(storage.Configurer); !ok {
+		t.Error("IssuerTrustManager does not implement interface storage.Configurer")
+	}
+}
+
+func TestIssuerTrustMongoManager_ImplementsStorageIssuerTrustStorer(t *testing.T) {
+	m := &IssuerTrustManager{}
+
+	var i interface{} = m
+	if _, ok := i.(storage.IssuerTrustStorer); !ok {
+		t.Error("IssuerTrustManager does not implement interface storage.IssuerTrustStorer")
+	}
+}
+
+func TestIssuerTrustMongoManager_ImplementsStorageIssuerTrustManager(t *testing.T) {
+	m := &IssuerTrustManager{}
+
+	var i interface{} = m
+	if _, ok := i.(storage.IssuerTrustManager); !ok {
+		t.Error("IssuerTrustManager does not implement interface storage.IssuerTrustManager")
+	}
+}
+
+func TestIssuerTrustMongoManager_ImplementsStorageRFC7523KeyStorage(t *testing.T) {
+	m := &IssuerTrustManager{}
+
+	var i interface{} = m
+	if _, ok := i.(storage.RFC7523KeyStorage); !ok {
+		t.Error("IssuerTrustManager does not implement interface storage.RFC7523KeyStorage")
+	}
+}
+
+// issuerTrustDeniedJTIs provides an in-memory denied JTI storer.
+type issuerTrustDeniedJTIs struct {
+	storage.DeniedJTIStorer
+	jtis map[string]storage.DeniedJTI
+}
+
+func (d *issuerTrustDeniedJTIs) Get(ctx context.Context, jti string) (storage.DeniedJTI, error) {
+	deniedJTI, ok := d.jtis[jti]
+	if !ok {
+		return storage.DeniedJTI{}, storage.NewNotFoundError(storage.EntityJtiDenylist)
+	}
+	return deniedJTI, nil
+}
+
+func TestIssuerTrustManager_IsJWTUsed(t *testing.T) {
+	now := time.Now()
+	deniedJTIs := &issuerTrustDeniedJTIs{jtis: map[string]storage.DeniedJTI{
+		"used":    storage.NewDeniedJTI("used", now.Add(time.Hour)),
+		"expired": storage.NewDeniedJTI("expired", now.Add(-time.Hour)),
+	}}
+	m := &IssuerTrustManager{Clients: &ClientManager{DeniedJTIs: deniedJTIs}}
+
+	tests := []struct {
+		jti      string
+		expected bool
+	}{
+		{jti: "used", expected: true},
+		{jti: "expired", expected: false},
+		{jti: "unknown", expected: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.jti, func(t *testing.T) {
+			used, err := m.IsJWTUsed(context.Background(), tt.jti)
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+			if used != tt.expected {
+				t.Errorf("expected used %t, got %t", tt.expected, used)
+			}
+		})
+	}
+}
@@ -0,0 +1,273 @@
+package mongo_test
+
+import (
+	// Standard Library Imports
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	// External Imports
+	"github.com/google/uuid"
+	"gopkg.in/square/go-jose.v2"
+
+	// Internal Imports
+	"github.com/matthewhartstonge/storage"
+	"github.com/matthewhartstonge/storage/mongo"
+)
+
+const testIssuer = "https://idp.example.com"
+
+func expectedIssuerTrust(t *testing.T, subject string, keyID string) storage.IssuerTrust {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		AssertFatal(t, err, nil, "error generating key")
+	}
+
+	private := jose.JSONWebKey{Key: key, KeyID: keyID, Algorithm: string(jose.ES256), Use: "sig"}
+	trust, err := storage.NewIssuerTrust(testIssuer, subject, private.Public(), []string{"urn:test:cats:read"}, time.Now().Add(time.Hour))
+	if err != nil {
+		AssertFatal(t, err, nil, "error building issuer trust")
+	}
+
+	return trust
+}
+
+func createIssuerTrust(ctx context.Context, t *testing.T, store *mongo.Store, trust storage.IssuerTrust) storage.IssuerTrust {
+	got, err := store.IssuerTrustManager.Create(ctx, trust)
+	if err != nil {
+		AssertFatal(t, err, nil, "create should return no database errors")
+	}
+
+	return got
+}
+
+func TestIssuerTrustManager_Create(t *testing.T) {
+	store, ctx, teardown := setup(t)
+	defer teardown()
+
+	expected := createIssuerTrust(ctx, t, store, expectedIssuerTrust(t, "alice", "key-1"))
+
+	got, err := store.IssuerTrustManager.Get(ctx, expected.ID)
+	if err != nil {
+		AssertFatal(t, err, nil, "get should return no database errors")
+	}
+	if !reflect.DeepEqual(got, expected) {
+		AssertError(t, got, expected, "issuer trust not equal")
+	}
+
+	_, err = store.IssuerTrustManager.Create(ctx, expectedIssuerTrust(t, "alice", "key-1"))
+	if !errors.Is(err, storage.ErrResourceExists) {
+		AssertError(t, err, storage.ErrResourceExists, "create should conflict on the same issuer, subject and key")
+	}
+}
+
+func TestIssuerTrustManager_List(t *testing.T) {
+	store, ctx, teardown := setup(t)
+	defer teardown()
+
+	alice := createIssuerTrust(ctx, t, store, expectedIssuerTrust(t, "alice", "key-1"))
+	anySubject := createIssuerTrust(ctx, t, store, expectedIssuerTrust(t, "", "key-2"))
+	createIssuerTrust(ctx, t, store, expectedIssuerTrust(t, "bob", "key-3"))
+
+	got, err := store.IssuerTrustManager.List(ctx, storage.ListIssuerTrustsRequest{
+		Issuer:  testIssuer,
+		Subject: "alice",
+	})
+	if err != nil {
+		AssertFatal(t, err, nil, "list should return no database errors")
+	}
+
+	ids := map[string]bool{}
+	for _, trust := range got {
+		ids[trust.ID] = true
+	}
+	if len(got) != 2 || !ids[alice.ID] || !ids[anySubject.ID] {
+		AssertError(t, got, []string{alice.ID, anySubject.ID}, "list should match the subject and any subject")
+	}
+}
+
+func TestIssuerTrustManager_List_ShouldFilterExpired(t *testing.T) {
+	store, ctx, teardown := setup(t)
+	defer teardown()
+
+	trust := expectedIssuerTrust(t, "alice", "key-1")
+	trust.ExpiresAt = time.Now().Add(-time.Minute).Unix()
+	expired := createIssuerTrust(ctx, t, store, trust)
+
+	got, err := store.IssuerTrustManager.List(ctx, storage.ListIssuerTrustsRequest{Issuer: testIssuer})
+	if err != nil {
+		AssertFatal(t, err, nil, "list should return no database errors")
+	}
+	if len(got) != 0 {
+		AssertError(t, got, nil, "list should filter out expired trusts")
+	}
+
+	got, err = store.IssuerTrustManager.List(ctx, storage.ListIssuerTrustsRequest{
+		Issuer:         testIssuer,
+		IncludeExpired: true,
+	})
+	if err != nil {
+		AssertFatal(t, err, nil, "list should return no database errors")
+	}
+	if len(got) != 1 || got[0].ID != expired.ID {
+		AssertError(t, got, expired, "list should include expired trusts if requested")
+	}
+}
+
+func TestIssuerTrustManager_GetPublicKey(t *testing.T) {
+	store, ctx, teardown := setup(t)
+	defer teardown()
+
+	expected := createIssuerTrust(ctx, t, store, expectedIssuerTrust(t, "alice", "key-1"))
+
+	key, err := store.IssuerTrustManager.GetPublicKey(ctx, testIssuer, "alice", "key-1")
+	if err != nil {
+		AssertFatal(t, err, nil, "get public key should return no database errors")
+	}
+	if key.KeyID != expected.KeyID || !key.IsPublic() {
+		AssertError(t, key, expected.PublicKey, "get public key should return the trusted public key")
+	}
+
+	scopes, err := store.IssuerTrustManager.GetPublicKeyScopes(ctx, testIssuer, "alice", "key-1")
+	if err != nil {
+		AssertFatal(t, err, nil, "get public key scopes should return no database errors")
+	}
+	if !reflect.DeepEqual(scopes, expected.Scopes) {
+		AssertError(t, scopes, expected.Scopes, "get public key scopes should return the trusted scopes")
+	}
+
+	_, err = store.IssuerTrustManager.GetPublicKey(ctx, testIssuer, "bob", "key-1")
+	if !errors.Is(err, storage.ErrNotFound) {
+		AssertError(t, err, storage.ErrNotFound, "get public key should not match other subjects")
+	}
+
+	_, err = store.IssuerTrustManager.GetPublicKey(ctx, "https://other.example.com", "alice", "key-1")
+	if !errors.Is(err, storage.ErrNotFound) {
+		AssertError(t, err, storage.ErrNotFound, "get public key should not match other issuers")
+	}
+}
+
+func TestIssuerTrustManager_GetPublicKey_ShouldPreferSubject(t *testing.T) {
+	store, ctx, teardown := setup(t)
+	defer teardown()
+
+	anySubject := expectedIssuerTrust(t, "", "key-1")
+	anySubject.Scopes = []string{"urn:test:cats:read"}
+	createIssuerTrust(ctx, t, store, anySubject)
+
+	alice := expectedIssuerTrust(t, "alice", "key-1")
+	alice.Scopes = []string{"urn:test:cats:write"}
+	createIssuerTrust(ctx, t, store, alice)
+
+	scopes, err := store.IssuerTrustManager.GetPublicKeyScopes(ctx, testIssuer, "alice", "key-1")
+	if err != nil {
+		AssertFatal(t, err, nil, "get public key scopes should return no database errors")
+	}
+	if !reflect.DeepEqual(scopes, alice.Scopes) {
+		AssertError(t, scopes, alice.Scopes, "the subject's trust should be preferred over any subject")
+	}
+
+	scopes, err = store.IssuerTrustManager.GetPublicKeyScopes(ctx, testIssuer, "bob", "key-1")
+	if err != nil {
+		AssertFatal(t, err, nil, "get public key scopes should return no database errors")
+	}
+	if !reflect.DeepEqual(scopes, anySubject.Scopes) {
+		AssertError(t, scopes, anySubject.Scopes, "other subjects should match the trust for any subject")
+	}
+}
+
+func TestIssuerTrustManager_GetPublicKey_ShouldNotMatchExpired(t *testing.T) {
+	store, ctx, teardown := setup(t)
+	defer teardown()
+
+	trust := expectedIssuerTrust(t, "alice", "key-1")
+	trust.ExpiresAt = time.Now().Add(-time.Minute).Unix()
+	createIssuerTrust(ctx, t, store, trust)
+
+	_, err := store.IssuerTrustManager.GetPublicKey(ctx, testIssuer, "alice", "key-1")
+	if !errors.Is(err, storage.ErrNotFound) {
+		AssertError(t, err, storage.ErrNotFound, "get public key should not match expired trusts")
+	}
+
+	_, err = store.IssuerTrustManager.GetPublicKeys(ctx, testIssuer, "alice")
+	if !errors.Is(err, storage.ErrNotFound) {
+		AssertError(t, err, storage.ErrNotFound, "get public keys should not match expired trusts")
+	}
+}
+
+func TestIssuerTrustManager_GetPublicKeys(t *testing.T) {
+	store, ctx, teardown := setup(t)
+	defer teardown()
+
+	createIssuerTrust(ctx, t, store, expectedIssuerTrust(t, "alice", "key-1"))
+	createIssuerTrust(ctx, t, store, expectedIssuerTrust(t, "", "key-2"))
+	createIssuerTrust(ctx, t, store, expectedIssuerTrust(t, "bob", "key-3"))
+
+	keys, err := store.IssuerTrustManager.GetPublicKeys(ctx, testIssuer, "alice")
+	if err != nil {
+		AssertFatal(t, err, nil, "get public keys should return no database errors")
+	}
+	if len(keys.Keys) != 2 || len(keys.Key("key-1")) != 1 || len(keys.Key("key-2")) != 1 {
+		AssertError(t, keys, []string{"key-1", "key-2"}, "get public keys should return the subject's trusted keys")
+	}
+}
+
+func TestIssuerTrustManager_MarkJWTUsedForTime(t *testing.T) {
+	store, ctx, teardown := setup(t)
+	defer teardown()
+
+	jti := uuid.NewString()
+	used, err := store.IssuerTrustManager.IsJWTUsed(ctx, jti)
+	if err != nil {
+		AssertFatal(t, err, nil, "is jwt used should return no database errors")
+	}
+	if used {
+		AssertError(t, used, false, "an unmarked jti should not be used")
+	}
+
+	err = store.IssuerTrustManager.MarkJWTUsedForTime(ctx, jti, time.Now().Add(time.Hour))
+	if err != nil {
+		AssertFatal(t, err, nil, "mark jwt used should return no database errors")
+	}
+
+	used, err = store.IssuerTrustManager.IsJWTUsed(ctx, jti)
+	if err != nil {
+		AssertFatal(t, err, nil, "is jwt used should return no database errors")
+	}
+	if !used {
+		AssertError(t, used, true, "a marked jti should be used")
+	}
+
+	// Marked JTIs are shared with client assertions.
+	_, err = store.DeniedJTIManager.Get(ctx, jti)
+	if err != nil {
+		AssertError(t, err, nil, "a marked jti should be denied")
+	}
+}
+
+func TestIssuerTrustManager_Delete(t *testing.T) {
+	store, ctx, teardown := setup(t)
+	defer teardown()
+
+	expected := createIssuerTrust(ctx, t, store, expectedIssuerTrust(t, "alice", "key-1"))
+
+	err := store.IssuerTrustManager.Delete(ctx, expected.ID)
+	if err != nil {
+		AssertFatal(t, err, nil, "delete should return no database errors")
+	}
+
+	_, err = store.IssuerTrustManager.GetPublicKey(ctx, testIssuer, "alice", "key-1")
+	if !errors.Is(err, storage.ErrNotFound) {
+		AssertError(t, err, storage.ErrNotFound, "deleted trust should no longer be trusted")
+	}
+
+	err = store.IssuerTrustManager.Delete(ctx, expected.ID)
+	if !errors.Is(err, storage.ErrNotFound) {
+		AssertError(t, err, storage.ErrNotFound, "deleting a missing trust should return not found")
+	}
+}
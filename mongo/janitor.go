@@ -0,0 +1,418 @@
+package mongo
+
+import (
+	// Standard Library Imports
+	"context"
+	"sync"
+	"time"
+
+	// External Imports
+	"github.com/google/uuid"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	// Internal Imports
+	"github.com/matthewhartstonge/storage"
+)
+
+const (
+	// janitorLeaseID provides the ID of the lease held by the janitor, which
+	// ensures only one replica cleans up at a time.
+	janitorLeaseID = "janitor"
+
+	// defaultJanitorInterval provides the default time between janitor runs.
+	defaultJanitorInterval = 10 * time.Minute
+
+	// defaultJanitorBatchSize provides the default number of documents
+	// deleted per batch.
+	defaultJanitorBatchSize = 500
+
+	// defaultJanitorLeaseTTL provides the default time the janitor lease is
+	// held if the janitor is not run on a schedule.
+	defaultJanitorLeaseTTL = 5 * time.Minute
+
+	// defaultJanitorInactiveRetention provides the default time inactive
+	// requests are retained, in order to detect replayed authorization codes.
+	defaultJanitorInactiveRetention = 24 * time.Hour
+)
+
+// DefaultJanitorLifespans returns the default time after which requests
+// expire, by entity, matching fosite's default token lifespans.
+func DefaultJanitorLifespans() map[string]time.Duration {
+	return map[string]time.Duration{
+		storage.EntityAccessTokens:       time.Hour,
+		storage.EntityAuthorizationCodes: 15 * time.Minute,
+		storage.EntityOpenIDSessions:     15 * time.Minute,
+		storage.EntityPARSessions:        storage.DefaultPARLifespan,
+		storage.EntityPKCESessions:       15 * time.Minute,
+		storage.EntityRefreshTokens:      30 * 24 * time.Hour,
+	}
+}
+
+// JanitorReport reports what the janitor deleted in a single run.
+type JanitorReport struct {
+	// StartedAt is when the run started.
+	StartedAt time.Time `json:"startedAt"`
+
+	// Duration contains how long the run took.
+	Duration time.Duration `json:"duration"`
+
+	// Skipped is true if the run was skipped, because another replica holds
+	// the janitor lease.
+	Skipped bool `json:"skipped"`
+
+	// Deleted contains the number of documents deleted, by entity.
+	Deleted map[string]int64 `json:"deleted"`
+
+	// Limited contains the entities where the per run delete limit was
+	// reached, so documents remain to be cleaned up on the next run.
+	Limited []string `json:"limited,omitempty"`
+}
+
+// Total returns the total number of documents deleted.
+func (r JanitorReport) Total() (total int64) {
+	for _, deleted := range r.Deleted {
+		total += deleted
+	}
+
+	return total
+}
+
+// Janitor removes expired denied JTIs, login sessions and device codes, and
+// inactive or expired requests, in the background, so cleanup doesn't add
+// latency to the token endpoint.
+//
+// A lease is held while the janitor runs on a schedule, so only one replica
+// cleans up at a time. If the replica holding the lease stops, the lease
+// expires and another replica takes over.
+//
+// Implements:
+// - storage.Configurer
+type Janitor struct {
+	DB     *DB
+	Logger Logger
+
+	// Interval specifies how often Run cleans up.
+	Interval time.Duration
+
+	// BatchSize specifies the number of documents deleted per batch.
+	// Defaults to 500.
+	BatchSize int64
+
+	// MaxDeletesPerRun limits the number of documents deleted from each
+	// collection per run, in order to limit load on the database. If zero,
+	// there is no limit.
+	MaxDeletesPerRun int64
+
+	// Lifespans specifies how long after being requested a request expires,
+	// by entity. Entities without a lifespan aren't expired. If nil,
+	// DefaultJanitorLifespans is used.
+	Lifespans map[string]time.Duration
+
+	// InactiveRetention specifies how long inactive requests are retained
+	// after being invalidated, in order to detect replayed authorization
+	// codes. Defaults to 24 hours.
+	InactiveRetention time.Duration
+
+	// LeaseTTL specifies how long the janitor lease is held, before another
+	// replica can take over. Defaults to twice the Interval.
+	LeaseTTL time.Duration
+
+	// OnReport, if set, is called with the report of each run.
+	OnReport func(JanitorReport)
+
+	ownerOnce sync.Once
+	owner     string
+}
+
+// Configure implements storage.Configurer.
+func (j *Janitor) Configure(ctx context.Context) (err error) {
+	log := newLogger(ctx, j.Logger, Fields{
+		"package":    "mongo",
+		"collection": storage.EntityLeases,
+		"method":     "Configure",
+	})
+
+	indices := []mongo.IndexModel{
+		{
+			Keys: bson.D{
+				{
+					Key:   "id",
+					Value: int32(1),
+				},
+			},
+			Options: options.Index().
+				SetBackground(true).
+				SetName(IdxLeaseID).
+				SetSparse(true).
+				SetUnique(true),
+		},
+	}
+
+	err = j.DB.createIndexes(ctx, storage.EntityLeases, indices)
+	if err != nil {
+		log.WithError(err).Error(logError)
+		return toStorageError(storage.EntityLeases, err)
+	}
+
+	return nil
+}
+
+// lease returns the lease held by the janitor.
+func (j *Janitor) lease() *lease {
+	j.ownerOnce.Do(func() {
+		j.owner = uuid.NewString()
+	})
+
+	return &lease{
+		collection: j.DB.Collection(storage.EntityLeases),
+		id:         janitorLeaseID,
+		owner:      j.owner,
+		ttl:        j.leaseTTL(),
+	}
+}
+
+// leaseTTL returns how long the janitor lease is held.
+func (j *Janitor) leaseTTL() time.Duration {
+	if j.LeaseTTL > 0 {
+		return j.LeaseTTL
+	}
+	if j.Interval > 0 {
+		return 2 * j.Interval
+	}
+
+	return defaultJanitorLeaseTTL
+}
+
+// Run cleans up on each Interval until the context is done, releasing the
+// janitor lease on return.
+func (j *Janitor) Run(ctx context.Context) error {
+	log := newLogger(ctx, j.Logger, Fields{
+		"package": "mongo",
+		"method":  "Janitor.Run",
+	})
+
+	if j.Interval <= 0 {
+		return storage.NewInvalidArgumentError("janitor", "interval must be greater than zero", "Interval")
+	}
+
+	defer func() {
+		// The context is done, so release the lease on a fresh context.
+		ctx, cancel := context.WithTimeout(context.Background(), defaultHealthTimeout)
+		defer cancel()
+
+		if err := j.lease().release(ctx); err != nil {
+			log.WithError(err).Warn("error releasing janitor lease")
+		}
+	}()
+
+	ticker := time.NewTicker(j.Interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := j.RunOnce(ctx); err != nil && ctx.Err() == nil {
+			log.WithError(err).Error("error cleaning up")
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// RunOnce cleans up once, if the janitor lease can be acquired, returning a
+// report of what was deleted.
+func (j *Janitor) RunOnce(ctx context.Context) (report JanitorReport, err error) {
+	log := newLogger(ctx, j.Logger, Fields{
+		"package": "mongo",
+		"method":  "Janitor.RunOnce",
+	})
+
+	report = JanitorReport{
+		StartedAt: time.Now(),
+		Deleted:   map[string]int64{},
+	}
+	defer func() {
+		report.Duration = time.Since(report.StartedAt)
+		if err == nil && j.OnReport != nil {
+			j.OnReport(report)
+		}
+	}()
+
+	acquired, err := j.lease().tryAcquire(ctx)
+	if err != nil {
+		log.WithError(err).Error("error acquiring janitor lease")
+		return report, toStorageError(storage.EntityLeases, err)
+	}
+	if !acquired {
+		log.Debug("janitor lease held by another replica, skipping")
+		report.Skipped = true
+		return report, nil
+	}
+
+	for _, task := range j.tasks(report.StartedAt) {
+		deleted, limited, err := j.deleteBatched(ctx, task.entityName, task.query)
+		report.Deleted[task.entityName] += deleted
+		if limited {
+			report.Limited = append(report.Limited, task.entityName)
+		}
+		if err != nil {
+			log.WithError(err).WithFields(Fields{"collection": task.entityName}).Error(logError)
+			return report, err
+		}
+	}
+
+	log.WithFields(Fields{
+		"deleted": report.Deleted,
+		"total":   report.Total(),
+	}).Info("janitor run complete")
+
+	return report, nil
+}
+
+// janitorTask provides a query for documents to delete from a collection.
+type janitorTask struct {
+	entityName string
+	query      bson.M
+}
+
+// tasks returns the cleanup tasks to run, relative to now.
+func (j *Janitor) tasks(now time.Time) []janitorTask {
+	tasks := []janitorTask{
+		{
+			entityName: storage.EntityJtiDenylist,
+			query: bson.M{
+				"exp": bson.M{"$lt": now.Unix()},
+			},
+		},
+		{
+			entityName: storage.EntityLoginSessions,
+			query: bson.M{
+				"expiresAt": bson.M{"$lt": now.Unix()},
+			},
+		},
+	}
+
+	lifespans := j.Lifespans
+	if lifespans == nil {
+		lifespans = DefaultJanitorLifespans()
+	}
+
+	inactiveRetention := j.InactiveRetention
+	if inactiveRetention == 0 {
+		inactiveRetention = defaultJanitorInactiveRetention
+	}
+
+	// Expired device codes are retained like inactive requests, so devices
+	// polling late are told the device code has expired.
+	tasks = append(tasks, janitorTask{
+		entityName: storage.EntityDeviceCodes,
+		query: bson.M{
+			"expiresAt": bson.M{"$lt": now.Add(-inactiveRetention).Unix()},
+		},
+	})
+
+	entityNames := []string{
+		storage.EntityAccessTokens,
+		storage.EntityAuthorizationCodes,
+		storage.EntityOpenIDSessions,
+		storage.EntityPARSessions,
+		storage.EntityPKCESessions,
+		storage.EntityRefreshTokens,
+	}
+	for _, entityName := range entityNames {
+		conditions := []bson.M{
+			{
+				"active":     false,
+				"updateTime": bson.M{"$lt": now.Add(-inactiveRetention).Unix()},
+			},
+		}
+
+		if lifespan, ok := lifespans[entityName]; ok && lifespan > 0 {
+			conditions = append(conditions, bson.M{
+				"requestedAt": bson.M{"$lt": now.Add(-lifespan)},
+			})
+		}
+
+		tasks = append(tasks, janitorTask{
+			entityName: entityName,
+			query: bson.M{
+				"$or": conditions,
+			},
+		})
+	}
+
+	return tasks
+}
+
+// deleteBatched deletes the documents matching the query in batches, until
+// none remain, or the per run limit is reached.
+func (j *Janitor) deleteBatched(ctx context.Context, entityName string, query bson.M) (deleted int64, limited bool, err error) {
+	// Trace how long the Mongo operation takes to complete.
+	span, ctx := j.DB.traceMongoCall(ctx, DBTrace{
+		Manager:    "Janitor",
+		Method:     "deleteBatched",
+		Collection: entityName,
+		Operation:  "delete",
+		Query:      query,
+	})
+	defer span.Finish()
+
+	batchSize := j.BatchSize
+	if batchSize <= 0 {
+		batchSize = defaultJanitorBatchSize
+	}
+
+	collection := j.DB.Collection(entityName)
+	for {
+		limit := batchSize
+		if j.MaxDeletesPerRun > 0 {
+			remaining := j.MaxDeletesPerRun - deleted
+			if remaining <= 0 {
+				return deleted, true, nil
+			}
+			if remaining < limit {
+				limit = remaining
+			}
+		}
+
+		opts := options.Find().
+			SetProjection(bson.M{"_id": 1}).
+			SetLimit(limit)
+		cursor, err := collection.Find(ctx, query, opts)
+		if err != nil {
+			span.RecordError(err)
+			return deleted, false, toStorageError(entityName, err)
+		}
+
+		var batch []struct {
+			ID interface{} `bson:"_id"`
+		}
+		if err = cursor.All(ctx, &batch); err != nil {
+			span.RecordError(err)
+			return deleted, false, toStorageError(entityName, err)
+		}
+		if len(batch) == 0 {
+			return deleted, false, nil
+		}
+
+		ids := make([]interface{}, len(batch))
+		for i, doc := range batch {
+			ids[i] = doc.ID
+		}
+
+		res, err := collection.DeleteMany(ctx, bson.M{"_id": bson.M{"$in": ids}})
+		if err != nil {
+			span.RecordError(err)
+			return deleted, false, toStorageError(entityName, err)
+		}
+		deleted += res.DeletedCount
+
+		if int64(len(batch)) < limit {
+			return deleted, false, nil
+		}
+	}
+}
@@ -0,0 +1,164 @@
+package mongo
+
+import (
+	// Standard Library Imports
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	// External Imports
+	"go.mongodb.org/mongo-driver/bson"
+
+	// Internal Imports
+	"github.com/matthewhartstonge/storage"
+)
+
+func TestJanitor_ImplementsStorageConfigurer(t *testing.T) {
+	j := &Janitor{}
+
+	var i interface{} = j
+	if _, ok := i.(storage.Configurer); !ok {
+		t.Error("Janitor does not implement interface storage.Configurer")
+	}
+}
+
+func TestJanitorReport_Total(t *testing.T) {
+	report := JanitorReport{
+		Deleted: map[string]int64{
+			storage.EntityJtiDenylist:  3,
+			storage.EntityAccessTokens: 4,
+		},
+	}
+
+	if got := report.Total(); got != 7 {
+		t.Errorf("expected total 7, got %d", got)
+	}
+}
+
+func TestJanitor_Run_ShouldRequireInterval(t *testing.T) {
+	j := &Janitor{}
+
+	err := j.Run(context.Background())
+	if !errors.Is(err, storage.ErrInvalidArgument) {
+		t.Errorf("expected invalid argument error, got %v", err)
+	}
+}
+
+func TestJanitor_tasks_Default(t *testing.T) {
+	j := &Janitor{}
+	now := time.Now()
+
+	tasks := j.tasks(now)
+	if len(tasks) != 9 {
+		t.Fatalf("expected 9 tasks, got %d", len(tasks))
+	}
+
+	jtis := tasks[0]
+	if jtis.entityName != storage.EntityJtiDenylist {
+		t.Errorf("expected first task to clean up %s, got %s", storage.EntityJtiDenylist, jtis.entityName)
+	}
+	if exp := jtis.query["exp"].(bson.M)["$lt"]; exp != now.Unix() {
+		t.Errorf("expected jtis expired before %d, got %v", now.Unix(), exp)
+	}
+
+	sessions := tasks[1]
+	if sessions.entityName != storage.EntityLoginSessions {
+		t.Errorf("expected second task to clean up %s, got %s", storage.EntityLoginSessions, sessions.entityName)
+	}
+	if exp := sessions.query["expiresAt"].(bson.M)["$lt"]; exp != now.Unix() {
+		t.Errorf("expected login sessions expired before %d, got %v", now.Unix(), exp)
+	}
+
+	deviceCodes := tasks[2]
+	if deviceCodes.entityName != storage.EntityDeviceCodes {
+		t.Errorf("expected third task to clean up %s, got %s", storage.EntityDeviceCodes, deviceCodes.entityName)
+	}
+	if exp, expected := deviceCodes.query["expiresAt"].(bson.M)["$lt"], now.Add(-defaultJanitorInactiveRetention).Unix(); exp != expected {
+		t.Errorf("expected device codes expired before %d, got %v", expected, exp)
+	}
+
+	lifespans := DefaultJanitorLifespans()
+	for _, task := range tasks[3:] {
+		conditions := task.query["$or"].([]bson.M)
+		if len(conditions) != 2 {
+			t.Fatalf("%s: expected 2 conditions, got %d", task.entityName, len(conditions))
+		}
+
+		inactiveBefore := conditions[0]["updateTime"].(bson.M)["$lt"]
+		if expected := now.Add(-defaultJanitorInactiveRetention).Unix(); inactiveBefore != expected {
+			t.Errorf("%s: expected inactive before %d, got %v", task.entityName, expected, inactiveBefore)
+		}
+
+		requestedBefore := conditions[1]["requestedAt"].(bson.M)["$lt"]
+		if expected := now.Add(-lifespans[task.entityName]); requestedBefore != expected {
+			t.Errorf("%s: expected requested before %v, got %v", task.entityName, expected, requestedBefore)
+		}
+	}
+}
+
+func TestJanitor_tasks_CustomLifespans(t *testing.T) {
+	j := &Janitor{
+		Lifespans: map[string]time.Duration{
+			storage.EntityRefreshTokens: time.Hour,
+		},
+		InactiveRetention: time.Minute,
+	}
+	now := time.Now()
+
+	for _, task := range j.tasks(now)[3:] {
+		conditions := task.query["$or"].([]bson.M)
+
+		inactiveBefore := conditions[0]["updateTime"].(bson.M)["$lt"]
+		if expected := now.Add(-time.Minute).Unix(); inactiveBefore != expected {
+			t.Errorf("%s: expected inactive before %d, got %v", task.entityName, expected, inactiveBefore)
+		}
+
+		if task.entityName != storage.EntityRefreshTokens {
+			if len(conditions) != 1 {
+				t.Errorf("%s: expected requests without a lifespan not to expire", task.entityName)
+			}
+			continue
+		}
+
+		if len(conditions) != 2 {
+			t.Fatalf("%s: expected 2 conditions, got %d", task.entityName, len(conditions))
+		}
+		requestedBefore := conditions[1]["requestedAt"].(bson.M)["$lt"]
+		if expected := now.Add(-time.Hour); requestedBefore != expected {
+			t.Errorf("%s: expected requested before %v, got %v", task.entityName, expected, requestedBefore)
+		}
+	}
+}
+
+func TestJanitor_leaseTTL(t *testing.T) {
+	tests := []struct {
+		name     string
+		janitor  *Janitor
+		expected time.Duration
+	}{
+		{
+			name:     "default",
+			janitor:  &Janitor{},
+			expected: defaultJanitorLeaseTTL,
+		},
+		{
+			name:     "twice interval",
+			janitor:  &Janitor{Interval: time.Minute},
+			expected: 2 * time.Minute,
+		},
+		{
+			name:     "explicit",
+			janitor:  &Janitor{Interval: time.Minute, LeaseTTL: time.Second},
+			expected: time.Second,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.janitor.leaseTTL(); got != tt.expected {
+				t.Errorf("expected lease ttl %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
@@ -3,11 +3,8 @@ package mongo
 import (
 	// Standard Library Imports
 	"context"
-	"time"
 
 	// External Imports
-	"github.com/ory/fosite"
-	"github.com/sirupsen/logrus"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
@@ -19,12 +16,13 @@ import (
 // DeniedJtiManager provides a mongo backed implementation for denying JSON Web
 // Tokens (JWTs) by ID.
 type DeniedJtiManager struct {
-	DB *DB
+	DB     *DB
+	Logger Logger
 }
 
 // Configure implements storage.Configurer.
 func (d *DeniedJtiManager) Configure(ctx context.Context) (err error) {
-	log := logger.WithFields(logrus.Fields{
+	log := newLogger(ctx, d.Logger, Fields{
 		"package":    "mongo",
 		"collection": storage.EntityJtiDenylist,
 		"method":     "Configure",
@@ -58,19 +56,74 @@ func (d *DeniedJtiManager) Configure(ctx context.Context) (err error) {
 		},
 	}
 
-	collection := d.DB.Collection(storage.EntityJtiDenylist)
-	_, err = collection.Indexes().CreateMany(ctx, indices)
+	err = d.DB.createIndexes(ctx, storage.EntityJtiDenylist, indices)
 	if err != nil {
 		log.WithError(err).Error(logError)
-		return err
+		return toStorageError(storage.EntityJtiDenylist, err)
 	}
 
 	return nil
 }
 
+// List returns a list of denied JTI resources that match the provided
+// inputs.
+func (d *DeniedJtiManager) List(ctx context.Context, filter storage.ListDeniedJTIsRequest) (results []storage.DeniedJTI, err error) {
+	// Initialize contextual method logger
+	log := newLogger(ctx, d.Logger, Fields{
+		"package":    "mongo",
+		"collection": storage.EntityJtiDenylist,
+		"method":     "List",
+	})
+
+	// Build Query
+	query := bson.M{}
+	if filter.ExpiresBefore != 0 || filter.ExpiresAfter != 0 {
+		exp := bson.M{}
+		if filter.ExpiresBefore != 0 {
+			exp["$lt"] = filter.ExpiresBefore
+		}
+		if filter.ExpiresAfter != 0 {
+			exp["$gt"] = filter.ExpiresAfter
+		}
+		query["exp"] = exp
+	}
+
+	// Trace how long the Mongo operation takes to complete.
+	span, ctx := d.DB.traceMongoCall(ctx, DBTrace{
+		Manager:    "DeniedJtiManager",
+		Method:     "List",
+		Collection: storage.EntityJtiDenylist,
+		Operation:  "find",
+		Query:      query,
+	})
+	defer span.Finish()
+
+	collection := d.DB.Collection(storage.EntityJtiDenylist)
+	cursor, err := collection.Find(ctx, query, options.Find().SetSort(bson.M{"exp": 1}))
+	if err != nil {
+		// Log to StdOut
+		log.WithError(err).Error(logError)
+		// Log to Tracer
+		span.RecordError(err)
+		return results, toStorageError(storage.EntityJtiDenylist, err)
+	}
+
+	var deniedJTIs []storage.DeniedJTI
+	err = cursor.All(ctx, &deniedJTIs)
+	if err != nil {
+		// Log to StdOut
+		log.WithError(err).Error(logError)
+		// Log to Tracer
+		span.RecordError(err)
+		return results, toStorageError(storage.EntityJtiDenylist, err)
+	}
+
+	return deniedJTIs, nil
+}
+
 // getConcrete returns a denied jti resource.
 func (d *DeniedJtiManager) getConcrete(ctx context.Context, signature string) (result storage.DeniedJTI, err error) {
-	log := logger.WithFields(logrus.Fields{
+	log := newLogger(ctx, d.Logger, Fields{
 		"package":    "mongo",
 		"collection": storage.EntityJtiDenylist,
 		"method":     "getConcrete",
@@ -83,10 +136,12 @@ func (d *DeniedJtiManager) getConcrete(ctx context.Context, signature string) (r
 	}
 
 	// Trace how long the Mongo operation takes to complete.
-	span, _ := traceMongoCall(ctx, dbTrace{
-		Manager: "DeniedJtiManager",
-		Method:  "getConcrete",
-		Query:   query,
+	span, ctx := d.DB.traceMongoCall(ctx, DBTrace{
+		Manager:    "DeniedJtiManager",
+		Method:     "getConcrete",
+		Collection: storage.EntityJtiDenylist,
+		Operation:  "find",
+		Query:      query,
 	})
 	defer span.Finish()
 
@@ -96,14 +151,14 @@ func (d *DeniedJtiManager) getConcrete(ctx context.Context, signature string) (r
 	if err != nil {
 		if err == mongo.ErrNoDocuments {
 			log.WithError(err).Debug(logNotFound)
-			return result, fosite.ErrNotFound
+			return result, storage.NewNotFoundError(storage.EntityJtiDenylist)
 		}
 
 		// Log to StdOut
 		log.WithError(err).Error(logError)
-		// Log to OpenTracing
-		otLogErr(span, err)
-		return result, err
+		// Log to Tracer
+		span.RecordError(err)
+		return result, toStorageError(storage.EntityJtiDenylist, err)
 	}
 
 	return user, nil
@@ -113,16 +168,18 @@ func (d *DeniedJtiManager) getConcrete(ctx context.Context, signature string) (r
 // resource.
 func (d *DeniedJtiManager) Create(ctx context.Context, deniedJTI storage.DeniedJTI) (result storage.DeniedJTI, err error) {
 	// Initialize contextual method logger
-	log := logger.WithFields(logrus.Fields{
+	log := newLogger(ctx, d.Logger, Fields{
 		"package":    "mongo",
 		"collection": storage.EntityJtiDenylist,
 		"method":     "Create",
 	})
 
 	// Trace how long the Mongo operation takes to complete.
-	span, _ := traceMongoCall(ctx, dbTrace{
-		Manager: "DeniedJtiManager",
-		Method:  "Create",
+	span, ctx := d.DB.traceMongoCall(ctx, DBTrace{
+		Manager:    "DeniedJtiManager",
+		Method:     "Create",
+		Collection: storage.EntityJtiDenylist,
+		Operation:  "insert",
 	})
 	defer span.Finish()
 
@@ -133,29 +190,29 @@ func (d *DeniedJtiManager) Create(ctx context.Context, deniedJTI storage.DeniedJ
 		if isDup(err) {
 			// Log to StdOut
 			log.WithError(err).Debug(logConflict)
-			// Log to OpenTracing
-			otLogErr(span, err)
-			return result, storage.ErrResourceExists
+			// Log to Tracer
+			span.RecordError(err)
+			return result, toStorageError(storage.EntityJtiDenylist, err)
 		}
 
 		// Log to StdOut
 		log.WithError(err).Error(logError)
-		// Log to OpenTracing
-		otLogQuery(span, deniedJTI)
-		otLogErr(span, err)
-		return result, err
+		// Log to Tracer
+		span.SetQuery(deniedJTI)
+		span.RecordError(err)
+		return result, toStorageError(storage.EntityJtiDenylist, err)
 	}
 
 	return deniedJTI, nil
 }
 
-// Get returns the specified User resource.
-func (d *DeniedJtiManager) Get(ctx context.Context, signature string) (result storage.DeniedJTI, err error) {
-	return d.getConcrete(ctx, signature)
+// Get returns the specified denied JTI resource.
+func (d *DeniedJtiManager) Get(ctx context.Context, jti string) (result storage.DeniedJTI, err error) {
+	return d.getConcrete(ctx, storage.SignatureFromJTI(jti))
 }
 
 func (d *DeniedJtiManager) Delete(ctx context.Context, jti string) (err error) {
-	log := logger.WithFields(logrus.Fields{
+	log := newLogger(ctx, d.Logger, Fields{
 		"package":    "mongo",
 		"collection": storage.EntityJtiDenylist,
 		"method":     "Delete",
@@ -168,10 +225,12 @@ func (d *DeniedJtiManager) Delete(ctx context.Context, jti string) (err error) {
 	}
 
 	// Trace how long the Mongo operation takes to complete.
-	span, _ := traceMongoCall(ctx, dbTrace{
-		Manager: "UserManager",
-		Method:  "Delete",
-		Query:   query,
+	span, ctx := d.DB.traceMongoCall(ctx, DBTrace{
+		Manager:    "DeniedJtiManager",
+		Method:     "Delete",
+		Collection: storage.EntityJtiDenylist,
+		Operation:  "delete",
+		Query:      query,
 	})
 	defer span.Finish()
 
@@ -180,17 +239,17 @@ func (d *DeniedJtiManager) Delete(ctx context.Context, jti string) (err error) {
 	if err != nil {
 		// Log to StdOut
 		log.WithError(err).Error(logError)
-		// Log to OpenTracing
-		otLogErr(span, err)
-		return err
+		// Log to Tracer
+		span.RecordError(err)
+		return toStorageError(storage.EntityJtiDenylist, err)
 	}
 
 	if res.DeletedCount == 0 {
 		// Log to StdOut
 		log.WithError(err).Debug(logNotFound)
-		// Log to OpenTracing
-		otLogErr(span, err)
-		return fosite.ErrNotFound
+		// Log to Tracer
+		span.RecordError(err)
+		return storage.NewNotFoundError(storage.EntityJtiDenylist)
 	}
 
 	return nil
@@ -199,7 +258,7 @@ func (d *DeniedJtiManager) Delete(ctx context.Context, jti string) (err error) {
 // DeleteExpired removes all JTIs before the given time. Returns not found if
 // no tokens were found before the given time.
 func (d *DeniedJtiManager) DeleteBefore(ctx context.Context, expBefore int64) (err error) {
-	log := logger.WithFields(logrus.Fields{
+	log := newLogger(ctx, d.Logger, Fields{
 		"package":    "mongo",
 		"collection": storage.EntityJtiDenylist,
 		"method":     "DeleteExpired",
@@ -209,15 +268,17 @@ func (d *DeniedJtiManager) DeleteBefore(ctx context.Context, expBefore int64) (e
 	// Build Query
 	query := bson.M{
 		"exp": bson.M{
-			"$lt": time.Now().Unix(),
+			"$lt": expBefore,
 		},
 	}
 
 	// Trace how long the Mongo operation takes to complete.
-	span, _ := traceMongoCall(ctx, dbTrace{
-		Manager: "UserManager",
-		Method:  "Delete",
-		Query:   query,
+	span, ctx := d.DB.traceMongoCall(ctx, DBTrace{
+		Manager:    "DeniedJtiManager",
+		Method:     "DeleteBefore",
+		Collection: storage.EntityJtiDenylist,
+		Operation:  "delete",
+		Query:      query,
 	})
 	defer span.Finish()
 
@@ -226,17 +287,17 @@ func (d *DeniedJtiManager) DeleteBefore(ctx context.Context, expBefore int64) (e
 	if err != nil {
 		// Log to StdOut
 		log.WithError(err).Error(logError)
-		// Log to OpenTracing
-		otLogErr(span, err)
-		return err
+		// Log to Tracer
+		span.RecordError(err)
+		return toStorageError(storage.EntityJtiDenylist, err)
 	}
 
 	if res.DeletedCount == 0 {
 		// Log to StdOut
 		log.WithError(err).Debug(logNotFound)
-		// Log to OpenTracing
-		otLogErr(span, err)
-		return fosite.ErrNotFound
+		// Log to Tracer
+		span.RecordError(err)
+		return storage.NewNotFoundError(storage.EntityJtiDenylist)
 	}
 
 	return nil
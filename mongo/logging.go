@@ -1,8 +1,8 @@
 package mongo
 
 import (
-	// External Imports
-	"github.com/sirupsen/logrus"
+	// Standard Library Imports
+	"context"
 )
 
 const (
@@ -10,30 +10,89 @@ const (
 	logConflict    = "resource conflict"
 	logNotFound    = "resource not found"
 	logNotHashable = "unable to hash secret"
+	logInvalid     = "invalid resource"
 )
 
-// logger provides the package scoped logger implementation.
-var logger storeLogger
+// Fields provides a set of structured logging fields.
+type Fields map[string]interface{}
 
-// storeLogger provides a wrapper around the logrus logger in order to implement
-// required database library logging interfaces.
-type storeLogger struct {
-	*logrus.Logger
+// Logger provides the minimal structured logging interface used by the store
+// and each of its managers.
+//
+// Adapters are provided for logrus via NewLogrusLogger, `log/slog` styled
+// structured loggers via NewStructuredLogger and a no-op logger via
+// NewNoopLogger.
+type Logger interface {
+	// WithFields returns a logger that includes the given fields in each
+	// log entry.
+	WithFields(fields Fields) Logger
+
+	// WithError returns a logger that includes the given error in each log
+	// entry.
+	WithError(err error) Logger
+
+	Debug(msg string)
+	Info(msg string)
+	Warn(msg string)
+	Error(msg string)
+}
+
+// logFieldsKey provides the context key used to store request scoped log
+// fields.
+type logFieldsKey struct{}
+
+// LogFieldsToContext provides a way to push request scoped log fields, for
+// example, a request or correlation ID, into the current context. Any fields
+// found in the context are added to entries logged by the store's managers.
+// Fields already in the context are retained, unless overridden.
+func LogFieldsToContext(ctx context.Context, fields Fields) context.Context {
+	merged := Fields{}
+	for k, v := range ContextToLogFields(ctx) {
+		merged[k] = v
+	}
+	for k, v := range fields {
+		merged[k] = v
+	}
+
+	return context.WithValue(ctx, logFieldsKey{}, merged)
 }
 
-// SetDebug turns on debug level logging, including debug at the driver level.
-// If false, disables driver level logging and sets logging to info level.
-func SetDebug(isDebug bool) {
-	if isDebug {
-		logger.SetLevel(logrus.DebugLevel)
-	} else {
-		logger.SetLevel(logrus.InfoLevel)
+// ContextToLogFields provides a way to obtain the request scoped log fields,
+// if contained within the presented context.
+func ContextToLogFields(ctx context.Context) Fields {
+	if ctx == nil {
+		return nil
 	}
+
+	fields, _ := ctx.Value(logFieldsKey{}).(Fields)
+	return fields
 }
 
-// SetLogger enables binding in your own customised logrus logger.
-func SetLogger(log *logrus.Logger) {
-	logger = storeLogger{
-		Logger: log,
+// newLogger returns a method scoped logger, binding any request scoped fields
+// found in the context. If no logger is configured, logs are discarded.
+func newLogger(ctx context.Context, log Logger, fields Fields) Logger {
+	if log == nil {
+		log = NewNoopLogger()
+	}
+
+	if ctxFields := ContextToLogFields(ctx); len(ctxFields) > 0 {
+		log = log.WithFields(ctxFields)
 	}
+
+	return log.WithFields(fields)
 }
+
+// NewNoopLogger returns a logger that discards all log entries.
+func NewNoopLogger() Logger {
+	return noopLogger{}
+}
+
+// noopLogger provides a logger that discards all log entries.
+type noopLogger struct{}
+
+func (l noopLogger) WithFields(Fields) Logger { return l }
+func (l noopLogger) WithError(error) Logger   { return l }
+func (noopLogger) Debug(string)               {}
+func (noopLogger) Info(string)                {}
+func (noopLogger) Warn(string)                {}
+func (noopLogger) Error(string)               {}
@@ -0,0 +1,110 @@
+package mongo
+
+import (
+	// Standard Library Imports
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	// External Imports
+	"github.com/sirupsen/logrus"
+	"github.com/sirupsen/logrus/hooks/test"
+)
+
+func TestLogFieldsToContext(t *testing.T) {
+	ctx := LogFieldsToContext(context.Background(), Fields{
+		"requestId": "abc",
+		"tenantId":  "tenant-1",
+	})
+	ctx = LogFieldsToContext(ctx, Fields{
+		"tenantId": "tenant-2",
+	})
+
+	expected := Fields{
+		"requestId": "abc",
+		"tenantId":  "tenant-2",
+	}
+	if got := ContextToLogFields(ctx); !reflect.DeepEqual(got, expected) {
+		t.Errorf("ContextToLogFields() = %#+v, want %#+v", got, expected)
+	}
+
+	if got := ContextToLogFields(context.Background()); got != nil {
+		t.Errorf("ContextToLogFields() = %#+v, want nil", got)
+	}
+}
+
+func TestNewLogger_DefaultsToNoop(t *testing.T) {
+	log := newLogger(context.Background(), nil, Fields{"method": "List"})
+	if _, ok := log.(noopLogger); !ok {
+		t.Errorf("newLogger() = %T, want noopLogger", log)
+	}
+}
+
+func TestNewLogrusLogger(t *testing.T) {
+	base, hook := test.NewNullLogger()
+	base.SetLevel(logrus.DebugLevel)
+
+	ctx := LogFieldsToContext(context.Background(), Fields{"requestId": "abc"})
+	log := newLogger(ctx, NewLogrusLogger(base), Fields{"method": "List"})
+
+	err := errors.New("boom")
+	log.WithError(err).Warn(logError)
+
+	entry := hook.LastEntry()
+	if entry == nil {
+		t.Fatal("expected an entry to be logged")
+	}
+	if entry.Level != logrus.WarnLevel {
+		t.Errorf("level = %v, want %v", entry.Level, logrus.WarnLevel)
+	}
+	if entry.Message != logError {
+		t.Errorf("message = %v, want %v", entry.Message, logError)
+	}
+	expected := logrus.Fields{
+		"requestId":     "abc",
+		"method":        "List",
+		logrus.ErrorKey: err,
+	}
+	if !reflect.DeepEqual(entry.Data, expected) {
+		t.Errorf("fields = %#+v, want %#+v", entry.Data, expected)
+	}
+}
+
+// recordingLogger records the last log line sent to a StructuredLogger.
+type recordingLogger struct {
+	level string
+	msg   string
+	args  []interface{}
+}
+
+func (r *recordingLogger) record(level string, msg string, args []interface{}) {
+	r.level, r.msg, r.args = level, msg, args
+}
+
+func (r *recordingLogger) Debug(msg string, args ...interface{}) { r.record("debug", msg, args) }
+func (r *recordingLogger) Info(msg string, args ...interface{})  { r.record("info", msg, args) }
+func (r *recordingLogger) Warn(msg string, args ...interface{})  { r.record("warn", msg, args) }
+func (r *recordingLogger) Error(msg string, args ...interface{}) { r.record("error", msg, args) }
+
+func TestNewStructuredLogger(t *testing.T) {
+	rec := &recordingLogger{}
+	err := errors.New("boom")
+
+	log := NewStructuredLogger(rec).WithFields(Fields{"method": "Create"})
+	log.WithError(err).Error(logConflict)
+
+	if rec.level != "error" || rec.msg != logConflict {
+		t.Errorf("logged %s %q, want error %q", rec.level, rec.msg, logConflict)
+	}
+	expected := []interface{}{"method", "Create", "error", err}
+	if !reflect.DeepEqual(rec.args, expected) {
+		t.Errorf("args = %#+v, want %#+v", rec.args, expected)
+	}
+
+	// Deriving a logger must not leak fields back into its parent.
+	log.Info("parent")
+	if expected := []interface{}{"method", "Create"}; !reflect.DeepEqual(rec.args, expected) {
+		t.Errorf("args = %#+v, want %#+v", rec.args, expected)
+	}
+}
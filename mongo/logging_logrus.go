@@ -0,0 +1,57 @@
+package mongo
+
+import (
+	// External Imports
+	"github.com/sirupsen/logrus"
+)
+
+// NewLogrusLogger returns a Logger which writes to the given logrus logger,
+// or entry. If log is nil, a new logrus logger is created at info level.
+func NewLogrusLogger(log logrus.FieldLogger) Logger {
+	if log == nil {
+		log = logrus.New()
+	}
+
+	return &logrusLogger{
+		log: log,
+	}
+}
+
+// logrusLogger wraps a logrus logger in order to implement Logger.
+type logrusLogger struct {
+	log logrus.FieldLogger
+}
+
+// WithFields implements Logger.
+func (l *logrusLogger) WithFields(fields Fields) Logger {
+	return &logrusLogger{
+		log: l.log.WithFields(logrus.Fields(fields)),
+	}
+}
+
+// WithError implements Logger.
+func (l *logrusLogger) WithError(err error) Logger {
+	return &logrusLogger{
+		log: l.log.WithError(err),
+	}
+}
+
+// Debug implements Logger.
+func (l *logrusLogger) Debug(msg string) {
+	l.log.Debug(msg)
+}
+
+// Info implements Logger.
+func (l *logrusLogger) Info(msg string) {
+	l.log.Info(msg)
+}
+
+// Warn implements Logger.
+func (l *logrusLogger) Warn(msg string) {
+	l.log.Warn(msg)
+}
+
+// Error implements Logger.
+func (l *logrusLogger) Error(msg string) {
+	l.log.Error(msg)
+}
@@ -0,0 +1,77 @@
+package mongo
+
+// StructuredLogger provides the leveled, key-value pair logging methods
+// implemented by `log/slog`'s `*slog.Logger`, and similarly styled structured
+// loggers.
+type StructuredLogger interface {
+	Debug(msg string, args ...interface{})
+	Info(msg string, args ...interface{})
+	Warn(msg string, args ...interface{})
+	Error(msg string, args ...interface{})
+}
+
+// NewStructuredLogger returns a Logger which writes to the given `log/slog`
+// styled structured logger, for example, `slog.Default()`. Fields are passed
+// through as alternating key-value arguments.
+func NewStructuredLogger(log StructuredLogger) Logger {
+	if log == nil {
+		return NewNoopLogger()
+	}
+
+	return &structuredLogger{
+		log: log,
+	}
+}
+
+// structuredLogger wraps a structured logger in order to implement Logger.
+type structuredLogger struct {
+	log  StructuredLogger
+	args []interface{}
+}
+
+// with returns a copy of the logger with the given key-value pairs appended.
+func (l *structuredLogger) with(args ...interface{}) *structuredLogger {
+	merged := make([]interface{}, 0, len(l.args)+len(args))
+	merged = append(merged, l.args...)
+	merged = append(merged, args...)
+
+	return &structuredLogger{
+		log:  l.log,
+		args: merged,
+	}
+}
+
+// WithFields implements Logger.
+func (l *structuredLogger) WithFields(fields Fields) Logger {
+	args := make([]interface{}, 0, len(fields)*2)
+	for k, v := range fields {
+		args = append(args, k, v)
+	}
+
+	return l.with(args...)
+}
+
+// WithError implements Logger.
+func (l *structuredLogger) WithError(err error) Logger {
+	return l.with("error", err)
+}
+
+// Debug implements Logger.
+func (l *structuredLogger) Debug(msg string) {
+	l.log.Debug(msg, l.args...)
+}
+
+// Info implements Logger.
+func (l *structuredLogger) Info(msg string) {
+	l.log.Info(msg, l.args...)
+}
+
+// Warn implements Logger.
+func (l *structuredLogger) Warn(msg string) {
+	l.log.Warn(msg, l.args...)
+}
+
+// Error implements Logger.
+func (l *structuredLogger) Error(msg string) {
+	l.log.Error(msg, l.args...)
+}
@@ -0,0 +1,379 @@
+package mongo
+
+import (
+	// Standard Library Imports
+	"context"
+	"time"
+
+	// External Imports
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	// Internal Imports
+	"github.com/matthewhartstonge/storage"
+)
+
+// LoginSessionManager provides a mongo backed implementation for user login
+// sessions.
+//
+// Implements:
+// - storage.Configurer
+// - storage.LoginSessionStorer
+// - storage.LoginSessionManager
+type LoginSessionManager struct {
+	DB     *DB
+	Logger Logger
+}
+
+// Configure implements storage.Configurer.
+func (l *LoginSessionManager) Configure(ctx context.Context) (err error) {
+	log := newLogger(ctx, l.Logger, Fields{
+		"package":    "mongo",
+		"collection": storage.EntityLoginSessions,
+		"method":     "Configure",
+	})
+
+	indices := []mongo.IndexModel{
+		{
+			Keys: bson.D{
+				{
+					Key:   "id",
+					Value: int32(1),
+				},
+			},
+			Options: options.Index().
+				SetName(IdxLoginSessionID).
+				SetBackground(true).
+				SetSparse(true).
+				SetUnique(true),
+		},
+		{
+			Keys: bson.D{
+				{
+					Key:   "userId",
+					Value: int32(1),
+				},
+			},
+			Options: options.Index().
+				SetName(IdxUserID).
+				SetBackground(true).
+				SetSparse(true),
+		},
+	}
+
+	err = l.DB.createIndexes(ctx, storage.EntityLoginSessions, indices)
+	if err != nil {
+		log.WithError(err).Error(logError)
+		return toStorageError(storage.EntityLoginSessions, err)
+	}
+
+	return nil
+}
+
+// List returns a list of LoginSession resources that match the provided
+// inputs.
+func (l *LoginSessionManager) List(ctx context.Context, filter storage.ListLoginSessionsRequest) (results []storage.LoginSession, err error) {
+	// Initialize contextual method logger
+	log := newLogger(ctx, l.Logger, Fields{
+		"package":    "mongo",
+		"collection": storage.EntityLoginSessions,
+		"method":     "List",
+	})
+
+	// Build Query
+	query := bson.M{}
+	if filter.UserID != "" {
+		query["userId"] = filter.UserID
+	}
+	if !filter.IncludeExpired {
+		query["expiresAt"] = bson.M{"$gt": time.Now().Unix()}
+	}
+
+	// Trace how long the Mongo operation takes to complete.
+	span, ctx := l.DB.traceMongoCall(ctx, DBTrace{
+		Manager:    "LoginSessionManager",
+		Method:     "List",
+		Collection: storage.EntityLoginSessions,
+		Operation:  "find",
+		Query:      query,
+	})
+	defer span.Finish()
+
+	opts := options.Find().SetSort(bson.D{{Key: "authTime", Value: -1}})
+
+	collection := l.DB.Collection(storage.EntityLoginSessions)
+	cursor, err := collection.Find(ctx, query, opts)
+	if err != nil {
+		// Log to StdOut
+		log.WithError(err).Error(logError)
+		// Log to Tracer
+		span.RecordError(err)
+		return results, toStorageError(storage.EntityLoginSessions, err)
+	}
+
+	var sessions []storage.LoginSession
+	err = cursor.All(ctx, &sessions)
+	if err != nil {
+		// Log to StdOut
+		log.WithError(err).Error(logError)
+		// Log to Tracer
+		span.RecordError(err)
+		return results, toStorageError(storage.EntityLoginSessions, err)
+	}
+
+	return sessions, nil
+}
+
+// Create creates a new LoginSession resource, stored under the hash of the
+// session ID, and returns the newly created LoginSession resource.
+func (l *LoginSessionManager) Create(ctx context.Context, sessionID string, session storage.LoginSession) (result storage.LoginSession, err error) {
+	// Initialize contextual method logger
+	log := newLogger(ctx, l.Logger, Fields{
+		"package":    "mongo",
+		"collection": storage.EntityLoginSessions,
+		"method":     "Create",
+		"userID":     session.UserID,
+	})
+
+	if sessionID == "" {
+		log.Debug(logInvalid)
+		return result, storage.NewInvalidArgumentError(storage.EntityLoginSessions, "session id is required", "id")
+	}
+
+	session.ID = storage.HashLoginSessionID(sessionID)
+	if session.CreateTime == 0 {
+		session.CreateTime = time.Now().Unix()
+	}
+	if session.AuthTime == 0 {
+		session.AuthTime = session.CreateTime
+	}
+
+	if err = session.Validate(); err != nil {
+		log.WithError(err).Debug(logInvalid)
+		return result, err
+	}
+
+	// Trace how long the Mongo operation takes to complete.
+	span, ctx := l.DB.traceMongoCall(ctx, DBTrace{
+		Manager:    "LoginSessionManager",
+		Method:     "Create",
+		Collection: storage.EntityLoginSessions,
+		Operation:  "insert",
+	})
+	defer span.Finish()
+
+	// Create resource
+	collection := l.DB.Collection(storage.EntityLoginSessions)
+	_, err = collection.InsertOne(ctx, session)
+	if err != nil {
+		if isDup(err) {
+			// Log to StdOut
+			log.WithError(err).Debug(logConflict)
+			// Log to Tracer
+			span.RecordError(err)
+			return result, toStorageError(storage.EntityLoginSessions, err)
+		}
+
+		// Log to StdOut
+		log.WithError(err).Error(logError)
+		// Log to Tracer
+		span.RecordError(err)
+		return result, toStorageError(storage.EntityLoginSessions, err)
+	}
+
+	return session, nil
+}
+
+// Get returns the specified LoginSession resource, if it is yet to expire.
+func (l *LoginSessionManager) Get(ctx context.Context, sessionID string) (result storage.LoginSession, err error) {
+	// Initialize contextual method logger
+	log := newLogger(ctx, l.Logger, Fields{
+		"package":    "mongo",
+		"collection": storage.EntityLoginSessions,
+		"method":     "Get",
+	})
+
+	// Build Query
+	query := bson.M{
+		"id":        storage.HashLoginSessionID(sessionID),
+		"expiresAt": bson.M{"$gt": time.Now().Unix()},
+	}
+
+	// Trace how long the Mongo operation takes to complete.
+	span, ctx := l.DB.traceMongoCall(ctx, DBTrace{
+		Manager:    "LoginSessionManager",
+		Method:     "Get",
+		Collection: storage.EntityLoginSessions,
+		Operation:  "find",
+		Query:      query,
+	})
+	defer span.Finish()
+
+	var session storage.LoginSession
+	collection := l.DB.Collection(storage.EntityLoginSessions)
+	err = collection.FindOne(ctx, query).Decode(&session)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			log.WithError(err).Debug(logNotFound)
+			return result, storage.NewNotFoundError(storage.EntityLoginSessions)
+		}
+
+		// Log to StdOut
+		log.WithError(err).Error(logError)
+		// Log to Tracer
+		span.RecordError(err)
+		return result, toStorageError(storage.EntityLoginSessions, err)
+	}
+
+	return session, nil
+}
+
+// Extend atomically extends the unexpired LoginSession resource until the
+// given expiry and returns the extended LoginSession resource.
+func (l *LoginSessionManager) Extend(ctx context.Context, sessionID string, expiresAt int64) (result storage.LoginSession, err error) {
+	// Initialize contextual method logger
+	log := newLogger(ctx, l.Logger, Fields{
+		"package":    "mongo",
+		"collection": storage.EntityLoginSessions,
+		"method":     "Extend",
+	})
+
+	now := time.Now().Unix()
+	if expiresAt <= now {
+		log.Debug(logInvalid)
+		return result, storage.NewInvalidArgumentError(storage.EntityLoginSessions, "expiry must be in the future", "expiresAt")
+	}
+
+	// Build Query
+	selector := bson.M{
+		"id":        storage.HashLoginSessionID(sessionID),
+		"expiresAt": bson.M{"$gt": now},
+	}
+
+	// Trace how long the Mongo operation takes to complete.
+	span, ctx := l.DB.traceMongoCall(ctx, DBTrace{
+		Manager:    "LoginSessionManager",
+		Method:     "Extend",
+		Collection: storage.EntityLoginSessions,
+		Operation:  "update",
+		Selector:   selector,
+	})
+	defer span.Finish()
+
+	update := bson.M{
+		"$set": bson.M{
+			"expiresAt":  expiresAt,
+			"updateTime": now,
+		},
+	}
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+
+	var session storage.LoginSession
+	collection := l.DB.Collection(storage.EntityLoginSessions)
+	err = collection.FindOneAndUpdate(ctx, selector, update, opts).Decode(&session)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			log.WithError(err).Debug(logNotFound)
+			return result, storage.NewNotFoundError(storage.EntityLoginSessions)
+		}
+
+		// Log to StdOut
+		log.WithError(err).Error(logError)
+		// Log to Tracer
+		span.RecordError(err)
+		return result, toStorageError(storage.EntityLoginSessions, err)
+	}
+
+	return session, nil
+}
+
+// Delete deletes the specified LoginSession resource, logging out of the
+// session.
+func (l *LoginSessionManager) Delete(ctx context.Context, sessionID string) (err error) {
+	// Initialize contextual method logger
+	log := newLogger(ctx, l.Logger, Fields{
+		"package":    "mongo",
+		"collection": storage.EntityLoginSessions,
+		"method":     "Delete",
+	})
+
+	// Build Query
+	query := bson.M{
+		"id": storage.HashLoginSessionID(sessionID),
+	}
+
+	// Trace how long the Mongo operation takes to complete.
+	span, ctx := l.DB.traceMongoCall(ctx, DBTrace{
+		Manager:    "LoginSessionManager",
+		Method:     "Delete",
+		Collection: storage.EntityLoginSessions,
+		Operation:  "delete",
+		Query:      query,
+	})
+	defer span.Finish()
+
+	collection := l.DB.Collection(storage.EntityLoginSessions)
+	res, err := collection.DeleteOne(ctx, query)
+	if err != nil {
+		// Log to StdOut
+		log.WithError(err).Error(logError)
+		// Log to Tracer
+		span.RecordError(err)
+		return toStorageError(storage.EntityLoginSessions, err)
+	}
+
+	if res.DeletedCount == 0 {
+		// Log to StdOut
+		log.WithError(err).Debug(logNotFound)
+		// Log to Tracer
+		span.RecordError(err)
+		return storage.NewNotFoundError(storage.EntityLoginSessions)
+	}
+
+	return nil
+}
+
+// DeleteByUser deletes every LoginSession resource of the user, logging the
+// user out of every session, and returns the number of sessions deleted.
+func (l *LoginSessionManager) DeleteByUser(ctx context.Context, userID string) (deleted int64, err error) {
+	// Initialize contextual method logger
+	log := newLogger(ctx, l.Logger, Fields{
+		"package":    "mongo",
+		"collection": storage.EntityLoginSessions,
+		"method":     "DeleteByUser",
+		"userID":     userID,
+	})
+
+	if userID == "" {
+		log.Debug(logInvalid)
+		return 0, storage.NewInvalidArgumentError(storage.EntityLoginSessions, "user id is required", "userId")
+	}
+
+	// Build Query
+	query := bson.M{
+		"userId": userID,
+	}
+
+	// Trace how long the Mongo operation takes to complete.
+	span, ctx := l.DB.traceMongoCall(ctx, DBTrace{
+		Manager:    "LoginSessionManager",
+		Method:     "DeleteByUser",
+		Collection: storage.EntityLoginSessions,
+		Operation:  "delete",
+		Query:      query,
+	})
+	defer span.Finish()
+
+	collection := l.DB.Collection(storage.EntityLoginSessions)
+	res, err := collection.DeleteMany(ctx, query)
+	if err != nil {
+		// Log to StdOut
+		log.WithError(err).Error(logError)
+		// Log to Tracer
+		span.RecordError(err)
+		return 0, toStorageError(storage.EntityLoginSessions, err)
+	}
+
+	return res.DeletedCount, nil
+}
@@ -0,0 +1,34 @@
+package mongo
+
+import (
+	"testing"
+
+	"github.com/matthewhartstonge/storage"
+)
+
+func TestLoginSessionMongoManager_ImplementsStorageConfigurer(t *testing.T) {
+	l := &LoginSessionManager{}
+
+	var i interface{} = l
+	if _, ok := i.(storage.Configurer); !ok {
+		t.Error("LoginSessionManager does not implement interface storage.Configurer")
+	}
+}
+
+func TestLoginSessionMongoManager_ImplementsStorageLoginSessionStorer(t *testing.T) {
+	l := &LoginSessionManager{}
+
+	var i interface{} = l
+	if _, ok := i.(storage.LoginSessionStorer); !ok {
+		t.Error("LoginSessionManager does not implement interface storage.LoginSessionStorer")
+	}
+}
+
+func TestLoginSessionMongoManager_ImplementsStorageLoginSessionManager(t *testing.T) {
+	l := &LoginSessionManager{}
+
+	var i interface{} = l
+	if _, ok := i.(storage.LoginSessionManager); !ok {
+		t.Error("LoginSessionManager does not implement interface storage.LoginSessionManager")
+	}
+}
@@ -0,0 +1,244 @@
+package mongo_test
+
+import (
+	// Standard Library Imports
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	// External Imports
+	"github.com/google/uuid"
+	"go.mongodb.org/mongo-driver/bson"
+
+	// Internal Imports
+	"github.com/matthewhartstonge/storage"
+	"github.com/matthewhartstonge/storage/mongo"
+)
+
+func expectedLoginSession(userID string) storage.LoginSession {
+	now := time.Now()
+	return storage.LoginSession{
+		UserID:    userID,
+		AuthTime:  now.Add(-time.Minute).Unix(),
+		AMR:       []string{"pwd", "otp"},
+		ACR:       "urn:test:acr:mfa",
+		Remember:  true,
+		ExpiresAt: now.Add(time.Hour).Unix(),
+	}
+}
+
+func createLoginSession(ctx context.Context, t *testing.T, store *mongo.Store, session storage.LoginSession) (string, storage.LoginSession) {
+	sessionID := uuid.NewString()
+	got, err := store.LoginSessionManager.Create(ctx, sessionID, session)
+	if err != nil {
+		AssertFatal(t, err, nil, "create should return no database errors")
+	}
+
+	return sessionID, got
+}
+
+func TestLoginSessionManager_Create(t *testing.T) {
+	store, ctx, teardown := setup(t)
+	defer teardown()
+
+	sessionID, expected := createLoginSession(ctx, t, store, expectedLoginSession(uuid.NewString()))
+	if expected.ID != storage.HashLoginSessionID(sessionID) {
+		AssertError(t, expected.ID, storage.HashLoginSessionID(sessionID), "create should identify the session by the hash of the session id")
+	}
+
+	got, err := store.LoginSessionManager.Get(ctx, sessionID)
+	if err != nil {
+		AssertFatal(t, err, nil, "get should return no database errors")
+	}
+	if !reflect.DeepEqual(got, expected) {
+		AssertError(t, got, expected, "login session not equal")
+	}
+
+	// Only the hash of the session ID is stored.
+	count, err := store.DB.Collection(storage.EntityLoginSessions).CountDocuments(ctx, bson.M{"id": sessionID})
+	if err != nil {
+		AssertFatal(t, err, nil, "count should return no database errors")
+	}
+	if count != 0 {
+		AssertError(t, count, 0, "the raw session id should not be stored")
+	}
+}
+
+func TestLoginSessionManager_Create_ShouldValidate(t *testing.T) {
+	store, ctx, teardown := setup(t)
+	defer teardown()
+
+	_, err := store.LoginSessionManager.Create(ctx, "", expectedLoginSession(uuid.NewString()))
+	if !errors.Is(err, storage.ErrInvalidArgument) {
+		AssertError(t, err, storage.ErrInvalidArgument, "create should require a session id")
+	}
+
+	_, err = store.LoginSessionManager.Create(ctx, uuid.NewString(), expectedLoginSession(""))
+	if !errors.Is(err, storage.ErrInvalidArgument) {
+		AssertError(t, err, storage.ErrInvalidArgument, "create should require a user id")
+	}
+}
+
+func TestLoginSessionManager_Get_ShouldNotReturnExpired(t *testing.T) {
+	store, ctx, teardown := setup(t)
+	defer teardown()
+
+	session := expectedLoginSession(uuid.NewString())
+	session.ExpiresAt = time.Now().Add(-time.Minute).Unix()
+	sessionID, _ := createLoginSession(ctx, t, store, session)
+
+	_, err := store.LoginSessionManager.Get(ctx, sessionID)
+	if !errors.Is(err, storage.ErrNotFound) {
+		AssertError(t, err, storage.ErrNotFound, "expired login session should not be found")
+	}
+
+	_, err = store.LoginSessionManager.Get(ctx, uuid.NewString())
+	if !errors.Is(err, storage.ErrNotFound) {
+		AssertError(t, err, storage.ErrNotFound, "unknown login session should not be found")
+	}
+}
+
+func TestLoginSessionManager_List(t *testing.T) {
+	store, ctx, teardown := setup(t)
+	defer teardown()
+
+	userID := uuid.NewString()
+
+	older := expectedLoginSession(userID)
+	older.AuthTime = time.Now().Add(-time.Hour).Unix()
+	_, older = createLoginSession(ctx, t, store, older)
+
+	_, newer := createLoginSession(ctx, t, store, expectedLoginSession(userID))
+
+	expired := expectedLoginSession(userID)
+	expired.ExpiresAt = time.Now().Add(-time.Minute).Unix()
+	_, expired = createLoginSession(ctx, t, store, expired)
+
+	// Sessions of other users are filtered out.
+	createLoginSession(ctx, t, store, expectedLoginSession(uuid.NewString()))
+
+	got, err := store.LoginSessionManager.List(ctx, storage.ListLoginSessionsRequest{UserID: userID})
+	if err != nil {
+		AssertFatal(t, err, nil, "list should return no database errors")
+	}
+	if expected := []storage.LoginSession{newer, older}; !reflect.DeepEqual(got, expected) {
+		AssertError(t, got, expected, "list should return the unexpired sessions, most recently authenticated first")
+	}
+
+	got, err = store.LoginSessionManager.List(ctx, storage.ListLoginSessionsRequest{UserID: userID, IncludeExpired: true})
+	if err != nil {
+		AssertFatal(t, err, nil, "list should return no database errors")
+	}
+	if len(got) != 3 || got[2].ID != expired.ID {
+		AssertError(t, got, expired, "list should include expired sessions if requested")
+	}
+}
+
+func TestLoginSessionManager_Extend(t *testing.T) {
+	store, ctx, teardown := setup(t)
+	defer teardown()
+
+	sessionID, expected := createLoginSession(ctx, t, store, expectedLoginSession(uuid.NewString()))
+
+	expiresAt := time.Now().Add(24 * time.Hour).Unix()
+	got, err := store.LoginSessionManager.Extend(ctx, sessionID, expiresAt)
+	if err != nil {
+		AssertFatal(t, err, nil, "extend should return no database errors")
+	}
+	if got.ExpiresAt != expiresAt {
+		AssertError(t, got.ExpiresAt, expiresAt, "extend should extend the expiry")
+	}
+	if got.AuthTime != expected.AuthTime {
+		AssertError(t, got.AuthTime, expected.AuthTime, "extend should retain the auth time")
+	}
+
+	stored, err := store.LoginSessionManager.Get(ctx, sessionID)
+	if err != nil {
+		AssertFatal(t, err, nil, "get should return no database errors")
+	}
+	if stored.ExpiresAt != expiresAt {
+		AssertError(t, stored.ExpiresAt, expiresAt, "extended expiry should be stored")
+	}
+
+	_, err = store.LoginSessionManager.Extend(ctx, sessionID, time.Now().Add(-time.Minute).Unix())
+	if !errors.Is(err, storage.ErrInvalidArgument) {
+		AssertError(t, err, storage.ErrInvalidArgument, "extend should reject an expiry in the past")
+	}
+}
+
+func TestLoginSessionManager_Extend_ShouldNotExtendExpired(t *testing.T) {
+	store, ctx, teardown := setup(t)
+	defer teardown()
+
+	session := expectedLoginSession(uuid.NewString())
+	session.ExpiresAt = time.Now().Add(-time.Minute).Unix()
+	sessionID, _ := createLoginSession(ctx, t, store, session)
+
+	_, err := store.LoginSessionManager.Extend(ctx, sessionID, time.Now().Add(time.Hour).Unix())
+	if !errors.Is(err, storage.ErrNotFound) {
+		AssertError(t, err, storage.ErrNotFound, "expired login session should not be extended")
+	}
+
+	_, err = store.LoginSessionManager.Get(ctx, sessionID)
+	if !errors.Is(err, storage.ErrNotFound) {
+		AssertError(t, err, storage.ErrNotFound, "expired login session should remain expired")
+	}
+}
+
+func TestLoginSessionManager_Delete(t *testing.T) {
+	store, ctx, teardown := setup(t)
+	defer teardown()
+
+	sessionID, _ := createLoginSession(ctx, t, store, expectedLoginSession(uuid.NewString()))
+
+	err := store.LoginSessionManager.Delete(ctx, sessionID)
+	if err != nil {
+		AssertFatal(t, err, nil, "delete should return no database errors")
+	}
+
+	_, err = store.LoginSessionManager.Get(ctx, sessionID)
+	if !errors.Is(err, storage.ErrNotFound) {
+		AssertError(t, err, storage.ErrNotFound, "logged out session should not be found")
+	}
+
+	err = store.LoginSessionManager.Delete(ctx, sessionID)
+	if !errors.Is(err, storage.ErrNotFound) {
+		AssertError(t, err, storage.ErrNotFound, "logging out twice should return not found")
+	}
+}
+
+func TestLoginSessionManager_DeleteByUser(t *testing.T) {
+	store, ctx, teardown := setup(t)
+	defer teardown()
+
+	userID := uuid.NewString()
+	first, _ := createLoginSession(ctx, t, store, expectedLoginSession(userID))
+	second, _ := createLoginSession(ctx, t, store, expectedLoginSession(userID))
+	other, _ := createLoginSession(ctx, t, store, expectedLoginSession(uuid.NewString()))
+
+	deleted, err := store.LoginSessionManager.DeleteByUser(ctx, userID)
+	if err != nil {
+		AssertFatal(t, err, nil, "delete by user should return no database errors")
+	}
+	if deleted != 2 {
+		AssertError(t, deleted, 2, "every session of the user should be logged out")
+	}
+
+	for _, sessionID := range []string{first, second} {
+		_, err = store.LoginSessionManager.Get(ctx, sessionID)
+		if !errors.Is(err, storage.ErrNotFound) {
+			AssertError(t, err, storage.ErrNotFound, "logged out session should not be found")
+		}
+	}
+
+	if _, err = store.LoginSessionManager.Get(ctx, other); err != nil {
+		AssertError(t, err, nil, "sessions of other users should not be logged out")
+	}
+
+	_, err = store.LoginSessionManager.DeleteByUser(ctx, "")
+	if !errors.Is(err, storage.ErrInvalidArgument) {
+		AssertError(t, err, storage.ErrInvalidArgument, "delete by user should require a user id")
+	}
+}
@@ -0,0 +1,538 @@
+package mongo
+
+import (
+	// Standard Library Imports
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	// External Imports
+	"github.com/google/uuid"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	// Internal Imports
+	"github.com/matthewhartstonge/storage"
+)
+
+const (
+	// migrationLockID provides the ID of the lock document used to stop
+	// concurrent service starts racing to apply migrations.
+	migrationLockID = "lock:migrations"
+
+	// defaultMigrationLockTTL provides the default time a migration lock is
+	// held before it expires.
+	defaultMigrationLockTTL = 5 * time.Minute
+
+	// migrationLockPollInterval provides how often to check whether the
+	// migration lock has been released.
+	migrationLockPollInterval = time.Second
+)
+
+// errMigrationLockLost is returned if the migration lock is held by another
+// instance before a migration could be recorded as applied.
+var errMigrationLockLost = errors.New("migration lock lost to another instance")
+
+// Migration provides a versioned, named schema migration step.
+//
+// Up must be idempotent, so that a migration that was interrupted part way
+// through can safely be run again.
+type Migration struct {
+	// Version orders migrations. Versions must be unique.
+	Version int
+
+	// Name uniquely identifies the migration.
+	Name string
+
+	// Description provides a human readable explanation of what the
+	// migration changes.
+	Description string
+
+	// Up applies the migration.
+	Up func(ctx context.Context, db *DB) error
+}
+
+// toStorage returns the storage representation of the migration.
+func (m Migration) toStorage() storage.Migration {
+	return storage.Migration{
+		Version:     m.Version,
+		Name:        m.Name,
+		Description: m.Description,
+	}
+}
+
+// MigrationManager provides a mongo backed implementation for applying
+// versioned schema migrations.
+//
+// Applied migrations are recorded in the migrations collection. A lock is
+// held in the same collection while migrating so that only one service
+// instance applies migrations at a time.
+//
+// Implements:
+// - storage.Configurer
+// - storage.MigrationStorer
+// - storage.MigrationManager
+type MigrationManager struct {
+	DB     *DB
+	Logger Logger
+
+	// Migrations contains the migrations to apply. If nil, the default
+	// migrations are used.
+	Migrations []Migration
+
+	// DryRun, if true, stops Configure from applying migrations. Instead,
+	// any pending migrations are reported via the logger.
+	DryRun bool
+
+	// LockTTL specifies how long the migration lock is held before it
+	// expires, unless renewed. The lock is renewed while migrations run, so
+	// only needs to outlast a stalled renewal. Defaults to 5 minutes.
+	LockTTL time.Duration
+}
+
+// DefaultMigrations returns the migrations shipped with the store.
+func DefaultMigrations() []Migration {
+	return []Migration{
+		{
+			Version:     1,
+			Name:        "backfillCreateTime",
+			Description: "backfills createTime on clients and users created before it was recorded",
+			Up:          backfillCreateTime,
+		},
+		{
+			Version:     2,
+			Name:        "normalizeUsernames",
+			Description: "normalizes the usernames of users created before usernames were case insensitive, then indexes usernames case insensitively",
+			Up:          normalizeUsernames,
+		},
+	}
+}
+
+// backfillCreateTime sets createTime on clients and users missing it, using
+// updateTime if it has been set, otherwise, the time of migration.
+func backfillCreateTime(ctx context.Context, db *DB) error {
+	now := time.Now().Unix()
+	for _, entityName := range []string{storage.EntityClients, storage.EntityUsers} {
+		collection := db.Collection(entityName)
+		missing := []bson.M{
+			{"createTime": bson.M{"$exists": false}},
+			{"createTime": 0},
+		}
+
+		// Prefer the last update time, if it was recorded.
+		_, err := collection.UpdateMany(ctx,
+			bson.M{"$or": missing, "updateTime": bson.M{"$gt": 0}},
+			mongo.Pipeline{{{Key: "$set", Value: bson.M{"createTime": "$updateTime"}}}},
+		)
+		if err != nil {
+			return err
+		}
+
+		_, err = collection.UpdateMany(ctx,
+			bson.M{"$or": missing},
+			bson.M{"$set": bson.M{"createTime": now}},
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// normalizeUsernames stores usernames in their normalized form, then
+// replaces the case sensitive username index with the case insensitive
+// username index. Usernames that normalize to the same username, for example,
+// by only differing by case, are reported and must be resolved by hand before
+// migrating.
+func normalizeUsernames(ctx context.Context, db *DB) error {
+	collection := db.Collection(storage.EntityUsers)
+	opts := options.Find().SetProjection(bson.M{"_id": 0, "id": 1, "username": 1})
+	cursor, err := collection.Find(ctx, bson.M{"username": bson.M{"$gt": ""}}, opts)
+	if err != nil {
+		return err
+	}
+
+	var users []struct {
+		ID       string `bson:"id"`
+		Username string `bson:"username"`
+	}
+	if err = cursor.All(ctx, &users); err != nil {
+		return err
+	}
+
+	usernames := make(map[string][]string, len(users))
+	for _, user := range users {
+		normalized := storage.NormalizeUsername(user.Username)
+		usernames[normalized] = append(usernames[normalized], user.ID)
+	}
+	if conflicts := usernameConflicts(usernames); len(conflicts) > 0 {
+		return fmt.Errorf("usernames must be unique once normalized, resolve the conflicting users before migrating: %s", strings.Join(conflicts, "; "))
+	}
+
+	for _, user := range users {
+		normalized := storage.NormalizeUsername(user.Username)
+		if normalized == user.Username {
+			continue
+		}
+
+		_, err = collection.UpdateOne(ctx,
+			bson.M{"id": user.ID},
+			bson.M{"$set": bson.M{"username": normalized}},
+		)
+		if err != nil {
+			if isDup(err) {
+				return fmt.Errorf("normalizing username %q of user %s conflicts with another user: %w", user.Username, user.ID, err)
+			}
+			return err
+		}
+	}
+
+	_, err = collection.Indexes().DropOne(ctx, IdxUsername)
+	if err != nil && !hasErrorCode(err, errCodeIndexNotFound) && !hasErrorCode(err, errCodeNamespaceNotFound) {
+		return err
+	}
+
+	return db.createIndexes(ctx, storage.EntityUsers, []mongo.IndexModel{usernameIndex})
+}
+
+// usernameConflicts returns the normalized usernames shared by more than one
+// user, with the IDs of the users sharing them, sorted by username.
+func usernameConflicts(usernames map[string][]string) (conflicts []string) {
+	for username, userIDs := range usernames {
+		if len(userIDs) > 1 {
+			conflicts = append(conflicts, fmt.Sprintf("%q is shared by users %s", username, strings.Join(userIDs, ", ")))
+		}
+	}
+	sort.Strings(conflicts)
+
+	return conflicts
+}
+
+// migrations returns the configured migrations in version order.
+func (m *MigrationManager) migrations() ([]Migration, error) {
+	migrations := m.Migrations
+	if migrations == nil {
+		migrations = DefaultMigrations()
+	}
+
+	sorted := make([]Migration, len(migrations))
+	copy(sorted, migrations)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].Version < sorted[j].Version
+	})
+
+	versions := map[int]bool{}
+	names := map[string]bool{}
+	for _, migration := range sorted {
+		if versions[migration.Version] || names[migration.Name] {
+			return nil, storage.NewInvalidArgumentError(
+				storage.EntityMigrations,
+				fmt.Sprintf("migration %d %q is not unique", migration.Version, migration.Name),
+				"version", "name",
+			)
+		}
+		versions[migration.Version] = true
+		names[migration.Name] = true
+	}
+
+	return sorted, nil
+}
+
+// Configure implements storage.Configurer.
+// Configure applies any pending migrations, unless running in DryRun mode,
+// where pending migrations are reported instead.
+func (m *MigrationManager) Configure(ctx context.Context) (err error) {
+	log := newLogger(ctx, m.Logger, Fields{
+		"package":    "mongo",
+		"collection": storage.EntityMigrations,
+		"method":     "Configure",
+	})
+
+	indices := []mongo.IndexModel{
+		{
+			Keys: bson.D{
+				{
+					Key:   "id",
+					Value: int32(1),
+				},
+			},
+			Options: options.Index().
+				SetBackground(true).
+				SetName(IdxMigrationID).
+				SetSparse(true).
+				SetUnique(true),
+		},
+	}
+
+	err = m.DB.createIndexes(ctx, storage.EntityMigrations, indices)
+	if err != nil {
+		log.WithError(err).Error(logError)
+		return toStorageError(storage.EntityMigrations, err)
+	}
+
+	migrations, err := m.Migrate(ctx, m.DryRun)
+	if err != nil {
+		log.WithError(err).Error("error applying migrations")
+		return err
+	}
+
+	for _, migration := range migrations {
+		log := log.WithFields(Fields{
+			"version": migration.Version,
+			"name":    migration.Name,
+		})
+		if m.DryRun {
+			log.Info("pending migration")
+		} else {
+			log.Info("applied migration")
+		}
+	}
+
+	return nil
+}
+
+// applied returns the migrations that have been applied, keyed by name.
+func (m *MigrationManager) applied(ctx context.Context) (results map[string]storage.Migration, err error) {
+	log := newLogger(ctx, m.Logger, Fields{
+		"package":    "mongo",
+		"collection": storage.EntityMigrations,
+		"method":     "applied",
+	})
+
+	// Build Query
+	query := bson.M{
+		"version": bson.M{"$exists": true},
+	}
+
+	// Trace how long the Mongo operation takes to complete.
+	span, ctx := m.DB.traceMongoCall(ctx, DBTrace{
+		Manager:    "MigrationManager",
+		Method:     "applied",
+		Collection: storage.EntityMigrations,
+		Operation:  "find",
+		Query:      query,
+	})
+	defer span.Finish()
+
+	collection := m.DB.Collection(storage.EntityMigrations)
+	cursor, err := collection.Find(ctx, query)
+	if err != nil {
+		// Log to StdOut
+		log.WithError(err).Error(logError)
+		// Log to Tracer
+		span.RecordError(err)
+		return results, toStorageError(storage.EntityMigrations, err)
+	}
+
+	var migrations []storage.Migration
+	err = cursor.All(ctx, &migrations)
+	if err != nil {
+		// Log to StdOut
+		log.WithError(err).Error(logError)
+		// Log to Tracer
+		span.RecordError(err)
+		return results, toStorageError(storage.EntityMigrations, err)
+	}
+
+	results = make(map[string]storage.Migration, len(migrations))
+	for _, migration := range migrations {
+		results[migration.Name] = migration
+	}
+
+	return results, nil
+}
+
+// List returns all known migrations, applied or not, in version order.
+func (m *MigrationManager) List(ctx context.Context) (results []storage.Migration, err error) {
+	migrations, err := m.migrations()
+	if err != nil {
+		return nil, err
+	}
+
+	applied, err := m.applied(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, migration := range migrations {
+		result := migration.toStorage()
+		if record, ok := applied[migration.Name]; ok {
+			result.AppliedAt = record.AppliedAt
+		}
+		results = append(results, result)
+	}
+
+	return results, nil
+}
+
+// Pending returns the migrations that have not yet been applied, in version
+// order.
+func (m *MigrationManager) Pending(ctx context.Context) (results []storage.Migration, err error) {
+	migrations, err := m.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, migration := range migrations {
+		if !migration.IsApplied() {
+			results = append(results, migration)
+		}
+	}
+
+	return results, nil
+}
+
+// UpToDate returns true if there are no pending migrations.
+func (m *MigrationManager) UpToDate(ctx context.Context) (bool, error) {
+	pending, err := m.Pending(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	return len(pending) == 0, nil
+}
+
+// Migrate applies any pending migrations in version order, returning the
+// migrations that were applied. If dryRun is true, the pending migrations are
+// returned without being applied.
+//
+// While migrating, a lock is held to stop other service instances applying
+// migrations concurrently. If another instance holds the lock, Migrate waits
+// for it to be released, then applies anything still pending.
+func (m *MigrationManager) Migrate(ctx context.Context, dryRun bool) (results []storage.Migration, err error) {
+	log := newLogger(ctx, m.Logger, Fields{
+		"package":    "mongo",
+		"collection": storage.EntityMigrations,
+		"method":     "Migrate",
+	})
+
+	if dryRun {
+		return m.Pending(ctx)
+	}
+
+	upToDate, err := m.UpToDate(ctx)
+	if err != nil || upToDate {
+		return nil, err
+	}
+
+	lockTTL := m.LockTTL
+	if lockTTL == 0 {
+		lockTTL = defaultMigrationLockTTL
+	}
+	lock := &lease{
+		collection: m.DB.Collection(storage.EntityMigrations),
+		id:         migrationLockID,
+		owner:      uuid.NewString(),
+		ttl:        lockTTL,
+	}
+	if err = lock.acquire(ctx, migrationLockPollInterval); err != nil {
+		log.WithError(err).Error("error acquiring migration lock")
+		return nil, toStorageError(storage.EntityMigrations, err)
+	}
+	defer func() {
+		if err := lock.release(ctx); err != nil {
+			log.WithError(err).Warn("error releasing migration lock")
+		}
+	}()
+
+	// Renew the lock while migrating, so that a long running migration
+	// doesn't lose it. If the lock is lost anyway, lockCtx is cancelled to
+	// stop the running migration.
+	lockCtx, stopRenewing := lock.keepAlive(ctx)
+	defer stopRenewing()
+
+	// Another instance may have applied migrations while we were waiting on
+	// the lock, so work out what's pending now we hold it.
+	applied, err := m.applied(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	migrations, err := m.migrations()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, migration := range migrations {
+		if _, ok := applied[migration.Name]; ok {
+			continue
+		}
+
+		applied, err := m.apply(lockCtx, lock, migration)
+		if err != nil {
+			return results, err
+		}
+		results = append(results, applied)
+	}
+
+	return results, nil
+}
+
+// apply runs the migration and records it as applied, as long as the
+// migration lock is still held.
+func (m *MigrationManager) apply(ctx context.Context, lock *lease, migration Migration) (result storage.Migration, err error) {
+	log := newLogger(ctx, m.Logger, Fields{
+		"package":    "mongo",
+		"collection": storage.EntityMigrations,
+		"method":     "apply",
+		"version":    migration.Version,
+		"name":       migration.Name,
+	})
+
+	// Trace how long the migration takes to complete.
+	span, ctx := m.DB.traceMongoCall(ctx, DBTrace{
+		Manager:    "MigrationManager",
+		Method:     "apply",
+		Collection: storage.EntityMigrations,
+		CustomTags: []Tag{
+			{Key: "migration.version", Value: migration.Version},
+			{Key: "migration.name", Value: migration.Name},
+		},
+	})
+	defer span.Finish()
+
+	if migration.Up != nil {
+		if err = migration.Up(ctx, m.DB); err != nil {
+			// Log to StdOut
+			log.WithError(err).Error("error applying migration")
+			// Log to Tracer
+			span.RecordError(err)
+			return result, toStorageError(storage.EntityMigrations, err)
+		}
+	}
+
+	// Check the lock is still ours before recording the migration, as another
+	// instance may have taken over the lock, and the migration, if it was
+	// lost part way through.
+	if ok, err := lock.tryAcquire(ctx); err != nil || !ok {
+		if err == nil {
+			err = errMigrationLockLost
+		}
+		// Log to StdOut
+		log.WithError(err).Error("migration lock lost")
+		// Log to Tracer
+		span.RecordError(err)
+		return result, storage.NewError(storage.ErrPreconditionFailed, storage.EntityMigrations, err)
+	}
+
+	result = migration.toStorage()
+	result.AppliedAt = time.Now().Unix()
+
+	collection := m.DB.Collection(storage.EntityMigrations)
+	_, err = collection.InsertOne(ctx, result)
+	if err != nil {
+		// Log to StdOut
+		log.WithError(err).Error(logError)
+		// Log to Tracer
+		span.SetQuery(result)
+		span.RecordError(err)
+		return result, toStorageError(storage.EntityMigrations, err)
+	}
+
+	return result, nil
+}
@@ -0,0 +1,131 @@
+package mongo
+
+import (
+	// Standard Library Imports
+	"errors"
+	"reflect"
+	"testing"
+
+	// Internal Imports
+	"github.com/matthewhartstonge/storage"
+)
+
+func TestMigrationManager_ImplementsStorageConfigurer(t *testing.T) {
+	m := &MigrationManager{}
+
+	var i interface{} = m
+	if _, ok := i.(storage.Configurer); !ok {
+		t.Error("MigrationManager does not implement interface storage.Configurer")
+	}
+}
+
+func TestMigrationManager_ImplementsStorageMigrationStorer(t *testing.T) {
+	m := &MigrationManager{}
+
+	var i interface{} = m
+	if _, ok := i.(storage.MigrationStorer); !ok {
+		t.Error("MigrationManager does not implement interface storage.MigrationStorer")
+	}
+}
+
+func TestMigrationManager_ImplementsStorageMigrationManager(t *testing.T) {
+	m := &MigrationManager{}
+
+	var i interface{} = m
+	if _, ok := i.(storage.MigrationManager); !ok {
+		t.Error("MigrationManager does not implement interface storage.MigrationManager")
+	}
+}
+
+func TestMigrationManager_migrations_Default(t *testing.T) {
+	m := &MigrationManager{}
+
+	got, err := m.migrations()
+	if err != nil {
+		t.Fatalf("error getting migrations: %s", err)
+	}
+
+	if len(got) != len(DefaultMigrations()) {
+		t.Errorf("expected default migrations to be used, got %d migrations", len(got))
+	}
+}
+
+func TestMigrationManager_migrations_Ordered(t *testing.T) {
+	m := &MigrationManager{
+		Migrations: []Migration{
+			{Version: 3, Name: "three"},
+			{Version: 1, Name: "one"},
+			{Version: 2, Name: "two"},
+		},
+	}
+
+	got, err := m.migrations()
+	if err != nil {
+		t.Fatalf("error getting migrations: %s", err)
+	}
+
+	for i, expected := range []string{"one", "two", "three"} {
+		if got[i].Name != expected {
+			t.Errorf("expected migration %d to be %q, got %q", i, expected, got[i].Name)
+		}
+	}
+
+	if m.Migrations[0].Name != "three" {
+		t.Error("expected configured migrations not to be reordered in place")
+	}
+}
+
+func TestMigrationManager_migrations_NotUnique(t *testing.T) {
+	tests := []struct {
+		name       string
+		migrations []Migration
+	}{
+		{
+			name: "duplicate version",
+			migrations: []Migration{
+				{Version: 1, Name: "one"},
+				{Version: 1, Name: "uno"},
+			},
+		},
+		{
+			name: "duplicate name",
+			migrations: []Migration{
+				{Version: 1, Name: "one"},
+				{Version: 2, Name: "one"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := &MigrationManager{
+				Migrations: tt.migrations,
+			}
+
+			_, err := m.migrations()
+			if !errors.Is(err, storage.ErrInvalidArgument) {
+				t.Errorf("expected error to be storage.ErrInvalidArgument, got %v", err)
+			}
+		})
+	}
+}
+
+func TestUsernameConflicts(t *testing.T) {
+	conflicts := usernameConflicts(map[string][]string{
+		"peter": {"user-1", "user-2"},
+		"paul":  {"user-3"},
+		"mary":  {"user-4", "user-5", "user-6"},
+	})
+
+	expected := []string{
+		`"mary" is shared by users user-4, user-5, user-6`,
+		`"peter" is shared by users user-1, user-2`,
+	}
+	if !reflect.DeepEqual(conflicts, expected) {
+		t.Errorf("expected conflicts %v, got %v", expected, conflicts)
+	}
+
+	if conflicts := usernameConflicts(map[string][]string{"paul": {"user-3"}}); conflicts != nil {
+		t.Errorf("expected no conflicts, got %v", conflicts)
+	}
+}
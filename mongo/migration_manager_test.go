@@ -0,0 +1,150 @@
+package mongo_test
+
+import (
+	// Standard Library Imports
+	"context"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	// External Imports
+	"go.mongodb.org/mongo-driver/bson"
+
+	// Internal Imports
+	"github.com/matthewhartstonge/storage"
+	"github.com/matthewhartstonge/storage/mongo"
+)
+
+func TestMigrationManager_Migrate_ShouldRenewLockWhileMigrating(t *testing.T) {
+	store, _, teardown := setup(t)
+	defer teardown()
+
+	// The migration outlasts the lock TTL several times over, so without
+	// renewing the lock, the second instance would run it concurrently.
+	var runs int32
+	migrations := []mongo.Migration{
+		{
+			Version: 100,
+			Name:    "slowMigration",
+			Up: func(ctx context.Context, db *mongo.DB) error {
+				atomic.AddInt32(&runs, 1)
+				select {
+				case <-ctx.Done():
+					return ctx.Err()
+				case <-time.After(5 * time.Second):
+					return nil
+				}
+			},
+		},
+	}
+
+	var wg sync.WaitGroup
+	errs := make([]error, 2)
+	for i := range errs {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+
+			// Start the second instance once the first holds the lock.
+			time.Sleep(time.Duration(i) * 500 * time.Millisecond)
+			manager := &mongo.MigrationManager{
+				DB:         store.DB,
+				Migrations: migrations,
+				LockTTL:    2 * time.Second,
+			}
+			_, errs[i] = manager.Migrate(context.Background(), false)
+		}(i)
+	}
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			AssertError(t, err, nil, "migrate should return no errors")
+		}
+	}
+	if got := atomic.LoadInt32(&runs); got != 1 {
+		AssertError(t, got, 1, "migration should only run once")
+	}
+}
+
+// hasIndex returns whether the named index exists on the collection.
+func hasIndex(ctx context.Context, t *testing.T, store *mongo.Store, entityName string, indexName string) bool {
+	cursor, err := store.DB.Collection(entityName).Indexes().List(ctx)
+	if err != nil {
+		AssertFatal(t, err, nil, "list indexes should return no database errors")
+	}
+
+	var indexes []struct {
+		Name string `bson:"name"`
+	}
+	if err = cursor.All(ctx, &indexes); err != nil {
+		AssertFatal(t, err, nil, "list indexes should return no database errors")
+	}
+
+	for _, index := range indexes {
+		if index.Name == indexName {
+			return true
+		}
+	}
+
+	return false
+}
+
+func TestMigrationManager_NormalizeUsernames(t *testing.T) {
+	store, ctx, teardown := setup(t)
+	defer teardown()
+
+	// Rewind the users collection to before usernames were normalized.
+	users := store.DB.Collection(storage.EntityUsers)
+	if _, err := users.Indexes().DropOne(ctx, mongo.IdxUsernameCaseInsensitive); err != nil {
+		AssertFatal(t, err, nil, "drop index should return no database errors")
+	}
+	_, err := users.InsertMany(ctx, []interface{}{
+		bson.M{"id": "user-1", "username": "Bob"},
+		bson.M{"id": "user-2", "username": "bob"},
+		bson.M{"id": "user-3", "username": "Alice"},
+	})
+	if err != nil {
+		AssertFatal(t, err, nil, "insert should return no database errors")
+	}
+
+	manager := &mongo.MigrationManager{
+		DB: store.DB,
+		Migrations: []mongo.Migration{
+			{
+				Version: 100,
+				Name:    "renormalizeUsernames",
+				Up:      mongo.DefaultMigrations()[1].Up,
+			},
+		},
+	}
+
+	_, err = manager.Migrate(context.Background(), false)
+	if err == nil || !strings.Contains(err.Error(), `"bob" is shared by users user-1, user-2`) {
+		AssertError(t, err, "conflicting usernames", "migrate should report usernames that only differ by case")
+	}
+	if hasIndex(ctx, t, store, storage.EntityUsers, mongo.IdxUsernameCaseInsensitive) {
+		AssertError(t, true, false, "the username index should not be created while usernames conflict")
+	}
+
+	// Resolve the conflict by hand, then migrate again.
+	if _, err = users.DeleteOne(ctx, bson.M{"id": "user-2"}); err != nil {
+		AssertFatal(t, err, nil, "delete should return no database errors")
+	}
+	if _, err = manager.Migrate(context.Background(), false); err != nil {
+		AssertFatal(t, err, nil, "migrate should return no errors once conflicts are resolved")
+	}
+	if !hasIndex(ctx, t, store, storage.EntityUsers, mongo.IdxUsernameCaseInsensitive) {
+		AssertError(t, false, true, "the username index should be created")
+	}
+
+	got, err := store.UserManager.Get(ctx, "user-3")
+	if err != nil {
+		AssertFatal(t, err, nil, "get should return no database errors")
+	}
+	if got.Username != "alice" {
+		AssertError(t, got.Username, "alice", "usernames should be normalized")
+	}
+}
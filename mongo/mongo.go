@@ -4,13 +4,10 @@ import (
 	// Standard Library Imports
 	"context"
 	"crypto/tls"
-	"errors"
-	"fmt"
 	"time"
 
 	// External Imports
 	"github.com/ory/fosite"
-	"github.com/sirupsen/logrus"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
 	"go.mongodb.org/mongo-driver/mongo/readpref"
@@ -19,17 +16,13 @@ import (
 	"github.com/matthewhartstonge/storage"
 )
 
-func init() {
-	// Bind a logger, but only to panic level. Leave it to the user to decide
-	// whether they want datastore logging or not.
-	SetLogger(logrus.New())
-	logger.Level = logrus.PanicLevel
-}
-
 const (
 	defaultHost         = "localhost"
 	defaultPort         = 27017
+	defaultAuthDB       = "admin"
 	defaultDatabaseName = "oauth2"
+	defaultTimeout      = 10
+	defaultPoolMaxSize  = 100
 )
 
 // Store provides a MongoDB storage driver compatible with fosite's required
@@ -42,14 +35,41 @@ type Store struct {
 	// in-flight request.
 	timeout time.Duration
 
+	// logger provides the logger bound to the store and each of its managers.
+	logger Logger
+
+	// stopJanitor stops the background janitor, if started.
+	stopJanitor context.CancelFunc
+
 	// Public API
-	Hasher fosite.Hasher
+	Hasher     fosite.Hasher
+	Migrations *MigrationManager
+	Janitor    *Janitor
 	storage.Store
 }
 
 // DB wraps the mongo database connection and the features that are enabled.
 type DB struct {
 	*mongo.Database
+
+	// HasSessions is true if the connected deployment supports sessions.
+	HasSessions bool
+
+	// HasTransactions is true if the connected deployment supports
+	// multi-document transactions. Standalone servers never support
+	// transactions.
+	HasTransactions bool
+
+	// indexes records the indexes created by each manager's Configure, in
+	// order for health checks to verify they still exist.
+	indexes indexRegistry
+
+	// pool tracks connection pool usage, if connected via New.
+	pool *poolMonitor
+
+	// Tracer traces the calls made to mongo. If nil, spans are recorded to
+	// the globally registered OpenTelemetry tracer provider.
+	Tracer Tracer
 }
 
 // NewSession creates and returns a new mongo session.
@@ -69,18 +89,22 @@ type DB struct {
 // }
 // ```
 func (s *Store) NewSession(ctx context.Context) (context.Context, func(), error) {
-	return newSession(ctx, s.DB)
+	ctx, closeSession, err := newSession(ctx, s.DB)
+	if err != nil {
+		newLogger(ctx, s.logger, Fields{
+			"package": "mongo",
+			"method":  "NewSession",
+		}).WithError(err).Error("error starting mongo session")
+		return ctx, nil, err
+	}
+
+	return ctx, closeSession, nil
 }
 
 // newSession creates a new mongo session.
 func newSession(ctx context.Context, db *DB) (context.Context, func(), error) {
 	session, err := db.Client().StartSession()
 	if err != nil {
-		fields := logrus.Fields{
-			"package": "mongo",
-			"method":  "newSession",
-		}
-		logger.WithError(err).WithFields(fields).Error("error starting mongo session")
 		return ctx, nil, err
 	}
 
@@ -99,32 +123,153 @@ func closeSession(ctx context.Context, session mongo.Session) func() {
 	}
 }
 
-// Close terminates the mongo connection.
+// Close stops the background janitor, if started, and terminates the mongo
+// connection.
 func (s *Store) Close() {
+	if s.stopJanitor != nil {
+		s.stopJanitor()
+	}
+
 	err := s.DB.Client().Disconnect(nil)
 	if err != nil {
-		fields := logrus.Fields{
+		newLogger(context.Background(), s.logger, Fields{
 			"package": "mongo",
 			"method":  "Close",
-		}
-		logger.WithError(err).WithFields(fields).Error("error closing mongo connection")
+		}).WithError(err).Error("error closing mongo connection")
 	}
 }
 
 // Config defines the configuration parameters which are used by GetMongoSession.
+//
+// A MongoDB connection string can be provided via URI, for example,
+// `mongodb+srv://cluster0.example.com/oauth2?appName=auth&compressors=zstd`.
+// Hosts are taken from the URI if provided, otherwise from Hostnames and Port.
+// Other explicitly configured fields take precedence over the URI.
+//
+// Config can be loaded from the environment, and optionally a YAML or JSON
+// file, via LoadConfig and LoadConfigFile. Config is never modified by this
+// package, so it is safe to reuse once loaded.
 type Config struct {
-	Hostnames    []string    `default:"localhost" envconfig:"CONNECTIONS_MONGO_HOSTNAMES"`
-	Port         uint16      `default:"27017"     envconfig:"CONNECTIONS_MONGO_PORT"`
-	SSL          bool        `default:"false"     envconfig:"CONNECTIONS_MONGO_SSL"`
-	AuthDB       string      `default:"admin"     envconfig:"CONNECTIONS_MONGO_AUTHDB"`
-	Username     string      `default:""          envconfig:"CONNECTIONS_MONGO_USERNAME"`
-	Password     string      `default:""          envconfig:"CONNECTIONS_MONGO_PASSWORD"`
-	DatabaseName string      `default:""          envconfig:"CONNECTIONS_MONGO_NAME"`
-	Replset      string      `default:""          envconfig:"CONNECTIONS_MONGO_REPLSET"`
-	Timeout      uint        `default:"10"        envconfig:"CONNECTIONS_MONGO_TIMEOUT"`
-	PoolMinSize  uint64      `default:"0"         envconfig:"CONNECTIONS_MONGO_POOL_MIN_SIZE"`
-	PoolMaxSize  uint64      `default:"100"       envconfig:"CONNECTIONS_MONGO_POOL_MAX_SIZE"`
-	TLSConfig    *tls.Config `ignored:"true"`
+	URI          string      `default:""          envconfig:"CONNECTIONS_MONGO_URI"             json:"uri,omitempty"          yaml:"uri,omitempty"`
+	Hostnames    []string    `default:"localhost" envconfig:"CONNECTIONS_MONGO_HOSTNAMES"       json:"hostnames,omitempty"    yaml:"hostnames,omitempty"`
+	Port         uint16      `default:"27017"     envconfig:"CONNECTIONS_MONGO_PORT"            json:"port,omitempty"         yaml:"port,omitempty"`
+	SSL          bool        `default:"false"     envconfig:"CONNECTIONS_MONGO_SSL"             json:"ssl,omitempty"          yaml:"ssl,omitempty"`
+	AuthDB       string      `default:"admin"     envconfig:"CONNECTIONS_MONGO_AUTHDB"          json:"authDb,omitempty"       yaml:"authDb,omitempty"`
+	Username     string      `default:""          envconfig:"CONNECTIONS_MONGO_USERNAME"        json:"username,omitempty"     yaml:"username,omitempty"`
+	Password     string      `default:""          envconfig:"CONNECTIONS_MONGO_PASSWORD"        json:"password,omitempty"     yaml:"password,omitempty"`
+	DatabaseName string      `default:""          envconfig:"CONNECTIONS_MONGO_NAME"            json:"databaseName,omitempty" yaml:"databaseName,omitempty"`
+	Replset      string      `default:""          envconfig:"CONNECTIONS_MONGO_REPLSET"         json:"replset,omitempty"      yaml:"replset,omitempty"`
+	Timeout      uint        `default:"10"        envconfig:"CONNECTIONS_MONGO_TIMEOUT"         json:"timeout,omitempty"      yaml:"timeout,omitempty"`
+	PoolMinSize  uint64      `default:"0"         envconfig:"CONNECTIONS_MONGO_POOL_MIN_SIZE"   json:"poolMinSize,omitempty"  yaml:"poolMinSize,omitempty"`
+	PoolMaxSize  uint64      `default:"100"       envconfig:"CONNECTIONS_MONGO_POOL_MAX_SIZE"   json:"poolMaxSize,omitempty"  yaml:"poolMaxSize,omitempty"`
+	TLSConfig    *tls.Config `ignored:"true"                                                    json:"-"                      yaml:"-"`
+
+	// AuthMechanism specifies the mechanism used to authenticate the
+	// connection, one of SCRAM-SHA-1, SCRAM-SHA-256 or MONGODB-X509. If empty,
+	// the mechanism is negotiated with the server.
+	AuthMechanism string `default:"" envconfig:"CONNECTIONS_MONGO_AUTH_MECHANISM" json:"authMechanism,omitempty" yaml:"authMechanism,omitempty"`
+
+	// TLSCAFile specifies the path to a PEM encoded file containing the
+	// certificate authorities used to verify the server certificate. These
+	// take precedence over any RootCAs provided via TLSConfig or the URI.
+	TLSCAFile string `default:"" envconfig:"CONNECTIONS_MONGO_TLS_CA_FILE" json:"tlsCaFile,omitempty" yaml:"tlsCaFile,omitempty"`
+
+	// TLSCertFile specifies the path to a PEM encoded client certificate,
+	// required for MONGODB-X509 authentication. If TLSKeyFile is empty, the
+	// file must also contain the private key.
+	TLSCertFile string `default:"" envconfig:"CONNECTIONS_MONGO_TLS_CERT_FILE" json:"tlsCertFile,omitempty" yaml:"tlsCertFile,omitempty"`
+
+	// TLSKeyFile specifies the path to the PEM encoded private key of the
+	// client certificate.
+	TLSKeyFile string `default:"" envconfig:"CONNECTIONS_MONGO_TLS_KEY_FILE" json:"tlsKeyFile,omitempty" yaml:"tlsKeyFile,omitempty"`
+
+	// MigrationsDryRun, if true, reports pending migrations on start up
+	// instead of applying them.
+	MigrationsDryRun bool `default:"false" envconfig:"CONNECTIONS_MONGO_MIGRATIONS_DRY_RUN" json:"migrationsDryRun,omitempty" yaml:"migrationsDryRun,omitempty"`
+
+	// MigrationLockTTL specifies how long the migration lock is held before
+	// it expires, unless renewed. The lock is renewed while migrations run.
+	// Defaults to 5 minutes.
+	MigrationLockTTL time.Duration `default:"5m" envconfig:"CONNECTIONS_MONGO_MIGRATION_LOCK_TTL" json:"migrationLockTTL,omitempty" yaml:"migrationLockTTL,omitempty"`
+
+	// Migrations provides the schema migrations to apply on start up. If nil,
+	// the default migrations are applied.
+	Migrations []Migration `ignored:"true" json:"-" yaml:"-"`
+
+	// JanitorInterval specifies how often expired denied JTIs, login sessions
+	// and requests are cleaned up in the background. Defaults to 10 minutes.
+	//
+	// The janitor is the only cleanup for the JTI denylist, which grows with
+	// every client assertion, so it runs by default. Expiry is stored in
+	// seconds rather than as a date, so a TTL index can't be used instead.
+	// Each run adds load as documents are deleted in batches, which can be
+	// limited via JanitorBatchSize and JanitorMaxDeletesPerRun.
+	JanitorInterval time.Duration `default:"10m" envconfig:"CONNECTIONS_MONGO_JANITOR_INTERVAL" json:"janitorInterval,omitempty" yaml:"janitorInterval,omitempty"`
+
+	// JanitorDisabled, if true, doesn't start the janitor in the background,
+	// for deployments that clean up on their own schedule via Store.Janitor.
+	// Expired documents are retained until then.
+	JanitorDisabled bool `default:"false" envconfig:"CONNECTIONS_MONGO_JANITOR_DISABLED" json:"janitorDisabled,omitempty" yaml:"janitorDisabled,omitempty"`
+
+	// JanitorBatchSize specifies the number of documents the janitor deletes
+	// per batch. Defaults to 500.
+	JanitorBatchSize int64 `default:"500" envconfig:"CONNECTIONS_MONGO_JANITOR_BATCH_SIZE" json:"janitorBatchSize,omitempty" yaml:"janitorBatchSize,omitempty"`
+
+	// JanitorMaxDeletesPerRun limits the number of documents the janitor
+	// deletes from each collection per run. If zero, there is no limit.
+	JanitorMaxDeletesPerRun int64 `default:"0" envconfig:"CONNECTIONS_MONGO_JANITOR_MAX_DELETES_PER_RUN" json:"janitorMaxDeletesPerRun,omitempty" yaml:"janitorMaxDeletesPerRun,omitempty"`
+
+	// ValidateTenantReferences, if true, rejects creating or updating clients
+	// and users that grant access to tenants which do not exist.
+	ValidateTenantReferences bool `default:"false" envconfig:"CONNECTIONS_MONGO_VALIDATE_TENANTS" json:"validateTenantReferences,omitempty" yaml:"validateTenantReferences,omitempty"`
+
+	// StrictScopes, if true, rejects creating or updating clients, users and
+	// groups, or granting them scopes, that are not registered via the
+	// ScopeManager.
+	StrictScopes bool `default:"false" envconfig:"CONNECTIONS_MONGO_STRICT_SCOPES" json:"strictScopes,omitempty" yaml:"strictScopes,omitempty"`
+
+	// Region specifies the region the store is serving. If set, clients whose
+	// AllowedRegions exclude the region are denied. The region can also be
+	// bound per request via storage.RegionToContext.
+	Region string `default:"" envconfig:"CONNECTIONS_MONGO_REGION" json:"region,omitempty" yaml:"region,omitempty"`
+
+	// DataResidency, if true, refuses to persist request sessions for users
+	// whose tenant is pinned to a region other than the serving region.
+	DataResidency bool `default:"false" envconfig:"CONNECTIONS_MONGO_DATA_RESIDENCY" json:"dataResidency,omitempty" yaml:"dataResidency,omitempty"`
+
+	// PARLifespan specifies how long a pushed authorization request can be
+	// used for after being pushed. Defaults to one minute.
+	PARLifespan time.Duration `default:"1m" envconfig:"CONNECTIONS_MONGO_PAR_LIFESPAN" json:"parLifespan,omitempty" yaml:"parLifespan,omitempty"`
+
+	// AccessTokenLifespan specifies how long an access token is valid for,
+	// after which the janitor cleans it up. This should match the lifespan
+	// configured in fosite. Defaults to one hour.
+	AccessTokenLifespan time.Duration `default:"1h" envconfig:"CONNECTIONS_MONGO_ACCESS_TOKEN_LIFESPAN" json:"accessTokenLifespan,omitempty" yaml:"accessTokenLifespan,omitempty"`
+
+	// RefreshTokenLifespan specifies how long a refresh token is valid for,
+	// after which the janitor cleans it up. This should match the lifespan
+	// configured in fosite. Defaults to 30 days.
+	RefreshTokenLifespan time.Duration `default:"720h" envconfig:"CONNECTIONS_MONGO_REFRESH_TOKEN_LIFESPAN" json:"refreshTokenLifespan,omitempty" yaml:"refreshTokenLifespan,omitempty"`
+
+	// AuthorizeCodeLifespan specifies how long an authorization code, and
+	// the OpenID Connect and PKCE sessions stored with it, are valid for,
+	// after which the janitor cleans them up. This should match the lifespan
+	// configured in fosite. Defaults to 15 minutes.
+	AuthorizeCodeLifespan time.Duration `default:"15m" envconfig:"CONNECTIONS_MONGO_AUTHORIZE_CODE_LIFESPAN" json:"authorizeCodeLifespan,omitempty" yaml:"authorizeCodeLifespan,omitempty"`
+
+	// BulkHashWorkers specifies the number of workers used to hash client
+	// secrets and user passwords in bulk operations. If zero, a worker is
+	// started per CPU.
+	BulkHashWorkers int `default:"0" envconfig:"CONNECTIONS_MONGO_BULK_HASH_WORKERS" json:"bulkHashWorkers,omitempty" yaml:"bulkHashWorkers,omitempty"`
+
+	// Logger provides the logger used by the store and each of its managers.
+	// If nil, logs are discarded.
+	Logger Logger `ignored:"true" json:"-" yaml:"-"`
+
+	// Tracer provides the tracer used by the store and each of its managers.
+	// If nil, spans are recorded to the globally registered OpenTelemetry
+	// tracer provider. Use NewNoopTracer to disable tracing.
+	Tracer Tracer `ignored:"true" json:"-" yaml:"-"`
 }
 
 // DefaultConfig returns a configuration for a locally hosted, unauthenticated mongo
@@ -132,96 +277,140 @@ func DefaultConfig() *Config {
 	return &Config{
 		Hostnames:    []string{defaultHost},
 		Port:         defaultPort,
+		AuthDB:       defaultAuthDB,
 		DatabaseName: defaultDatabaseName,
+		Timeout:      defaultTimeout,
+		PoolMaxSize:  defaultPoolMaxSize,
 	}
 }
 
 // ConnectionInfo configures options for establishing a session with a MongoDB cluster.
+//
+// Deprecated: ConnectionInfo does not validate the configuration, and TLS
+// files that fail to load are logged and skipped. Use NewClientOptions.
 func ConnectionInfo(cfg *Config) *options.ClientOptions {
-	if len(cfg.Hostnames) == 0 {
-		cfg.Hostnames = []string{defaultHost}
+	dialInfo, err := buildClientOptions(cfg)
+	if err != nil {
+		newLogger(context.Background(), cfg.Logger, Fields{
+			"package": "mongo",
+			"method":  "ConnectionInfo",
+		}).WithError(err).Error("error loading TLS configuration")
 	}
 
-	if cfg.DatabaseName == "" {
-		cfg.DatabaseName = defaultDatabaseName
-	}
+	return dialInfo
+}
 
-	if cfg.Port > 0 {
-		for i := range cfg.Hostnames {
-			cfg.Hostnames[i] = fmt.Sprintf("%s:%d", cfg.Hostnames[i], cfg.Port)
-		}
+// buildClientOptions configures options for establishing a session with a
+// MongoDB cluster. The returned options are always non-nil, even if loading
+// the configured TLS files fails.
+func buildClientOptions(cfg *Config) (*options.ClientOptions, error) {
+	dialInfo := options.Client()
+	if cfg.URI != "" {
+		dialInfo.ApplyURI(cfg.URI)
 	}
 
-	if cfg.Timeout == 0 {
-		cfg.Timeout = 10
+	resolved := cfg.withDefaults()
+	if cfg.URI == "" {
+		dialInfo.SetHosts(resolved.hosts())
+	}
+	if cfg.Replset != "" {
+		dialInfo.SetReplicaSet(cfg.Replset)
+	}
+	if dialInfo.ReadPreference == nil {
+		dialInfo.SetReadPreference(readpref.SecondaryPreferred())
+	}
+	if cfg.PoolMinSize > 0 {
+		dialInfo.SetMinPoolSize(cfg.PoolMinSize)
+	}
+	if cfg.PoolMaxSize > 0 {
+		dialInfo.SetMaxPoolSize(cfg.PoolMaxSize)
 	}
 
-	dialInfo := options.Client().
-		SetHosts(cfg.Hostnames).
-		SetReplicaSet(cfg.Replset).
-		SetConnectTimeout(time.Second * time.Duration(cfg.Timeout)).
-		SetReadPreference(readpref.SecondaryPreferred()).
-		SetMinPoolSize(cfg.PoolMinSize).
-		SetMaxPoolSize(cfg.PoolMaxSize)
+	// A connect timeout provided via the URI is only overridden if a timeout
+	// has been explicitly configured.
+	if cfg.Timeout > 0 || dialInfo.ConnectTimeout == nil {
+		dialInfo.SetConnectTimeout(time.Second * time.Duration(resolved.Timeout))
+	}
 
-	if cfg.Username != "" || cfg.Password != "" {
-		auth := options.Credential{
-			AuthMechanism: "SCRAM-SHA-1",
-			AuthSource:    cfg.AuthDB,
-			Username:      cfg.Username,
-			Password:      cfg.Password,
-		}
+	if auth, ok := credential(cfg, dialInfo.Auth); ok {
 		dialInfo.SetAuth(auth)
 	}
 
-	if cfg.SSL {
-		dialInfo = dialInfo.SetTLSConfig(cfg.TLSConfig)
+	if cfg.tlsEnabled() || dialInfo.TLSConfig != nil {
+		tlsConfig, err := loadTLSConfig(cfg, dialInfo.TLSConfig)
+		dialInfo.SetTLSConfig(tlsConfig)
+		if err != nil {
+			return dialInfo, err
+		}
 	}
 
-	return dialInfo
+	return dialInfo, nil
 }
 
 // Connect returns a connection to a mongo database.
 func Connect(cfg *Config) (*mongo.Database, error) {
-	log := logger.WithFields(logrus.Fields{
+	database, _, err := connect(cfg)
+	return database, err
+}
+
+// connect returns a connection to a mongo database, along with the monitor
+// tracking the connection pool.
+func connect(cfg *Config) (*mongo.Database, *poolMonitor, error) {
+	log := newLogger(context.Background(), cfg.Logger, Fields{
 		"package": "mongo",
 		"method":  "Connect",
 	})
 
 	ctx := context.Background()
-	dialInfo := ConnectionInfo(cfg)
+	dialInfo, err := NewClientOptions(cfg)
+	if err != nil {
+		log.WithError(err).Error("Invalid mongo configuration!")
+		return nil, nil, err
+	}
+
+	pool := newPoolMonitor(dialInfo.PoolMonitor)
+	dialInfo.SetPoolMonitor(pool.monitor())
+
 	client, err := mongo.Connect(ctx, dialInfo)
 	if err != nil {
 		log.WithError(err).Error("Unable to build mongo connection!")
-		return nil, err
+		return nil, nil, err
 	}
 
 	// check connection works as mongo-go lazily connects.
 	err = client.Ping(ctx, nil)
 	if err != nil {
 		log.WithError(err).Error("Unable to connect to mongo! Have you configured your connection properly?")
-		return nil, err
+		return nil, nil, err
 	}
 
-	return client.Database(cfg.DatabaseName), nil
+	return client.Database(cfg.withDefaults().DatabaseName), pool, nil
 }
 
 // New allows for custom mongo configuration and custom hashers.
 func New(cfg *Config, hashee fosite.Hasher) (*Store, error) {
-	log := logger.WithFields(logrus.Fields{
+	log := newLogger(context.Background(), cfg.Logger, Fields{
 		"package": "mongo",
 		"method":  "NewFromConfig",
 	})
 
-	database, err := Connect(cfg)
+	database, pool, err := connect(cfg)
 	if err != nil {
 		log.WithError(err).Error("Unable to connect to mongo! Are you sure mongo is running?")
 		return nil, err
 	}
 
 	// Wrap database with mongo feature detection.
-	mongoDB := &DB{
-		Database: database,
+	mongoDB, err := detectFeatures(context.Background(), database)
+	if err != nil {
+		log.WithError(err).Warn("Unable to detect mongo features, continuing without transactions")
+	}
+	mongoDB.pool = pool
+	mongoDB.Tracer = cfg.Tracer
+	if mongoDB.Tracer == nil {
+		mongoDB.Tracer = &OpenTelemetryTracer{
+			DatabaseName: database.Name(),
+		}
 	}
 
 	if hashee == nil {
@@ -233,31 +422,113 @@ func New(cfg *Config, hashee fosite.Hasher) (*Store, error) {
 
 	// Build up the mongo endpoints
 	mongoDeniedJtis := &DeniedJtiManager{
-		DB: mongoDB,
+		DB:     mongoDB,
+		Logger: cfg.Logger,
+	}
+	mongoTenants := &TenantManager{
+		DB:     mongoDB,
+		Logger: cfg.Logger,
 	}
 	mongoClients := &ClientManager{
 		DB:     mongoDB,
 		Hasher: hashee,
+		Logger: cfg.Logger,
 
-		DeniedJTIs: mongoDeniedJtis,
+		DeniedJTIs:  mongoDeniedJtis,
+		Region:      cfg.Region,
+		HashWorkers: cfg.BulkHashWorkers,
+	}
+	mongoGroups := &GroupManager{
+		DB:     mongoDB,
+		Logger: cfg.Logger,
 	}
 	mongoUsers := &UserManager{
 		DB:     mongoDB,
 		Hasher: hashee,
+		Logger: cfg.Logger,
+
+		Groups:      mongoGroups,
+		HashWorkers: cfg.BulkHashWorkers,
+	}
+	if cfg.ValidateTenantReferences {
+		mongoClients.Tenants = mongoTenants
+		mongoUsers.Tenants = mongoTenants
+	}
+	mongoScopes := &ScopeManager{
+		DB:     mongoDB,
+		Logger: cfg.Logger,
+	}
+	if cfg.StrictScopes {
+		mongoClients.Scopes = mongoScopes
+		mongoUsers.Scopes = mongoScopes
+		mongoGroups.Scopes = mongoScopes
 	}
 	mongoRequests := &RequestManager{
-		DB: mongoDB,
+		DB:     mongoDB,
+		Logger: cfg.Logger,
 
 		Clients: mongoClients,
 		Users:   mongoUsers,
+		Groups:  mongoGroups,
+		Tenants: mongoTenants,
+
+		Region:        cfg.Region,
+		DataResidency: cfg.DataResidency,
+		PARLifespan:   cfg.PARLifespan,
+	}
+	mongoDeviceCodes := &DeviceCodeManager{
+		DB:     mongoDB,
+		Logger: cfg.Logger,
+	}
+	mongoIssuerTrusts := &IssuerTrustManager{
+		DB:     mongoDB,
+		Logger: cfg.Logger,
+
+		Clients: mongoClients,
+	}
+	mongoLoginSessions := &LoginSessionManager{
+		DB:     mongoDB,
+		Logger: cfg.Logger,
+	}
+	mongoConsents := &ConsentManager{
+		DB:     mongoDB,
+		Logger: cfg.Logger,
+
+		Requests: mongoRequests,
+	}
+	mongoMigrations := &MigrationManager{
+		DB:         mongoDB,
+		Logger:     cfg.Logger,
+		Migrations: cfg.Migrations,
+		DryRun:     cfg.MigrationsDryRun,
+		LockTTL:    cfg.MigrationLockTTL,
+	}
+	mongoJanitor := &Janitor{
+		DB:               mongoDB,
+		Logger:           cfg.Logger,
+		Interval:         cfg.withDefaults().JanitorInterval,
+		BatchSize:        cfg.JanitorBatchSize,
+		MaxDeletesPerRun: cfg.JanitorMaxDeletesPerRun,
+		Lifespans:        cfg.janitorLifespans(),
 	}
 
 	// Init DB collections, indices e.t.c.
+	// Migrations are configured last, so that they run against the indexed
+	// collections.
 	managers := []storage.Configurer{
+		mongoTenants,
+		mongoScopes,
+		mongoGroups,
 		mongoClients,
 		mongoDeniedJtis,
 		mongoUsers,
 		mongoRequests,
+		mongoConsents,
+		mongoDeviceCodes,
+		mongoIssuerTrusts,
+		mongoLoginSessions,
+		mongoJanitor,
+		mongoMigrations,
 	}
 
 	// attempt to perform index updates in a session.
@@ -279,16 +550,35 @@ func New(cfg *Config, hashee fosite.Hasher) (*Store, error) {
 	}
 
 	store := &Store{
-		DB:      mongoDB,
-		timeout: time.Second * time.Duration(cfg.Timeout),
-		Hasher:  hashee,
+		DB:         mongoDB,
+		timeout:    time.Second * time.Duration(cfg.withDefaults().Timeout),
+		logger:     cfg.Logger,
+		Hasher:     hashee,
+		Migrations: mongoMigrations,
+		Janitor:    mongoJanitor,
 		Store: storage.Store{
-			ClientManager:    mongoClients,
-			DeniedJTIManager: mongoDeniedJtis,
-			RequestManager:   mongoRequests,
-			UserManager:      mongoUsers,
+			ClientManager:       mongoClients,
+			ConsentManager:      mongoConsents,
+			DeniedJTIManager:    mongoDeniedJtis,
+			DeviceCodeManager:   mongoDeviceCodes,
+			GroupManager:        mongoGroups,
+			IssuerTrustManager:  mongoIssuerTrusts,
+			LoginSessionManager: mongoLoginSessions,
+			RequestManager:      mongoRequests,
+			ScopeManager:        mongoScopes,
+			TenantManager:       mongoTenants,
+			UserManager:         mongoUsers,
 		},
 	}
+
+	if !cfg.JanitorDisabled {
+		var janitorCtx context.Context
+		janitorCtx, store.stopJanitor = context.WithCancel(context.Background())
+		go func() {
+			_ = mongoJanitor.Run(janitorCtx)
+		}()
+	}
+
 	return store, nil
 }
 
@@ -298,23 +588,3 @@ func NewDefaultStore() (*Store, error) {
 	cfg := DefaultConfig()
 	return New(cfg, nil)
 }
-
-const (
-	// errCodeDuplicate provides the mongo error code for duplicate key error.
-	errCodeDuplicate = 11000
-)
-
-// isDup replicates mgo.IsDup functionality for the official driver in order
-// to know when a conflict has occurred.
-func isDup(err error) (isDup bool) {
-	var e mongo.WriteException
-	if errors.As(err, &e) {
-		for _, we := range e.WriteErrors {
-			if we.Code == errCodeDuplicate {
-				return true
-			}
-		}
-	}
-
-	return
-}
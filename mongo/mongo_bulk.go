@@ -0,0 +1,215 @@
+package mongo
+
+import (
+	// Standard Library Imports
+	"context"
+	"errors"
+	"runtime"
+	"sort"
+	"sync"
+
+	// External Imports
+	"github.com/ory/fosite"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	// Internal Imports
+	"github.com/matthewhartstonge/storage"
+)
+
+// hashJob provides a secret to hash for the item at index.
+type hashJob struct {
+	index  int
+	secret string
+}
+
+// hashResult provides the hashed secret, or error, for the item at index.
+type hashResult struct {
+	index int
+	hash  string
+	err   error
+}
+
+// hashSecrets hashes the secrets in a bounded pool of workers, as hashing is
+// CPU bound and dominates the time taken to write a batch. If workers is
+// zero, a worker is started per CPU.
+func hashSecrets(ctx context.Context, hasher fosite.Hasher, jobs []hashJob, workers int) []hashResult {
+	results := make([]hashResult, len(jobs))
+	if len(jobs) == 0 {
+		return results
+	}
+
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+	if workers > len(jobs) {
+		workers = len(jobs)
+	}
+
+	next := make(chan int)
+	var wg sync.WaitGroup
+	wg.Add(workers)
+	for w := 0; w < workers; w++ {
+		go func() {
+			defer wg.Done()
+			for i := range next {
+				job := jobs[i]
+				if err := ctx.Err(); err != nil {
+					results[i] = hashResult{index: job.index, err: err}
+					continue
+				}
+
+				hash, err := hasher.Hash(ctx, []byte(job.secret))
+				results[i] = hashResult{index: job.index, hash: string(hash), err: err}
+			}
+		}()
+	}
+
+	for i := range jobs {
+		next <- i
+	}
+	close(next)
+	wg.Wait()
+
+	return results
+}
+
+// validateEach validates the references of each item. The union of the
+// references is validated first, so that a valid batch only costs a single
+// lookup.
+func validateEach(references [][]string, validate func(references []string) error) []error {
+	errs := make([]error, len(references))
+
+	var all []string
+	for _, refs := range references {
+		all = union(all, refs)
+	}
+	if validate(all) == nil {
+		return errs
+	}
+
+	for i, refs := range references {
+		errs[i] = validate(refs)
+	}
+
+	return errs
+}
+
+// bulkWrite performs the writes unordered, so that a failing write doesn't
+// stop the remaining writes, and returns the error of each failed write by
+// the index of its model. An error is returned if the batch as a whole
+// failed.
+func bulkWrite(ctx context.Context, collection *mongo.Collection, entityName string, models []mongo.WriteModel) (map[int]error, error) {
+	if len(models) == 0 {
+		return nil, nil
+	}
+
+	opts := options.BulkWrite().SetOrdered(false)
+	_, err := collection.BulkWrite(ctx, models, opts)
+	if err == nil {
+		return nil, nil
+	}
+
+	var e mongo.BulkWriteException
+	if !errors.As(err, &e) || e.WriteConcernError != nil || len(e.WriteErrors) == 0 {
+		return nil, toStorageError(entityName, err)
+	}
+
+	errs := make(map[int]error, len(e.WriteErrors))
+	for _, we := range e.WriteErrors {
+		errs[we.Index] = toStorageError(entityName, mongo.WriteException{
+			WriteErrors: mongo.WriteErrors{we.WriteError},
+		})
+	}
+
+	return errs, nil
+}
+
+// writeIndexed writes the models, keyed by the index of the item they write,
+// recording the error of each failed write as the item's result.
+func writeIndexed(ctx context.Context, collection *mongo.Collection, entityName string, results []storage.BulkResult, models map[int]mongo.WriteModel) error {
+	indexes := make([]int, 0, len(models))
+	for i := range models {
+		indexes = append(indexes, i)
+	}
+	sort.Ints(indexes)
+
+	ordered := make([]mongo.WriteModel, len(indexes))
+	for m, i := range indexes {
+		ordered[m] = models[i]
+	}
+
+	errs, err := bulkWrite(ctx, collection, entityName, ordered)
+	if err != nil {
+		return err
+	}
+	for m, err := range errs {
+		results[indexes[m]].Err = err
+	}
+
+	return nil
+}
+
+// replaceByID returns a model replacing the entity with the given ID.
+func replaceByID(id string, replacement interface{}) mongo.WriteModel {
+	return mongo.NewReplaceOneModel().
+		SetFilter(bson.M{"id": id}).
+		SetReplacement(replacement)
+}
+
+// findIDs returns the IDs of the entities that exist out of the given IDs.
+func findIDs(ctx context.Context, collection *mongo.Collection, entityName string, ids []string) (map[string]bool, error) {
+	opts := options.Find().SetProjection(bson.M{"_id": 0, "id": 1})
+	cursor, err := collection.Find(ctx, bson.M{"id": bson.M{"$in": ids}}, opts)
+	if err != nil {
+		return nil, toStorageError(entityName, err)
+	}
+
+	var docs []struct {
+		ID string `bson:"id"`
+	}
+	if err = cursor.All(ctx, &docs); err != nil {
+		return nil, toStorageError(entityName, err)
+	}
+
+	found := make(map[string]bool, len(docs))
+	for _, doc := range docs {
+		found[doc.ID] = true
+	}
+
+	return found, nil
+}
+
+// bulkDelete deletes the entities by ID, reporting those that don't exist as
+// not found.
+func bulkDelete(ctx context.Context, collection *mongo.Collection, entityName string, ids []string) ([]storage.BulkResult, error) {
+	found, err := findIDs(ctx, collection, entityName, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]storage.BulkResult, len(ids))
+	var models []mongo.WriteModel
+	var modelIndex []int
+	for i, id := range ids {
+		results[i].ID = id
+		if !found[id] {
+			results[i].Err = storage.NewNotFoundError(entityName)
+			continue
+		}
+
+		models = append(models, mongo.NewDeleteOneModel().SetFilter(bson.M{"id": id}))
+		modelIndex = append(modelIndex, i)
+	}
+
+	errs, err := bulkWrite(ctx, collection, entityName, models)
+	if err != nil {
+		return nil, err
+	}
+	for m, err := range errs {
+		results[modelIndex[m]].Err = err
+	}
+
+	return results, nil
+}
@@ -0,0 +1,114 @@
+package mongo
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/matthewhartstonge/storage"
+)
+
+// prefixHasher provides a fosite.Hasher that prefixes secrets, failing to
+// hash empty secrets.
+type prefixHasher struct{}
+
+func (prefixHasher) Hash(ctx context.Context, data []byte) ([]byte, error) {
+	if len(data) == 0 {
+		return nil, errors.New("empty secret")
+	}
+	return append([]byte("hashed:"), data...), nil
+}
+
+func (prefixHasher) Compare(ctx context.Context, hash, data []byte) error {
+	return nil
+}
+
+func TestHashSecrets(t *testing.T) {
+	jobs := []hashJob{
+		{index: 0, secret: "a"},
+		{index: 2, secret: ""},
+		{index: 5, secret: "c"},
+	}
+
+	for _, workers := range []int{0, 1, 8} {
+		results := hashSecrets(context.Background(), prefixHasher{}, jobs, workers)
+		if len(results) != len(jobs) {
+			t.Fatalf("workers=%d: expected %d results, got %d", workers, len(jobs), len(results))
+		}
+
+		if results[0].index != 0 || results[0].hash != "hashed:a" || results[0].err != nil {
+			t.Errorf("workers=%d: unexpected result %+v", workers, results[0])
+		}
+		if results[1].index != 2 || results[1].err == nil {
+			t.Errorf("workers=%d: expected an error hashing an empty secret, got %+v", workers, results[1])
+		}
+		if results[2].index != 5 || results[2].hash != "hashed:c" {
+			t.Errorf("workers=%d: unexpected result %+v", workers, results[2])
+		}
+	}
+}
+
+func TestHashSecrets_Cancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	results := hashSecrets(ctx, prefixHasher{}, []hashJob{{index: 0, secret: "a"}}, 1)
+	if !errors.Is(results[0].err, context.Canceled) {
+		t.Errorf("expected a cancelled context to stop hashing, got %v", results[0].err)
+	}
+}
+
+func TestValidateEach(t *testing.T) {
+	calls := 0
+	validate := func(refs []string) error {
+		calls++
+		for _, ref := range refs {
+			if ref == "bad" {
+				return errors.New("bad reference")
+			}
+		}
+		return nil
+	}
+
+	errs := validateEach([][]string{{"a"}, {"b"}}, validate)
+	if !reflect.DeepEqual(errs, []error{nil, nil}) {
+		t.Errorf("expected no errors, got %v", errs)
+	}
+	if calls != 1 {
+		t.Errorf("expected a valid batch to be validated once, got %d calls", calls)
+	}
+
+	errs = validateEach([][]string{{"a"}, {"bad"}, nil}, validate)
+	if errs[0] != nil || errs[1] == nil || errs[2] != nil {
+		t.Errorf("expected only the second item to be invalid, got %v", errs)
+	}
+}
+
+func TestBulkWrite_NoModels(t *testing.T) {
+	errs, err := bulkWrite(context.Background(), nil, "clients", nil)
+	if err != nil || errs != nil {
+		t.Errorf("expected no writes to succeed, got %v, %v", errs, err)
+	}
+}
+
+func TestBulkScopes_ShouldRequireFilter(t *testing.T) {
+	ctx := context.Background()
+	scopes := []string{"cats:read"}
+
+	c := &ClientManager{}
+	if _, err := c.BulkGrantScopes(ctx, storage.ListClientsRequest{}, scopes); !errors.Is(err, storage.ErrInvalidArgument) {
+		t.Errorf("expected granting scopes to every client without all to be invalid, got %v", err)
+	}
+	if _, err := c.BulkRemoveScopes(ctx, storage.ListClientsRequest{}, scopes); !errors.Is(err, storage.ErrInvalidArgument) {
+		t.Errorf("expected removing scopes from every client without all to be invalid, got %v", err)
+	}
+
+	u := &UserManager{}
+	if _, err := u.BulkGrantScopes(ctx, storage.ListUsersRequest{}, scopes); !errors.Is(err, storage.ErrInvalidArgument) {
+		t.Errorf("expected granting scopes to every user without all to be invalid, got %v", err)
+	}
+	if _, err := u.BulkRemoveScopes(ctx, storage.ListUsersRequest{}, scopes); !errors.Is(err, storage.ErrInvalidArgument) {
+		t.Errorf("expected removing scopes from every user without all to be invalid, got %v", err)
+	}
+}
@@ -0,0 +1,405 @@
+package mongo
+
+import (
+	// Standard Library Imports
+	"bytes"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	// External Imports
+	"github.com/kelseyhightower/envconfig"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.mongodb.org/mongo-driver/x/mongo/driver/connstring"
+	"gopkg.in/yaml.v2"
+
+	// Internal Imports
+	"github.com/matthewhartstonge/storage"
+)
+
+const (
+	// AuthMechanismSCRAMSHA1 provides the SCRAM-SHA-1 authentication
+	// mechanism.
+	AuthMechanismSCRAMSHA1 = "SCRAM-SHA-1"
+
+	// AuthMechanismSCRAMSHA256 provides the SCRAM-SHA-256 authentication
+	// mechanism, supported by mongo 4.0 and above.
+	AuthMechanismSCRAMSHA256 = "SCRAM-SHA-256"
+
+	// AuthMechanismX509 provides the MONGODB-X509 authentication mechanism,
+	// which authenticates using a TLS client certificate.
+	AuthMechanismX509 = "MONGODB-X509"
+
+	// authSourceExternal provides the auth source required for mechanisms
+	// where the credentials are stored outside of mongo.
+	authSourceExternal = "$external"
+
+	// entityConfig provides the entity name reported in configuration errors.
+	entityConfig = "config"
+
+	// maxTimeout provides the maximum connect timeout, in seconds.
+	maxTimeout = 300
+)
+
+// invalidConfig returns an error reporting invalid configuration fields.
+func invalidConfig(reason string, fields ...string) error {
+	return storage.NewInvalidArgumentError(entityConfig, reason, fields...)
+}
+
+// LoadConfig loads the configuration from the environment, as named by each
+// field's `envconfig` tag, applying the defaults provided by each field's
+// `default` tag. The loaded configuration is validated before being returned.
+func LoadConfig() (*Config, error) {
+	return LoadConfigFile("")
+}
+
+// LoadConfigFile loads the configuration from the environment, then overlays
+// the configuration provided by the YAML or JSON file at the given path.
+// Fields not present in the file retain the value loaded from the
+// environment. The format is determined by the file extension, either
+// `.yaml`, `.yml` or `.json`. If path is empty, only the environment is
+// loaded. The loaded configuration is validated before being returned.
+func LoadConfigFile(path string) (*Config, error) {
+	cfg := &Config{}
+	if err := envconfig.Process("", cfg); err != nil {
+		return nil, storage.NewError(storage.ErrInvalidArgument, entityConfig, err)
+	}
+
+	if path != "" {
+		if err := overlayConfigFile(cfg, path); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+
+	return cfg, nil
+}
+
+// overlayConfigFile decodes the YAML or JSON file at the given path over the
+// configuration. Unknown fields are rejected, to catch misspelt options.
+func overlayConfigFile(cfg *Config, path string) error {
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		return storage.NewError(storage.ErrInvalidArgument, entityConfig, err)
+	}
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		err = yaml.UnmarshalStrict(content, cfg)
+
+	case ".json":
+		decoder := json.NewDecoder(bytes.NewReader(content))
+		decoder.DisallowUnknownFields()
+		err = decoder.Decode(cfg)
+
+	default:
+		err = fmt.Errorf("unsupported config file format %q, expected .yaml, .yml or .json", filepath.Ext(path))
+	}
+	if err != nil {
+		return storage.NewError(storage.ErrInvalidArgument, entityConfig, err)
+	}
+
+	return nil
+}
+
+// withDefaults returns a copy of the configuration with defaults applied to
+// unset fields, leaving the configuration itself untouched.
+func (cfg *Config) withDefaults() *Config {
+	resolved := *cfg
+	resolved.Hostnames = append([]string(nil), cfg.Hostnames...)
+	if len(resolved.Hostnames) == 0 {
+		resolved.Hostnames = []string{defaultHost}
+	}
+
+	if resolved.DatabaseName == "" {
+		resolved.DatabaseName = defaultDatabaseName
+		if cs, err := connstring.Parse(cfg.URI); cfg.URI != "" && err == nil && cs.Database != "" {
+			resolved.DatabaseName = cs.Database
+		}
+	}
+
+	if resolved.Timeout == 0 {
+		resolved.Timeout = defaultTimeout
+	}
+
+	if resolved.JanitorInterval == 0 {
+		resolved.JanitorInterval = defaultJanitorInterval
+	}
+
+	return &resolved
+}
+
+// janitorLifespans returns the time after which requests expire, by entity,
+// overriding the defaults with any configured lifespans.
+func (cfg *Config) janitorLifespans() map[string]time.Duration {
+	lifespans := DefaultJanitorLifespans()
+
+	overrides := []struct {
+		lifespan    time.Duration
+		entityNames []string
+	}{
+		{cfg.PARLifespan, []string{storage.EntityPARSessions}},
+		{cfg.AccessTokenLifespan, []string{storage.EntityAccessTokens}},
+		{cfg.RefreshTokenLifespan, []string{storage.EntityRefreshTokens}},
+		{cfg.AuthorizeCodeLifespan, []string{storage.EntityAuthorizationCodes, storage.EntityOpenIDSessions, storage.EntityPKCESessions}},
+	}
+	for _, override := range overrides {
+		if override.lifespan <= 0 {
+			continue
+		}
+		for _, entityName := range override.entityNames {
+			lifespans[entityName] = override.lifespan
+		}
+	}
+
+	return lifespans
+}
+
+// hosts returns the configured hostnames, with the configured port appended
+// to any hostname that does not already specify a port.
+func (cfg *Config) hosts() []string {
+	hosts := make([]string, len(cfg.Hostnames))
+	for i, host := range cfg.Hostnames {
+		hosts[i] = host
+		if cfg.Port == 0 {
+			continue
+		}
+
+		if _, _, err := net.SplitHostPort(host); err != nil {
+			hosts[i] = net.JoinHostPort(host, strconv.Itoa(int(cfg.Port)))
+		}
+	}
+
+	return hosts
+}
+
+// NewClientOptions validates the configuration and returns the options to
+// establish a session with a MongoDB cluster. TLS certificates are loaded from
+// the configured file paths.
+func NewClientOptions(cfg *Config) (*options.ClientOptions, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+
+	dialInfo, err := buildClientOptions(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := dialInfo.Validate(); err != nil {
+		return nil, storage.NewError(storage.ErrInvalidArgument, entityConfig, err)
+	}
+
+	return dialInfo, nil
+}
+
+// Validate returns an error if the configuration is inconsistent, for
+// example, MONGODB-X509 authentication without a client certificate.
+func (cfg *Config) Validate() error {
+	var cs connstring.ConnString
+	if cfg.URI != "" {
+		var err error
+		cs, err = connstring.ParseAndValidate(cfg.URI)
+		if err != nil {
+			return storage.NewError(storage.ErrInvalidArgument, entityConfig, err, "URI")
+		}
+	}
+
+	if cfg.URI == "" {
+		for _, host := range cfg.Hostnames {
+			if strings.TrimSpace(host) == "" || strings.Contains(host, "://") {
+				return invalidConfig(fmt.Sprintf("invalid hostname %q, use URI to provide a connection string", host), "Hostnames")
+			}
+		}
+	}
+
+	if cfg.PoolMaxSize > 0 && cfg.PoolMinSize > cfg.PoolMaxSize {
+		return invalidConfig("the minimum pool size must not exceed the maximum pool size", "PoolMinSize", "PoolMaxSize")
+	}
+
+	if cfg.Timeout > maxTimeout {
+		return invalidConfig(fmt.Sprintf("timeout must not exceed %d seconds", maxTimeout), "Timeout")
+	}
+
+	if cfg.MigrationLockTTL < 0 {
+		return invalidConfig("migration lock TTL must not be negative", "MigrationLockTTL")
+	}
+
+	if cfg.JanitorInterval < 0 {
+		return invalidConfig("janitor interval must not be negative", "JanitorInterval")
+	}
+
+	if cfg.JanitorBatchSize < 0 || cfg.JanitorMaxDeletesPerRun < 0 {
+		return invalidConfig("janitor limits must not be negative", "JanitorBatchSize", "JanitorMaxDeletesPerRun")
+	}
+
+	if cfg.PARLifespan < 0 {
+		return invalidConfig("PAR lifespan must not be negative", "PARLifespan")
+	}
+
+	if cfg.AccessTokenLifespan < 0 || cfg.RefreshTokenLifespan < 0 || cfg.AuthorizeCodeLifespan < 0 {
+		return invalidConfig("token lifespans must not be negative", "AccessTokenLifespan", "RefreshTokenLifespan", "AuthorizeCodeLifespan")
+	}
+
+	if cfg.BulkHashWorkers < 0 {
+		return invalidConfig("bulk hash workers must not be negative", "BulkHashWorkers")
+	}
+
+	if cfg.TLSKeyFile != "" && cfg.TLSCertFile == "" {
+		return invalidConfig("a TLS key file requires a TLS certificate file", "TLSKeyFile", "TLSCertFile")
+	}
+
+	tlsEnabled := cfg.SSL || (cs.SSLSet && cs.SSL)
+	if !tlsEnabled && (cfg.TLSCAFile != "" || cfg.TLSCertFile != "") {
+		return invalidConfig("TLS files are configured, but TLS is not enabled", "SSL")
+	}
+
+	mechanism := strings.ToUpper(cfg.AuthMechanism)
+	if mechanism == "" {
+		mechanism = strings.ToUpper(cs.AuthMechanism)
+	}
+
+	username := cfg.Username
+	if username == "" {
+		username = cs.Username
+	}
+
+	passwordSet := cfg.Password != "" || cs.PasswordSet
+
+	switch mechanism {
+	case "", AuthMechanismSCRAMSHA1, AuthMechanismSCRAMSHA256:
+		if username == "" && passwordSet {
+			return invalidConfig("a password requires a username", "Username")
+		}
+		if mechanism != "" && username == "" {
+			return invalidConfig(fmt.Sprintf("%s requires a username and password", mechanism), "Username", "Password")
+		}
+		if username != "" && !passwordSet {
+			return invalidConfig("a username requires a password", "Password")
+		}
+
+	case AuthMechanismX509:
+		if !tlsEnabled {
+			return invalidConfig(fmt.Sprintf("%s requires TLS to be enabled", mechanism), "SSL")
+		}
+		hasCert := cfg.TLSCertFile != "" ||
+			cs.SSLClientCertificateKeyFileSet ||
+			cs.SSLCertificateFileSet ||
+			(cfg.TLSConfig != nil && (len(cfg.TLSConfig.Certificates) > 0 || cfg.TLSConfig.GetClientCertificate != nil))
+		if !hasCert {
+			return invalidConfig(fmt.Sprintf("%s requires a TLS client certificate", mechanism), "TLSCertFile")
+		}
+		if passwordSet {
+			return invalidConfig(fmt.Sprintf("%s does not accept a password", mechanism), "Password")
+		}
+
+	default:
+		return invalidConfig(fmt.Sprintf("unsupported auth mechanism %q", cfg.AuthMechanism), "AuthMechanism")
+	}
+
+	return nil
+}
+
+// tlsEnabled returns true if the configuration requires TLS, or provides
+// TLS files.
+func (cfg *Config) tlsEnabled() bool {
+	return cfg.SSL || cfg.TLSCAFile != "" || cfg.TLSCertFile != ""
+}
+
+// credential merges the explicitly configured credentials over the
+// credentials provided via the URI, if any. Returns false if no
+// authentication has been configured.
+func credential(cfg *Config, fromURI *options.Credential) (auth options.Credential, ok bool) {
+	if fromURI != nil {
+		auth = *fromURI
+	}
+
+	if fromURI == nil && cfg.Username == "" && cfg.Password == "" && cfg.AuthMechanism == "" {
+		return auth, false
+	}
+
+	if cfg.Username != "" {
+		auth.Username = cfg.Username
+	}
+
+	if cfg.Password != "" {
+		auth.Password = cfg.Password
+		auth.PasswordSet = true
+	}
+
+	if cfg.AuthMechanism != "" {
+		auth.AuthMechanism = strings.ToUpper(cfg.AuthMechanism)
+	}
+
+	switch {
+	case auth.AuthMechanism == AuthMechanismX509:
+		auth.AuthSource = authSourceExternal
+
+	case auth.AuthSource == "":
+		// An auth source specified via the URI takes precedence, as AuthDB
+		// is defaulted.
+		auth.AuthSource = cfg.AuthDB
+	}
+
+	return auth, true
+}
+
+// loadTLSConfig returns the TLS configuration to dial with, loading the
+// certificate authorities and client certificate from the configured file
+// paths. A TLS configuration provided via Config.TLSConfig takes precedence
+// over one configured via the URI.
+func loadTLSConfig(cfg *Config, fromURI *tls.Config) (*tls.Config, error) {
+	var tlsConfig *tls.Config
+	switch {
+	case cfg.TLSConfig != nil:
+		tlsConfig = cfg.TLSConfig.Clone()
+
+	case fromURI != nil:
+		tlsConfig = fromURI
+
+	default:
+		tlsConfig = &tls.Config{}
+	}
+
+	if cfg.TLSCAFile != "" {
+		pem, err := ioutil.ReadFile(cfg.TLSCAFile)
+		if err != nil {
+			return tlsConfig, storage.NewError(storage.ErrInvalidArgument, entityConfig, err, "TLSCAFile")
+		}
+
+		// Load into a fresh pool, as Clone is shallow, so appending to the
+		// provided pool would modify the caller's TLS configuration.
+		rootCAs := x509.NewCertPool()
+		if !rootCAs.AppendCertsFromPEM(pem) {
+			err = errors.New("no PEM encoded certificates found in " + cfg.TLSCAFile)
+			return tlsConfig, storage.NewError(storage.ErrInvalidArgument, entityConfig, err, "TLSCAFile")
+		}
+		tlsConfig.RootCAs = rootCAs
+	}
+
+	if cfg.TLSCertFile != "" {
+		keyFile := cfg.TLSKeyFile
+		if keyFile == "" {
+			keyFile = cfg.TLSCertFile
+		}
+
+		cert, err := tls.LoadX509KeyPair(cfg.TLSCertFile, keyFile)
+		if err != nil {
+			return tlsConfig, storage.NewError(storage.ErrInvalidArgument, entityConfig, err, "TLSCertFile", "TLSKeyFile")
+		}
+		tlsConfig.Certificates = append(append([]tls.Certificate(nil), tlsConfig.Certificates...), cert)
+	}
+
+	return tlsConfig, nil
+}
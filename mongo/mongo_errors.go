@@ -0,0 +1,182 @@
+package mongo
+
+import (
+	// Standard Library Imports
+	"context"
+	"errors"
+	"regexp"
+	"strings"
+
+	// External Imports
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/x/mongo/driver/topology"
+
+	// Internal Imports
+	"github.com/matthewhartstonge/storage"
+)
+
+const (
+	// errCodeDuplicate provides the mongo error code for duplicate key error.
+	errCodeDuplicate = 11000
+
+	// errCodeBadValue provides the mongo error code for an invalid value.
+	errCodeBadValue = 2
+
+	// errCodeFailedToParse provides the mongo error code for a query, or
+	// document, that could not be parsed.
+	errCodeFailedToParse = 9
+
+	// errCodeUnauthorized provides the mongo error code for an
+	// unauthorized command.
+	errCodeUnauthorized = 13
+
+	// errCodeAuthenticationFailed provides the mongo error code for failing to
+	// authenticate the connection.
+	errCodeAuthenticationFailed = 18
+
+	// errCodeNamespaceNotFound provides the mongo error code for a
+	// collection that doesn't exist.
+	errCodeNamespaceNotFound = 26
+
+	// errCodeIndexNotFound provides the mongo error code for an index that
+	// doesn't exist.
+	errCodeIndexNotFound = 27
+
+	// errCodeCommandNotFound provides the mongo error code for a command that
+	// is not supported by the server.
+	errCodeCommandNotFound = 59
+
+	// errCodeWriteConflict provides the mongo error code for a write conflict
+	// within a transaction.
+	errCodeWriteConflict = 112
+
+	// errCodeNotWritablePrimary provides the mongo error code for a write
+	// being sent to a node that is no longer the primary.
+	errCodeNotWritablePrimary = 10107
+)
+
+// dupKeyRegex extracts the duplicated key document from a duplicate key error
+// message, for example: `dup key: { username: "bob" }`.
+var dupKeyRegex = regexp.MustCompile(`dup key: \{\s*(.*?)\s*\}`)
+
+// dupIndexRegex extracts the name of the index that caused a duplicate key
+// error, for example: `index: idxUsername dup key`.
+var dupIndexRegex = regexp.MustCompile(`index: (\S+) dup key`)
+
+// isDup replicates mgo.IsDup functionality for the official driver in order
+// to know when a conflict has occurred.
+func isDup(err error) (isDup bool) {
+	var e mongo.WriteException
+	if errors.As(err, &e) {
+		for _, we := range e.WriteErrors {
+			if we.Code == errCodeDuplicate {
+				return true
+			}
+		}
+	}
+
+	return
+}
+
+// dupKeyFields returns the names of the fields that caused a duplicate key
+// error. If the fields can't be determined, the name of the conflicting index
+// is returned instead.
+func dupKeyFields(err error) (fields []string) {
+	var e mongo.WriteException
+	if !errors.As(err, &e) {
+		return nil
+	}
+
+	for _, we := range e.WriteErrors {
+		if we.Code != errCodeDuplicate {
+			continue
+		}
+
+		if match := dupKeyRegex.FindStringSubmatch(we.Message); len(match) == 2 && match[1] != "" {
+			for _, kv := range strings.Split(match[1], ", ") {
+				if i := strings.Index(kv, ":"); i > 0 {
+					fields = append(fields, strings.TrimSpace(kv[:i]))
+				}
+			}
+			continue
+		}
+
+		if match := dupIndexRegex.FindStringSubmatch(we.Message); len(match) == 2 {
+			fields = append(fields, match[1])
+		}
+	}
+
+	return fields
+}
+
+// hasErrorCode returns true if the error, or any of the errors it wraps, was
+// returned by the server with one of the given error codes.
+func hasErrorCode(err error, codes ...int) bool {
+	var e mongo.ServerError
+	if !errors.As(err, &e) {
+		return false
+	}
+
+	for _, code := range codes {
+		if e.HasErrorCode(code) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// isUnavailable returns true if the error was caused by mongo being
+// unreachable, a timeout, or a transient failure that may succeed if retried.
+func isUnavailable(err error) bool {
+	if errors.Is(err, context.DeadlineExceeded) ||
+		mongo.IsTimeout(err) ||
+		mongo.IsNetworkError(err) ||
+		errors.Is(err, topology.ErrServerSelectionTimeout) ||
+		errors.Is(err, mongo.ErrClientDisconnected) {
+		return true
+	}
+
+	var selectionErr topology.ServerSelectionError
+	if errors.As(err, &selectionErr) {
+		return true
+	}
+
+	var e mongo.ServerError
+	if errors.As(err, &e) {
+		if e.HasErrorLabel("TransientTransactionError") ||
+			e.HasErrorLabel("RetryableWriteError") {
+			return true
+		}
+	}
+
+	return hasErrorCode(err, errCodeWriteConflict, errCodeNotWritablePrimary)
+}
+
+// toStorageError maps errors returned by the mongo driver to the
+// corresponding backend neutral storage error. Errors that can't be mapped
+// are returned as is.
+func toStorageError(entityName string, err error) error {
+	switch {
+	case err == nil:
+		return nil
+
+	case errors.Is(err, mongo.ErrNoDocuments):
+		return storage.NewNotFoundError(entityName)
+
+	case isDup(err):
+		return storage.NewConflictError(entityName, err, dupKeyFields(err)...)
+
+	case hasErrorCode(err, errCodeAuthenticationFailed, errCodeUnauthorized):
+		return storage.NewAuthenticationFailedError(entityName, err)
+
+	case hasErrorCode(err, errCodeBadValue, errCodeFailedToParse):
+		return storage.NewError(storage.ErrInvalidArgument, entityName, err)
+
+	case isUnavailable(err):
+		return storage.NewError(storage.ErrUnavailable, entityName, err)
+
+	default:
+		return err
+	}
+}
@@ -0,0 +1,93 @@
+package mongo
+
+import (
+	// Standard Library Imports
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	// External Imports
+	"github.com/ory/fosite"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	// Internal Imports
+	"github.com/matthewhartstonge/storage"
+)
+
+func TestToStorageError(t *testing.T) {
+	dupErr := mongo.WriteException{
+		WriteErrors: mongo.WriteErrors{{
+			Code:    errCodeDuplicate,
+			Message: `E11000 duplicate key error collection: oauth2.users index: idxUsername dup key: { username: "bob" }`,
+		}},
+	}
+
+	tests := []struct {
+		name   string
+		err    error
+		want   []error
+		fields []string
+	}{
+		{
+			name: "should map no documents to not found",
+			err:  mongo.ErrNoDocuments,
+			want: []error{storage.ErrNotFound, fosite.ErrNotFound},
+		},
+		{
+			name:   "should map duplicate keys to a conflict",
+			err:    dupErr,
+			want:   []error{storage.ErrResourceExists},
+			fields: []string{"username"},
+		},
+		{
+			name: "should map authentication failures",
+			err:  mongo.CommandError{Code: errCodeAuthenticationFailed, Message: "auth failed"},
+			want: []error{storage.ErrAuthenticationFailed},
+		},
+		{
+			name: "should map bad values to invalid arguments",
+			err:  mongo.CommandError{Code: errCodeBadValue, Message: "bad value"},
+			want: []error{storage.ErrInvalidArgument},
+		},
+		{
+			name: "should map timeouts to unavailable",
+			err:  context.DeadlineExceeded,
+			want: []error{storage.ErrUnavailable},
+		},
+		{
+			name: "should map transient errors to unavailable",
+			err:  mongo.CommandError{Code: errCodeWriteConflict, Labels: []string{"TransientTransactionError"}},
+			want: []error{storage.ErrUnavailable},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := toStorageError(storage.EntityUsers, tt.err)
+			for _, want := range tt.want {
+				if !errors.Is(got, want) {
+					t.Errorf("toStorageError() = %v, want %v", got, want)
+				}
+			}
+
+			var storeErr *storage.Error
+			if !errors.As(got, &storeErr) {
+				t.Fatalf("toStorageError() = %T, want *storage.Error", got)
+			}
+			if storeErr.Entity != storage.EntityUsers {
+				t.Errorf("entity = %v, want %v", storeErr.Entity, storage.EntityUsers)
+			}
+			if !reflect.DeepEqual(storeErr.Fields, tt.fields) {
+				t.Errorf("fields = %#v, want %#v", storeErr.Fields, tt.fields)
+			}
+		})
+	}
+
+	unknown := errors.New("unknown")
+	if got := toStorageError(storage.EntityUsers, unknown); got != unknown {
+		t.Errorf("toStorageError() = %v, want errors to be passed through as is", got)
+	}
+	if got := toStorageError(storage.EntityUsers, nil); got != nil {
+		t.Errorf("toStorageError() = %v, want nil", got)
+	}
+}
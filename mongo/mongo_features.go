@@ -0,0 +1,96 @@
+package mongo
+
+import (
+	// Standard Library Imports
+	"context"
+
+	// External Imports
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+const (
+	// wireVersionReplicaSetTransactions provides the minimum wire version
+	// (mongo 4.0) that supports transactions on replica sets.
+	wireVersionReplicaSetTransactions = 7
+
+	// wireVersionShardedTransactions provides the minimum wire version
+	// (mongo 4.2) that supports transactions on sharded clusters.
+	wireVersionShardedTransactions = 8
+
+	// mongosMessage provides the message returned by mongos in the hello
+	// reply.
+	mongosMessage = "isdbgrid"
+)
+
+// helloReply provides the topology information returned by the `hello`, or
+// legacy `isMaster`, command. Neither command requires admin permissions.
+type helloReply struct {
+	// SetName contains the name of the replica set, if the server is a
+	// member of one.
+	SetName string `bson:"setName"`
+
+	// Msg contains "isdbgrid" if the server is a mongos.
+	Msg string `bson:"msg"`
+
+	// MaxWireVersion contains the newest wire protocol version the server
+	// supports.
+	MaxWireVersion int32 `bson:"maxWireVersion"`
+
+	// LogicalSessionTimeoutMinutes is only returned if the server supports
+	// sessions.
+	LogicalSessionTimeoutMinutes *int64 `bson:"logicalSessionTimeoutMinutes"`
+}
+
+// hasSessions returns true if the server supports sessions.
+func (h helloReply) hasSessions() bool {
+	return h.LogicalSessionTimeoutMinutes != nil
+}
+
+// hasTransactions returns true if the server supports multi-document
+// transactions. Standalone servers never support transactions.
+func (h helloReply) hasTransactions() bool {
+	if !h.hasSessions() {
+		return false
+	}
+
+	switch {
+	case h.Msg == mongosMessage:
+		return h.MaxWireVersion >= wireVersionShardedTransactions
+
+	case h.SetName != "":
+		return h.MaxWireVersion >= wireVersionReplicaSetTransactions
+
+	default:
+		return false
+	}
+}
+
+// hello returns the topology information of the connected server. `hello` is
+// attempted first, falling back to `isMaster` for servers older than 4.4.2.
+func hello(ctx context.Context, database *mongo.Database) (reply helloReply, err error) {
+	err = database.RunCommand(ctx, bson.D{{Key: "hello", Value: 1}}).Decode(&reply)
+	if err != nil && hasErrorCode(err, errCodeCommandNotFound) {
+		err = database.RunCommand(ctx, bson.D{{Key: "isMaster", Value: 1}}).Decode(&reply)
+	}
+
+	return reply, err
+}
+
+// detectFeatures probes the connected server in order to detect which
+// features the database supports.
+func detectFeatures(ctx context.Context, database *mongo.Database) (*DB, error) {
+	db := &DB{
+		Database: database,
+	}
+
+	reply, err := hello(ctx, database)
+	if err != nil {
+		return db, err
+	}
+
+	db.HasSessions = reply.hasSessions()
+	db.HasTransactions = reply.hasTransactions()
+
+	return db, nil
+}
@@ -0,0 +1,86 @@
+package mongo
+
+import (
+	// Standard Library Imports
+	"testing"
+)
+
+func TestHelloReply_Features(t *testing.T) {
+	sessionTimeout := int64(30)
+
+	tests := []struct {
+		name            string
+		reply           helloReply
+		hasSessions     bool
+		hasTransactions bool
+	}{
+		{
+			name: "standalone",
+			reply: helloReply{
+				MaxWireVersion:               13,
+				LogicalSessionTimeoutMinutes: &sessionTimeout,
+			},
+			hasSessions:     true,
+			hasTransactions: false,
+		},
+		{
+			name: "standalone without sessions",
+			reply: helloReply{
+				MaxWireVersion: 5,
+			},
+			hasSessions:     false,
+			hasTransactions: false,
+		},
+		{
+			name: "replica set mongo 4.0",
+			reply: helloReply{
+				SetName:                      "rs0",
+				MaxWireVersion:               7,
+				LogicalSessionTimeoutMinutes: &sessionTimeout,
+			},
+			hasSessions:     true,
+			hasTransactions: true,
+		},
+		{
+			name: "replica set mongo 3.6",
+			reply: helloReply{
+				SetName:                      "rs0",
+				MaxWireVersion:               6,
+				LogicalSessionTimeoutMinutes: &sessionTimeout,
+			},
+			hasSessions:     true,
+			hasTransactions: false,
+		},
+		{
+			name: "mongos mongo 4.2",
+			reply: helloReply{
+				Msg:                          mongosMessage,
+				MaxWireVersion:               8,
+				LogicalSessionTimeoutMinutes: &sessionTimeout,
+			},
+			hasSessions:     true,
+			hasTransactions: true,
+		},
+		{
+			name: "mongos mongo 4.0",
+			reply: helloReply{
+				Msg:                          mongosMessage,
+				MaxWireVersion:               7,
+				LogicalSessionTimeoutMinutes: &sessionTimeout,
+			},
+			hasSessions:     true,
+			hasTransactions: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.reply.hasSessions(); got != tt.hasSessions {
+				t.Errorf("hasSessions() = %v, expected %v", got, tt.hasSessions)
+			}
+			if got := tt.reply.hasTransactions(); got != tt.hasTransactions {
+				t.Errorf("hasTransactions() = %v, expected %v", got, tt.hasTransactions)
+			}
+		})
+	}
+}
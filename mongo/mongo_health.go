@@ -0,0 +1,232 @@
+package mongo
+
+import (
+	// Standard Library Imports
+	"context"
+	"time"
+
+	// External Imports
+	"go.mongodb.org/mongo-driver/mongo/readpref"
+)
+
+const (
+	// defaultHealthTimeout provides the maximum time each health check is
+	// given to complete.
+	defaultHealthTimeout = 2 * time.Second
+
+	// defaultPoolSaturationThreshold provides the ratio of in use to maximum
+	// connections at which a connection pool is reported as saturated.
+	defaultPoolSaturationThreshold = 0.9
+)
+
+// HealthStatus provides the overall health of the store.
+type HealthStatus string
+
+const (
+	// HealthStatusUp reports the store is fully operational.
+	HealthStatusUp HealthStatus = "up"
+
+	// HealthStatusDegraded reports the store can reach mongo, but is not
+	// ready to serve requests reliably. For example, the primary is
+	// unreachable, indexes are missing or the connection pool is saturated.
+	HealthStatusDegraded HealthStatus = "degraded"
+
+	// HealthStatusDown reports the store can't reach mongo.
+	HealthStatusDown HealthStatus = "down"
+)
+
+// Health provides the structured result of the store's health checks.
+type Health struct {
+	// Status reports the overall health of the store.
+	Status HealthStatus `json:"status"`
+
+	// Live reports whether mongo is reachable. Suitable for liveness probes.
+	Live bool `json:"live"`
+
+	// Ready reports whether the store is ready to serve requests. Suitable
+	// for readiness probes.
+	Ready bool `json:"ready"`
+
+	// CheckedAt is when the health checks were run.
+	CheckedAt time.Time `json:"checkedAt"`
+
+	Ping     PingHealth     `json:"ping"`
+	Topology TopologyHealth `json:"topology"`
+	Indexes  IndexHealth    `json:"indexes"`
+	Pool     PoolHealth     `json:"pool"`
+}
+
+// PingHealth provides the result of pinging mongo.
+type PingHealth struct {
+	OK        bool   `json:"ok"`
+	LatencyMS int64  `json:"latencyMs"`
+	Error     string `json:"error,omitempty"`
+}
+
+// TopologyHealth provides the topology of the connected deployment.
+type TopologyHealth struct {
+	// PrimaryReachable reports whether a primary could be selected, which
+	// is required for writes.
+	PrimaryReachable bool `json:"primaryReachable"`
+
+	// ReplicaSet contains the name of the replica set, if connected to one.
+	ReplicaSet string `json:"replicaSet,omitempty"`
+
+	// Sharded reports whether connected to a sharded cluster via mongos.
+	Sharded bool `json:"sharded"`
+
+	// ReadPreference contains the read preference in effect.
+	ReadPreference string `json:"readPreference"`
+
+	// HasTransactions reports whether multi-document transactions are
+	// supported.
+	HasTransactions bool `json:"hasTransactions"`
+
+	Error string `json:"error,omitempty"`
+}
+
+// IndexHealth provides the result of verifying the indexes created by each
+// manager's Configure still exist.
+type IndexHealth struct {
+	OK bool `json:"ok"`
+
+	// Missing contains the missing indexes, formatted as
+	// `collection.index`.
+	Missing []string `json:"missing,omitempty"`
+
+	Error string `json:"error,omitempty"`
+}
+
+// PoolHealth provides the usage of the connection pools.
+type PoolHealth struct {
+	// Open contains the number of open connections across all pools.
+	Open uint64 `json:"open"`
+
+	// InUse contains the number of checked out connections across all pools.
+	InUse uint64 `json:"inUse"`
+
+	// MaxSize contains the maximum number of connections across all pools.
+	MaxSize uint64 `json:"maxSize"`
+
+	// Saturation contains the highest ratio of in use to maximum
+	// connections of any single pool.
+	Saturation float64 `json:"saturation"`
+
+	// Saturated reports whether any pool is close to being exhausted.
+	Saturated bool `json:"saturated"`
+
+	Servers []ServerPoolHealth `json:"servers,omitempty"`
+}
+
+// ServerPoolHealth provides the usage of a single server's connection pool.
+type ServerPoolHealth struct {
+	Address    string  `json:"address"`
+	Open       uint64  `json:"open"`
+	InUse      uint64  `json:"inUse"`
+	MaxSize    uint64  `json:"maxSize"`
+	Saturation float64 `json:"saturation"`
+}
+
+// Health checks connectivity with a timed ping, reports the topology in
+// effect, verifies the indexes created by each manager's Configure still
+// exist and reports connection pool saturation.
+func (s *Store) Health(ctx context.Context) Health {
+	log := newLogger(ctx, s.logger, Fields{
+		"package": "mongo",
+		"method":  "Health",
+	})
+
+	health := Health{
+		CheckedAt: time.Now(),
+	}
+
+	health.Ping = s.pingHealth(ctx)
+	if !health.Ping.OK {
+		health.Status = HealthStatusDown
+		log.WithFields(Fields{"error": health.Ping.Error}).Warn("mongo is unreachable")
+		return health
+	}
+	health.Live = true
+
+	health.Topology = s.topologyHealth(ctx)
+	health.Indexes = s.indexHealth(ctx)
+	health.Pool = s.DB.pool.health(defaultPoolSaturationThreshold)
+
+	health.Ready = health.Topology.PrimaryReachable &&
+		health.Indexes.OK &&
+		!health.Pool.Saturated
+
+	health.Status = HealthStatusUp
+	if !health.Ready {
+		health.Status = HealthStatusDegraded
+		log.WithFields(Fields{
+			"primaryReachable": health.Topology.PrimaryReachable,
+			"missingIndexes":   health.Indexes.Missing,
+			"poolSaturation":   health.Pool.Saturation,
+		}).Warn("mongo is degraded")
+	}
+
+	return health
+}
+
+// pingHealth pings mongo, recording the latency.
+func (s *Store) pingHealth(ctx context.Context) (health PingHealth) {
+	ctx, cancel := context.WithTimeout(ctx, defaultHealthTimeout)
+	defer cancel()
+
+	start := time.Now()
+	err := s.DB.Client().Ping(ctx, nil)
+	health.LatencyMS = time.Since(start).Milliseconds()
+	if err != nil {
+		health.Error = err.Error()
+		return health
+	}
+
+	health.OK = true
+	return health
+}
+
+// topologyHealth reports the topology of the connected deployment.
+func (s *Store) topologyHealth(ctx context.Context) (health TopologyHealth) {
+	ctx, cancel := context.WithTimeout(ctx, defaultHealthTimeout)
+	defer cancel()
+
+	health.HasTransactions = s.DB.HasTransactions
+	if rp := s.DB.ReadPreference(); rp != nil {
+		health.ReadPreference = rp.Mode().String()
+	}
+
+	reply, err := hello(ctx, s.DB.Database)
+	if err != nil {
+		health.Error = err.Error()
+		return health
+	}
+	health.ReplicaSet = reply.SetName
+	health.Sharded = reply.Msg == mongosMessage
+
+	err = s.DB.Client().Ping(ctx, readpref.Primary())
+	if err != nil {
+		health.Error = err.Error()
+		return health
+	}
+	health.PrimaryReachable = true
+
+	return health
+}
+
+// indexHealth verifies the indexes created by each manager's Configure still
+// exist.
+func (s *Store) indexHealth(ctx context.Context) (health IndexHealth) {
+	ctx, cancel := context.WithTimeout(ctx, defaultHealthTimeout)
+	defer cancel()
+
+	missing, err := s.DB.missingIndexes(ctx)
+	if err != nil {
+		health.Error = err.Error()
+		return health
+	}
+
+	health.Missing = missing
+	health.OK = len(missing) == 0
+	return health
+}
@@ -0,0 +1,68 @@
+package mongo
+
+import (
+	// Standard Library Imports
+	"encoding/json"
+	"net/http"
+)
+
+// HealthHandler serves the store's health as JSON, responding with
+// `200 OK` if healthy, otherwise `503 Service Unavailable`.
+type HealthHandler struct {
+	Store *Store
+
+	// Readiness, if true, reports the store as healthy only if it is ready
+	// to serve requests. Otherwise, the store is reported as healthy if mongo
+	// is reachable, which is suitable for liveness probes.
+	Readiness bool
+}
+
+// NewLivenessHandler returns an http.Handler suitable for liveness probes.
+func NewLivenessHandler(store *Store) http.Handler {
+	return &HealthHandler{
+		Store: store,
+	}
+}
+
+// NewReadinessHandler returns an http.Handler suitable for readiness probes.
+func NewReadinessHandler(store *Store) http.Handler {
+	return &HealthHandler{
+		Store:     store,
+		Readiness: true,
+	}
+}
+
+// ServeHTTP implements http.Handler.
+func (h *HealthHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+
+	health := h.Store.Health(r.Context())
+
+	healthy := health.Live
+	if h.Readiness {
+		healthy = health.Ready
+	}
+
+	status := http.StatusOK
+	if !healthy {
+		status = http.StatusServiceUnavailable
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(status)
+	if r.Method == http.MethodHead {
+		return
+	}
+
+	if err := json.NewEncoder(w).Encode(health); err != nil {
+		newLogger(r.Context(), h.Store.logger, Fields{
+			"package": "mongo",
+			"method":  "HealthHandler.ServeHTTP",
+		}).WithError(err).Error("error writing health response")
+	}
+}
@@ -0,0 +1,19 @@
+package mongo
+
+import (
+	// Standard Library Imports
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestHealthHandler_MethodNotAllowed(t *testing.T) {
+	h := NewReadinessHandler(&Store{})
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/health", nil))
+
+	if w.Code != http.StatusMethodNotAllowed {
+		t.Errorf("expected status %d, got %d", http.StatusMethodNotAllowed, w.Code)
+	}
+}
@@ -0,0 +1,118 @@
+package mongo
+
+import (
+	// Standard Library Imports
+	"context"
+	"sort"
+	"sync"
+
+	// External Imports
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// indexRegistry records the indexes created by each manager's Configure, in
+// order for health checks to verify they still exist.
+type indexRegistry struct {
+	mu sync.Mutex
+
+	// indexes contains the names of the indexes created, keyed by
+	// collection.
+	indexes map[string]map[string]bool
+}
+
+// record records the named indexes as expected on the collection.
+func (r *indexRegistry) record(entityName string, indices []mongo.IndexModel) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.indexes == nil {
+		r.indexes = map[string]map[string]bool{}
+	}
+	if r.indexes[entityName] == nil {
+		r.indexes[entityName] = map[string]bool{}
+	}
+
+	for _, index := range indices {
+		if index.Options != nil && index.Options.Name != nil {
+			r.indexes[entityName][*index.Options.Name] = true
+		}
+	}
+}
+
+// expected returns the names of the recorded indexes, keyed by collection.
+func (r *indexRegistry) expected() map[string][]string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	expected := make(map[string][]string, len(r.indexes))
+	for entityName, indexes := range r.indexes {
+		for name := range indexes {
+			expected[entityName] = append(expected[entityName], name)
+		}
+		sort.Strings(expected[entityName])
+	}
+
+	return expected
+}
+
+// createIndexes creates the indexes on the given collection, recording them
+// in order for health checks to verify they still exist.
+func (d *DB) createIndexes(ctx context.Context, entityName string, indices []mongo.IndexModel) error {
+	_, err := d.Collection(entityName).Indexes().CreateMany(ctx, indices)
+	if err != nil {
+		return err
+	}
+
+	d.indexes.record(entityName, indices)
+	return nil
+}
+
+// listIndexes returns the names of the indexes that exist on the given
+// collection.
+func (d *DB) listIndexes(ctx context.Context, entityName string) (map[string]bool, error) {
+	cursor, err := d.Collection(entityName).Indexes().List(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var indexes []struct {
+		Name string `bson:"name"`
+	}
+	if err := cursor.All(ctx, &indexes); err != nil {
+		return nil, err
+	}
+
+	names := make(map[string]bool, len(indexes))
+	for _, index := range indexes {
+		names[index.Name] = true
+	}
+
+	return names, nil
+}
+
+// missingIndexes returns the recorded indexes that no longer exist, formatted
+// as `collection.index`.
+func (d *DB) missingIndexes(ctx context.Context) (missing []string, err error) {
+	expected := d.indexes.expected()
+
+	entityNames := make([]string, 0, len(expected))
+	for entityName := range expected {
+		entityNames = append(entityNames, entityName)
+	}
+	sort.Strings(entityNames)
+
+	for _, entityName := range entityNames {
+		existing, err := d.listIndexes(ctx, entityName)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, name := range expected[entityName] {
+			if !existing[name] {
+				missing = append(missing, entityName+"."+name)
+			}
+		}
+	}
+
+	return missing, nil
+}
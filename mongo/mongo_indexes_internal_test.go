@@ -0,0 +1,35 @@
+package mongo
+
+import (
+	// Standard Library Imports
+	"testing"
+
+	// External Imports
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+func TestIndexRegistry_Expected(t *testing.T) {
+	r := &indexRegistry{}
+	r.record("clients", []mongo.IndexModel{
+		{Options: options.Index().SetName(IdxClientID)},
+		{Options: options.Index()},
+		{},
+	})
+	r.record("users", []mongo.IndexModel{
+		{Options: options.Index().SetName(IdxUsernameCaseInsensitive)},
+		{Options: options.Index().SetName(IdxUserID)},
+	})
+	r.record("clients", []mongo.IndexModel{
+		{Options: options.Index().SetName(IdxClientID)},
+	})
+
+	got := r.expected()
+	if len(got["clients"]) != 1 || got["clients"][0] != IdxClientID {
+		t.Errorf("expected named client indexes to be recorded once, got %v", got["clients"])
+	}
+
+	if len(got["users"]) != 2 || got["users"][0] != IdxUserID || got["users"][1] != IdxUsernameCaseInsensitive {
+		t.Errorf("expected user indexes to be recorded in order, got %v", got["users"])
+	}
+}
@@ -0,0 +1,167 @@
+package mongo
+
+import (
+	// Standard Library Imports
+	"context"
+	"time"
+
+	// External Imports
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// lease provides a distributed, expiring lock stored as a document in a mongo
+// collection. The collection must have a unique index on `id`.
+//
+// A lease is held by a single owner until it is released, or until it
+// expires, which ensures a crashed holder can't hold the lease forever.
+type lease struct {
+	// collection stores the lease document.
+	collection *mongo.Collection
+
+	// id identifies the lease document.
+	id string
+
+	// owner uniquely identifies the holder of the lease.
+	owner string
+
+	// ttl specifies how long the lease is held before it expires.
+	ttl time.Duration
+}
+
+// leaseDocument provides the stored structure of a lease.
+type leaseDocument struct {
+	ID      string `bson:"id"`
+	Owner   string `bson:"owner"`
+	Expires int64  `bson:"expires"`
+}
+
+// tryAcquire attempts to acquire, or extend, the lease. Returns false if the
+// lease is currently held by another owner.
+func (l *lease) tryAcquire(ctx context.Context) (bool, error) {
+	now := time.Now()
+
+	// Match the lease if it has expired, or is already ours. If the lease is
+	// held by someone else, the upsert will attempt to insert a new lease
+	// document, which will fail on the unique index.
+	selector := bson.M{
+		"id": l.id,
+		"$or": []bson.M{
+			{"expires": bson.M{"$lt": now.Unix()}},
+			{"owner": l.owner},
+		},
+	}
+	update := bson.M{
+		"$set": leaseDocument{
+			ID:      l.id,
+			Owner:   l.owner,
+			Expires: now.Add(l.ttl).Unix(),
+		},
+	}
+
+	opts := options.Update().SetUpsert(true)
+	_, err := l.collection.UpdateOne(ctx, selector, update, opts)
+	if err != nil {
+		if isDup(err) {
+			return false, nil
+		}
+
+		return false, err
+	}
+
+	return true, nil
+}
+
+// acquire blocks until the lease is acquired, polling at the provided
+// interval, or until the context is done.
+func (l *lease) acquire(ctx context.Context, interval time.Duration) error {
+	for {
+		ok, err := l.tryAcquire(ctx)
+		if err != nil {
+			return err
+		}
+		if ok {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(interval):
+		}
+	}
+}
+
+// release releases the lease, if held by the owner.
+func (l *lease) release(ctx context.Context) error {
+	_, err := l.collection.DeleteOne(ctx, bson.M{
+		"id":    l.id,
+		"owner": l.owner,
+	})
+
+	return err
+}
+
+// renewInterval returns how often a held lease is renewed, so that it is
+// renewed several times before it expires.
+func (l *lease) renewInterval() time.Duration {
+	interval := l.ttl / 3
+	if interval < time.Second {
+		// Lease expiry is recorded in seconds.
+		interval = time.Second
+	}
+
+	return interval
+}
+
+// keepAlive renews the held lease in the background until stop is called.
+// The returned context is cancelled if the lease is lost, either to another
+// owner, or because it couldn't be renewed before it expired, so that work
+// done under the lease stops rather than racing the new holder.
+func (l *lease) keepAlive(ctx context.Context) (leaseCtx context.Context, stop func()) {
+	leaseCtx, cancel := context.WithCancel(ctx)
+	done := make(chan struct{})
+
+	// Renew on a context free of any mongo session bound to ctx, as sessions
+	// can't be used concurrently.
+	renewCtx, cancelRenew := context.WithCancel(context.Background())
+
+	go func() {
+		defer close(done)
+		defer cancelRenew()
+
+		ticker := time.NewTicker(l.renewInterval())
+		defer ticker.Stop()
+
+		renewed := time.Now()
+		for {
+			select {
+			case <-leaseCtx.Done():
+				return
+
+			case <-ticker.C:
+				ok, err := l.tryAcquire(renewCtx)
+				switch {
+				case err == nil && ok:
+					renewed = time.Now()
+
+				case err == nil && !ok:
+					// Held by another owner.
+					cancel()
+					return
+
+				case time.Since(renewed) >= l.ttl:
+					// Unable to renew the lease before it expired.
+					cancel()
+					return
+				}
+			}
+		}
+	}()
+
+	return leaseCtx, func() {
+		cancel()
+		<-done
+	}
+}
@@ -0,0 +1,24 @@
+package mongo
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLease_renewInterval(t *testing.T) {
+	tests := []struct {
+		ttl      time.Duration
+		expected time.Duration
+	}{
+		{ttl: 5 * time.Minute, expected: 100 * time.Second},
+		{ttl: 3 * time.Second, expected: time.Second},
+		{ttl: time.Second, expected: time.Second},
+	}
+
+	for _, tt := range tests {
+		l := &lease{ttl: tt.ttl}
+		if got := l.renewInterval(); got != tt.expected {
+			t.Errorf("ttl %v: expected renew interval %v, got %v", tt.ttl, tt.expected, got)
+		}
+	}
+}
@@ -18,9 +18,21 @@ const (
 	// IdxUserID provides a mongo index based on userId
 	IdxUserID = "idxUserId"
 
-	// IdxUsername provides a mongo index based on username
+	// IdxUsername provides the name of the case sensitive username index,
+	// superseded by IdxUsernameCaseInsensitive and dropped by the
+	// normalizeUsernames migration.
 	IdxUsername = "idxUsername"
 
+	// IdxUsernameCaseInsensitive provides a unique mongo index based on
+	// username, compared case insensitively
+	IdxUsernameCaseInsensitive = "idxUsernameCaseInsensitive"
+
+	// IdxUserSearch provides a mongo text index used to search users
+	IdxUserSearch = "idxUserSearch"
+
+	// IdxEmail provides a unique mongo index based on email address
+	IdxEmail = "idxEmail"
+
 	// IdxSessionID provides a mongo index based on Session
 	IdxSessionID = "idxSessionId"
 
@@ -30,6 +42,57 @@ const (
 	// IdxCompoundRequester provides a mongo compound index based on Client ID
 	// and User ID for when filtering request records.
 	IdxCompoundRequester = "idxCompoundRequester"
+
+	// IdxConsentID provides a mongo index based on consent ID
+	IdxConsentID = "idxConsentId"
+
+	// IdxCompoundConsent provides a unique mongo compound index based on User
+	// ID and Client ID, ensuring a user has one consent per client.
+	IdxCompoundConsent = "idxCompoundConsent"
+
+	// IdxDeviceCodeID provides a mongo index based on device code ID
+	IdxDeviceCodeID = "idxDeviceCodeId"
+
+	// IdxUserCode provides a mongo index based on the user code of pending
+	// device codes
+	IdxUserCode = "idxUserCode"
+
+	// IdxGroupID provides a mongo index based on group ID
+	IdxGroupID = "idxGroupId"
+
+	// IdxGroupName provides a unique mongo index based on group name
+	IdxGroupName = "idxGroupName"
+
+	// IdxGroups provides a mongo index based on the groups a user is a
+	// member of
+	IdxGroups = "idxGroups"
+
+	// IdxIssuerTrustID provides a mongo index based on issuer trust ID
+	IdxIssuerTrustID = "idxIssuerTrustId"
+
+	// IdxCompoundIssuerTrust provides a unique mongo compound index based on
+	// Issuer, Subject and Key ID, ensuring an issuer has one trust
+	// relationship per subject and key.
+	IdxCompoundIssuerTrust = "idxCompoundIssuerTrust"
+
+	// IdxLoginSessionID provides a mongo index based on the hashed login
+	// session ID
+	IdxLoginSessionID = "idxLoginSessionId"
+
+	// IdxScopeID provides a mongo index based on scope ID
+	IdxScopeID = "idxScopeId"
+
+	// IdxScopeName provides a unique mongo index based on scope name
+	IdxScopeName = "idxScopeName"
+
+	// IdxTenantID provides a mongo index based on tenantId
+	IdxTenantID = "idxTenantId"
+
+	// IdxMigrationID provides a mongo index based on migration ID
+	IdxMigrationID = "idxMigrationId"
+
+	// IdxLeaseID provides a mongo index based on lease ID
+	IdxLeaseID = "idxLeaseId"
 )
 
 // SessionToContext provides a way to push a mongo datastore session into the
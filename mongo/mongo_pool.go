@@ -0,0 +1,129 @@
+package mongo
+
+import (
+	// Standard Library Imports
+	"sort"
+	"sync"
+
+	// External Imports
+	"go.mongodb.org/mongo-driver/event"
+)
+
+// driverDefaultPoolMaxSize provides the driver's default maximum pool size,
+// used if a pool doesn't report its maximum size.
+const driverDefaultPoolMaxSize = 100
+
+// poolMonitor tracks connection pool usage per server, in order to report
+// pool saturation.
+type poolMonitor struct {
+	mu    sync.Mutex
+	pools map[string]*poolUsage
+
+	// next contains a pool monitor to forward events to, if one was
+	// configured on the client options.
+	next *event.PoolMonitor
+}
+
+// poolUsage provides the usage of a single server's connection pool.
+type poolUsage struct {
+	open    uint64
+	inUse   uint64
+	maxSize uint64
+}
+
+// newPoolMonitor returns a pool monitor, which forwards events to next, if
+// provided.
+func newPoolMonitor(next *event.PoolMonitor) *poolMonitor {
+	return &poolMonitor{
+		pools: map[string]*poolUsage{},
+		next:  next,
+	}
+}
+
+// monitor returns the driver pool monitor that feeds the pool monitor.
+func (p *poolMonitor) monitor() *event.PoolMonitor {
+	return &event.PoolMonitor{
+		Event: p.handle,
+	}
+}
+
+// handle records a pool event.
+func (p *poolMonitor) handle(e *event.PoolEvent) {
+	p.mu.Lock()
+	pool, ok := p.pools[e.Address]
+	if !ok {
+		pool = &poolUsage{
+			maxSize: driverDefaultPoolMaxSize,
+		}
+		p.pools[e.Address] = pool
+	}
+
+	switch e.Type {
+	case event.PoolCreated:
+		if e.PoolOptions != nil && e.PoolOptions.MaxPoolSize > 0 {
+			pool.maxSize = e.PoolOptions.MaxPoolSize
+		}
+
+	case event.ConnectionCreated:
+		pool.open++
+
+	case event.ConnectionClosed:
+		if pool.open > 0 {
+			pool.open--
+		}
+
+	case event.GetSucceeded:
+		pool.inUse++
+
+	case event.ConnectionReturned:
+		if pool.inUse > 0 {
+			pool.inUse--
+		}
+
+	case event.PoolClosedEvent:
+		delete(p.pools, e.Address)
+	}
+	p.mu.Unlock()
+
+	if p.next != nil && p.next.Event != nil {
+		p.next.Event(e)
+	}
+}
+
+// health returns the usage of each server's connection pool.
+func (p *poolMonitor) health(saturationThreshold float64) PoolHealth {
+	health := PoolHealth{}
+	if p == nil {
+		return health
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for address, pool := range p.pools {
+		server := ServerPoolHealth{
+			Address: address,
+			Open:    pool.open,
+			InUse:   pool.inUse,
+			MaxSize: pool.maxSize,
+		}
+		if pool.maxSize > 0 {
+			server.Saturation = float64(pool.inUse) / float64(pool.maxSize)
+		}
+
+		health.InUse += server.InUse
+		health.Open += server.Open
+		health.MaxSize += server.MaxSize
+		if server.Saturation > health.Saturation {
+			health.Saturation = server.Saturation
+		}
+		health.Servers = append(health.Servers, server)
+	}
+
+	sort.Slice(health.Servers, func(i, j int) bool {
+		return health.Servers[i].Address < health.Servers[j].Address
+	})
+	health.Saturated = health.Saturation >= saturationThreshold
+
+	return health
+}
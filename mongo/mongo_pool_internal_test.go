@@ -0,0 +1,81 @@
+package mongo
+
+import (
+	// Standard Library Imports
+	"testing"
+
+	// External Imports
+	"go.mongodb.org/mongo-driver/event"
+)
+
+func TestPoolMonitor_Health(t *testing.T) {
+	var forwarded int
+	pool := newPoolMonitor(&event.PoolMonitor{
+		Event: func(*event.PoolEvent) {
+			forwarded++
+		},
+	})
+	monitor := pool.monitor()
+
+	events := []*event.PoolEvent{
+		{Type: event.PoolCreated, Address: "db0:27017", PoolOptions: &event.MonitorPoolOptions{MaxPoolSize: 10}},
+		{Type: event.PoolCreated, Address: "db1:27017", PoolOptions: &event.MonitorPoolOptions{MaxPoolSize: 10}},
+	}
+	for i := 0; i < 9; i++ {
+		events = append(events,
+			&event.PoolEvent{Type: event.ConnectionCreated, Address: "db0:27017"},
+			&event.PoolEvent{Type: event.GetSucceeded, Address: "db0:27017"},
+		)
+	}
+	events = append(events,
+		&event.PoolEvent{Type: event.ConnectionCreated, Address: "db1:27017"},
+		&event.PoolEvent{Type: event.GetSucceeded, Address: "db1:27017"},
+		&event.PoolEvent{Type: event.ConnectionReturned, Address: "db1:27017"},
+	)
+	for _, e := range events {
+		monitor.Event(e)
+	}
+
+	if forwarded != len(events) {
+		t.Errorf("expected %d events to be forwarded, got %d", len(events), forwarded)
+	}
+
+	health := pool.health(defaultPoolSaturationThreshold)
+	if health.Open != 10 {
+		t.Errorf("expected 10 open connections, got %d", health.Open)
+	}
+	if health.InUse != 9 {
+		t.Errorf("expected 9 connections in use, got %d", health.InUse)
+	}
+	if health.MaxSize != 20 {
+		t.Errorf("expected max size 20, got %d", health.MaxSize)
+	}
+	if health.Saturation != 0.9 {
+		t.Errorf("expected saturation of the busiest pool to be reported, got %f", health.Saturation)
+	}
+	if !health.Saturated {
+		t.Error("expected pool to be saturated")
+	}
+	if len(health.Servers) != 2 || health.Servers[0].Address != "db0:27017" {
+		t.Errorf("expected per server usage sorted by address, got %v", health.Servers)
+	}
+
+	monitor.Event(&event.PoolEvent{Type: event.ConnectionReturned, Address: "db0:27017"})
+	if pool.health(defaultPoolSaturationThreshold).Saturated {
+		t.Error("expected pool not to be saturated once connections are returned")
+	}
+
+	monitor.Event(&event.PoolEvent{Type: event.PoolClosedEvent, Address: "db0:27017"})
+	if got := pool.health(defaultPoolSaturationThreshold); len(got.Servers) != 1 {
+		t.Errorf("expected closed pools to be removed, got %v", got.Servers)
+	}
+}
+
+func TestPoolMonitor_Health_Nil(t *testing.T) {
+	var pool *poolMonitor
+
+	health := pool.health(defaultPoolSaturationThreshold)
+	if health.Saturated {
+		t.Error("expected an untracked pool not to be saturated")
+	}
+}
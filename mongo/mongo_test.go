@@ -8,13 +8,14 @@ import (
 	"testing"
 
 	// Public Imports
+	"github.com/matthewhartstonge/storage"
 	"github.com/matthewhartstonge/storage/mongo"
 )
 
 func TestMain(m *testing.M) {
-	// If needed, enable logging when debugging for tests
-	// mongo.SetLogger(logrus.New())
-	// mongo.SetDebug(true)
+	// If needed, enable logging when debugging for tests by binding a logger
+	// to the config in setup, for example:
+	// cfg.Logger = mongo.NewLogrusLogger(logrus.StandardLogger())
 
 	exitCode := m.Run()
 	os.Exit(exitCode)
@@ -59,3 +60,35 @@ func setup(t *testing.T) (*mongo.Store, context.Context, func()) {
 		store.Close()
 	}
 }
+
+func TestStore_Health(t *testing.T) {
+	store, ctx, teardown := setup(t)
+	defer teardown()
+
+	health := store.Health(ctx)
+	if health.Status != mongo.HealthStatusUp {
+		AssertError(t, health.Status, mongo.HealthStatusUp, "store should be healthy")
+	}
+	if !health.Live || !health.Ready {
+		AssertError(t, health, "live and ready", "store should be live and ready")
+	}
+
+	// Drop an index created by Configure.
+	_, err := store.DB.Collection(storage.EntityClients).Indexes().DropOne(ctx, mongo.IdxClientID)
+	if err != nil {
+		AssertFatal(t, err, nil, "error dropping index")
+	}
+
+	health = store.Health(ctx)
+	if health.Status != mongo.HealthStatusDegraded {
+		AssertError(t, health.Status, mongo.HealthStatusDegraded, "store should be degraded")
+	}
+	if !health.Live || health.Ready {
+		AssertError(t, health, "live, but not ready", "store should be live, but not ready")
+	}
+
+	expected := storage.EntityClients + "." + mongo.IdxClientID
+	if len(health.Indexes.Missing) != 1 || health.Indexes.Missing[0] != expected {
+		AssertError(t, health.Indexes.Missing, []string{expected}, "dropped index should be reported missing")
+	}
+}
@@ -4,64 +4,116 @@ import (
 	// Standard Library Imports
 	"context"
 	"fmt"
-
-	// External Imports
-	ot "github.com/opentracing/opentracing-go"
-	otExt "github.com/opentracing/opentracing-go/ext"
-	otLog "github.com/opentracing/opentracing-go/log"
 )
 
-type dbTrace struct {
-	// The Name of the struct who
-	Manager    string
-	Method     string
-	Selector   interface{}
-	Query      interface{}
-	CustomTags []ot.Tag
+// Tracer provides a pluggable abstraction over distributed tracing
+// implementations in order to trace the calls made to mongo.
+//
+// Adapters are provided for OpenTelemetry (the default) and OpenTracing.
+type Tracer interface {
+	// StartSpan starts a new child span for the given database call. The
+	// returned context carries the span so it can be propagated on to any
+	// nested calls.
+	StartSpan(ctx context.Context, trace DBTrace) (Span, context.Context)
 }
 
-// traceMongoCall provides an abstraction from opentracing to obtain a span
-// with relevant details when tracing call time to mongoDB
-func traceMongoCall(ctx context.Context, trace dbTrace) (ot.Span, context.Context) {
-	// Build a new OpenTracing Child span to track how long it takes for mongo
-	// to complete the operation.
-	opName := fmt.Sprintf("storage.mongo.%s.%s", trace.Manager, trace.Method)
-	span, ctx := ot.StartSpanFromContext(ctx, opName)
-
-	// Tag component details
-	otExt.Component.Set(span, "storage")
-	otExt.DBType.Set(span, "mongo")
-
-	// Set the DB selector if provided.
-	// Generally useful for mongo updates where a selector is applied, then the
-	// payload supplied updates the given selected document. For example, the
-	// selector could end up selecting an inner document to be updated.
-	if trace.Selector != nil {
-		span.SetTag("DB.selector", fmt.Sprintf("%#+v", trace.Selector))
-	}
+// Span provides the functionality required to record the details of a traced
+// call to mongo.
+type Span interface {
+	// SetQuery records the query that was sent to mongo.
+	SetQuery(query interface{})
+
+	// RecordError records an error returned while performing the call.
+	RecordError(err error)
+
+	// Finish completes the span.
+	Finish()
+}
+
+// DBTrace provides the details of a call to mongo that is being traced.
+type DBTrace struct {
+	// Manager is the name of the manager performing the call.
+	Manager string
+
+	// Method is the name of the manager's method performing the call.
+	Method string
+
+	// Collection is the name of the mongo collection being operated on.
+	Collection string
+
+	// Operation is the name of the mongo operation being performed, for
+	// example, find, insert, update or delete. If not specified, Method is
+	// used to name the operation.
+	Operation string
+
+	// Selector is the selector used to match documents to be updated.
+	Selector interface{}
+
+	// Query is the query sent to mongo.
+	Query interface{}
+
+	// CustomTags provides any additional details to be recorded against the
+	// span.
+	CustomTags []Tag
+}
 
-	// Set the DB query if provided.
-	// Generally speaking, the query may not be needed, but may be helpful in
-	// debugging errors, therefore it is better advised to log the query out if
-	// an error occurs.
-	if trace.Query != nil {
-		otExt.DBStatement.Set(span, fmt.Sprintf("%#+v", trace.Query))
+// operationName returns the span name for the traced call.
+func (t DBTrace) operationName() string {
+	return fmt.Sprintf("storage.mongo.%s.%s", t.Manager, t.Method)
+}
+
+// operation returns the name of the database operation being performed.
+func (t DBTrace) operation() string {
+	if t.Operation != "" {
+		return t.Operation
 	}
 
-	// Set the custom tags if provided
-	for _, tag := range trace.CustomTags {
-		tag.Set(span)
+	return t.Method
+}
+
+// Tag provides a key value pair to be recorded against a span.
+type Tag struct {
+	Key   string
+	Value interface{}
+}
+
+// NewNoopTracer returns a Tracer that records nothing, in order to disable
+// tracing.
+func NewNoopTracer() Tracer {
+	return noopTracer{}
+}
+
+// traceMongoCall provides an abstraction from the configured tracer to obtain
+// a span with relevant details when tracing call time to mongoDB.
+func (d *DB) traceMongoCall(ctx context.Context, trace DBTrace) (Span, context.Context) {
+	if d == nil || d.Tracer == nil {
+		return (&OpenTelemetryTracer{}).StartSpan(ctx, trace)
 	}
 
-	return span, ctx
+	return d.Tracer.StartSpan(ctx, trace)
 }
 
-// otLogQuery given a span and a query,
-func otLogQuery(span ot.Span, query interface{}) {
-	otExt.DBStatement.Set(span, fmt.Sprintf("%#+v", query))
+// formatQuery formats a query, or selector, to be recorded against a span.
+func formatQuery(query interface{}) string {
+	return fmt.Sprintf("%#+v", query)
 }
 
-// otLogErr given a span, logs out the error
-func otLogErr(span ot.Span, err error) {
-	span.LogFields(otLog.Error(err))
+// noopTracer provides a tracer that records nothing.
+type noopTracer struct{}
+
+// StartSpan implements Tracer.
+func (noopTracer) StartSpan(ctx context.Context, _ DBTrace) (Span, context.Context) {
+	return noopSpan{}, ctx
 }
+
+// noopSpan provides a span that records nothing.
+type noopSpan struct{}
+
+// SetQuery implements Span.
+func (noopSpan) SetQuery(interface{}) {}
+
+// RecordError implements Span.
+func (noopSpan) RecordError(error) {}
+
+// Finish implements Span.
+func (noopSpan) Finish() {}
@@ -0,0 +1,140 @@
+package mongo
+
+import (
+	// Standard Library Imports
+	"context"
+	"errors"
+	"testing"
+
+	// External Imports
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	semconv "go.opentelemetry.io/otel/semconv/v1.4.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestOpenTelemetryTracer_ImplementsTracer(t *testing.T) {
+	var i interface{} = &OpenTelemetryTracer{}
+	if _, ok := i.(Tracer); !ok {
+		t.Error("OpenTelemetryTracer does not implement interface Tracer")
+	}
+}
+
+func TestOpenTracingTracer_ImplementsTracer(t *testing.T) {
+	var i interface{} = &OpenTracingTracer{}
+	if _, ok := i.(Tracer); !ok {
+		t.Error("OpenTracingTracer does not implement interface Tracer")
+	}
+}
+
+func TestDBTrace_Operation(t *testing.T) {
+	tests := []struct {
+		name  string
+		trace DBTrace
+		want  string
+	}{
+		{
+			name:  "should use the operation if provided",
+			trace: DBTrace{Method: "getConcrete", Operation: "find"},
+			want:  "find",
+		},
+		{
+			name:  "should fall back to the method if no operation is provided",
+			trace: DBTrace{Method: "Authenticate"},
+			want:  "Authenticate",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.trace.operation(); got != tt.want {
+				t.Errorf("operation() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDB_traceMongoCall(t *testing.T) {
+	tracers := []Tracer{
+		nil,
+		NewNoopTracer(),
+		NewOpenTelemetryTracer(nil),
+		NewOpenTracingTracer(nil),
+	}
+	for _, tr := range tracers {
+		db := &DB{Tracer: tr}
+		span, ctx := db.traceMongoCall(context.Background(), DBTrace{
+			Manager:    "ClientManager",
+			Method:     "getConcrete",
+			Collection: "clients",
+			Operation:  "find",
+			Query:      map[string]string{"id": "1"},
+		})
+		if ctx == nil {
+			t.Errorf("%T: expected a context to be returned", tr)
+		}
+
+		// spans should be safe to use with, or without, an error.
+		span.SetQuery(map[string]string{"id": "1"})
+		span.RecordError(nil)
+		span.RecordError(errors.New("boom"))
+		span.Finish()
+	}
+}
+
+func TestDB_traceMongoCall_PerStore(t *testing.T) {
+	cats, dogs := tracetest.NewSpanRecorder(), tracetest.NewSpanRecorder()
+	catsDB := &DB{Tracer: &OpenTelemetryTracer{
+		TracerProvider: sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(cats)),
+		DatabaseName:   "cats",
+	}}
+	dogsDB := &DB{Tracer: &OpenTelemetryTracer{
+		TracerProvider: sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(dogs)),
+		DatabaseName:   "dogs",
+	}}
+
+	span, _ := catsDB.traceMongoCall(context.Background(), DBTrace{
+		Manager:    "ClientManager",
+		Method:     "getConcrete",
+		Collection: "clients",
+		Operation:  "find",
+	})
+	span.Finish()
+
+	span, _ = dogsDB.traceMongoCall(context.Background(), DBTrace{
+		Manager: "UserManager",
+		Method:  "List",
+	})
+	span.Finish()
+
+	if len(dogs.Ended()) != 1 {
+		t.Errorf("expected a span to be recorded to the other store's tracer, got %d", len(dogs.Ended()))
+	}
+	if len(cats.Ended()) != 1 {
+		t.Fatalf("expected a span to be recorded, got %d", len(cats.Ended()))
+	}
+
+	recorded := cats.Ended()[0]
+	if name := recorded.Name(); name != "storage.mongo.ClientManager.getConcrete" {
+		t.Errorf("unexpected span name %q", name)
+	}
+	if kind := recorded.SpanKind(); kind != trace.SpanKindClient {
+		t.Errorf("expected a client span, got %v", kind)
+	}
+
+	attrs := map[attribute.Key]string{}
+	for _, attr := range recorded.Attributes() {
+		attrs[attr.Key] = attr.Value.Emit()
+	}
+	expected := map[attribute.Key]string{
+		semconv.DBSystemKey:            "mongodb",
+		semconv.DBNameKey:              "cats",
+		semconv.DBOperationKey:         "find",
+		semconv.DBMongoDBCollectionKey: "clients",
+	}
+	for key, value := range expected {
+		if attrs[key] != value {
+			t.Errorf("expected %s to be %q, got %q", key, value, attrs[key])
+		}
+	}
+}
@@ -0,0 +1,113 @@
+package mongo
+
+import (
+	// Standard Library Imports
+	"context"
+	"fmt"
+
+	// External Imports
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.4.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	// otelInstrumentationName provides the name of the instrumentation
+	// library used when obtaining an OpenTelemetry tracer.
+	otelInstrumentationName = "github.com/matthewhartstonge/storage/mongo"
+
+	// otelSelectorKey provides the attribute key used to record an update
+	// selector.
+	otelSelectorKey = attribute.Key("db.mongodb.selector")
+)
+
+// OpenTelemetryTracer provides a Tracer that records spans to OpenTelemetry
+// using the semantic conventions for database client calls.
+type OpenTelemetryTracer struct {
+	// TracerProvider provides the OpenTelemetry tracer provider spans are
+	// created from. If nil, the globally registered provider is used.
+	TracerProvider trace.TracerProvider
+
+	// DatabaseName, if provided, is recorded against each span as `db.name`.
+	DatabaseName string
+}
+
+// NewOpenTelemetryTracer returns a Tracer which records spans to the given
+// OpenTelemetry tracer provider. If provider is nil, the globally registered
+// provider is used.
+func NewOpenTelemetryTracer(provider trace.TracerProvider) *OpenTelemetryTracer {
+	return &OpenTelemetryTracer{
+		TracerProvider: provider,
+	}
+}
+
+// StartSpan implements Tracer.
+func (t *OpenTelemetryTracer) StartSpan(ctx context.Context, dbTrace DBTrace) (Span, context.Context) {
+	provider := t.TracerProvider
+	if provider == nil {
+		provider = otel.GetTracerProvider()
+	}
+
+	attrs := []attribute.KeyValue{
+		semconv.DBSystemMongoDB,
+		semconv.DBOperationKey.String(dbTrace.operation()),
+	}
+	if t.DatabaseName != "" {
+		attrs = append(attrs, semconv.DBNameKey.String(t.DatabaseName))
+	}
+	if dbTrace.Collection != "" {
+		attrs = append(attrs, semconv.DBMongoDBCollectionKey.String(dbTrace.Collection))
+	}
+
+	// Set the DB selector if provided.
+	// Generally useful for mongo updates where a selector is applied, then the
+	// payload supplied updates the given selected document.
+	if dbTrace.Selector != nil {
+		attrs = append(attrs, otelSelectorKey.String(formatQuery(dbTrace.Selector)))
+	}
+
+	// Set the DB query if provided.
+	if dbTrace.Query != nil {
+		attrs = append(attrs, semconv.DBStatementKey.String(formatQuery(dbTrace.Query)))
+	}
+
+	for _, tag := range dbTrace.CustomTags {
+		attrs = append(attrs, attribute.String(tag.Key, fmt.Sprintf("%v", tag.Value)))
+	}
+
+	ctx, span := provider.Tracer(otelInstrumentationName).Start(
+		ctx,
+		dbTrace.operationName(),
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(attrs...),
+	)
+
+	return &otelSpan{span: span}, ctx
+}
+
+// otelSpan wraps an OpenTelemetry span to implement Span.
+type otelSpan struct {
+	span trace.Span
+}
+
+// SetQuery implements Span.
+func (s *otelSpan) SetQuery(query interface{}) {
+	s.span.SetAttributes(semconv.DBStatementKey.String(formatQuery(query)))
+}
+
+// RecordError implements Span.
+func (s *otelSpan) RecordError(err error) {
+	if err == nil {
+		return
+	}
+
+	s.span.RecordError(err)
+	s.span.SetStatus(codes.Error, err.Error())
+}
+
+// Finish implements Span.
+func (s *otelSpan) Finish() {
+	s.span.End()
+}
@@ -0,0 +1,94 @@
+package mongo
+
+import (
+	// Standard Library Imports
+	"context"
+
+	// External Imports
+	ot "github.com/opentracing/opentracing-go"
+	otExt "github.com/opentracing/opentracing-go/ext"
+	otLog "github.com/opentracing/opentracing-go/log"
+)
+
+// OpenTracingTracer provides a Tracer adapter which records spans to
+// OpenTracing.
+type OpenTracingTracer struct {
+	// Tracer provides the OpenTracing tracer spans are created from. If nil,
+	// the globally registered tracer is used.
+	Tracer ot.Tracer
+}
+
+// NewOpenTracingTracer returns a Tracer which records spans to the given
+// OpenTracing tracer. If t is nil, the globally registered tracer is used.
+func NewOpenTracingTracer(t ot.Tracer) *OpenTracingTracer {
+	return &OpenTracingTracer{
+		Tracer: t,
+	}
+}
+
+// StartSpan implements Tracer.
+func (t *OpenTracingTracer) StartSpan(ctx context.Context, trace DBTrace) (Span, context.Context) {
+	otTracer := t.Tracer
+	if otTracer == nil {
+		otTracer = ot.GlobalTracer()
+	}
+
+	// Build a new OpenTracing Child span to track how long it takes for mongo
+	// to complete the operation.
+	span, ctx := ot.StartSpanFromContextWithTracer(ctx, otTracer, trace.operationName())
+
+	// Tag component details
+	otExt.Component.Set(span, "storage")
+	otExt.DBType.Set(span, "mongo")
+
+	if trace.Collection != "" {
+		span.SetTag("DB.collection", trace.Collection)
+	}
+
+	// Set the DB selector if provided.
+	// Generally useful for mongo updates where a selector is applied, then the
+	// payload supplied updates the given selected document. For example, the
+	// selector could end up selecting an inner document to be updated.
+	if trace.Selector != nil {
+		span.SetTag("DB.selector", formatQuery(trace.Selector))
+	}
+
+	// Set the DB query if provided.
+	// Generally speaking, the query may not be needed, but may be helpful in
+	// debugging errors, therefore it is better advised to log the query out if
+	// an error occurs.
+	if trace.Query != nil {
+		otExt.DBStatement.Set(span, formatQuery(trace.Query))
+	}
+
+	// Set the custom tags if provided
+	for _, tag := range trace.CustomTags {
+		span.SetTag(tag.Key, tag.Value)
+	}
+
+	return &otSpan{span: span}, ctx
+}
+
+// otSpan wraps an OpenTracing span to implement Span.
+type otSpan struct {
+	span ot.Span
+}
+
+// SetQuery implements Span.
+func (s *otSpan) SetQuery(query interface{}) {
+	otExt.DBStatement.Set(s.span, formatQuery(query))
+}
+
+// RecordError implements Span.
+func (s *otSpan) RecordError(err error) {
+	if err == nil {
+		return
+	}
+
+	s.span.LogFields(otLog.Error(err))
+}
+
+// Finish implements Span.
+func (s *otSpan) Finish() {
+	s.span.Finish()
+}
@@ -0,0 +1,101 @@
+package mongo
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/ory/fosite"
+
+	"github.com/matthewhartstonge/storage"
+)
+
+// regionUsers provides an in-memory user storer.
+type regionUsers struct {
+	storage.UserStorer
+	users map[string]storage.User
+}
+
+func (u *regionUsers) Get(ctx context.Context, userID string) (storage.User, error) {
+	user, ok := u.users[userID]
+	if !ok {
+		return storage.User{}, storage.NewNotFoundError(storage.EntityUsers)
+	}
+	return user, nil
+}
+
+// regionTenants provides an in-memory tenant storer.
+type regionTenants struct {
+	storage.TenantStorer
+	tenants map[string]storage.Tenant
+}
+
+func (t *regionTenants) List(ctx context.Context, filter storage.ListTenantsRequest) (results []storage.Tenant, err error) {
+	for _, tenantID := range filter.IDs {
+		if tenant, ok := t.tenants[tenantID]; ok {
+			results = append(results, tenant)
+		}
+	}
+	return results, nil
+}
+
+func TestClientManager_AllowedRegion(t *testing.T) {
+	c := &ClientManager{Region: "us-east-1"}
+	client := storage.Client{ID: "client-1", AllowedRegions: []string{"eu-west-1"}}
+
+	err := c.allowedRegion(context.Background(), client)
+	if !errors.Is(err, storage.ErrNotFound) || !errors.Is(err, fosite.ErrNotFound) {
+		t.Errorf("expected client to be not found outside of its regions, got %v", err)
+	}
+
+	ctx := storage.RegionToContext(context.Background(), "eu-west-1")
+	if err := c.allowedRegion(ctx, client); err != nil {
+		t.Errorf("expected the context region to take precedence, got %v", err)
+	}
+
+	if err := (&ClientManager{}).allowedRegion(context.Background(), client); err != nil {
+		t.Errorf("expected no enforcement without a serving region, got %v", err)
+	}
+}
+
+func TestRequestManager_CheckDataResidency(t *testing.T) {
+	r := &RequestManager{
+		Users: &regionUsers{users: map[string]storage.User{
+			"user-eu": {ID: "user-eu", AllowedTenantAccess: []string{"tenant-eu"}},
+			"user-1":  {ID: "user-1", AllowedTenantAccess: []string{"tenant-1"}},
+		}},
+		Tenants: &regionTenants{tenants: map[string]storage.Tenant{
+			"tenant-1":  {ID: "tenant-1"},
+			"tenant-eu": {ID: "tenant-eu", AllowedRegions: []string{"eu-west-1"}},
+		}},
+		Region: "us-east-1",
+	}
+	ctx := context.Background()
+	request := storage.Request{UserID: "user-eu"}
+
+	if err := r.checkDataResidency(ctx, storage.EntityAccessTokens, request); err != nil {
+		t.Errorf("expected no check when data residency is disabled, got %v", err)
+	}
+
+	r.DataResidency = true
+	err := r.checkDataResidency(ctx, storage.EntityAccessTokens, request)
+	if !errors.Is(err, storage.ErrDataResidency) {
+		t.Errorf("expected a data residency violation, got %v", err)
+	}
+
+	tenantCtx := storage.TenantToContext(ctx, "tenant-1")
+	if err := r.checkDataResidency(tenantCtx, storage.EntityAccessTokens, request); err != nil {
+		t.Errorf("expected the context tenant to be checked, got %v", err)
+	}
+
+	regionCtx := storage.RegionToContext(ctx, "eu-west-1")
+	if err := r.checkDataResidency(regionCtx, storage.EntityAccessTokens, request); err != nil {
+		t.Errorf("expected the user to be allowed in its tenant's region, got %v", err)
+	}
+
+	for _, userID := range []string{"", "user-1", "user-unknown"} {
+		if err := r.checkDataResidency(ctx, storage.EntityAccessTokens, storage.Request{UserID: userID}); err != nil {
+			t.Errorf("expected user %q to be allowed, got %v", userID, err)
+		}
+	}
+}
@@ -4,13 +4,12 @@ import (
 	// Standard Library Imports
 	"context"
 	"encoding/json"
+	"errors"
 	"time"
 
-	ot "github.com/opentracing/opentracing-go"
 	// External Imports
 	"github.com/google/uuid"
 	"github.com/ory/fosite"
-	"github.com/sirupsen/logrus"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
@@ -25,6 +24,9 @@ type RequestManager struct {
 	// copied and closed.
 	DB *DB
 
+	// Logger provides the logger used to log datastore operations.
+	Logger Logger
+
 	// Clients provides access to Client entities in order to create, read,
 	// update and delete resources from the clients collection.
 	// A client is required when cross referencing scope access rights.
@@ -35,6 +37,27 @@ type RequestManager struct {
 	// Users are required when the Password Credentials Grant, is implemented
 	// in order to find and authenticate users.
 	Users storage.UserStorer
+
+	// Groups, if set, provides access to Group entities in order to include
+	// the tenant access users inherit from their groups when enforcing data
+	// residency.
+	Groups storage.GroupStorer
+
+	// Tenants provides access to Tenant entities in order to enforce data
+	// residency.
+	Tenants storage.TenantStorer
+
+	// Region provides the region the store is serving. A region bound to the
+	// context via storage.RegionToContext takes precedence.
+	Region string
+
+	// DataResidency, if true, refuses to persist requests for a user whose
+	// tenant is pinned to another region.
+	DataResidency bool
+
+	// PARLifespan specifies how long a pushed authorization request can be
+	// used for after being pushed. Defaults to storage.DefaultPARLifespan.
+	PARLifespan time.Duration
 }
 
 // Configure implements storage.Configurer.
@@ -46,6 +69,7 @@ func (r *RequestManager) Configure(ctx context.Context) (err error) {
 		storage.EntityAccessTokens,
 		storage.EntityAuthorizationCodes,
 		storage.EntityOpenIDSessions,
+		storage.EntityPARSessions,
 		storage.EntityPKCESessions,
 		storage.EntityRefreshTokens,
 	}
@@ -97,17 +121,16 @@ func (r *RequestManager) Configure(ctx context.Context) (err error) {
 	}
 
 	for _, entityName := range collections {
-		log := logger.WithFields(logrus.Fields{
+		log := newLogger(ctx, r.Logger, Fields{
 			"package":    "mongo",
 			"collection": entityName,
 			"method":     "Configure",
 		})
 
-		collection := r.DB.Collection(entityName)
-		_, err = collection.Indexes().CreateMany(ctx, indices)
+		err = r.DB.createIndexes(ctx, entityName, indices)
 		if err != nil {
 			log.WithError(err).Error(logError)
-			return err
+			return toStorageError(entityName, err)
 		}
 	}
 
@@ -116,7 +139,7 @@ func (r *RequestManager) Configure(ctx context.Context) (err error) {
 
 // getConcrete returns a Request resource.
 func (r *RequestManager) getConcrete(ctx context.Context, entityName string, requestID string) (result storage.Request, err error) {
-	log := logger.WithFields(logrus.Fields{
+	log := newLogger(ctx, r.Logger, Fields{
 		"package":    "mongo",
 		"collection": entityName,
 		"method":     "getConcrete",
@@ -129,10 +152,12 @@ func (r *RequestManager) getConcrete(ctx context.Context, entityName string, req
 	}
 
 	// Trace how long the Mongo operation takes to complete.
-	span, _ := traceMongoCall(ctx, dbTrace{
-		Manager: "RequestManager",
-		Method:  "getConcrete",
-		Query:   query,
+	span, ctx := r.DB.traceMongoCall(ctx, DBTrace{
+		Manager:    "RequestManager",
+		Method:     "getConcrete",
+		Collection: entityName,
+		Operation:  "find",
+		Query:      query,
 	})
 	defer span.Finish()
 
@@ -142,14 +167,14 @@ func (r *RequestManager) getConcrete(ctx context.Context, entityName string, req
 	if err != nil {
 		if err == mongo.ErrNoDocuments {
 			log.WithError(err).Debug(logNotFound)
-			return result, fosite.ErrNotFound
+			return result, storage.NewNotFoundError(entityName)
 		}
 
 		// Log to StdOut
 		log.WithError(err).Error(logError)
-		// Log to OpenTracing
-		otLogErr(span, err)
-		return result, err
+		// Log to Tracer
+		span.RecordError(err)
+		return result, toStorageError(entityName, err)
 	}
 
 	return request, nil
@@ -158,7 +183,7 @@ func (r *RequestManager) getConcrete(ctx context.Context, entityName string, req
 // List returns a list of Request resources that match the provided inputs.
 func (r *RequestManager) List(ctx context.Context, entityName string, filter storage.ListRequestsRequest) (results []storage.Request, err error) {
 	// Initialize contextual method logger
-	log := logger.WithFields(logrus.Fields{
+	log := newLogger(ctx, r.Logger, Fields{
 		"package":    "mongo",
 		"collection": entityName,
 		"method":     "List",
@@ -186,10 +211,12 @@ func (r *RequestManager) List(ctx context.Context, entityName string, filter sto
 	}
 
 	// Trace how long the Mongo operation takes to complete.
-	span, _ := traceMongoCall(ctx, dbTrace{
-		Manager: "RequestManager",
-		Method:  "List",
-		Query:   query,
+	span, ctx := r.DB.traceMongoCall(ctx, DBTrace{
+		Manager:    "RequestManager",
+		Method:     "List",
+		Collection: entityName,
+		Operation:  "find",
+		Query:      query,
 	})
 	defer span.Finish()
 
@@ -198,9 +225,9 @@ func (r *RequestManager) List(ctx context.Context, entityName string, filter sto
 	if err != nil {
 		// Log to StdOut
 		log.WithError(err).Error(logError)
-		// Log to OpenTracing
-		otLogErr(span, err)
-		return results, err
+		// Log to Tracer
+		span.RecordError(err)
+		return results, toStorageError(entityName, err)
 	}
 
 	var requests []storage.Request
@@ -208,9 +235,9 @@ func (r *RequestManager) List(ctx context.Context, entityName string, filter sto
 	if err != nil {
 		// Log to StdOut
 		log.WithError(err).Error(logError)
-		// Log to OpenTracing
-		otLogErr(span, err)
-		return results, err
+		// Log to Tracer
+		span.RecordError(err)
+		return results, toStorageError(entityName, err)
 	}
 
 	return requests, nil
@@ -220,7 +247,7 @@ func (r *RequestManager) List(ctx context.Context, entityName string, filter sto
 // resource.
 func (r *RequestManager) Create(ctx context.Context, entityName string, request storage.Request) (result storage.Request, err error) {
 	// Initialize contextual method logger
-	log := logger.WithFields(logrus.Fields{
+	log := newLogger(ctx, r.Logger, Fields{
 		"package":    "mongo",
 		"collection": entityName,
 		"method":     "Create",
@@ -237,10 +264,17 @@ func (r *RequestManager) Create(ctx context.Context, entityName string, request
 		request.RequestedAt = time.Now()
 	}
 
+	if err = r.checkDataResidency(ctx, entityName, request); err != nil {
+		log.WithError(err).Warn("request refused by data residency")
+		return result, err
+	}
+
 	// Trace how long the Mongo operation takes to complete.
-	span, _ := traceMongoCall(ctx, dbTrace{
-		Manager: "RequestManager",
-		Method:  "Create",
+	span, ctx := r.DB.traceMongoCall(ctx, DBTrace{
+		Manager:    "RequestManager",
+		Method:     "Create",
+		Collection: entityName,
+		Operation:  "insert",
 	})
 	defer span.Finish()
 
@@ -251,22 +285,52 @@ func (r *RequestManager) Create(ctx context.Context, entityName string, request
 		if isDup(err) {
 			// Log to StdOut
 			log.WithError(err).Debug(logConflict)
-			// Log to OpenTracing
-			otLogErr(span, err)
-			return result, storage.ErrResourceExists
+			// Log to Tracer
+			span.RecordError(err)
+			return result, toStorageError(entityName, err)
 		}
 
 		// Log to StdOut
 		log.WithError(err).Error(logError)
-		// Log to OpenTracing
-		otLogQuery(span, request)
-		otLogErr(span, err)
-		return result, err
+		// Log to Tracer
+		span.SetQuery(request)
+		span.RecordError(err)
+		return result, toStorageError(entityName, err)
 	}
 
 	return request, nil
 }
 
+// checkDataResidency returns an error if data residency is enabled and the
+// request's user belongs to a tenant pinned to another region. The tenant
+// bound to the context via storage.TenantToContext is checked if present,
+// otherwise the tenants the user has access to, including via their groups.
+// Requests without a user, or for users not held in the store, are not
+// checked.
+func (r *RequestManager) checkDataResidency(ctx context.Context, entityName string, request storage.Request) error {
+	if !r.DataResidency || r.Tenants == nil || request.UserID == "" {
+		return nil
+	}
+
+	var tenantIDs []string
+	if tenantID, ok := storage.ContextToTenant(ctx); ok {
+		tenantIDs = []string{tenantID}
+	} else if r.Users != nil {
+		user, err := r.Users.Get(ctx, request.UserID)
+		if err != nil && !errors.Is(err, storage.ErrNotFound) {
+			return err
+		}
+
+		permissions, err := storage.EffectivePermissions(ctx, r.Groups, user)
+		if err != nil {
+			return err
+		}
+		tenantIDs = permissions.AllowedTenantAccess
+	}
+
+	return storage.CheckDataResidency(ctx, r.Tenants, entityName, storage.ServingRegion(ctx, r.Region), tenantIDs)
+}
+
 // Get returns the specified Request resource.
 func (r *RequestManager) Get(ctx context.Context, entityName string, requestID string) (result storage.Request, err error) {
 	return r.getConcrete(ctx, entityName, requestID)
@@ -275,7 +339,7 @@ func (r *RequestManager) Get(ctx context.Context, entityName string, requestID s
 // GetBySignature returns a Request resource, if the presented signature returns
 // a match.
 func (r *RequestManager) GetBySignature(ctx context.Context, entityName string, signature string) (result storage.Request, err error) {
-	log := logger.WithFields(logrus.Fields{
+	log := newLogger(ctx, r.Logger, Fields{
 		"package":    "mongo",
 		"collection": entityName,
 		"method":     "GetBySignature",
@@ -287,10 +351,12 @@ func (r *RequestManager) GetBySignature(ctx context.Context, entityName string,
 	}
 
 	// Trace how long the Mongo operation takes to complete.
-	span, _ := traceMongoCall(ctx, dbTrace{
-		Manager: "RequestManager",
-		Method:  "GetBySignature",
-		Query:   query,
+	span, ctx := r.DB.traceMongoCall(ctx, DBTrace{
+		Manager:    "RequestManager",
+		Method:     "GetBySignature",
+		Collection: entityName,
+		Operation:  "find",
+		Query:      query,
 	})
 	defer span.Finish()
 
@@ -300,14 +366,14 @@ func (r *RequestManager) GetBySignature(ctx context.Context, entityName string,
 	if err != nil {
 		if err == mongo.ErrNoDocuments {
 			log.WithError(err).Debug(logNotFound)
-			return result, fosite.ErrNotFound
+			return result, storage.NewNotFoundError(entityName)
 		}
 
 		// Log to StdOut
 		log.WithError(err).Error(logError)
-		// Log to OpenTracing
-		otLogErr(span, err)
-		return result, err
+		// Log to Tracer
+		span.RecordError(err)
+		return result, toStorageError(entityName, err)
 	}
 
 	return request, nil
@@ -317,7 +383,7 @@ func (r *RequestManager) GetBySignature(ctx context.Context, entityName string,
 // Request resource.
 func (r *RequestManager) Update(ctx context.Context, entityName string, requestID string, updatedRequest storage.Request) (result storage.Request, err error) {
 	// Initialize contextual method logger
-	log := logger.WithFields(logrus.Fields{
+	log := newLogger(ctx, r.Logger, Fields{
 		"package":    "mongo",
 		"collection": entityName,
 		"method":     "Update",
@@ -335,10 +401,12 @@ func (r *RequestManager) Update(ctx context.Context, entityName string, requestI
 	}
 
 	// Trace how long the Mongo operation takes to complete.
-	span, _ := traceMongoCall(ctx, dbTrace{
-		Manager:  "RequestManager",
-		Method:   "Update",
-		Selector: selector,
+	span, ctx := r.DB.traceMongoCall(ctx, DBTrace{
+		Manager:    "RequestManager",
+		Method:     "Update",
+		Collection: entityName,
+		Operation:  "update",
+		Selector:   selector,
 	})
 	defer span.Finish()
 
@@ -348,25 +416,25 @@ func (r *RequestManager) Update(ctx context.Context, entityName string, requestI
 		if isDup(err) {
 			// Log to StdOut
 			log.WithError(err).Debug(logConflict)
-			// Log to OpenTracing
-			otLogErr(span, err)
-			return result, storage.ErrResourceExists
+			// Log to Tracer
+			span.RecordError(err)
+			return result, toStorageError(entityName, err)
 		}
 
 		// Log to StdOut
 		log.WithError(err).Error(logError)
-		// Log to OpenTracing
-		otLogQuery(span, updatedRequest)
-		otLogErr(span, err)
-		return result, err
+		// Log to Tracer
+		span.SetQuery(updatedRequest)
+		span.RecordError(err)
+		return result, toStorageError(entityName, err)
 	}
 
 	if res.MatchedCount == 0 {
 		// Log to StdOut
 		log.WithError(err).Debug(logNotFound)
-		// Log to OpenTracing
-		otLogErr(span, err)
-		return result, fosite.ErrNotFound
+		// Log to Tracer
+		span.RecordError(err)
+		return result, storage.NewNotFoundError(entityName)
 	}
 
 	return updatedRequest, nil
@@ -375,7 +443,7 @@ func (r *RequestManager) Update(ctx context.Context, entityName string, requestI
 // Delete deletes the specified Request resource.
 func (r *RequestManager) Delete(ctx context.Context, entityName string, requestID string) (err error) {
 	// Initialize contextual method logger
-	log := logger.WithFields(logrus.Fields{
+	log := newLogger(ctx, r.Logger, Fields{
 		"package":    "mongo",
 		"collection": entityName,
 		"method":     "Delete",
@@ -388,10 +456,12 @@ func (r *RequestManager) Delete(ctx context.Context, entityName string, requestI
 	}
 
 	// Trace how long the Mongo operation takes to complete.
-	span, _ := traceMongoCall(ctx, dbTrace{
-		Manager: "RequestManager",
-		Method:  "Delete",
-		Query:   query,
+	span, ctx := r.DB.traceMongoCall(ctx, DBTrace{
+		Manager:    "RequestManager",
+		Method:     "Delete",
+		Collection: entityName,
+		Operation:  "delete",
+		Query:      query,
 	})
 	defer span.Finish()
 
@@ -400,17 +470,17 @@ func (r *RequestManager) Delete(ctx context.Context, entityName string, requestI
 	if err != nil {
 		// Log to StdOut
 		log.WithError(err).Error(logError)
-		// Log to OpenTracing
-		otLogErr(span, err)
-		return err
+		// Log to Tracer
+		span.RecordError(err)
+		return toStorageError(entityName, err)
 	}
 
 	if res.DeletedCount == 0 {
 		// Log to StdOut
 		log.WithError(err).Debug(logNotFound)
-		// Log to OpenTracing
-		otLogErr(span, err)
-		return fosite.ErrNotFound
+		// Log to Tracer
+		span.RecordError(err)
+		return storage.NewNotFoundError(entityName)
 	}
 
 	return nil
@@ -420,7 +490,7 @@ func (r *RequestManager) Delete(ctx context.Context, entityName string, requestI
 // signature returns a match.
 func (r *RequestManager) DeleteBySignature(ctx context.Context, entityName string, signature string) (err error) {
 	// Initialize contextual method logger
-	log := logger.WithFields(logrus.Fields{
+	log := newLogger(ctx, r.Logger, Fields{
 		"package":    "mongo",
 		"collection": entityName,
 		"method":     "DeleteBySignature",
@@ -432,10 +502,12 @@ func (r *RequestManager) DeleteBySignature(ctx context.Context, entityName strin
 	}
 
 	// Trace how long the Mongo operation takes to complete.
-	span, _ := traceMongoCall(ctx, dbTrace{
-		Manager: "RequestManager",
-		Method:  "DeleteBySignature",
-		Query:   query,
+	span, ctx := r.DB.traceMongoCall(ctx, DBTrace{
+		Manager:    "RequestManager",
+		Method:     "DeleteBySignature",
+		Collection: entityName,
+		Operation:  "delete",
+		Query:      query,
 	})
 	defer span.Finish()
 
@@ -444,17 +516,17 @@ func (r *RequestManager) DeleteBySignature(ctx context.Context, entityName strin
 	if err != nil {
 		// Log to StdOut
 		log.WithError(err).Error(logError)
-		// Log to OpenTracing
-		otLogErr(span, err)
-		return err
+		// Log to Tracer
+		span.RecordError(err)
+		return toStorageError(entityName, err)
 	}
 
 	if res.DeletedCount == 0 {
 		// Log to StdOut
 		log.WithError(err).Debug(logNotFound)
-		// Log to OpenTracing
-		otLogErr(span, err)
-		return fosite.ErrNotFound
+		// Log to Tracer
+		span.RecordError(err)
+		return storage.NewNotFoundError(entityName)
 	}
 
 	return nil
@@ -473,7 +545,7 @@ func (r *RequestManager) RevokeAccessToken(ctx context.Context, requestID string
 // revokeToken deletes a token based on the provided request id.
 func (r *RequestManager) revokeToken(ctx context.Context, entityName string, requestID string) (err error) {
 	// Initialize contextual method logger
-	log := logger.WithFields(logrus.Fields{
+	log := newLogger(ctx, r.Logger, Fields{
 		"package":    "mongo",
 		"collection": entityName,
 		"method":     "revokeToken",
@@ -481,26 +553,23 @@ func (r *RequestManager) revokeToken(ctx context.Context, entityName string, req
 	})
 
 	// Trace how long the Mongo operation takes to complete.
-	span, ctx := traceMongoCall(ctx, dbTrace{
-		Manager: "RequestManager",
-		Method:  "revokeToken",
-		Query:   requestID,
-		CustomTags: []ot.Tag{{
-			Key:   "collection",
-			Value: entityName,
-		}},
+	span, ctx := r.DB.traceMongoCall(ctx, DBTrace{
+		Manager:    "RequestManager",
+		Method:     "revokeToken",
+		Collection: entityName,
+		Query:      requestID,
 	})
 	defer span.Finish()
 
 	err = r.Delete(ctx, entityName, requestID)
-	if err != nil && err != fosite.ErrNotFound {
+	if err != nil && !errors.Is(err, storage.ErrNotFound) {
 		// Note: If the token is not found, we can declare it revoked.
 
 		// Log to StdOut
 		log.WithError(err).Error(logError)
-		// Log to OpenTracing
-		otLogErr(span, err)
-		return err
+		// Log to Tracer
+		span.RecordError(err)
+		return toStorageError(entityName, err)
 	}
 
 	return nil
@@ -3,10 +3,10 @@ package mongo
 import (
 	// Standard Library Imports
 	"context"
+	"errors"
 
 	// External Imports
 	"github.com/ory/fosite"
-	"github.com/sirupsen/logrus"
 
 	// Internal Imports
 	"github.com/matthewhartstonge/storage"
@@ -15,23 +15,24 @@ import (
 // CreateAccessTokenSession creates a new session for an Access Token
 func (r *RequestManager) CreateAccessTokenSession(ctx context.Context, signature string, request fosite.Requester) (err error) {
 	// Initialize contextual method logger
-	log := logger.WithFields(logrus.Fields{
+	log := newLogger(ctx, r.Logger, Fields{
 		"package":    "mongo",
 		"collection": storage.EntityAccessTokens,
 		"method":     "CreateAccessTokenSession",
 	})
 
 	// Trace how long the Mongo operation takes to complete.
-	span, ctx := traceMongoCall(ctx, dbTrace{
-		Manager: "RequestManager",
-		Method:  "CreateAccessTokenSession",
+	span, ctx := r.DB.traceMongoCall(ctx, DBTrace{
+		Manager:    "RequestManager",
+		Method:     "CreateAccessTokenSession",
+		Collection: storage.EntityAccessTokens,
 	})
 	defer span.Finish()
 
 	// Store session request
 	_, err = r.Create(ctx, storage.EntityAccessTokens, toMongo(signature, request))
 	if err != nil {
-		if err == storage.ErrResourceExists {
+		if errors.Is(err, storage.ErrResourceExists) {
 			log.WithError(err).Debug(logConflict)
 			return err
 		}
@@ -47,7 +48,7 @@ func (r *RequestManager) CreateAccessTokenSession(ctx context.Context, signature
 // GetAccessTokenSession returns a session if it can be found by signature
 func (r *RequestManager) GetAccessTokenSession(ctx context.Context, signature string, session fosite.Session) (request fosite.Requester, err error) {
 	// Initialize contextual method logger
-	log := logger.WithFields(logrus.Fields{
+	log := newLogger(ctx, r.Logger, Fields{
 		"package":    "mongo",
 		"collection": storage.EntityAccessTokens,
 		"method":     "GetAccessTokenSession",
@@ -66,16 +67,17 @@ func (r *RequestManager) GetAccessTokenSession(ctx context.Context, signature st
 	}
 
 	// Trace how long the Mongo operation takes to complete.
-	span, ctx := traceMongoCall(ctx, dbTrace{
-		Manager: "RequestManager",
-		Method:  "GetAccessTokenSession",
+	span, ctx := r.DB.traceMongoCall(ctx, DBTrace{
+		Manager:    "RequestManager",
+		Method:     "GetAccessTokenSession",
+		Collection: storage.EntityAccessTokens,
 	})
 	defer span.Finish()
 
 	// Get the stored request
 	req, err := r.GetBySignature(ctx, storage.EntityAccessTokens, signature)
 	if err != nil {
-		if err == fosite.ErrNotFound {
+		if errors.Is(err, storage.ErrNotFound) {
 			log.WithError(err).Debug(logNotFound)
 			return nil, err
 		}
@@ -87,7 +89,7 @@ func (r *RequestManager) GetAccessTokenSession(ctx context.Context, signature st
 	// Transform to a fosite.Request
 	request, err = req.ToRequest(ctx, session, r.Clients)
 	if err != nil {
-		if err == fosite.ErrNotFound {
+		if errors.Is(err, storage.ErrNotFound) {
 			log.WithError(err).Debug(logNotFound)
 			return nil, err
 		}
@@ -102,23 +104,24 @@ func (r *RequestManager) GetAccessTokenSession(ctx context.Context, signature st
 // DeleteAccessTokenSession removes an Access Token's session
 func (r *RequestManager) DeleteAccessTokenSession(ctx context.Context, signature string) (err error) {
 	// Initialize contextual method logger
-	log := logger.WithFields(logrus.Fields{
+	log := newLogger(ctx, r.Logger, Fields{
 		"package":    "mongo",
 		"collection": storage.EntityAccessTokens,
 		"method":     "DeleteAccessTokenSession",
 	})
 
 	// Trace how long the Mongo operation takes to complete.
-	span, ctx := traceMongoCall(ctx, dbTrace{
-		Manager: "RequestManager",
-		Method:  "DeleteAccessTokenSession",
+	span, ctx := r.DB.traceMongoCall(ctx, DBTrace{
+		Manager:    "RequestManager",
+		Method:     "DeleteAccessTokenSession",
+		Collection: storage.EntityAccessTokens,
 	})
 	defer span.Finish()
 
 	// Remove session request
 	err = r.DeleteBySignature(ctx, storage.EntityAccessTokens, signature)
 	if err != nil {
-		if err == fosite.ErrNotFound {
+		if errors.Is(err, storage.ErrNotFound) {
 			log.WithError(err).Debug(logNotFound)
 			return err
 		}
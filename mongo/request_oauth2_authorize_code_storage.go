@@ -3,11 +3,11 @@ package mongo
 import (
 	// Standard Library Imports
 	"context"
+	"errors"
 	"time"
 
 	// External Imports
 	"github.com/ory/fosite"
-	"github.com/sirupsen/logrus"
 
 	// Internal Imports
 	"github.com/matthewhartstonge/storage"
@@ -17,23 +17,24 @@ import (
 // authorization code.
 func (r *RequestManager) CreateAuthorizeCodeSession(ctx context.Context, code string, request fosite.Requester) (err error) {
 	// Initialize contextual method logger
-	log := logger.WithFields(logrus.Fields{
+	log := newLogger(ctx, r.Logger, Fields{
 		"package":    "mongo",
 		"collection": storage.EntityAuthorizationCodes,
 		"method":     "CreateAuthorizeCodeSession",
 	})
 
 	// Trace how long the Mongo operation takes to complete.
-	span, ctx := traceMongoCall(ctx, dbTrace{
-		Manager: "RequestManager",
-		Method:  "CreateAuthorizeCodeSession",
+	span, ctx := r.DB.traceMongoCall(ctx, DBTrace{
+		Manager:    "RequestManager",
+		Method:     "CreateAuthorizeCodeSession",
+		Collection: storage.EntityAuthorizationCodes,
 	})
 	defer span.Finish()
 
 	// Store session request
 	_, err = r.Create(ctx, storage.EntityAuthorizationCodes, toMongo(code, request))
 	if err != nil {
-		if err == storage.ErrResourceExists {
+		if errors.Is(err, storage.ErrResourceExists) {
 			log.WithError(err).Debug(logConflict)
 			return err
 		}
@@ -50,7 +51,7 @@ func (r *RequestManager) CreateAuthorizeCodeSession(ctx context.Context, code st
 // returns the authorization request.
 func (r *RequestManager) GetAuthorizeCodeSession(ctx context.Context, code string, session fosite.Session) (request fosite.Requester, err error) {
 	// Initialize contextual method logger
-	log := logger.WithFields(logrus.Fields{
+	log := newLogger(ctx, r.Logger, Fields{
 		"package":    "mongo",
 		"collection": storage.EntityAuthorizationCodes,
 		"method":     "GetAuthorizeCodeSession",
@@ -69,16 +70,17 @@ func (r *RequestManager) GetAuthorizeCodeSession(ctx context.Context, code strin
 	}
 
 	// Trace how long the Mongo operation takes to complete.
-	span, ctx := traceMongoCall(ctx, dbTrace{
-		Manager: "RequestManager",
-		Method:  "GetAuthorizeCodeSession",
+	span, ctx := r.DB.traceMongoCall(ctx, DBTrace{
+		Manager:    "RequestManager",
+		Method:     "GetAuthorizeCodeSession",
+		Collection: storage.EntityAuthorizationCodes,
 	})
 	defer span.Finish()
 
 	// Get the stored request
 	req, err := r.GetBySignature(ctx, storage.EntityAuthorizationCodes, code)
 	if err != nil {
-		if err == fosite.ErrNotFound {
+		if errors.Is(err, storage.ErrNotFound) {
 			log.WithError(err).Debug(logNotFound)
 			return nil, err
 		}
@@ -90,7 +92,7 @@ func (r *RequestManager) GetAuthorizeCodeSession(ctx context.Context, code strin
 	// Transform to a fosite.Request
 	request, err = req.ToRequest(ctx, session, r.Clients)
 	if err != nil {
-		if err == fosite.ErrNotFound {
+		if errors.Is(err, storage.ErrNotFound) {
 			log.WithError(err).Debug(logNotFound)
 			return nil, err
 		}
@@ -117,7 +119,7 @@ func (r *RequestManager) GetAuthorizeCodeSession(ctx context.Context, code strin
 // ErrInvalidatedAuthorizeCode error.
 func (r *RequestManager) InvalidateAuthorizeCodeSession(ctx context.Context, code string) (err error) {
 	// Initialize contextual method logger
-	log := logger.WithFields(logrus.Fields{
+	log := newLogger(ctx, r.Logger, Fields{
 		"package":    "mongo",
 		"collection": storage.EntityAuthorizationCodes,
 		"method":     "InvalidateAuthorizeCodeSession",
@@ -136,16 +138,17 @@ func (r *RequestManager) InvalidateAuthorizeCodeSession(ctx context.Context, cod
 	}
 
 	// Trace how long the Mongo operation takes to complete.
-	span, ctx := traceMongoCall(ctx, dbTrace{
-		Manager: "RequestManager",
-		Method:  "InvalidateAuthorizeCodeSession",
+	span, ctx := r.DB.traceMongoCall(ctx, DBTrace{
+		Manager:    "RequestManager",
+		Method:     "InvalidateAuthorizeCodeSession",
+		Collection: storage.EntityAuthorizationCodes,
 	})
 	defer span.Finish()
 
 	// Get the stored request
 	req, err := r.GetBySignature(ctx, storage.EntityAuthorizationCodes, code)
 	if err != nil {
-		if err == fosite.ErrNotFound {
+		if errors.Is(err, storage.ErrNotFound) {
 			log.WithError(err).Debug(logNotFound)
 			return err
 		}
@@ -161,7 +164,7 @@ func (r *RequestManager) InvalidateAuthorizeCodeSession(ctx context.Context, cod
 	// Push the update back
 	req, err = r.Update(ctx, storage.EntityAuthorizationCodes, req.ID, req)
 	if err != nil {
-		if err == fosite.ErrNotFound {
+		if errors.Is(err, storage.ErrNotFound) {
 			log.WithError(err).Debug(logNotFound)
 			return err
 		}
@@ -3,10 +3,10 @@ package mongo
 import (
 	// Standard Library Imports
 	"context"
+	"errors"
 
 	// External Imports
 	"github.com/ory/fosite"
-	"github.com/sirupsen/logrus"
 
 	// Internal Imports
 	"github.com/matthewhartstonge/storage"
@@ -15,23 +15,24 @@ import (
 // CreateRefreshTokenSession implements fosite.RefreshTokenStorage.
 func (r *RequestManager) CreateRefreshTokenSession(ctx context.Context, signature string, request fosite.Requester) (err error) {
 	// Initialize contextual method logger
-	log := logger.WithFields(logrus.Fields{
+	log := newLogger(ctx, r.Logger, Fields{
 		"package":    "mongo",
 		"collection": storage.EntityRefreshTokens,
 		"method":     "CreateRefreshTokenSession",
 	})
 
 	// Trace how long the Mongo operation takes to complete.
-	span, ctx := traceMongoCall(ctx, dbTrace{
-		Manager: "RequestManager",
-		Method:  "CreateRefreshTokenSession",
+	span, ctx := r.DB.traceMongoCall(ctx, DBTrace{
+		Manager:    "RequestManager",
+		Method:     "CreateRefreshTokenSession",
+		Collection: storage.EntityRefreshTokens,
 	})
 	defer span.Finish()
 
 	// Store session request
 	_, err = r.Create(ctx, storage.EntityRefreshTokens, toMongo(signature, request))
 	if err != nil {
-		if err == storage.ErrResourceExists {
+		if errors.Is(err, storage.ErrResourceExists) {
 			log.WithError(err).Debug(logConflict)
 			return err
 		}
@@ -47,7 +48,7 @@ func (r *RequestManager) CreateRefreshTokenSession(ctx context.Context, signatur
 // GetRefreshTokenSession implements fosite.RefreshTokenStorage.
 func (r *RequestManager) GetRefreshTokenSession(ctx context.Context, signature string, session fosite.Session) (request fosite.Requester, err error) {
 	// Initialize contextual method logger
-	log := logger.WithFields(logrus.Fields{
+	log := newLogger(ctx, r.Logger, Fields{
 		"package":    "mongo",
 		"collection": storage.EntityRefreshTokens,
 		"method":     "GetRefreshTokenSession",
@@ -66,16 +67,17 @@ func (r *RequestManager) GetRefreshTokenSession(ctx context.Context, signature s
 	}
 
 	// Trace how long the Mongo operation takes to complete.
-	span, ctx := traceMongoCall(ctx, dbTrace{
-		Manager: "RequestManager",
-		Method:  "GetRefreshTokenSession",
+	span, ctx := r.DB.traceMongoCall(ctx, DBTrace{
+		Manager:    "RequestManager",
+		Method:     "GetRefreshTokenSession",
+		Collection: storage.EntityRefreshTokens,
 	})
 	defer span.Finish()
 
 	// Get the stored request
 	req, err := r.GetBySignature(ctx, storage.EntityRefreshTokens, signature)
 	if err != nil {
-		if err == fosite.ErrNotFound {
+		if errors.Is(err, storage.ErrNotFound) {
 			log.WithError(err).Debug(logNotFound)
 			return nil, err
 		}
@@ -87,7 +89,7 @@ func (r *RequestManager) GetRefreshTokenSession(ctx context.Context, signature s
 	// Transform to a fosite.Request
 	request, err = req.ToRequest(ctx, session, r.Clients)
 	if err != nil {
-		if err == fosite.ErrNotFound {
+		if errors.Is(err, storage.ErrNotFound) {
 			log.WithError(err).Debug(logNotFound)
 			return nil, err
 		}
@@ -102,23 +104,24 @@ func (r *RequestManager) GetRefreshTokenSession(ctx context.Context, signature s
 // DeleteRefreshTokenSession implements fosite.RefreshTokenStorage.
 func (r *RequestManager) DeleteRefreshTokenSession(ctx context.Context, signature string) (err error) {
 	// Initialize contextual method logger
-	log := logger.WithFields(logrus.Fields{
+	log := newLogger(ctx, r.Logger, Fields{
 		"package":    "mongo",
 		"collection": storage.EntityRefreshTokens,
 		"method":     "DeleteRefreshTokenSession",
 	})
 
 	// Trace how long the Mongo operation takes to complete.
-	span, ctx := traceMongoCall(ctx, dbTrace{
-		Manager: "RequestManager",
-		Method:  "DeleteRefreshTokenSession",
+	span, ctx := r.DB.traceMongoCall(ctx, DBTrace{
+		Manager:    "RequestManager",
+		Method:     "DeleteRefreshTokenSession",
+		Collection: storage.EntityRefreshTokens,
 	})
 	defer span.Finish()
 
 	// Remove session request
 	err = r.DeleteBySignature(ctx, storage.EntityRefreshTokens, signature)
 	if err != nil {
-		if err == fosite.ErrNotFound {
+		if errors.Is(err, storage.ErrNotFound) {
 			log.WithError(err).Debug(logNotFound)
 			return err
 		}
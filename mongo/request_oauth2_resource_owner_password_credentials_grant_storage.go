@@ -3,10 +3,10 @@ package mongo
 import (
 	// Standard Library Imports
 	"context"
+	"errors"
 
 	// External Imports
 	"github.com/ory/fosite"
-	"github.com/sirupsen/logrus"
 
 	// Internal Imports
 	"github.com/matthewhartstonge/storage"
@@ -20,26 +20,34 @@ import (
 // hashed password within a User resource, found by username.
 func (r *RequestManager) Authenticate(ctx context.Context, username string, secret string) (err error) {
 	// Initialize contextual method logger
-	log := logger.WithFields(logrus.Fields{
+	log := newLogger(ctx, r.Logger, Fields{
 		"package":    "mongo",
 		"collection": storage.EntityUsers,
 		"method":     "Authenticate",
 	})
 
 	// Trace how long the Mongo operation takes to complete.
-	span, ctx := traceMongoCall(ctx, dbTrace{
-		Manager: "RequestManager",
-		Method:  "Authenticate",
+	span, ctx := r.DB.traceMongoCall(ctx, DBTrace{
+		Manager:    "RequestManager",
+		Method:     "Authenticate",
+		Collection: storage.EntityUsers,
 	})
 	defer span.Finish()
 
 	_, err = r.Users.Authenticate(ctx, username, secret)
 	if err != nil {
-		if err == fosite.ErrNotFound {
+		if errors.Is(err, storage.ErrNotFound) {
 			log.WithError(err).Debug(logNotFound)
 			return err
 		}
 
+		if errors.Is(err, storage.ErrAuthenticationFailed) && !errors.Is(err, fosite.ErrAccessDenied) {
+			// fosite requires fosite.ErrNotFound to be returned in order to
+			// respond with an invalid grant, rather than a server error.
+			log.WithError(err).Debug("failed to authenticate user")
+			return storage.NewError(storage.ErrAuthenticationFailed, storage.EntityUsers, fosite.ErrNotFound)
+		}
+
 		// Log to StdOut
 		log.WithError(err).Error(logError)
 		return err
@@ -3,10 +3,10 @@ package mongo
 import (
 	// Standard Library Imports
 	"context"
+	"errors"
 
 	// External Imports
 	"github.com/ory/fosite"
-	"github.com/sirupsen/logrus"
 
 	// Internal Imports
 	"github.com/matthewhartstonge/storage"
@@ -16,23 +16,24 @@ import (
 // given authorize code. This is relevant for explicit open id connect flow.
 func (r *RequestManager) CreateOpenIDConnectSession(ctx context.Context, authorizeCode string, request fosite.Requester) (err error) {
 	// Initialize contextual method logger
-	log := logger.WithFields(logrus.Fields{
+	log := newLogger(ctx, r.Logger, Fields{
 		"package":    "mongo",
 		"collection": storage.EntityOpenIDSessions,
 		"method":     "CreateOpenIDConnectSession",
 	})
 
 	// Trace how long the Mongo operation takes to complete.
-	span, ctx := traceMongoCall(ctx, dbTrace{
-		Manager: "RequestManager",
-		Method:  "CreateOpenIDConnectSession",
+	span, ctx := r.DB.traceMongoCall(ctx, DBTrace{
+		Manager:    "RequestManager",
+		Method:     "CreateOpenIDConnectSession",
+		Collection: storage.EntityOpenIDSessions,
 	})
 	defer span.Finish()
 
 	// Store session request
 	_, err = r.Create(ctx, storage.EntityOpenIDSessions, toMongo(authorizeCode, request))
 	if err != nil {
-		if err == storage.ErrResourceExists {
+		if errors.Is(err, storage.ErrResourceExists) {
 			log.WithError(err).Debug(logConflict)
 			return err
 		}
@@ -49,7 +50,7 @@ func (r *RequestManager) CreateOpenIDConnectSession(ctx context.Context, authori
 // and returns a fosite.Requester, or an error.
 func (r *RequestManager) GetOpenIDConnectSession(ctx context.Context, authorizeCode string, requester fosite.Requester) (request fosite.Requester, err error) {
 	// Initialize contextual method logger
-	log := logger.WithFields(logrus.Fields{
+	log := newLogger(ctx, r.Logger, Fields{
 		"package":    "mongo",
 		"collection": storage.EntityOpenIDSessions,
 		"method":     "GetOpenIDConnectSession",
@@ -68,16 +69,17 @@ func (r *RequestManager) GetOpenIDConnectSession(ctx context.Context, authorizeC
 	}
 
 	// Trace how long the Mongo operation takes to complete.
-	span, ctx := traceMongoCall(ctx, dbTrace{
-		Manager: "RequestManager",
-		Method:  "GetOpenIDConnectSession",
+	span, ctx := r.DB.traceMongoCall(ctx, DBTrace{
+		Manager:    "RequestManager",
+		Method:     "GetOpenIDConnectSession",
+		Collection: storage.EntityOpenIDSessions,
 	})
 	defer span.Finish()
 
 	// Get the stored request
 	req, err := r.GetBySignature(ctx, storage.EntityOpenIDSessions, authorizeCode)
 	if err != nil {
-		if err == fosite.ErrNotFound {
+		if errors.Is(err, storage.ErrNotFound) {
 			log.WithError(err).Debug(logNotFound)
 			return nil, err
 		}
@@ -89,12 +91,12 @@ func (r *RequestManager) GetOpenIDConnectSession(ctx context.Context, authorizeC
 	// Transform to a fosite.Request
 	session := requester.GetSession()
 	if session == nil {
-		return nil, fosite.ErrNotFound
+		return nil, storage.NewNotFoundError(storage.EntityOpenIDSessions)
 	}
 
 	request, err = req.ToRequest(ctx, session, r.Clients)
 	if err != nil {
-		if err == fosite.ErrNotFound {
+		if errors.Is(err, storage.ErrNotFound) {
 			log.WithError(err).Debug(logNotFound)
 			return nil, err
 		}
@@ -109,23 +111,24 @@ func (r *RequestManager) GetOpenIDConnectSession(ctx context.Context, authorizeC
 // DeleteOpenIDConnectSession removes an open id connect session from mongo.
 func (r *RequestManager) DeleteOpenIDConnectSession(ctx context.Context, authorizeCode string) (err error) {
 	// Initialize contextual method logger
-	log := logger.WithFields(logrus.Fields{
+	log := newLogger(ctx, r.Logger, Fields{
 		"package":    "mongo",
 		"collection": storage.EntityOpenIDSessions,
 		"method":     "DeleteOpenIDConnectSession",
 	})
 
 	// Trace how long the Mongo operation takes to complete.
-	span, ctx := traceMongoCall(ctx, dbTrace{
-		Manager: "RequestManager",
-		Method:  "DeleteOpenIDConnectSession",
+	span, ctx := r.DB.traceMongoCall(ctx, DBTrace{
+		Manager:    "RequestManager",
+		Method:     "DeleteOpenIDConnectSession",
+		Collection: storage.EntityOpenIDSessions,
 	})
 	defer span.Finish()
 
 	// Remove session request
 	err = r.DeleteBySignature(ctx, storage.EntityOpenIDSessions, authorizeCode)
 	if err != nil {
-		if err == fosite.ErrNotFound {
+		if errors.Is(err, storage.ErrNotFound) {
 			log.WithError(err).Debug(logNotFound)
 			return err
 		}
@@ -0,0 +1,222 @@
+package mongo
+
+import (
+	// Standard Library Imports
+	"context"
+	"errors"
+	"net/url"
+	"strings"
+	"time"
+
+	// External Imports
+	"github.com/ory/fosite"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	// Internal Imports
+	"github.com/matthewhartstonge/storage"
+)
+
+// CreatePARSession stores the pushed authorization request under the
+// request_uri.
+func (r *RequestManager) CreatePARSession(ctx context.Context, requestURI string, request fosite.AuthorizeRequester) (err error) {
+	// Initialize contextual method logger
+	log := newLogger(ctx, r.Logger, Fields{
+		"package":    "mongo",
+		"collection": storage.EntityPARSessions,
+		"method":     "CreatePARSession",
+	})
+
+	// Trace how long the Mongo operation takes to complete.
+	span, ctx := r.DB.traceMongoCall(ctx, DBTrace{
+		Manager:    "RequestManager",
+		Method:     "CreatePARSession",
+		Collection: storage.EntityPARSessions,
+	})
+	defer span.Finish()
+
+	// Store session request
+	_, err = r.Create(ctx, storage.EntityPARSessions, toMongoPAR(requestURI, request))
+	if err != nil {
+		if errors.Is(err, storage.ErrResourceExists) {
+			log.WithError(err).Debug(logConflict)
+			return err
+		}
+
+		// Log to StdOut
+		log.WithError(err).Error(logError)
+		return err
+	}
+
+	return nil
+}
+
+// GetPARSession returns the pushed authorization request stored under the
+// request_uri. The request is invalidated on retrieval, so consecutive calls,
+// or calls after the request's lifespan has passed, return not found.
+func (r *RequestManager) GetPARSession(ctx context.Context, requestURI string) (request fosite.AuthorizeRequester, err error) {
+	// Initialize contextual method logger
+	log := newLogger(ctx, r.Logger, Fields{
+		"package":    "mongo",
+		"collection": storage.EntityPARSessions,
+		"method":     "GetPARSession",
+	})
+
+	// Build Query
+	query := bson.M{
+		"signature": requestURI,
+		"active":    true,
+	}
+
+	// Trace how long the Mongo operation takes to complete.
+	span, ctx := r.DB.traceMongoCall(ctx, DBTrace{
+		Manager:    "RequestManager",
+		Method:     "GetPARSession",
+		Collection: storage.EntityPARSessions,
+		Operation:  "update",
+		Selector:   query,
+	})
+	defer span.Finish()
+
+	// Atomically invalidate the request, so it can only be used once.
+	update := bson.M{
+		"$set": bson.M{
+			"active":     false,
+			"updateTime": time.Now().Unix(),
+		},
+	}
+
+	var req storage.Request
+	collection := r.DB.Collection(storage.EntityPARSessions)
+	err = collection.FindOneAndUpdate(ctx, query, update, options.FindOneAndUpdate()).Decode(&req)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			log.WithError(err).Debug(logNotFound)
+			return nil, storage.NewNotFoundError(storage.EntityPARSessions)
+		}
+
+		// Log to StdOut
+		log.WithError(err).Error(logError)
+		// Log to Tracer
+		span.RecordError(err)
+		return nil, toStorageError(storage.EntityPARSessions, err)
+	}
+
+	if time.Since(req.RequestedAt) > r.parLifespan() {
+		log.Debug("pushed authorization request has expired")
+		return nil, storage.NewNotFoundError(storage.EntityPARSessions)
+	}
+
+	// Transform to a fosite.AuthorizeRequest
+	return toAuthorizeRequest(ctx, req, r.Clients)
+}
+
+// DeletePARSession deletes the pushed authorization request stored under the
+// request_uri.
+func (r *RequestManager) DeletePARSession(ctx context.Context, requestURI string) (err error) {
+	// Initialize contextual method logger
+	log := newLogger(ctx, r.Logger, Fields{
+		"package":    "mongo",
+		"collection": storage.EntityPARSessions,
+		"method":     "DeletePARSession",
+	})
+
+	// Trace how long the Mongo operation takes to complete.
+	span, ctx := r.DB.traceMongoCall(ctx, DBTrace{
+		Manager:    "RequestManager",
+		Method:     "DeletePARSession",
+		Collection: storage.EntityPARSessions,
+	})
+	defer span.Finish()
+
+	err = r.DeleteBySignature(ctx, storage.EntityPARSessions, requestURI)
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			log.WithError(err).Debug(logNotFound)
+			return err
+		}
+
+		// Log to StdOut
+		log.WithError(err).Error(logError)
+		return err
+	}
+
+	return nil
+}
+
+// parLifespan returns how long a pushed authorization request can be used
+// for, defaulting to storage.DefaultPARLifespan.
+func (r *RequestManager) parLifespan() time.Duration {
+	if r.PARLifespan > 0 {
+		return r.PARLifespan
+	}
+
+	return storage.DefaultPARLifespan
+}
+
+// parClientAuthParams provides the client authentication parameters, which
+// are sent to the PAR endpoint alongside the authorization request, but are
+// credentials rather than part of the request, so are never stored.
+var parClientAuthParams = []string{
+	"client_secret",
+	"client_assertion",
+	"client_assertion_type",
+}
+
+// toMongoPAR transforms a pushed fosite.AuthorizeRequester to a
+// storage.Request. The authorization specific parameters are retained in the
+// request form, as validated by fosite, less any client authentication
+// parameters. The session is not stored, as the pushed request is yet to be
+// authorized.
+func toMongoPAR(requestURI string, r fosite.AuthorizeRequester) storage.Request {
+	form := url.Values{}
+	for key, values := range r.GetRequestForm() {
+		form[key] = append([]string{}, values...)
+	}
+	for _, key := range parClientAuthParams {
+		form.Del(key)
+	}
+	form.Set("response_type", strings.Join(r.GetResponseTypes(), " "))
+	if redirectURI := r.GetRedirectURI(); redirectURI != nil {
+		form.Set("redirect_uri", redirectURI.String())
+	}
+	if state := r.GetState(); state != "" {
+		form.Set("state", state)
+	}
+
+	return storage.Request{
+		ID:                r.GetID(),
+		RequestedAt:       r.GetRequestedAt(),
+		Signature:         requestURI,
+		ClientID:          r.GetClient().GetID(),
+		RequestedScope:    r.GetRequestedScopes(),
+		GrantedScope:      r.GetGrantedScopes(),
+		RequestedAudience: r.GetRequestedAudience(),
+		GrantedAudience:   r.GetGrantedAudience(),
+		Form:              form,
+		Active:            true,
+	}
+}
+
+// toAuthorizeRequest transforms a stored pushed authorization request to a
+// fosite.AuthorizeRequest.
+func toAuthorizeRequest(ctx context.Context, req storage.Request, clients storage.ClientStorer) (*fosite.AuthorizeRequest, error) {
+	request, err := req.ToRequest(ctx, nil, clients)
+	if err != nil {
+		return nil, err
+	}
+
+	redirectURI, err := url.Parse(req.Form.Get("redirect_uri"))
+	if err != nil {
+		return nil, storage.NewError(storage.ErrInvalidArgument, storage.EntityPARSessions, err, "redirect_uri")
+	}
+
+	authorizeRequest := fosite.NewAuthorizeRequest()
+	authorizeRequest.Request = *request
+	authorizeRequest.ResponseTypes = fosite.RemoveEmpty(strings.Split(req.Form.Get("response_type"), " "))
+	authorizeRequest.RedirectURI = redirectURI
+	authorizeRequest.State = req.Form.Get("state")
+
+	return authorizeRequest, nil
+}
@@ -0,0 +1,115 @@
+package mongo
+
+import (
+	// Standard Library Imports
+	"context"
+	"net/url"
+	"reflect"
+	"testing"
+	"time"
+
+	// External Imports
+	"github.com/ory/fosite"
+
+	// Internal Imports
+	"github.com/matthewhartstonge/storage"
+)
+
+func TestRequestMongoManager_ImplementsStoragePARStorageInterface(t *testing.T) {
+	r := &RequestManager{}
+
+	var i interface{} = r
+	if _, ok := i.(storage.PARStorage); !ok {
+		t.Error("RequestManager does not implement interface storage.PARStorage")
+	}
+}
+
+// parClients provides a client storer returning a client for any ID.
+type parClients struct {
+	storage.ClientStorer
+}
+
+func (c *parClients) GetClient(ctx context.Context, clientID string) (fosite.Client, error) {
+	return &storage.Client{ID: clientID}, nil
+}
+
+func TestToMongoPAR_RoundTrip(t *testing.T) {
+	redirectURI, _ := url.Parse("https://cats.example.com/callback")
+
+	pushed := fosite.NewAuthorizeRequest()
+	pushed.ID = "request-1"
+	pushed.RequestedAt = time.Now()
+	pushed.Client = &storage.Client{ID: "client-1"}
+	pushed.RequestedScope = fosite.Arguments{"openid", "cats:read"}
+	pushed.Form = url.Values{"nonce": {"some-nonce"}}
+	pushed.ResponseTypes = fosite.Arguments{"code", "id_token"}
+	pushed.RedirectURI = redirectURI
+	pushed.State = "some-state"
+
+	req := toMongoPAR("urn:ietf:params:oauth:request_uri:abc", pushed)
+	if req.Signature != "urn:ietf:params:oauth:request_uri:abc" {
+		t.Errorf("expected the request_uri to be the signature, got %s", req.Signature)
+	}
+	if req.Session != nil {
+		t.Error("expected the session to not be stored")
+	}
+	if _, ok := pushed.Form["response_type"]; ok {
+		t.Error("expected the pushed request form to not be modified")
+	}
+
+	got, err := toAuthorizeRequest(context.Background(), req, &parClients{})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if got.GetID() != "request-1" || got.GetClient().GetID() != "client-1" {
+		t.Errorf("expected request-1 for client-1, got %s for %s", got.GetID(), got.GetClient().GetID())
+	}
+	if !reflect.DeepEqual(got.GetResponseTypes(), pushed.ResponseTypes) {
+		t.Errorf("expected response types %v, got %v", pushed.ResponseTypes, got.GetResponseTypes())
+	}
+	if got.GetRedirectURI().String() != redirectURI.String() {
+		t.Errorf("expected redirect uri %s, got %s", redirectURI, got.GetRedirectURI())
+	}
+	if got.GetState() != "some-state" {
+		t.Errorf("expected state some-state, got %s", got.GetState())
+	}
+	if got.GetRequestForm().Get("nonce") != "some-nonce" {
+		t.Error("expected the request form to be retained")
+	}
+	if !reflect.DeepEqual(got.GetRequestedScopes(), pushed.RequestedScope) {
+		t.Errorf("expected scopes %v, got %v", pushed.RequestedScope, got.GetRequestedScopes())
+	}
+}
+
+func TestToMongoPAR_ShouldNotStoreClientCredentials(t *testing.T) {
+	pushed := fosite.NewAuthorizeRequest()
+	pushed.Client = &storage.Client{ID: "client-1"}
+	pushed.Form = url.Values{
+		"nonce":                 {"some-nonce"},
+		"client_secret":         {"some-secret"},
+		"client_assertion":      {"some.signed.jwt"},
+		"client_assertion_type": {"urn:ietf:params:oauth:client-assertion-type:jwt-bearer"},
+	}
+
+	req := toMongoPAR("urn:ietf:params:oauth:request_uri:abc", pushed)
+	for _, key := range []string{"client_secret", "client_assertion", "client_assertion_type"} {
+		if _, ok := req.Form[key]; ok {
+			t.Errorf("expected %s to not be stored", key)
+		}
+		if _, ok := pushed.Form[key]; !ok {
+			t.Errorf("expected the pushed request form to retain %s", key)
+		}
+	}
+	if req.Form.Get("nonce") != "some-nonce" {
+		t.Error("expected the request form to be retained")
+	}
+}
+
+func TestRequestManager_parLifespan(t *testing.T) {
+	if lifespan := (&RequestManager{}).parLifespan(); lifespan != storage.DefaultPARLifespan {
+		t.Errorf("expected the default lifespan, got %s", lifespan)
+	}
+	if lifespan := (&RequestManager{PARLifespan: time.Minute * 5}).parLifespan(); lifespan != 5*time.Minute {
+		t.Errorf("expected a 5m lifespan, got %s", lifespan)
+	}
+}
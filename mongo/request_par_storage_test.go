@@ -0,0 +1,185 @@
+package mongo_test
+
+import (
+	// Standard Library Imports
+	"context"
+	"errors"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+
+	// External Imports
+	"github.com/google/uuid"
+	"github.com/ory/fosite"
+	"go.mongodb.org/mongo-driver/bson"
+
+	// Internal Imports
+	"github.com/matthewhartstonge/storage"
+	"github.com/matthewhartstonge/storage/mongo"
+)
+
+func expectedPAR(client storage.Client) *fosite.AuthorizeRequest {
+	redirectURI, _ := url.Parse("https://cats.example.com/callback")
+
+	pushed := fosite.NewAuthorizeRequest()
+	pushed.ID = uuid.NewString()
+	pushed.RequestedAt = time.Now()
+	pushed.Client = &client
+	pushed.RequestedScope = fosite.Arguments{"openid"}
+	pushed.Form = url.Values{
+		"nonce":         {"some-nonce"},
+		"client_secret": {"some-secret"},
+	}
+	pushed.ResponseTypes = fosite.Arguments{"code"}
+	pushed.RedirectURI = redirectURI
+	pushed.State = "some-state"
+
+	return pushed
+}
+
+func createPAR(ctx context.Context, t *testing.T, store *mongo.Store, pushed *fosite.AuthorizeRequest) string {
+	requestURI := storage.PARRequestURIPrefix + uuid.NewString()
+	err := store.RequestManager.CreatePARSession(ctx, requestURI, pushed)
+	if err != nil {
+		AssertFatal(t, err, nil, "create should return no database errors")
+	}
+
+	return requestURI
+}
+
+func TestRequestManager_CreatePARSession(t *testing.T) {
+	store, ctx, teardown := setup(t)
+	defer teardown()
+
+	client := createClient(ctx, t, store)
+	requestURI := createPAR(ctx, t, store, expectedPAR(client))
+
+	var stored storage.Request
+	err := store.DB.Collection(storage.EntityPARSessions).
+		FindOne(ctx, bson.M{"signature": requestURI}).
+		Decode(&stored)
+	if err != nil {
+		AssertFatal(t, err, nil, "pushed request should be stored")
+	}
+	if _, ok := stored.Form["client_secret"]; ok {
+		AssertError(t, stored.Form, "no client_secret", "client credentials should not be stored")
+	}
+	if stored.Form.Get("nonce") != "some-nonce" {
+		AssertError(t, stored.Form.Get("nonce"), "some-nonce", "request parameters should be stored")
+	}
+}
+
+func TestRequestManager_GetPARSession(t *testing.T) {
+	store, ctx, teardown := setup(t)
+	defer teardown()
+
+	client := createClient(ctx, t, store)
+	pushed := expectedPAR(client)
+	requestURI := createPAR(ctx, t, store, pushed)
+
+	got, err := store.RequestManager.GetPARSession(ctx, requestURI)
+	if err != nil {
+		AssertFatal(t, err, nil, "get should return no database errors")
+	}
+	if got.GetID() != pushed.ID || got.GetClient().GetID() != client.ID {
+		AssertError(t, got, pushed, "pushed request not equal")
+	}
+	if got.GetRedirectURI().String() != pushed.RedirectURI.String() {
+		AssertError(t, got.GetRedirectURI(), pushed.RedirectURI, "redirect uri not equal")
+	}
+	if got.GetState() != pushed.State {
+		AssertError(t, got.GetState(), pushed.State, "state not equal")
+	}
+}
+
+func TestRequestManager_GetPARSession_ShouldOnlyBeUsedOnce(t *testing.T) {
+	store, ctx, teardown := setup(t)
+	defer teardown()
+
+	client := createClient(ctx, t, store)
+	requestURI := createPAR(ctx, t, store, expectedPAR(client))
+
+	// Race retrievals, of which exactly one should succeed.
+	const retrievers = 10
+	var wg sync.WaitGroup
+	errs := make([]error, retrievers)
+	for i := 0; i < retrievers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+
+			// Sessions can't be used concurrently, so retrieve outside of the
+			// test session.
+			_, errs[i] = store.RequestManager.GetPARSession(context.Background(), requestURI)
+		}(i)
+	}
+	wg.Wait()
+
+	retrieved := 0
+	for _, err := range errs {
+		switch {
+		case err == nil:
+			retrieved++
+		case !errors.Is(err, storage.ErrNotFound):
+			AssertError(t, err, storage.ErrNotFound, "used pushed requests should not be found")
+		}
+	}
+	if retrieved != 1 {
+		AssertError(t, retrieved, 1, "pushed request should be retrieved exactly once")
+	}
+
+	var stored storage.Request
+	err := store.DB.Collection(storage.EntityPARSessions).
+		FindOne(ctx, bson.M{"signature": requestURI}).
+		Decode(&stored)
+	if err != nil {
+		AssertFatal(t, err, nil, "used pushed request should be retained")
+	}
+	if stored.Active {
+		AssertError(t, stored.Active, false, "used pushed request should be invalidated")
+	}
+}
+
+func TestRequestManager_GetPARSession_ShouldExpire(t *testing.T) {
+	store, ctx, teardown := setup(t)
+	defer teardown()
+
+	client := createClient(ctx, t, store)
+	pushed := expectedPAR(client)
+	pushed.RequestedAt = time.Now().Add(-2 * storage.DefaultPARLifespan)
+	requestURI := createPAR(ctx, t, store, pushed)
+
+	_, err := store.RequestManager.GetPARSession(ctx, requestURI)
+	if !errors.Is(err, storage.ErrNotFound) {
+		AssertError(t, err, storage.ErrNotFound, "expired pushed request should not be found")
+	}
+}
+
+func TestRequestManager_GetPARSession_ShouldReturnNotFound(t *testing.T) {
+	store, ctx, teardown := setup(t)
+	defer teardown()
+
+	_, err := store.RequestManager.GetPARSession(ctx, storage.PARRequestURIPrefix+uuid.NewString())
+	if !errors.Is(err, storage.ErrNotFound) {
+		AssertError(t, err, storage.ErrNotFound, "unknown pushed request should not be found")
+	}
+}
+
+func TestRequestManager_DeletePARSession(t *testing.T) {
+	store, ctx, teardown := setup(t)
+	defer teardown()
+
+	client := createClient(ctx, t, store)
+	requestURI := createPAR(ctx, t, store, expectedPAR(client))
+
+	err := store.RequestManager.DeletePARSession(ctx, requestURI)
+	if err != nil {
+		AssertFatal(t, err, nil, "delete should return no database errors")
+	}
+
+	_, err = store.RequestManager.GetPARSession(ctx, requestURI)
+	if !errors.Is(err, storage.ErrNotFound) {
+		AssertError(t, err, storage.ErrNotFound, "deleted pushed request should not be found")
+	}
+}
@@ -0,0 +1,153 @@
+package mongo
+
+import (
+	// Standard Library Imports
+	"context"
+	"errors"
+
+	// External Imports
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.mongodb.org/mongo-driver/mongo/readconcern"
+	"go.mongodb.org/mongo-driver/mongo/readpref"
+	"go.mongodb.org/mongo-driver/mongo/writeconcern"
+
+	// Internal Imports
+	"github.com/matthewhartstonge/storage"
+)
+
+// txContextKey provides the key used to store the active transaction in the
+// context.
+type txContextKey struct{}
+
+// transaction provides the state of an in-flight transaction.
+type transaction struct {
+	// session contains the session the transaction was started on.
+	session mongo.Session
+
+	// ownsSession is true if the session was started in order to run the
+	// transaction, and should be ended once the transaction completes.
+	ownsSession bool
+}
+
+// contextToTransaction returns the transaction, if one has been started
+// within the presented context.
+func contextToTransaction(ctx context.Context) (tx *transaction, ok bool) {
+	tx, ok = ctx.Value(txContextKey{}).(*transaction)
+	return tx, ok
+}
+
+// transactionOptions returns the options used to start a transaction.
+// Transactions must read from the primary, regardless of the read preference
+// the client has been configured with.
+func transactionOptions() *options.TransactionOptions {
+	return options.Transaction().
+		SetReadPreference(readpref.Primary()).
+		SetReadConcern(readconcern.Snapshot()).
+		SetWriteConcern(writeconcern.New(writeconcern.WMajority()))
+}
+
+// BeginTX implements fosite.storage.Transactional.
+//
+// BeginTX starts a transaction on the session contained in the context, or if
+// there is none, a new session. If the connected deployment does not support
+// transactions, for example, a standalone server, the context is returned
+// as is and each operation is applied individually.
+func (r *RequestManager) BeginTX(ctx context.Context) (context.Context, error) {
+	log := newLogger(ctx, r.Logger, Fields{
+		"package": "mongo",
+		"method":  "BeginTX",
+	})
+
+	if !r.DB.HasTransactions {
+		log.Debug("transactions not supported, continuing without a transaction")
+		return ctx, nil
+	}
+
+	if _, ok := contextToTransaction(ctx); ok {
+		err := storage.NewError(storage.ErrPreconditionFailed, "", errors.New("transaction already in progress"))
+		log.WithError(err).Error(logError)
+		return ctx, err
+	}
+
+	tx := &transaction{}
+	session, ok := ContextToSession(ctx)
+	if !ok {
+		var err error
+		session, err = r.DB.Client().StartSession()
+		if err != nil {
+			log.WithError(err).Error("error starting session")
+			return ctx, toStorageError("", err)
+		}
+
+		tx.ownsSession = true
+		ctx = SessionToContext(ctx, session)
+	}
+	tx.session = session
+
+	if err := session.StartTransaction(transactionOptions()); err != nil {
+		log.WithError(err).Error("error starting transaction")
+		if tx.ownsSession {
+			session.EndSession(ctx)
+		}
+
+		return ctx, toStorageError("", err)
+	}
+
+	return context.WithValue(ctx, txContextKey{}, tx), nil
+}
+
+// Commit implements fosite.storage.Transactional.
+//
+// Commit commits the transaction started by BeginTX. If BeginTX continued
+// without a transaction, Commit does nothing.
+func (r *RequestManager) Commit(ctx context.Context) error {
+	tx, ok := contextToTransaction(ctx)
+	if !ok {
+		return nil
+	}
+
+	log := newLogger(ctx, r.Logger, Fields{
+		"package": "mongo",
+		"method":  "Commit",
+	})
+
+	if tx.ownsSession {
+		defer tx.session.EndSession(ctx)
+	}
+
+	if err := tx.session.CommitTransaction(ctx); err != nil {
+		log.WithError(err).Error("error committing transaction")
+		return toStorageError("", err)
+	}
+
+	return nil
+}
+
+// Rollback implements fosite.storage.Transactional.
+//
+// Rollback aborts the transaction started by BeginTX, discarding any writes
+// made within it. If BeginTX continued without a transaction, Rollback does
+// nothing.
+func (r *RequestManager) Rollback(ctx context.Context) error {
+	tx, ok := contextToTransaction(ctx)
+	if !ok {
+		return nil
+	}
+
+	log := newLogger(ctx, r.Logger, Fields{
+		"package": "mongo",
+		"method":  "Rollback",
+	})
+
+	if tx.ownsSession {
+		defer tx.session.EndSession(ctx)
+	}
+
+	if err := tx.session.AbortTransaction(ctx); err != nil {
+		log.WithError(err).Error("error rolling back transaction")
+		return toStorageError("", err)
+	}
+
+	return nil
+}
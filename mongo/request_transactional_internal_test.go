@@ -0,0 +1,43 @@
+package mongo
+
+import (
+	// Standard Library Imports
+	"context"
+	"testing"
+
+	// External Imports
+	fositeStorage "github.com/ory/fosite/storage"
+)
+
+func TestRequestMongoManager_ImplementsFositeStorageTransactionalInterface(t *testing.T) {
+	r := &RequestManager{}
+
+	var i interface{} = r
+	if _, ok := i.(fositeStorage.Transactional); !ok {
+		t.Error("RequestManager does not implement interface storage.Transactional")
+	}
+}
+
+func TestRequestManager_BeginTX_TransactionsNotSupported(t *testing.T) {
+	r := &RequestManager{
+		DB: &DB{},
+	}
+
+	ctx := context.Background()
+	got, err := r.BeginTX(ctx)
+	if err != nil {
+		t.Fatalf("expected no error when transactions aren't supported, got %s", err)
+	}
+
+	if got != ctx {
+		t.Error("expected the context to be returned as is when transactions aren't supported")
+	}
+
+	if err := r.Commit(got); err != nil {
+		t.Errorf("expected commit without a transaction to do nothing, got %s", err)
+	}
+
+	if err := r.Rollback(got); err != nil {
+		t.Errorf("expected rollback without a transaction to do nothing, got %s", err)
+	}
+}
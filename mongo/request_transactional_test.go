@@ -0,0 +1,103 @@
+package mongo_test
+
+import (
+	// Standard Library Imports
+	"context"
+	"errors"
+	"testing"
+
+	// External Imports
+	"github.com/google/uuid"
+	"github.com/ory/fosite"
+
+	// Internal Imports
+	"github.com/matthewhartstonge/storage"
+	"github.com/matthewhartstonge/storage/mongo"
+)
+
+// createAccessTokenInTX creates an access token within a transaction, which
+// is committed, or rolled back, returning the token signature.
+func createAccessTokenInTX(ctx context.Context, t *testing.T, store *mongo.Store, commit bool) string {
+	client := createClient(ctx, t, store)
+
+	request := fosite.NewRequest()
+	request.ID = uuid.NewString()
+	request.Client = &client
+	request.Session = &fosite.DefaultSession{Subject: uuid.NewString()}
+
+	txCtx, err := store.RequestManager.BeginTX(ctx)
+	if err != nil {
+		AssertFatal(t, err, nil, "begin should return no database errors")
+	}
+
+	signature := uuid.NewString()
+	err = store.RequestManager.CreateAccessTokenSession(txCtx, signature, request)
+	if err != nil {
+		_ = store.RequestManager.Rollback(txCtx)
+		AssertFatal(t, err, nil, "create access token should return no database errors")
+	}
+
+	if commit {
+		err = store.RequestManager.Commit(txCtx)
+	} else {
+		err = store.RequestManager.Rollback(txCtx)
+	}
+	if err != nil {
+		AssertFatal(t, err, nil, "ending the transaction should return no database errors")
+	}
+
+	return signature
+}
+
+func TestRequestManager_Commit(t *testing.T) {
+	store, ctx, teardown := setup(t)
+	defer teardown()
+
+	signature := createAccessTokenInTX(ctx, t, store, true)
+
+	_, err := store.RequestManager.GetAccessTokenSession(ctx, signature, &fosite.DefaultSession{})
+	if err != nil {
+		AssertError(t, err, nil, "committed access token should be visible")
+	}
+}
+
+func TestRequestManager_Rollback(t *testing.T) {
+	store, ctx, teardown := setup(t)
+	defer teardown()
+
+	if !store.DB.HasTransactions {
+		t.Skip("transactions are not supported by the connected deployment")
+	}
+
+	signature := createAccessTokenInTX(ctx, t, store, false)
+
+	_, err := store.RequestManager.GetAccessTokenSession(ctx, signature, &fosite.DefaultSession{})
+	if !errors.Is(err, storage.ErrNotFound) {
+		AssertError(t, err, storage.ErrNotFound, "rolled back access token should not be visible")
+	}
+}
+
+func TestRequestManager_Rollback_ShouldNotTransactOnStandalone(t *testing.T) {
+	store, ctx, teardown := setup(t)
+	defer teardown()
+
+	if store.DB.HasTransactions {
+		t.Skip("transactions are supported by the connected deployment")
+	}
+
+	txCtx, err := store.RequestManager.BeginTX(ctx)
+	if err != nil {
+		AssertFatal(t, err, nil, "begin should return no database errors")
+	}
+	if txCtx != ctx {
+		AssertError(t, txCtx, ctx, "begin should return the context as is")
+	}
+
+	// Without a transaction, each write is applied as it is made.
+	signature := createAccessTokenInTX(ctx, t, store, false)
+
+	_, err = store.RequestManager.GetAccessTokenSession(ctx, signature, &fosite.DefaultSession{})
+	if err != nil {
+		AssertError(t, err, nil, "access token should be visible, as there was no transaction to roll back")
+	}
+}
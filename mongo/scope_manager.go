@@ -0,0 +1,421 @@
+package mongo
+
+import (
+	// Standard Library Imports
+	"context"
+	"errors"
+	"sort"
+	"time"
+
+	// External Imports
+	"github.com/google/uuid"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	// Internal Imports
+	"github.com/matthewhartstonge/storage"
+)
+
+// ScopeManager provides a mongo backed implementation for registered scopes.
+//
+// Implements:
+// - storage.Configurer
+// - storage.ScopeStorer
+// - storage.ScopeManager
+type ScopeManager struct {
+	DB     *DB
+	Logger Logger
+}
+
+// Configure implements storage.Configurer.
+func (s *ScopeManager) Configure(ctx context.Context) (err error) {
+	log := newLogger(ctx, s.Logger, Fields{
+		"package":    "mongo",
+		"collection": storage.EntityScopes,
+		"method":     "Configure",
+	})
+
+	indices := []mongo.IndexModel{
+		{
+			Keys: bson.D{
+				{
+					Key:   "id",
+					Value: int32(1),
+				},
+			},
+			Options: options.Index().
+				SetName(IdxScopeID).
+				SetBackground(true).
+				SetSparse(true).
+				SetUnique(true),
+		},
+		{
+			Keys: bson.D{
+				{
+					Key:   "name",
+					Value: int32(1),
+				},
+			},
+			Options: options.Index().
+				SetName(IdxScopeName).
+				SetBackground(true).
+				SetSparse(true).
+				SetUnique(true),
+		},
+	}
+
+	err = s.DB.createIndexes(ctx, storage.EntityScopes, indices)
+	if err != nil {
+		log.WithError(err).Error(logError)
+		return toStorageError(storage.EntityScopes, err)
+	}
+
+	return nil
+}
+
+// getConcrete returns a Scope resource.
+func (s *ScopeManager) getConcrete(ctx context.Context, scopeName string) (result storage.Scope, err error) {
+	log := newLogger(ctx, s.Logger, Fields{
+		"package":    "mongo",
+		"collection": storage.EntityScopes,
+		"method":     "getConcrete",
+		"scope":      scopeName,
+	})
+
+	// Build Query
+	query := bson.M{
+		"name": scopeName,
+	}
+
+	// Trace how long the Mongo operation takes to complete.
+	span, ctx := s.DB.traceMongoCall(ctx, DBTrace{
+		Manager:    "ScopeManager",
+		Method:     "getConcrete",
+		Collection: storage.EntityScopes,
+		Operation:  "find",
+		Query:      query,
+	})
+	defer span.Finish()
+
+	var scope storage.Scope
+	collection := s.DB.Collection(storage.EntityScopes)
+	err = collection.FindOne(ctx, query).Decode(&scope)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			log.WithError(err).Debug(logNotFound)
+			return result, storage.NewNotFoundError(storage.EntityScopes)
+		}
+
+		// Log to StdOut
+		log.WithError(err).Error(logError)
+		// Log to Tracer
+		span.RecordError(err)
+		return result, toStorageError(storage.EntityScopes, err)
+	}
+
+	return scope, nil
+}
+
+// List returns a list of Scope resources that match the provided inputs.
+func (s *ScopeManager) List(ctx context.Context, filter storage.ListScopesRequest) (results []storage.Scope, err error) {
+	// Initialize contextual method logger
+	log := newLogger(ctx, s.Logger, Fields{
+		"package":    "mongo",
+		"collection": storage.EntityScopes,
+		"method":     "List",
+	})
+
+	// Build Query
+	query := bson.M{}
+	if len(filter.Names) > 0 {
+		query["name"] = bson.M{"$in": filter.Names}
+	}
+	if filter.Audience != "" {
+		query["$or"] = []bson.M{
+			{"audience": bson.M{"$in": bson.A{filter.Audience, nil}}},
+			{"audience": bson.M{"$size": 0}},
+		}
+	}
+	if filter.Sensitive {
+		query["sensitive"] = true
+	}
+
+	// Trace how long the Mongo operation takes to complete.
+	span, ctx := s.DB.traceMongoCall(ctx, DBTrace{
+		Manager:    "ScopeManager",
+		Method:     "List",
+		Collection: storage.EntityScopes,
+		Operation:  "find",
+		Query:      query,
+	})
+	defer span.Finish()
+
+	opts := options.Find().SetSort(bson.D{{Key: "name", Value: 1}})
+
+	collection := s.DB.Collection(storage.EntityScopes)
+	cursor, err := collection.Find(ctx, query, opts)
+	if err != nil {
+		// Log to StdOut
+		log.WithError(err).Error(logError)
+		// Log to Tracer
+		span.RecordError(err)
+		return results, toStorageError(storage.EntityScopes, err)
+	}
+
+	var scopes []storage.Scope
+	err = cursor.All(ctx, &scopes)
+	if err != nil {
+		// Log to StdOut
+		log.WithError(err).Error(logError)
+		// Log to Tracer
+		span.RecordError(err)
+		return results, toStorageError(storage.EntityScopes, err)
+	}
+
+	return scopes, nil
+}
+
+// Create creates a new Scope resource and returns the newly created Scope
+// resource.
+func (s *ScopeManager) Create(ctx context.Context, scope storage.Scope) (result storage.Scope, err error) {
+	// Initialize contextual method logger
+	log := newLogger(ctx, s.Logger, Fields{
+		"package":    "mongo",
+		"collection": storage.EntityScopes,
+		"method":     "Create",
+		"scope":      scope.Name,
+	})
+
+	// Enable developers to provide their own IDs
+	if scope.ID == "" {
+		scope.ID = uuid.NewString()
+	}
+	if scope.CreateTime == 0 {
+		scope.CreateTime = time.Now().Unix()
+	}
+
+	if err = scope.Validate(); err != nil {
+		log.WithError(err).Debug(logInvalid)
+		return result, err
+	}
+
+	// Trace how long the Mongo operation takes to complete.
+	span, ctx := s.DB.traceMongoCall(ctx, DBTrace{
+		Manager:    "ScopeManager",
+		Method:     "Create",
+		Collection: storage.EntityScopes,
+		Operation:  "insert",
+	})
+	defer span.Finish()
+
+	// Create resource
+	collection := s.DB.Collection(storage.EntityScopes)
+	_, err = collection.InsertOne(ctx, scope)
+	if err != nil {
+		if isDup(err) {
+			// Log to StdOut
+			log.WithError(err).Debug(logConflict)
+			// Log to Tracer
+			span.RecordError(err)
+			return result, toStorageError(storage.EntityScopes, err)
+		}
+
+		// Log to StdOut
+		log.WithError(err).Error(logError)
+		// Log to Tracer
+		span.SetQuery(scope)
+		span.RecordError(err)
+		return result, toStorageError(storage.EntityScopes, err)
+	}
+
+	return scope, nil
+}
+
+// Get returns the specified Scope resource.
+func (s *ScopeManager) Get(ctx context.Context, scopeName string) (result storage.Scope, err error) {
+	return s.getConcrete(ctx, scopeName)
+}
+
+// Update updates the Scope resource and attributes and returns the updated
+// Scope resource. A scope can't be renamed, as clients and users reference it
+// by name.
+func (s *ScopeManager) Update(ctx context.Context, scopeName string, updatedScope storage.Scope) (result storage.Scope, err error) {
+	// Initialize contextual method logger
+	log := newLogger(ctx, s.Logger, Fields{
+		"package":    "mongo",
+		"collection": storage.EntityScopes,
+		"method":     "Update",
+		"scope":      scopeName,
+	})
+
+	currentResource, err := s.getConcrete(ctx, scopeName)
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			log.Debug(logNotFound)
+			return result, err
+		}
+
+		log.WithError(err).Error(logError)
+		return result, err
+	}
+
+	// Deny updating the entity Id and name
+	updatedScope.ID = currentResource.ID
+	updatedScope.Name = currentResource.Name
+	// Retain the create time
+	updatedScope.CreateTime = currentResource.CreateTime
+	// Update modified time
+	updatedScope.UpdateTime = time.Now().Unix()
+
+	// Build Query
+	selector := bson.M{
+		"name": scopeName,
+	}
+
+	// Trace how long the Mongo operation takes to complete.
+	span, ctx := s.DB.traceMongoCall(ctx, DBTrace{
+		Manager:    "ScopeManager",
+		Method:     "Update",
+		Collection: storage.EntityScopes,
+		Operation:  "update",
+		Selector:   selector,
+	})
+	defer span.Finish()
+
+	collection := s.DB.Collection(storage.EntityScopes)
+	res, err := collection.ReplaceOne(ctx, selector, updatedScope)
+	if err != nil {
+		// Log to StdOut
+		log.WithError(err).Error(logError)
+		// Log to Tracer
+		span.SetQuery(updatedScope)
+		span.RecordError(err)
+		return result, toStorageError(storage.EntityScopes, err)
+	}
+
+	if res.MatchedCount == 0 {
+		// Log to StdOut
+		log.WithError(err).Debug(logNotFound)
+		// Log to Tracer
+		span.RecordError(err)
+		return result, storage.NewNotFoundError(storage.EntityScopes)
+	}
+
+	return updatedScope, nil
+}
+
+// Delete deletes the specified Scope resource. Clients and users that
+// reference the scope are left as is, and are reported by ListUnregistered.
+func (s *ScopeManager) Delete(ctx context.Context, scopeName string) (err error) {
+	// Initialize contextual method logger
+	log := newLogger(ctx, s.Logger, Fields{
+		"package":    "mongo",
+		"collection": storage.EntityScopes,
+		"method":     "Delete",
+		"scope":      scopeName,
+	})
+
+	// Build Query
+	query := bson.M{
+		"name": scopeName,
+	}
+
+	// Trace how long the Mongo operation takes to complete.
+	span, ctx := s.DB.traceMongoCall(ctx, DBTrace{
+		Manager:    "ScopeManager",
+		Method:     "Delete",
+		Collection: storage.EntityScopes,
+		Operation:  "delete",
+		Query:      query,
+	})
+	defer span.Finish()
+
+	collection := s.DB.Collection(storage.EntityScopes)
+	res, err := collection.DeleteOne(ctx, query)
+	if err != nil {
+		// Log to StdOut
+		log.WithError(err).Error(logError)
+		// Log to Tracer
+		span.RecordError(err)
+		return toStorageError(storage.EntityScopes, err)
+	}
+
+	if res.DeletedCount == 0 {
+		// Log to StdOut
+		log.WithError(err).Debug(logNotFound)
+		// Log to Tracer
+		span.RecordError(err)
+		return storage.NewNotFoundError(storage.EntityScopes)
+	}
+
+	return nil
+}
+
+// ListUnregistered returns the scopes referenced by clients, users or groups
+// that are not registered, sorted by name.
+func (s *ScopeManager) ListUnregistered(ctx context.Context) (results []string, err error) {
+	var inUse []string
+	for _, entityName := range []string{storage.EntityClients, storage.EntityUsers, storage.EntityGroups} {
+		scopes, err := s.distinctScopes(ctx, entityName)
+		if err != nil {
+			return nil, err
+		}
+		inUse = union(inUse, scopes)
+	}
+	if len(inUse) == 0 {
+		return nil, nil
+	}
+
+	registered, err := s.List(ctx, storage.ListScopesRequest{Names: inUse})
+	if err != nil {
+		return nil, err
+	}
+
+	registeredNames := make([]string, len(registered))
+	for i, scope := range registered {
+		registeredNames[i] = scope.Name
+	}
+
+	results = difference(inUse, registeredNames)
+	sort.Strings(results)
+
+	return results, nil
+}
+
+// distinctScopes returns the distinct scopes referenced by the entity.
+func (s *ScopeManager) distinctScopes(ctx context.Context, entityName string) (results []string, err error) {
+	// Initialize contextual method logger
+	log := newLogger(ctx, s.Logger, Fields{
+		"package":    "mongo",
+		"collection": entityName,
+		"method":     "ListUnregistered",
+	})
+
+	// Trace how long the Mongo operation takes to complete.
+	span, ctx := s.DB.traceMongoCall(ctx, DBTrace{
+		Manager:    "ScopeManager",
+		Method:     "ListUnregistered",
+		Collection: entityName,
+		Operation:  "distinct",
+	})
+	defer span.Finish()
+
+	collection := s.DB.Collection(entityName)
+	values, err := collection.Distinct(ctx, "scopes", bson.M{})
+	if err != nil {
+		// Log to StdOut
+		log.WithError(err).Error(logError)
+		// Log to Tracer
+		span.RecordError(err)
+		return nil, toStorageError(entityName, err)
+	}
+
+	for _, value := range values {
+		if scope, ok := value.(string); ok {
+			results = append(results, scope)
+		}
+	}
+
+	return results, nil
+}
@@ -0,0 +1,68 @@
+package mongo
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/matthewhartstonge/storage"
+)
+
+func TestScopeMongoManager_ImplementsStorageConfigurer(t *testing.T) {
+	m := &ScopeManager{}
+
+	var i interface{} = m
+	if _, ok := i.(storage.Configurer); !ok {
+		t.Error("ScopeManager does not implement interface storage.Configurer")
+	}
+}
+
+func TestScopeMongoManager_ImplementsStorageScopeStorer(t *testing.T) {
+	m := &ScopeManager{}
+
+	var i interface{} = m
+	if _, ok := i.(storage.ScopeStorer); !ok {
+		t.Error("ScopeManager does not implement interface storage.ScopeStorer")
+	}
+}
+
+func TestScopeMongoManager_ImplementsStorageScopeManager(t *testing.T) {
+	m := &ScopeManager{}
+
+	var i interface{} = m
+	if _, ok := i.(storage.ScopeManager); !ok {
+		t.Error("ScopeManager does not implement interface storage.ScopeManager")
+	}
+}
+
+// strictScopes provides an in-memory scope storer, with no registered
+// scopes.
+type strictScopes struct {
+	storage.ScopeStorer
+}
+
+func (s *strictScopes) List(ctx context.Context, filter storage.ListScopesRequest) ([]storage.Scope, error) {
+	return nil, nil
+}
+
+func TestStrictScopes_ShouldRejectUnregisteredScopes(t *testing.T) {
+	ctx := context.Background()
+
+	c := &ClientManager{Scopes: &strictScopes{}}
+	_, err := c.Create(ctx, storage.Client{Scopes: []string{"cats:raed"}})
+	if !errors.Is(err, storage.ErrInvalidArgument) {
+		t.Errorf("expected client with unregistered scopes to be invalid, got %v", err)
+	}
+
+	u := &UserManager{Scopes: &strictScopes{}}
+	_, err = u.Create(ctx, storage.User{Scopes: []string{"cats:raed"}})
+	if !errors.Is(err, storage.ErrInvalidArgument) {
+		t.Errorf("expected user with unregistered scopes to be invalid, got %v", err)
+	}
+
+	g := &GroupManager{Scopes: &strictScopes{}}
+	_, err = g.Create(ctx, storage.Group{Name: "staff", Scopes: []string{"cats:raed"}})
+	if !errors.Is(err, storage.ErrInvalidArgument) {
+		t.Errorf("expected group with unregistered scopes to be invalid, got %v", err)
+	}
+}
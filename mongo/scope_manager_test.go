@@ -0,0 +1,190 @@
+package mongo_test
+
+import (
+	// Standard Library Imports
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	// External Imports
+	"github.com/google/uuid"
+
+	// Internal Imports
+	"github.com/matthewhartstonge/storage"
+	"github.com/matthewhartstonge/storage/mongo"
+)
+
+// setupStrictScopes returns a store that rejects unregistered scopes.
+func setupStrictScopes(t *testing.T) (*mongo.Store, context.Context, func()) {
+	cfg := mongo.DefaultConfig()
+	cfg.DatabaseName = "fositeStorageTest"
+	cfg.StrictScopes = true
+	store, err := mongo.New(cfg, nil)
+	if err != nil {
+		AssertFatal(t, err, nil, "mongo connection error")
+	}
+
+	ctx := context.Background()
+	return store, ctx, func() {
+		if err := store.DB.Drop(ctx); err != nil {
+			t.Errorf("error dropping database on cleanup: %s", err)
+		}
+		store.Close()
+	}
+}
+
+func createScopes(ctx context.Context, t *testing.T, store *mongo.Store, scopeNames ...string) {
+	for _, scopeName := range scopeNames {
+		_, err := store.ScopeManager.Create(ctx, storage.Scope{Name: scopeName})
+		if err != nil {
+			AssertFatal(t, err, nil, "create should return no database errors")
+		}
+	}
+}
+
+func TestScopeManager_Create(t *testing.T) {
+	store, ctx, teardown := setup(t)
+	defer teardown()
+
+	expected, err := store.ScopeManager.Create(ctx, storage.Scope{
+		Name:        "urn:test:cats:read",
+		Description: "Read cats",
+		Sensitive:   true,
+	})
+	if err != nil {
+		AssertFatal(t, err, nil, "create should return no database errors")
+	}
+
+	got, err := store.ScopeManager.Get(ctx, expected.Name)
+	if err != nil {
+		AssertFatal(t, err, nil, "get should return no database errors")
+	}
+	if !reflect.DeepEqual(got, expected) {
+		AssertError(t, got, expected, "scope not equal")
+	}
+
+	_, err = store.ScopeManager.Create(ctx, storage.Scope{Name: expected.Name})
+	if !errors.Is(err, storage.ErrResourceExists) {
+		AssertError(t, err, storage.ErrResourceExists, "create should conflict on the scope name")
+	}
+}
+
+func TestScopeManager_StrictScopes_ShouldRejectUnregisteredScopes(t *testing.T) {
+	store, ctx, teardown := setupStrictScopes(t)
+	defer teardown()
+
+	client := expectedClient()
+	_, err := store.ClientManager.Create(ctx, client)
+	if !errors.Is(err, storage.ErrInvalidArgument) {
+		AssertError(t, err, storage.ErrInvalidArgument, "creating a client with unregistered scopes should fail")
+	}
+
+	user := expectedUser()
+	_, err = store.UserManager.Create(ctx, user)
+	if !errors.Is(err, storage.ErrInvalidArgument) {
+		AssertError(t, err, storage.ErrInvalidArgument, "creating a user with unregistered scopes should fail")
+	}
+
+	_, err = store.GroupManager.Create(ctx, storage.Group{
+		Name:   "cats",
+		Scopes: []string{"urn:test:cats:write"},
+	})
+	if !errors.Is(err, storage.ErrInvalidArgument) {
+		AssertError(t, err, storage.ErrInvalidArgument, "creating a group with unregistered scopes should fail")
+	}
+
+	createScopes(ctx, t, store, client.Scopes...)
+	client, err = store.ClientManager.Create(ctx, client)
+	if err != nil {
+		AssertFatal(t, err, nil, "creating a client with registered scopes should succeed")
+	}
+
+	_, err = store.ClientManager.GrantScopes(ctx, client.ID, []string{"urn:test:birds:read"})
+	if !errors.Is(err, storage.ErrInvalidArgument) {
+		AssertError(t, err, storage.ErrInvalidArgument, "granting unregistered scopes should fail")
+	}
+
+	got, err := store.ClientManager.Get(ctx, client.ID)
+	if err != nil {
+		AssertFatal(t, err, nil, "get should return no database errors")
+	}
+	if !reflect.DeepEqual(got.Scopes, client.Scopes) {
+		AssertError(t, got.Scopes, client.Scopes, "rejected scopes should not be granted")
+	}
+}
+
+func TestScopeManager_ListUnregistered(t *testing.T) {
+	store, ctx, teardown := setup(t)
+	defer teardown()
+
+	createClient(ctx, t, store)
+	user := expectedUser()
+	user.Scopes = []string{"urn:test:cats:write", "urn:test:birds:read"}
+	if _, err := store.UserManager.Create(ctx, user); err != nil {
+		AssertFatal(t, err, nil, "create should return no database errors")
+	}
+	_, err := store.GroupManager.Create(ctx, storage.Group{
+		Name:   "fish",
+		Scopes: []string{"urn:test:fish:read"},
+	})
+	if err != nil {
+		AssertFatal(t, err, nil, "create should return no database errors")
+	}
+
+	createScopes(ctx, t, store, "urn:test:cats:write", uuid.NewString())
+
+	got, err := store.ScopeManager.ListUnregistered(ctx)
+	if err != nil {
+		AssertFatal(t, err, nil, "list unregistered should return no database errors")
+	}
+
+	expected := []string{"urn:test:birds:read", "urn:test:dogs:read", "urn:test:fish:read"}
+	if !reflect.DeepEqual(got, expected) {
+		AssertError(t, got, expected, "list unregistered should return the unregistered scopes in use, sorted")
+	}
+}
+
+func TestScopeManager_Delete_ShouldLeaveReferencesInPlace(t *testing.T) {
+	store, ctx, teardown := setupStrictScopes(t)
+	defer teardown()
+
+	client := expectedClient()
+	createScopes(ctx, t, store, client.Scopes...)
+	client, err := store.ClientManager.Create(ctx, client)
+	if err != nil {
+		AssertFatal(t, err, nil, "create should return no database errors")
+	}
+
+	deleted := client.Scopes[0]
+	if err = store.ScopeManager.Delete(ctx, deleted); err != nil {
+		AssertFatal(t, err, nil, "deleting a scope in use should succeed")
+	}
+
+	got, err := store.ClientManager.Get(ctx, client.ID)
+	if err != nil {
+		AssertFatal(t, err, nil, "get should return no database errors")
+	}
+	if !reflect.DeepEqual(got.Scopes, client.Scopes) {
+		AssertError(t, got.Scopes, client.Scopes, "deleting a scope should leave the client's scopes as is")
+	}
+
+	unregistered, err := store.ScopeManager.ListUnregistered(ctx)
+	if err != nil {
+		AssertFatal(t, err, nil, "list unregistered should return no database errors")
+	}
+	if !reflect.DeepEqual(unregistered, []string{deleted}) {
+		AssertError(t, unregistered, []string{deleted}, "the deleted scope should be reported as unregistered")
+	}
+
+	// Only added scopes are validated, so the client can still be updated.
+	got.Name = "Updated Client"
+	if _, err = store.ClientManager.Update(ctx, got.ID, got); err != nil {
+		AssertError(t, err, nil, "updating a client referencing a deleted scope should succeed")
+	}
+
+	err = store.ScopeManager.Delete(ctx, deleted)
+	if !errors.Is(err, storage.ErrNotFound) {
+		AssertError(t, err, storage.ErrNotFound, "deleting a missing scope should return not found")
+	}
+}
@@ -0,0 +1,357 @@
+package mongo
+
+import (
+	// Standard Library Imports
+	"context"
+	"errors"
+	"time"
+
+	// External Imports
+	"github.com/google/uuid"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	// Internal Imports
+	"github.com/matthewhartstonge/storage"
+)
+
+// TenantManager provides a mongo backed implementation for tenant resources.
+//
+// Implements:
+// - storage.Configurer
+// - storage.TenantStorer
+// - storage.TenantManager
+type TenantManager struct {
+	DB     *DB
+	Logger Logger
+}
+
+// Configure implements storage.Configurer.
+func (t *TenantManager) Configure(ctx context.Context) (err error) {
+	log := newLogger(ctx, t.Logger, Fields{
+		"package":    "mongo",
+		"collection": storage.EntityTenants,
+		"method":     "Configure",
+	})
+
+	indices := []mongo.IndexModel{
+		{
+			Keys: bson.D{
+				{
+					Key:   "id",
+					Value: int32(1),
+				},
+			},
+			Options: options.Index().
+				SetName(IdxTenantID).
+				SetBackground(true).
+				SetSparse(true).
+				SetUnique(true),
+		},
+	}
+
+	err = t.DB.createIndexes(ctx, storage.EntityTenants, indices)
+	if err != nil {
+		log.WithError(err).Error(logError)
+		return toStorageError(storage.EntityTenants, err)
+	}
+
+	return nil
+}
+
+// getConcrete returns a Tenant resource.
+func (t *TenantManager) getConcrete(ctx context.Context, tenantID string) (result storage.Tenant, err error) {
+	log := newLogger(ctx, t.Logger, Fields{
+		"package":    "mongo",
+		"collection": storage.EntityTenants,
+		"method":     "getConcrete",
+		"tenantID":   tenantID,
+	})
+
+	// Build Query
+	query := bson.M{
+		"id": tenantID,
+	}
+
+	// Trace how long the Mongo operation takes to complete.
+	span, ctx := t.DB.traceMongoCall(ctx, DBTrace{
+		Manager:    "TenantManager",
+		Method:     "getConcrete",
+		Collection: storage.EntityTenants,
+		Operation:  "find",
+		Query:      query,
+	})
+	defer span.Finish()
+
+	var tenant storage.Tenant
+	collection := t.DB.Collection(storage.EntityTenants)
+	err = collection.FindOne(ctx, query).Decode(&tenant)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			log.WithError(err).Debug(logNotFound)
+			return result, storage.NewNotFoundError(storage.EntityTenants)
+		}
+
+		// Log to StdOut
+		log.WithError(err).Error(logError)
+		// Log to Tracer
+		span.RecordError(err)
+		return result, toStorageError(storage.EntityTenants, err)
+	}
+
+	return tenant, nil
+}
+
+// List returns a list of Tenant resources that match the provided inputs.
+func (t *TenantManager) List(ctx context.Context, filter storage.ListTenantsRequest) (results []storage.Tenant, err error) {
+	// Initialize contextual method logger
+	log := newLogger(ctx, t.Logger, Fields{
+		"package":    "mongo",
+		"collection": storage.EntityTenants,
+		"method":     "List",
+	})
+
+	// Build Query
+	query := bson.M{}
+	if len(filter.IDs) > 0 {
+		query["id"] = bson.M{"$in": filter.IDs}
+	}
+	if filter.Name != "" {
+		query["name"] = filter.Name
+	}
+	if filter.Status != "" {
+		query["status"] = filter.Status
+	}
+	if filter.AllowedRegion != "" {
+		query["allowedRegions"] = filter.AllowedRegion
+	}
+
+	// Trace how long the Mongo operation takes to complete.
+	span, ctx := t.DB.traceMongoCall(ctx, DBTrace{
+		Manager:    "TenantManager",
+		Method:     "List",
+		Collection: storage.EntityTenants,
+		Operation:  "find",
+		Query:      query,
+	})
+	defer span.Finish()
+
+	collection := t.DB.Collection(storage.EntityTenants)
+	cursor, err := collection.Find(ctx, query)
+	if err != nil {
+		// Log to StdOut
+		log.WithError(err).Error(logError)
+		// Log to Tracer
+		span.RecordError(err)
+		return results, toStorageError(storage.EntityTenants, err)
+	}
+
+	var tenants []storage.Tenant
+	err = cursor.All(ctx, &tenants)
+	if err != nil {
+		// Log to StdOut
+		log.WithError(err).Error(logError)
+		// Log to Tracer
+		span.RecordError(err)
+		return results, toStorageError(storage.EntityTenants, err)
+	}
+
+	return tenants, nil
+}
+
+// Create creates a new Tenant resource and returns the newly created Tenant
+// resource.
+func (t *TenantManager) Create(ctx context.Context, tenant storage.Tenant) (result storage.Tenant, err error) {
+	// Initialize contextual method logger
+	log := newLogger(ctx, t.Logger, Fields{
+		"package":    "mongo",
+		"collection": storage.EntityTenants,
+		"method":     "Create",
+	})
+
+	// Enable developers to provide their own IDs
+	if tenant.ID == "" {
+		tenant.ID = uuid.NewString()
+	}
+	if tenant.CreateTime == 0 {
+		tenant.CreateTime = time.Now().Unix()
+	}
+	if tenant.Status == "" {
+		tenant.Status = storage.TenantStatusActive
+	}
+
+	if err = tenant.Validate(); err != nil {
+		log.WithError(err).Debug(logInvalid)
+		return result, err
+	}
+
+	// Trace how long the Mongo operation takes to complete.
+	span, ctx := t.DB.traceMongoCall(ctx, DBTrace{
+		Manager:    "TenantManager",
+		Method:     "Create",
+		Collection: storage.EntityTenants,
+		Operation:  "insert",
+	})
+	defer span.Finish()
+
+	// Create resource
+	collection := t.DB.Collection(storage.EntityTenants)
+	_, err = collection.InsertOne(ctx, tenant)
+	if err != nil {
+		if isDup(err) {
+			// Log to StdOut
+			log.WithError(err).Debug(logConflict)
+			// Log to Tracer
+			span.RecordError(err)
+			return result, toStorageError(storage.EntityTenants, err)
+		}
+
+		// Log to StdOut
+		log.WithError(err).Error(logError)
+		// Log to Tracer
+		span.SetQuery(tenant)
+		span.RecordError(err)
+		return result, toStorageError(storage.EntityTenants, err)
+	}
+
+	return tenant, nil
+}
+
+// Get returns the specified Tenant resource.
+func (t *TenantManager) Get(ctx context.Context, tenantID string) (result storage.Tenant, err error) {
+	return t.getConcrete(ctx, tenantID)
+}
+
+// Update updates the Tenant resource and attributes and returns the updated
+// Tenant resource.
+func (t *TenantManager) Update(ctx context.Context, tenantID string, updatedTenant storage.Tenant) (result storage.Tenant, err error) {
+	// Initialize contextual method logger
+	log := newLogger(ctx, t.Logger, Fields{
+		"package":    "mongo",
+		"collection": storage.EntityTenants,
+		"method":     "Update",
+		"id":         tenantID,
+	})
+
+	currentResource, err := t.getConcrete(ctx, tenantID)
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			log.Debug(logNotFound)
+			return result, err
+		}
+
+		log.WithError(err).Error(logError)
+		return result, err
+	}
+
+	// Deny updating the entity Id
+	updatedTenant.ID = tenantID
+	// Retain the create time
+	updatedTenant.CreateTime = currentResource.CreateTime
+	// Update modified time
+	updatedTenant.UpdateTime = time.Now().Unix()
+
+	if err = updatedTenant.Validate(); err != nil {
+		log.WithError(err).Debug(logInvalid)
+		return result, err
+	}
+
+	// Build Query
+	selector := bson.M{
+		"id": tenantID,
+	}
+
+	// Trace how long the Mongo operation takes to complete.
+	span, ctx := t.DB.traceMongoCall(ctx, DBTrace{
+		Manager:    "TenantManager",
+		Method:     "Update",
+		Collection: storage.EntityTenants,
+		Operation:  "update",
+		Selector:   selector,
+	})
+	defer span.Finish()
+
+	collection := t.DB.Collection(storage.EntityTenants)
+	res, err := collection.ReplaceOne(ctx, selector, updatedTenant)
+	if err != nil {
+		// Log to StdOut
+		log.WithError(err).Error(logError)
+		// Log to Tracer
+		span.SetQuery(updatedTenant)
+		span.RecordError(err)
+		return result, toStorageError(storage.EntityTenants, err)
+	}
+
+	if res.MatchedCount == 0 {
+		// Log to StdOut
+		log.WithError(err).Debug(logNotFound)
+		// Log to Tracer
+		span.RecordError(err)
+		return result, storage.NewNotFoundError(storage.EntityTenants)
+	}
+
+	return updatedTenant, nil
+}
+
+// Delete deletes the specified Tenant resource.
+func (t *TenantManager) Delete(ctx context.Context, tenantID string) (err error) {
+	// Initialize contextual method logger
+	log := newLogger(ctx, t.Logger, Fields{
+		"package":    "mongo",
+		"collection": storage.EntityTenants,
+		"method":     "Delete",
+		"id":         tenantID,
+	})
+
+	// Build Query
+	query := bson.M{
+		"id": tenantID,
+	}
+
+	// Trace how long the Mongo operation takes to complete.
+	span, ctx := t.DB.traceMongoCall(ctx, DBTrace{
+		Manager:    "TenantManager",
+		Method:     "Delete",
+		Collection: storage.EntityTenants,
+		Operation:  "delete",
+		Query:      query,
+	})
+	defer span.Finish()
+
+	collection := t.DB.Collection(storage.EntityTenants)
+	res, err := collection.DeleteOne(ctx, query)
+	if err != nil {
+		// Log to StdOut
+		log.WithError(err).Error(logError)
+		// Log to Tracer
+		span.RecordError(err)
+		return toStorageError(storage.EntityTenants, err)
+	}
+
+	if res.DeletedCount == 0 {
+		// Log to StdOut
+		log.WithError(err).Debug(logNotFound)
+		// Log to Tracer
+		span.RecordError(err)
+		return storage.NewNotFoundError(storage.EntityTenants)
+	}
+
+	return nil
+}
+
+// difference returns the values in a that are not in b.
+func difference(a []string, b []string) (diff []string) {
+	exists := make(map[string]bool, len(b))
+	for _, v := range b {
+		exists[v] = true
+	}
+
+	for _, v := range a {
+		if !exists[v] {
+			diff = append(diff, v)
+		}
+	}
+
+	return diff
+}
@@ -0,0 +1,46 @@
+package mongo
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/matthewhartstonge/storage"
+)
+
+func TestTenantMongoManager_ImplementsStorageConfigurer(t *testing.T) {
+	m := &TenantManager{}
+
+	var i interface{} = m
+	if _, ok := i.(storage.Configurer); !ok {
+		t.Error("TenantManager does not implement interface storage.Configurer")
+	}
+}
+
+func TestTenantMongoManager_ImplementsStorageTenantStorer(t *testing.T) {
+	m := &TenantManager{}
+
+	var i interface{} = m
+	if _, ok := i.(storage.TenantStorer); !ok {
+		t.Error("TenantManager does not implement interface storage.TenantStorer")
+	}
+}
+
+func TestTenantMongoManager_ImplementsStorageTenantManager(t *testing.T) {
+	m := &TenantManager{}
+
+	var i interface{} = m
+	if _, ok := i.(storage.TenantManager); !ok {
+		t.Error("TenantManager does not implement interface storage.TenantManager")
+	}
+}
+
+func TestDifference(t *testing.T) {
+	got := difference([]string{"a", "b", "c"}, []string{"b"})
+	if expected := []string{"a", "c"}; !reflect.DeepEqual(got, expected) {
+		t.Errorf("expected %v, got %v", expected, got)
+	}
+
+	if got := difference([]string{"a"}, []string{"a", "b"}); len(got) != 0 {
+		t.Errorf("expected no difference, got %v", got)
+	}
+}
@@ -0,0 +1,258 @@
+package mongo_test
+
+import (
+	// Standard Library Imports
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	// External Imports
+	"github.com/google/uuid"
+
+	// Internal Imports
+	"github.com/matthewhartstonge/storage"
+	"github.com/matthewhartstonge/storage/mongo"
+)
+
+func expectedTenant() storage.Tenant {
+	return storage.Tenant{
+		ID:             uuid.NewString(),
+		Name:           "Cats Inc.",
+		AllowedRegions: []string{"nz"},
+		DefaultScopes:  []string{"urn:test:cats:read"},
+	}
+}
+
+func createTenant(ctx context.Context, t *testing.T, store *mongo.Store, tenant storage.Tenant) storage.Tenant {
+	got, err := store.TenantManager.Create(ctx, tenant)
+	if err != nil {
+		AssertFatal(t, err, nil, "create should return no database errors")
+	}
+
+	return got
+}
+
+func TestTenantManager_Create(t *testing.T) {
+	store, ctx, teardown := setup(t)
+	defer teardown()
+
+	expected := expectedTenant()
+	got := createTenant(ctx, t, store, expected)
+	if got.Status != storage.TenantStatusActive {
+		AssertError(t, got.Status, storage.TenantStatusActive, "create should default the tenant to active")
+	}
+	if got.CreateTime == 0 {
+		AssertError(t, got.CreateTime, "now", "create should set the create time")
+	}
+
+	stored, err := store.TenantManager.Get(ctx, expected.ID)
+	if err != nil {
+		AssertFatal(t, err, nil, "get should return no database errors")
+	}
+	if !reflect.DeepEqual(stored, got) {
+		AssertError(t, stored, got, "tenant not equal")
+	}
+}
+
+func TestTenantManager_Create_ShouldGenerateID(t *testing.T) {
+	store, ctx, teardown := setup(t)
+	defer teardown()
+
+	tenant := expectedTenant()
+	tenant.ID = ""
+	got := createTenant(ctx, t, store, tenant)
+	if got.ID == "" {
+		AssertFatal(t, got.ID, "a generated id", "create should generate an id")
+	}
+
+	if _, err := store.TenantManager.Get(ctx, got.ID); err != nil {
+		AssertError(t, err, nil, "get should find the tenant by its generated id")
+	}
+}
+
+func TestTenantManager_Create_ShouldConflict(t *testing.T) {
+	store, ctx, teardown := setup(t)
+	defer teardown()
+
+	expected := createTenant(ctx, t, store, expectedTenant())
+
+	_, err := store.TenantManager.Create(ctx, expected)
+	if !errors.Is(err, storage.ErrResourceExists) {
+		AssertError(t, err, storage.ErrResourceExists, "create should return conflict")
+	}
+}
+
+func TestTenantManager_Create_ShouldValidate(t *testing.T) {
+	store, ctx, teardown := setup(t)
+	defer teardown()
+
+	tenant := expectedTenant()
+	tenant.Name = " "
+	_, err := store.TenantManager.Create(ctx, tenant)
+	if !errors.Is(err, storage.ErrInvalidArgument) {
+		AssertError(t, err, storage.ErrInvalidArgument, "create should reject a tenant without a name")
+	}
+
+	_, err = store.TenantManager.Get(ctx, tenant.ID)
+	if !errors.Is(err, storage.ErrNotFound) {
+		AssertError(t, err, storage.ErrNotFound, "invalid tenant should not be stored")
+	}
+}
+
+func TestTenantManager_Get_ShouldReturnNotFound(t *testing.T) {
+	store, ctx, teardown := setup(t)
+	defer teardown()
+
+	_, err := store.TenantManager.Get(ctx, uuid.NewString())
+	if !errors.Is(err, storage.ErrNotFound) {
+		AssertError(t, err, storage.ErrNotFound, "get should return not found")
+	}
+}
+
+func TestTenantManager_List(t *testing.T) {
+	store, ctx, teardown := setup(t)
+	defer teardown()
+
+	active := createTenant(ctx, t, store, expectedTenant())
+
+	suspended := expectedTenant()
+	suspended.Name = "Dogs Inc."
+	suspended.Status = storage.TenantStatusSuspended
+	suspended.AllowedRegions = []string{"au"}
+	suspended = createTenant(ctx, t, store, suspended)
+
+	tests := []struct {
+		name     string
+		filter   storage.ListTenantsRequest
+		expected []storage.Tenant
+	}{
+		{
+			name:     "should list all tenants",
+			filter:   storage.ListTenantsRequest{},
+			expected: []storage.Tenant{active, suspended},
+		},
+		{
+			name:     "should filter tenants by id",
+			filter:   storage.ListTenantsRequest{IDs: []string{suspended.ID, uuid.NewString()}},
+			expected: []storage.Tenant{suspended},
+		},
+		{
+			name:     "should filter tenants by status",
+			filter:   storage.ListTenantsRequest{Status: storage.TenantStatusActive},
+			expected: []storage.Tenant{active},
+		},
+		{
+			name:     "should filter tenants by region",
+			filter:   storage.ListTenantsRequest{AllowedRegion: "au"},
+			expected: []storage.Tenant{suspended},
+		},
+		{
+			name:     "should return empty if no tenants match",
+			filter:   storage.ListTenantsRequest{Name: "Birds Inc."},
+			expected: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := store.TenantManager.List(ctx, tt.filter)
+			if err != nil {
+				AssertFatal(t, err, nil, "list should return no database errors")
+			}
+			if !reflect.DeepEqual(got, tt.expected) {
+				AssertError(t, got, tt.expected, "tenants not equal")
+			}
+		})
+	}
+}
+
+func TestTenantManager_Update(t *testing.T) {
+	store, ctx, teardown := setup(t)
+	defer teardown()
+
+	expected := createTenant(ctx, t, store, expectedTenant())
+
+	update := expected
+	update.ID = uuid.NewString()
+	update.CreateTime = 0
+	update.Status = storage.TenantStatusSuspended
+	got, err := store.TenantManager.Update(ctx, expected.ID, update)
+	if err != nil {
+		AssertFatal(t, err, nil, "update should return no database errors")
+	}
+	if got.ID != expected.ID {
+		AssertError(t, got.ID, expected.ID, "update should not change the id")
+	}
+	if got.CreateTime != expected.CreateTime {
+		AssertError(t, got.CreateTime, expected.CreateTime, "update should retain the create time")
+	}
+	if got.UpdateTime == 0 {
+		AssertError(t, got.UpdateTime, "now", "update should set the update time")
+	}
+
+	stored, err := store.TenantManager.Get(ctx, expected.ID)
+	if err != nil {
+		AssertFatal(t, err, nil, "get should return no database errors")
+	}
+	if stored.IsActive() {
+		AssertError(t, stored.Status, storage.TenantStatusSuspended, "tenant should be suspended")
+	}
+	if !reflect.DeepEqual(stored, got) {
+		AssertError(t, stored, got, "tenant not equal")
+	}
+}
+
+func TestTenantManager_Update_ShouldValidate(t *testing.T) {
+	store, ctx, teardown := setup(t)
+	defer teardown()
+
+	expected := createTenant(ctx, t, store, expectedTenant())
+
+	update := expected
+	update.Status = "deleted"
+	_, err := store.TenantManager.Update(ctx, expected.ID, update)
+	if !errors.Is(err, storage.ErrInvalidArgument) {
+		AssertError(t, err, storage.ErrInvalidArgument, "update should reject an unknown status")
+	}
+
+	stored, err := store.TenantManager.Get(ctx, expected.ID)
+	if err != nil {
+		AssertFatal(t, err, nil, "get should return no database errors")
+	}
+	if !stored.IsActive() {
+		AssertError(t, stored.Status, storage.TenantStatusActive, "invalid update should not be stored")
+	}
+}
+
+func TestTenantManager_Update_ShouldReturnNotFound(t *testing.T) {
+	store, ctx, teardown := setup(t)
+	defer teardown()
+
+	_, err := store.TenantManager.Update(ctx, uuid.NewString(), expectedTenant())
+	if !errors.Is(err, storage.ErrNotFound) {
+		AssertError(t, err, storage.ErrNotFound, "update should return not found")
+	}
+}
+
+func TestTenantManager_Delete(t *testing.T) {
+	store, ctx, teardown := setup(t)
+	defer teardown()
+
+	expected := createTenant(ctx, t, store, expectedTenant())
+
+	err := store.TenantManager.Delete(ctx, expected.ID)
+	if err != nil {
+		AssertFatal(t, err, nil, "delete should return no database errors")
+	}
+
+	_, err = store.TenantManager.Get(ctx, expected.ID)
+	if !errors.Is(err, storage.ErrNotFound) {
+		AssertError(t, err, storage.ErrNotFound, "deleted tenant should not be found")
+	}
+
+	err = store.TenantManager.Delete(ctx, expected.ID)
+	if !errors.Is(err, storage.ErrNotFound) {
+		AssertError(t, err, storage.ErrNotFound, "deleting a deleted tenant should return not found")
+	}
+}
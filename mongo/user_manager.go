@@ -3,12 +3,12 @@ package mongo
 import (
 	// Standard Library Imports
 	"context"
+	"errors"
 	"time"
 
 	// External Imports
 	"github.com/google/uuid"
 	"github.com/ory/fosite"
-	"github.com/sirupsen/logrus"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
@@ -27,11 +27,55 @@ import (
 type UserManager struct {
 	DB     *DB
 	Hasher fosite.Hasher
+	Logger Logger
+
+	// Tenants, if set, validates the tenants referenced by the user's
+	// AllowedTenantAccess exist on create and update.
+	Tenants storage.TenantStorer
+
+	// Scopes, if set, enables strict scopes, validating the scopes granted
+	// to the user are registered on create, update and GrantScopes.
+	Scopes storage.ScopeStorer
+
+	// Groups, if set, extends filtering users by AllowedTenantAccess to the
+	// members of groups granting access to the tenant.
+	Groups storage.GroupStorer
+
+	// HashWorkers provides the number of workers to hash passwords with in
+	// bulk operations. If zero, a worker is started per CPU.
+	HashWorkers int
+}
+
+// usernameCollation compares usernames case insensitively, so that usernames
+// stored before they were normalized still conflict with, and are found by,
+// their normalized form.
+var usernameCollation = &options.Collation{
+	Locale:   "en",
+	Strength: 2,
+}
+
+// usernameIndex provides the unique, case insensitive, username index. The
+// index is created on Configure, unless stored usernames only differ by case,
+// in which case it is created by the normalizeUsernames migration once the
+// conflicting usernames have been resolved.
+var usernameIndex = mongo.IndexModel{
+	Keys: bson.D{
+		{
+			Key:   "username",
+			Value: int32(1),
+		},
+	},
+	Options: options.Index().
+		SetName(IdxUsernameCaseInsensitive).
+		SetBackground(true).
+		SetSparse(true).
+		SetUnique(true).
+		SetCollation(usernameCollation),
 }
 
 // Configure implements storage.Configurer.
 func (u *UserManager) Configure(ctx context.Context) (err error) {
-	log := logger.WithFields(logrus.Fields{
+	log := newLogger(ctx, u.Logger, Fields{
 		"package":    "mongo",
 		"collection": storage.EntityUsers,
 		"method":     "Configure",
@@ -54,23 +98,67 @@ func (u *UserManager) Configure(ctx context.Context) (err error) {
 		{
 			Keys: bson.D{
 				{
-					Key:   "username",
+					Key:   "email",
 					Value: int32(1),
 				},
 			},
 			Options: options.Index().
-				SetName(IdxUsername).
+				SetName(IdxEmail).
 				SetBackground(true).
 				SetSparse(true).
 				SetUnique(true),
 		},
+		{
+			Keys: bson.D{
+				{Key: "username", Value: "text"},
+				{Key: "firstName", Value: "text"},
+				{Key: "lastName", Value: "text"},
+				{Key: "personId", Value: "text"},
+			},
+			Options: options.Index().
+				SetName(IdxUserSearch).
+				SetBackground(true).
+				SetDefaultLanguage("none").
+				SetWeights(bson.M{
+					"username":  10,
+					"firstName": 5,
+					"lastName":  5,
+					"personId":  1,
+				}),
+		},
+		{
+			Keys: bson.D{
+				{
+					Key:   "groups",
+					Value: int32(1),
+				},
+			},
+			Options: options.Index().
+				SetName(IdxGroups).
+				SetBackground(true).
+				SetSparse(true),
+		},
 	}
 
-	collection := u.DB.Collection(storage.EntityUsers)
-	_, err = collection.Indexes().CreateMany(ctx, indices)
+	err = u.DB.createIndexes(ctx, storage.EntityUsers, indices)
 	if err != nil {
 		log.WithError(err).Error(logError)
-		return err
+		return toStorageError(storage.EntityUsers, err)
+	}
+
+	err = u.DB.createIndexes(ctx, storage.EntityUsers, []mongo.IndexModel{usernameIndex})
+	if err != nil {
+		if !hasErrorCode(err, errCodeDuplicate) {
+			log.WithError(err).Error(logError)
+			return toStorageError(storage.EntityUsers, err)
+		}
+
+		// Usernames that only differ by case must be resolved before the
+		// index can be created, which the normalizeUsernames migration
+		// reports. The index is expected regardless, so that the health
+		// check reports it missing in the meantime.
+		log.WithError(err).Warn("usernames conflict regardless of case, resolve them to create the username index")
+		u.DB.indexes.record(storage.EntityUsers, []mongo.IndexModel{usernameIndex})
 	}
 
 	return nil
@@ -78,7 +166,7 @@ func (u *UserManager) Configure(ctx context.Context) (err error) {
 
 // getConcrete returns an OAuth 2.0 User resource.
 func (u *UserManager) getConcrete(ctx context.Context, userID string) (result storage.User, err error) {
-	log := logger.WithFields(logrus.Fields{
+	log := newLogger(ctx, u.Logger, Fields{
 		"package":    "mongo",
 		"collection": storage.EntityUsers,
 		"method":     "getConcrete",
@@ -91,10 +179,12 @@ func (u *UserManager) getConcrete(ctx context.Context, userID string) (result st
 	}
 
 	// Trace how long the Mongo operation takes to complete.
-	span, _ := traceMongoCall(ctx, dbTrace{
-		Manager: "UserManager",
-		Method:  "getConcrete",
-		Query:   query,
+	span, ctx := u.DB.traceMongoCall(ctx, DBTrace{
+		Manager:    "UserManager",
+		Method:     "getConcrete",
+		Collection: storage.EntityUsers,
+		Operation:  "find",
+		Query:      query,
 	})
 	defer span.Finish()
 
@@ -104,14 +194,14 @@ func (u *UserManager) getConcrete(ctx context.Context, userID string) (result st
 	if err != nil {
 		if err == mongo.ErrNoDocuments {
 			log.WithError(err).Debug(logNotFound)
-			return result, fosite.ErrNotFound
+			return result, storage.NewNotFoundError(storage.EntityUsers)
 		}
 
 		// Log to StdOut
 		log.WithError(err).Error(logError)
-		// Log to OpenTracing
-		otLogErr(span, err)
-		return result, err
+		// Log to Tracer
+		span.RecordError(err)
+		return result, toStorageError(storage.EntityUsers, err)
 	}
 
 	return user, nil
@@ -120,16 +210,163 @@ func (u *UserManager) getConcrete(ctx context.Context, userID string) (result st
 // List returns a list of User resources that match the provided inputs.
 func (u *UserManager) List(ctx context.Context, filter storage.ListUsersRequest) (results []storage.User, err error) {
 	// Initialize contextual method logger
-	log := logger.WithFields(logrus.Fields{
+	log := newLogger(ctx, u.Logger, Fields{
 		"package":    "mongo",
 		"collection": storage.EntityUsers,
 		"method":     "List",
 	})
 
+	tenantGroups, err := u.tenantGroups(ctx, filter.AllowedTenantAccess)
+	if err != nil {
+		log.WithError(err).Error(logError)
+		return results, err
+	}
+
 	// Build Query
+	query := listUsersQuery(filter, tenantGroups)
+
+	// Trace how long the Mongo operation takes to complete.
+	span, ctx := u.DB.traceMongoCall(ctx, DBTrace{
+		Manager:    "UserManager",
+		Method:     "List",
+		Collection: storage.EntityUsers,
+		Operation:  "find",
+		Query:      query,
+	})
+	defer span.Finish()
+
+	collection := u.DB.Collection(storage.EntityUsers)
+	cursor, err := collection.Find(ctx, query)
+	if err != nil {
+		// Log to StdOut
+		log.WithError(err).Error(logError)
+		// Log to Tracer
+		span.RecordError(err)
+		return results, toStorageError(storage.EntityUsers, err)
+	}
+
+	var users []storage.User
+	err = cursor.All(ctx, &users)
+	if err != nil {
+		// Log to StdOut
+		log.WithError(err).Error(logError)
+		// Log to Tracer
+		span.RecordError(err)
+		return results, toStorageError(storage.EntityUsers, err)
+	}
+
+	return users, nil
+}
+
+// Each calls fn with each user matching the filter, decoding users from the
+// cursor one at a time rather than reading them all into memory. Each stops
+// at, and returns, the first error returned by fn.
+func (u *UserManager) Each(ctx context.Context, filter storage.ListUsersRequest, fn func(user storage.User) error) (err error) {
+	// Initialize contextual method logger
+	log := newLogger(ctx, u.Logger, Fields{
+		"package":    "mongo",
+		"collection": storage.EntityUsers,
+		"method":     "Each",
+	})
+
+	tenantGroups, err := u.tenantGroups(ctx, filter.AllowedTenantAccess)
+	if err != nil {
+		log.WithError(err).Error(logError)
+		return err
+	}
+
+	// Build Query
+	query := listUsersQuery(filter, tenantGroups)
+
+	// Trace how long the Mongo operation takes to complete.
+	span, ctx := u.DB.traceMongoCall(ctx, DBTrace{
+		Manager:    "UserManager",
+		Method:     "Each",
+		Collection: storage.EntityUsers,
+		Operation:  "find",
+		Query:      query,
+	})
+	defer span.Finish()
+
+	collection := u.DB.Collection(storage.EntityUsers)
+	cursor, err := collection.Find(ctx, query)
+	if err != nil {
+		// Log to StdOut
+		log.WithError(err).Error(logError)
+		// Log to Tracer
+		span.RecordError(err)
+		return toStorageError(storage.EntityUsers, err)
+	}
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		var user storage.User
+		if err := cursor.Decode(&user); err != nil {
+			// Log to StdOut
+			log.WithError(err).Error(logError)
+			// Log to Tracer
+			span.RecordError(err)
+			return toStorageError(storage.EntityUsers, err)
+		}
+
+		if err := fn(user); err != nil {
+			return err
+		}
+	}
+	if err := cursor.Err(); err != nil {
+		// Log to StdOut
+		log.WithError(err).Error(logError)
+		// Log to Tracer
+		span.RecordError(err)
+		return toStorageError(storage.EntityUsers, err)
+	}
+
+	return nil
+}
+
+// tenantGroups returns the IDs of the groups granting access to the tenant,
+// if groups are set.
+func (u *UserManager) tenantGroups(ctx context.Context, tenantID string) ([]string, error) {
+	if u.Groups == nil || tenantID == "" {
+		return nil, nil
+	}
+
+	groups, err := u.Groups.List(ctx, storage.ListGroupsRequest{AllowedTenantAccess: tenantID})
+	if err != nil {
+		return nil, err
+	}
+
+	groupIDs := make([]string, len(groups))
+	for i := range groups {
+		groupIDs[i] = groups[i].ID
+	}
+
+	return groupIDs, nil
+}
+
+// tenantAccessQuery returns the query matching the users with access to the
+// tenant, either directly, or as a member of one of the groups granting
+// access to the tenant.
+func tenantAccessQuery(tenantID string, tenantGroups []string) bson.M {
+	if len(tenantGroups) == 0 {
+		return bson.M{"allowedTenantAccess": tenantID}
+	}
+
+	return bson.M{
+		"$or": []bson.M{
+			{"allowedTenantAccess": tenantID},
+			{"groups": bson.M{"$in": tenantGroups}},
+		},
+	}
+}
+
+// listUsersQuery returns the query matching the users that match the provided
+// inputs. tenantGroups contains the groups granting access to the filtered
+// tenant.
+func listUsersQuery(filter storage.ListUsersRequest, tenantGroups []string) bson.M {
 	query := bson.M{}
 	if filter.AllowedTenantAccess != "" {
-		query["allowedTenantAccess"] = filter.AllowedTenantAccess
+		query = tenantAccessQuery(filter.AllowedTenantAccess, tenantGroups)
 	}
 	if filter.AllowedPersonAccess != "" {
 		query["allowedPersonAccess"] = filter.AllowedPersonAccess
@@ -137,8 +374,14 @@ func (u *UserManager) List(ctx context.Context, filter storage.ListUsersRequest)
 	if filter.PersonID != "" {
 		query["personId"] = filter.PersonID
 	}
+	if filter.Group != "" {
+		query["groups"] = filter.Group
+	}
 	if filter.Username != "" {
-		query["username"] = filter.Username
+		query["username"] = storage.NormalizeUsername(filter.Username)
+	}
+	if filter.Email != "" {
+		query["email"] = storage.NormalizeEmail(filter.Email)
 	}
 	if len(filter.ScopesIntersection) > 0 {
 		query["scopes"] = bson.M{"$all": filter.ScopesIntersection}
@@ -156,42 +399,14 @@ func (u *UserManager) List(ctx context.Context, filter storage.ListUsersRequest)
 		query["disabled"] = filter.Disabled
 	}
 
-	// Trace how long the Mongo operation takes to complete.
-	span, _ := traceMongoCall(ctx, dbTrace{
-		Manager: "UserManager",
-		Method:  "List",
-		Query:   query,
-	})
-	defer span.Finish()
-
-	collection := u.DB.Collection(storage.EntityUsers)
-	cursor, err := collection.Find(ctx, query)
-	if err != nil {
-		// Log to StdOut
-		log.WithError(err).Error(logError)
-		// Log to OpenTracing
-		otLogErr(span, err)
-		return results, err
-	}
-
-	var users []storage.User
-	err = cursor.All(ctx, &users)
-	if err != nil {
-		// Log to StdOut
-		log.WithError(err).Error(logError)
-		// Log to OpenTracing
-		otLogErr(span, err)
-		return results, err
-	}
-
-	return users, nil
+	return query
 }
 
 // Create creates a new User resource and returns the newly created User
 // resource.
 func (u *UserManager) Create(ctx context.Context, user storage.User) (result storage.User, err error) {
 	// Initialize contextual method logger
-	log := logger.WithFields(logrus.Fields{
+	log := newLogger(ctx, u.Logger, Fields{
 		"package":    "mongo",
 		"collection": storage.EntityUsers,
 		"method":     "Create",
@@ -204,6 +419,20 @@ func (u *UserManager) Create(ctx context.Context, user storage.User) (result sto
 	if user.CreateTime == 0 {
 		user.CreateTime = time.Now().Unix()
 	}
+	user.Username = storage.NormalizeUsername(user.Username)
+	user.Email = storage.NormalizeEmail(user.Email)
+
+	err = storage.ValidateTenantReferences(ctx, u.Tenants, storage.EntityUsers, user.AllowedTenantAccess)
+	if err != nil {
+		log.WithError(err).Debug(logInvalid)
+		return result, err
+	}
+
+	err = storage.ValidateScopes(ctx, u.Scopes, storage.EntityUsers, user.Scopes)
+	if err != nil {
+		log.WithError(err).Debug(logInvalid)
+		return result, err
+	}
 
 	// Hash incoming secret
 	hash, err := u.Hasher.Hash(ctx, []byte(user.Password))
@@ -214,9 +443,11 @@ func (u *UserManager) Create(ctx context.Context, user storage.User) (result sto
 	user.Password = string(hash)
 
 	// Trace how long the Mongo operation takes to complete.
-	span, _ := traceMongoCall(ctx, dbTrace{
-		Manager: "UserManager",
-		Method:  "Create",
+	span, ctx := u.DB.traceMongoCall(ctx, DBTrace{
+		Manager:    "UserManager",
+		Method:     "Create",
+		Collection: storage.EntityUsers,
+		Operation:  "insert",
 	})
 	defer span.Finish()
 
@@ -227,18 +458,18 @@ func (u *UserManager) Create(ctx context.Context, user storage.User) (result sto
 		if isDup(err) {
 			// Log to StdOut
 			log.WithError(err).Debug(logConflict)
-			// Log to OpenTracing
-			otLogErr(span, err)
-			return result, storage.ErrResourceExists
+			// Log to Tracer
+			span.RecordError(err)
+			return result, toStorageError(storage.EntityUsers, err)
 		}
 
 		// Log to StdOut
 		log.WithError(err).Error(logError)
-		// Log to OpenTracing
+		// Log to Tracer
 		user.Password = "REDACTED"
-		otLogQuery(span, user)
-		otLogErr(span, err)
-		return result, err
+		span.SetQuery(user)
+		span.RecordError(err)
+		return result, toStorageError(storage.EntityUsers, err)
 	}
 
 	return user, nil
@@ -251,7 +482,7 @@ func (u *UserManager) Get(ctx context.Context, userID string) (result storage.Us
 
 // GetByUsername returns a user resource if found by username.
 func (u *UserManager) GetByUsername(ctx context.Context, username string) (result storage.User, err error) {
-	log := logger.WithFields(logrus.Fields{
+	log := newLogger(ctx, u.Logger, Fields{
 		"package":    "mongo",
 		"collection": storage.EntityUsers,
 		"method":     "GetByUsername",
@@ -259,14 +490,59 @@ func (u *UserManager) GetByUsername(ctx context.Context, username string) (resul
 
 	// Build Query
 	query := bson.M{
-		"username": username,
+		"username": storage.NormalizeUsername(username),
 	}
 
 	// Trace how long the Mongo operation takes to complete.
-	span, _ := traceMongoCall(ctx, dbTrace{
-		Manager: "UserManager",
-		Method:  "getConcrete",
-		Query:   query,
+	span, ctx := u.DB.traceMongoCall(ctx, DBTrace{
+		Manager:    "UserManager",
+		Method:     "GetByUsername",
+		Collection: storage.EntityUsers,
+		Operation:  "find",
+		Query:      query,
+	})
+	defer span.Finish()
+
+	var user storage.User
+	collection := u.DB.Collection(storage.EntityUsers)
+	opts := options.FindOne().SetCollation(usernameCollation)
+	err = collection.FindOne(ctx, query, opts).Decode(&user)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			log.WithError(err).Debug(logNotFound)
+			return result, storage.NewNotFoundError(storage.EntityUsers)
+		}
+
+		// Log to StdOut
+		log.WithError(err).Error(logError)
+		// Log to Tracer
+		span.RecordError(err)
+		return result, toStorageError(storage.EntityUsers, err)
+	}
+
+	return user, nil
+}
+
+// GetByEmail returns a user resource if found by email address.
+func (u *UserManager) GetByEmail(ctx context.Context, email string) (result storage.User, err error) {
+	log := newLogger(ctx, u.Logger, Fields{
+		"package":    "mongo",
+		"collection": storage.EntityUsers,
+		"method":     "GetByEmail",
+	})
+
+	// Build Query
+	query := bson.M{
+		"email": storage.NormalizeEmail(email),
+	}
+
+	// Trace how long the Mongo operation takes to complete.
+	span, ctx := u.DB.traceMongoCall(ctx, DBTrace{
+		Manager:    "UserManager",
+		Method:     "GetByEmail",
+		Collection: storage.EntityUsers,
+		Operation:  "find",
+		Query:      query,
 	})
 	defer span.Finish()
 
@@ -276,14 +552,14 @@ func (u *UserManager) GetByUsername(ctx context.Context, username string) (resul
 	if err != nil {
 		if err == mongo.ErrNoDocuments {
 			log.WithError(err).Debug(logNotFound)
-			return result, fosite.ErrNotFound
+			return result, storage.NewNotFoundError(storage.EntityUsers)
 		}
 
 		// Log to StdOut
 		log.WithError(err).Error(logError)
-		// Log to OpenTracing
-		otLogErr(span, err)
-		return result, err
+		// Log to Tracer
+		span.RecordError(err)
+		return result, toStorageError(storage.EntityUsers, err)
 	}
 
 	return user, nil
@@ -293,7 +569,7 @@ func (u *UserManager) GetByUsername(ctx context.Context, username string) (resul
 // User resource.
 func (u *UserManager) Update(ctx context.Context, userID string, updatedUser storage.User) (result storage.User, err error) {
 	// Initialize contextual method logger
-	log := logger.WithFields(logrus.Fields{
+	log := newLogger(ctx, u.Logger, Fields{
 		"package":    "mongo",
 		"collection": storage.EntityUsers,
 		"method":     "Update",
@@ -314,7 +590,7 @@ func (u *UserManager) Update(ctx context.Context, userID string, updatedUser sto
 
 	currentResource, err := u.getConcrete(ctx, userID)
 	if err != nil {
-		if err == fosite.ErrNotFound {
+		if errors.Is(err, storage.ErrNotFound) {
 			log.Debug(logNotFound)
 			return result, err
 		}
@@ -327,6 +603,30 @@ func (u *UserManager) Update(ctx context.Context, userID string, updatedUser sto
 	updatedUser.ID = userID
 	// Update modified time
 	updatedUser.UpdateTime = time.Now().Unix()
+	updatedUser.Username = storage.NormalizeUsername(updatedUser.Username)
+	updatedUser.Email = storage.NormalizeEmail(updatedUser.Email)
+
+	// Changing the email address requires it to be verified again.
+	if updatedUser.Email != currentResource.Email {
+		updatedUser.EmailVerified = false
+	}
+
+	// Only validate newly referenced tenants, so that clients and users
+	// referencing tenants created before tenants were tracked can be updated.
+	addedTenants := difference(updatedUser.AllowedTenantAccess, currentResource.AllowedTenantAccess)
+	err = storage.ValidateTenantReferences(ctx, u.Tenants, storage.EntityUsers, addedTenants)
+	if err != nil {
+		log.WithError(err).Debug(logInvalid)
+		return result, err
+	}
+
+	// Likewise, only validate newly granted scopes.
+	addedScopes := difference(updatedUser.Scopes, currentResource.Scopes)
+	err = storage.ValidateScopes(ctx, u.Scopes, storage.EntityUsers, addedScopes)
+	if err != nil {
+		log.WithError(err).Debug(logInvalid)
+		return result, err
+	}
 
 	if currentResource.Password == updatedUser.Password || updatedUser.Password == "" {
 		// If the password/hash is blank or hash matches, set using old hash.
@@ -346,10 +646,12 @@ func (u *UserManager) Update(ctx context.Context, userID string, updatedUser sto
 	}
 
 	// Trace how long the Mongo operation takes to complete.
-	span, _ := traceMongoCall(ctx, dbTrace{
-		Manager:  "UserManager",
-		Method:   "Update",
-		Selector: selector,
+	span, ctx := u.DB.traceMongoCall(ctx, DBTrace{
+		Manager:    "UserManager",
+		Method:     "Update",
+		Collection: storage.EntityUsers,
+		Operation:  "update",
+		Selector:   selector,
 	})
 	defer span.Finish()
 
@@ -359,25 +661,25 @@ func (u *UserManager) Update(ctx context.Context, userID string, updatedUser sto
 		if isDup(err) {
 			// Log to StdOut
 			log.WithError(err).Debug(logConflict)
-			// Log to OpenTracing
-			otLogErr(span, err)
-			return result, storage.ErrResourceExists
+			// Log to Tracer
+			span.RecordError(err)
+			return result, toStorageError(storage.EntityUsers, err)
 		}
 
 		// Log to StdOut
 		log.WithError(err).Error(logError)
-		// Log to OpenTracing
-		otLogQuery(span, updatedUser)
-		otLogErr(span, err)
-		return result, err
+		// Log to Tracer
+		span.SetQuery(updatedUser)
+		span.RecordError(err)
+		return result, toStorageError(storage.EntityUsers, err)
 	}
 
 	if res.MatchedCount == 0 {
 		// Log to StdOut
 		log.WithError(err).Debug(logNotFound)
-		// Log to OpenTracing
-		otLogErr(span, err)
-		return result, fosite.ErrNotFound
+		// Log to Tracer
+		span.RecordError(err)
+		return result, storage.NewNotFoundError(storage.EntityUsers)
 	}
 
 	return updatedUser, nil
@@ -389,7 +691,7 @@ func (u *UserManager) Update(ctx context.Context, userID string, updatedUser sto
 // newly provided full record. Use with caution, be secure, don't be dumb.
 func (u *UserManager) Migrate(ctx context.Context, migratedUser storage.User) (result storage.User, err error) {
 	// Initialize contextual method logger
-	log := logger.WithFields(logrus.Fields{
+	log := newLogger(ctx, u.Logger, Fields{
 		"package":    "mongo",
 		"collection": storage.EntityUsers,
 		"method":     "Migrate",
@@ -405,6 +707,8 @@ func (u *UserManager) Migrate(ctx context.Context, migratedUser storage.User) (r
 	}
 	// Update modified time
 	migratedUser.UpdateTime = time.Now().Unix()
+	migratedUser.Username = storage.NormalizeUsername(migratedUser.Username)
+	migratedUser.Email = storage.NormalizeEmail(migratedUser.Email)
 
 	// Build Query
 	selector := bson.M{
@@ -412,10 +716,12 @@ func (u *UserManager) Migrate(ctx context.Context, migratedUser storage.User) (r
 	}
 
 	// Trace how long the Mongo operation takes to complete.
-	span, _ := traceMongoCall(ctx, dbTrace{
-		Manager:  "UserManager",
-		Method:   "Migrate",
-		Selector: selector,
+	span, ctx := u.DB.traceMongoCall(ctx, DBTrace{
+		Manager:    "UserManager",
+		Method:     "Migrate",
+		Collection: storage.EntityUsers,
+		Operation:  "update",
+		Selector:   selector,
 	})
 	defer span.Finish()
 
@@ -426,17 +732,17 @@ func (u *UserManager) Migrate(ctx context.Context, migratedUser storage.User) (r
 		if isDup(err) {
 			// Log to StdOut
 			log.WithError(err).Debug(logConflict)
-			// Log to OpenTracing
-			otLogErr(span, err)
-			return result, storage.ErrResourceExists
+			// Log to Tracer
+			span.RecordError(err)
+			return result, toStorageError(storage.EntityUsers, err)
 		}
 
 		// Log to StdOut
 		log.WithError(err).Error(logError)
-		// Log to OpenTracing
-		otLogQuery(span, migratedUser)
-		otLogErr(span, err)
-		return result, err
+		// Log to Tracer
+		span.SetQuery(migratedUser)
+		span.RecordError(err)
+		return result, toStorageError(storage.EntityUsers, err)
 	}
 
 	return migratedUser, nil
@@ -445,7 +751,7 @@ func (u *UserManager) Migrate(ctx context.Context, migratedUser storage.User) (r
 // Delete deletes the specified User resource.
 func (u *UserManager) Delete(ctx context.Context, userID string) (err error) {
 	// Initialize contextual method logger
-	log := logger.WithFields(logrus.Fields{
+	log := newLogger(ctx, u.Logger, Fields{
 		"package":    "mongo",
 		"collection": storage.EntityUsers,
 		"method":     "Delete",
@@ -458,10 +764,12 @@ func (u *UserManager) Delete(ctx context.Context, userID string) (err error) {
 	}
 
 	// Trace how long the Mongo operation takes to complete.
-	span, _ := traceMongoCall(ctx, dbTrace{
-		Manager: "UserManager",
-		Method:  "Delete",
-		Query:   query,
+	span, ctx := u.DB.traceMongoCall(ctx, DBTrace{
+		Manager:    "UserManager",
+		Method:     "Delete",
+		Collection: storage.EntityUsers,
+		Operation:  "delete",
+		Query:      query,
 	})
 	defer span.Finish()
 
@@ -470,17 +778,17 @@ func (u *UserManager) Delete(ctx context.Context, userID string) (err error) {
 	if err != nil {
 		// Log to StdOut
 		log.WithError(err).Error(logError)
-		// Log to OpenTracing
-		otLogErr(span, err)
-		return err
+		// Log to Tracer
+		span.RecordError(err)
+		return toStorageError(storage.EntityUsers, err)
 	}
 
 	if res.DeletedCount == 0 {
 		// Log to StdOut
 		log.WithError(err).Debug(logNotFound)
-		// Log to OpenTracing
-		otLogErr(span, err)
-		return fosite.ErrNotFound
+		// Log to Tracer
+		span.RecordError(err)
+		return storage.NewNotFoundError(storage.EntityUsers)
 	}
 
 	return nil
@@ -498,16 +806,17 @@ func (u *UserManager) Authenticate(ctx context.Context, username string, passwor
 // The User resource returned is matched by User ID.
 func (u *UserManager) AuthenticateByID(ctx context.Context, userID string, password string) (result storage.User, err error) {
 	// Initialize contextual method logger
-	log := logger.WithFields(logrus.Fields{
+	log := newLogger(ctx, u.Logger, Fields{
 		"package":    "mongo",
 		"collection": storage.EntityUsers,
 		"method":     "AuthenticateByID",
 	})
 
 	// Trace how long the Mongo operation takes to complete.
-	span, ctx := traceMongoCall(ctx, dbTrace{
-		Manager: "UserManager",
-		Method:  "AuthenticateByID",
+	span, ctx := u.DB.traceMongoCall(ctx, DBTrace{
+		Manager:    "UserManager",
+		Method:     "AuthenticateByID",
+		Collection: storage.EntityUsers,
 	})
 	defer span.Finish()
 
@@ -519,13 +828,13 @@ func (u *UserManager) AuthenticateByID(ctx context.Context, userID string, passw
 
 	if user.Disabled {
 		log.Debug("disabled user denied access")
-		return result, fosite.ErrAccessDenied
+		return result, storage.NewError(storage.ErrAuthenticationFailed, storage.EntityUsers, fosite.ErrAccessDenied)
 	}
 
 	err = u.Hasher.Compare(ctx, []byte(user.Password), []byte(password))
 	if err != nil {
 		log.WithError(err).Warn("failed to authenticate user password")
-		return result, err
+		return result, storage.NewAuthenticationFailedError(storage.EntityUsers, err)
 	}
 
 	return user, nil
@@ -536,16 +845,17 @@ func (u *UserManager) AuthenticateByID(ctx context.Context, userID string, passw
 // The User resource returned is matched by username.
 func (u *UserManager) AuthenticateByUsername(ctx context.Context, username string, password string) (result storage.User, err error) {
 	// Initialize contextual method logger
-	log := logger.WithFields(logrus.Fields{
+	log := newLogger(ctx, u.Logger, Fields{
 		"package":    "mongo",
 		"collection": storage.EntityUsers,
 		"method":     "AuthenticateByUsername",
 	})
 
 	// Trace how long the Mongo operation takes to complete.
-	span, ctx := traceMongoCall(ctx, dbTrace{
-		Manager: "UserManager",
-		Method:  "AuthenticateByUsername",
+	span, ctx := u.DB.traceMongoCall(ctx, DBTrace{
+		Manager:    "UserManager",
+		Method:     "AuthenticateByUsername",
+		Collection: storage.EntityUsers,
 	})
 	defer span.Finish()
 
@@ -557,15 +867,54 @@ func (u *UserManager) AuthenticateByUsername(ctx context.Context, username strin
 
 	if user.Disabled {
 		log.Debug("disabled user denied access")
-		return result, fosite.ErrAccessDenied
+		return result, storage.NewError(storage.ErrAuthenticationFailed, storage.EntityUsers, fosite.ErrAccessDenied)
 	}
 
 	err = u.Hasher.Compare(ctx, []byte(user.Password), []byte(password))
 	if err != nil {
 		log.WithError(err).Warn("failed to authenticate user password")
+		return result, storage.NewAuthenticationFailedError(storage.EntityUsers, err)
+	}
+
+	return user, nil
+}
+
+// AuthenticateByEmail confirms whether the specified password matches the
+// stored hashed password within the User resource.
+// The User resource returned is matched by email address.
+func (u *UserManager) AuthenticateByEmail(ctx context.Context, email string, password string) (result storage.User, err error) {
+	// Initialize contextual method logger
+	log := newLogger(ctx, u.Logger, Fields{
+		"package":    "mongo",
+		"collection": storage.EntityUsers,
+		"method":     "AuthenticateByEmail",
+	})
+
+	// Trace how long the Mongo operation takes to complete.
+	span, ctx := u.DB.traceMongoCall(ctx, DBTrace{
+		Manager:    "UserManager",
+		Method:     "AuthenticateByEmail",
+		Collection: storage.EntityUsers,
+	})
+	defer span.Finish()
+
+	user, err := u.GetByEmail(ctx, email)
+	if err != nil {
+		log.WithError(err).Warn(logError)
 		return result, err
 	}
 
+	if user.Disabled {
+		log.Debug("disabled user denied access")
+		return result, storage.NewError(storage.ErrAuthenticationFailed, storage.EntityUsers, fosite.ErrAccessDenied)
+	}
+
+	err = u.Hasher.Compare(ctx, []byte(user.Password), []byte(password))
+	if err != nil {
+		log.WithError(err).Warn("failed to authenticate user password")
+		return result, storage.NewAuthenticationFailedError(storage.EntityUsers, err)
+	}
+
 	return user, nil
 }
 
@@ -574,7 +923,7 @@ func (u *UserManager) AuthenticateByUsername(ctx context.Context, username strin
 // to the Hasher implemented within fosite.
 func (u *UserManager) AuthenticateMigration(ctx context.Context, currentAuth storage.AuthUserFunc, userID string, password string) (result storage.User, err error) {
 	// Initialize contextual method logger
-	log := logger.WithFields(logrus.Fields{
+	log := newLogger(ctx, u.Logger, Fields{
 		"package":    "mongo",
 		"collection": storage.EntityUsers,
 		"method":     "AuthenticateMigration",
@@ -594,9 +943,10 @@ func (u *UserManager) AuthenticateMigration(ctx context.Context, currentAuth sto
 	}
 
 	// Trace how long the Mongo operation takes to complete.
-	span, ctx := traceMongoCall(ctx, dbTrace{
-		Manager: "UserManager",
-		Method:  "AuthenticateMigration",
+	span, ctx := u.DB.traceMongoCall(ctx, DBTrace{
+		Manager:    "UserManager",
+		Method:     "AuthenticateMigration",
+		Collection: storage.EntityUsers,
 	})
 	defer span.Finish()
 
@@ -606,12 +956,12 @@ func (u *UserManager) AuthenticateMigration(ctx context.Context, currentAuth sto
 	// Check for user not found
 	if user.IsEmpty() && !authenticated {
 		log.Debug(logNotFound)
-		return result, fosite.ErrNotFound
+		return result, storage.NewNotFoundError(storage.EntityUsers)
 	}
 
 	if user.Disabled {
 		log.Debug("disabled user denied access")
-		return result, fosite.ErrAccessDenied
+		return result, storage.NewError(storage.ErrAuthenticationFailed, storage.EntityUsers, fosite.ErrAccessDenied)
 	}
 
 	if !authenticated {
@@ -619,7 +969,7 @@ func (u *UserManager) AuthenticateMigration(ctx context.Context, currentAuth sto
 		err := u.Hasher.Compare(ctx, user.GetHashedSecret(), []byte(password))
 		if err != nil {
 			log.WithError(err).Warn("failed to authenticate user password")
-			return result, err
+			return result, storage.NewAuthenticationFailedError(storage.EntityUsers, err)
 		}
 		return user, nil
 	}
@@ -642,7 +992,7 @@ func (u *UserManager) AuthenticateMigration(ctx context.Context, currentAuth sto
 // GrantScopes grants the provided scopes to the specified User resource.
 func (u *UserManager) GrantScopes(ctx context.Context, userID string, scopes []string) (result storage.User, err error) {
 	// Initialize contextual method logger
-	log := logger.WithFields(logrus.Fields{
+	log := newLogger(ctx, u.Logger, Fields{
 		"package":    "mongo",
 		"collection": storage.EntityUsers,
 		"method":     "GrantScopes",
@@ -662,15 +1012,16 @@ func (u *UserManager) GrantScopes(ctx context.Context, userID string, scopes []s
 	}
 
 	// Trace how long the Mongo operation takes to complete.
-	span, ctx := traceMongoCall(ctx, dbTrace{
-		Manager: "UserManager",
-		Method:  "GrantScopes",
+	span, ctx := u.DB.traceMongoCall(ctx, DBTrace{
+		Manager:    "UserManager",
+		Method:     "GrantScopes",
+		Collection: storage.EntityUsers,
 	})
 	defer span.Finish()
 
 	user, err := u.getConcrete(ctx, userID)
 	if err != nil {
-		if err == fosite.ErrNotFound {
+		if errors.Is(err, storage.ErrNotFound) {
 			log.Debug(logNotFound)
 			return result, err
 		}
@@ -689,7 +1040,7 @@ func (u *UserManager) GrantScopes(ctx context.Context, userID string, scopes []s
 // RemoveScopes revokes the provided scopes from the specified User Resource.
 func (u *UserManager) RemoveScopes(ctx context.Context, userID string, scopes []string) (result storage.User, err error) {
 	// Initialize contextual method logger
-	log := logger.WithFields(logrus.Fields{
+	log := newLogger(ctx, u.Logger, Fields{
 		"package":    "mongo",
 		"collection": storage.EntityUsers,
 		"method":     "RemoveScopes",
@@ -709,15 +1060,16 @@ func (u *UserManager) RemoveScopes(ctx context.Context, userID string, scopes []
 	}
 
 	// Trace how long the Mongo operation takes to complete.
-	span, ctx := traceMongoCall(ctx, dbTrace{
-		Manager: "UserManager",
-		Method:  "RemoveScopes",
+	span, ctx := u.DB.traceMongoCall(ctx, DBTrace{
+		Manager:    "UserManager",
+		Method:     "RemoveScopes",
+		Collection: storage.EntityUsers,
 	})
 	defer span.Finish()
 
 	user, err := u.getConcrete(ctx, userID)
 	if err != nil {
-		if err == fosite.ErrNotFound {
+		if errors.Is(err, storage.ErrNotFound) {
 			log.Debug(logNotFound)
 			return result, err
 		}
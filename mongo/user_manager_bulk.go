@@ -0,0 +1,359 @@
+package mongo
+
+import (
+	// Standard Library Imports
+	"context"
+	"time"
+
+	// External Imports
+	"github.com/google/uuid"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	// Internal Imports
+	"github.com/matthewhartstonge/storage"
+)
+
+// BulkCreate creates the users, returning a result per user in the order
+// given. Passwords are hashed in a bounded pool of workers and the users are
+// inserted with a single unordered bulk write, so that invalid, or
+// conflicting, users are reported individually rather than failing the
+// batch.
+func (u *UserManager) BulkCreate(ctx context.Context, users []storage.User) (results []storage.BulkResult, err error) {
+	// Work on a copy, so that the caller's users aren't modified.
+	users = append([]storage.User(nil), users...)
+
+	now := time.Now().Unix()
+	results = make([]storage.BulkResult, len(users))
+	tenants := make([][]string, len(users))
+	scopes := make([][]string, len(users))
+	for i := range users {
+		// Enable developers to provide their own IDs
+		if users[i].ID == "" {
+			users[i].ID = uuid.NewString()
+		}
+		if users[i].CreateTime == 0 {
+			users[i].CreateTime = now
+		}
+		users[i].Username = storage.NormalizeUsername(users[i].Username)
+		users[i].Email = storage.NormalizeEmail(users[i].Email)
+
+		results[i].ID = users[i].ID
+		tenants[i] = users[i].AllowedTenantAccess
+		scopes[i] = users[i].Scopes
+	}
+	u.validateBulk(ctx, results, tenants, scopes)
+
+	var jobs []hashJob
+	for i := range users {
+		if results[i].Err == nil {
+			jobs = append(jobs, hashJob{index: i, secret: users[i].Password})
+		}
+	}
+
+	models := make(map[int]mongo.WriteModel, len(jobs))
+	for _, hashed := range hashSecrets(ctx, u.Hasher, jobs, u.HashWorkers) {
+		if hashed.err != nil {
+			results[hashed.index].Err = hashed.err
+			continue
+		}
+
+		user := users[hashed.index]
+		user.Password = hashed.hash
+		models[hashed.index] = mongo.NewInsertOneModel().SetDocument(user)
+	}
+
+	err = u.bulkWrite(ctx, "BulkCreate", results, models)
+	if err != nil {
+		return nil, err
+	}
+
+	return results, nil
+}
+
+// BulkUpdate updates the users by ID, returning a result per user in the
+// order given. As with Update, a blank password retains the current password.
+func (u *UserManager) BulkUpdate(ctx context.Context, users []storage.User) (results []storage.BulkResult, err error) {
+	// Work on a copy, so that the caller's users aren't modified.
+	users = append([]storage.User(nil), users...)
+
+	ids := make([]string, len(users))
+	for i := range users {
+		ids[i] = users[i].ID
+	}
+
+	current, err := u.findByIDs(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().Unix()
+	results = make([]storage.BulkResult, len(users))
+	addedTenants := make([][]string, len(users))
+	addedScopes := make([][]string, len(users))
+	for i := range users {
+		results[i].ID = users[i].ID
+
+		currentResource, ok := current[users[i].ID]
+		if !ok {
+			results[i].Err = storage.NewNotFoundError(storage.EntityUsers)
+			continue
+		}
+
+		// Update modified time
+		users[i].UpdateTime = now
+		users[i].Username = storage.NormalizeUsername(users[i].Username)
+		users[i].Email = storage.NormalizeEmail(users[i].Email)
+
+		// Changing the email address requires it to be verified again.
+		if users[i].Email != currentResource.Email {
+			users[i].EmailVerified = false
+		}
+
+		// Only validate newly referenced tenants and scopes, as with Update.
+		addedTenants[i] = difference(users[i].AllowedTenantAccess, currentResource.AllowedTenantAccess)
+		addedScopes[i] = difference(users[i].Scopes, currentResource.Scopes)
+	}
+	u.validateBulk(ctx, results, addedTenants, addedScopes)
+
+	var jobs []hashJob
+	models := make(map[int]mongo.WriteModel, len(users))
+	for i := range users {
+		if results[i].Err != nil {
+			continue
+		}
+
+		currentResource := current[users[i].ID]
+		if currentResource.Password == users[i].Password || users[i].Password == "" {
+			// If the password/hash is blank or hash matches, set using old hash.
+			users[i].Password = currentResource.Password
+			models[i] = replaceByID(users[i].ID, users[i])
+			continue
+		}
+
+		jobs = append(jobs, hashJob{index: i, secret: users[i].Password})
+	}
+
+	for _, hashed := range hashSecrets(ctx, u.Hasher, jobs, u.HashWorkers) {
+		if hashed.err != nil {
+			results[hashed.index].Err = hashed.err
+			continue
+		}
+
+		user := users[hashed.index]
+		user.Password = hashed.hash
+		models[hashed.index] = replaceByID(user.ID, user)
+	}
+
+	err = u.bulkWrite(ctx, "BulkUpdate", results, models)
+	if err != nil {
+		return nil, err
+	}
+
+	return results, nil
+}
+
+// BulkDelete deletes the users by ID, returning a result per user in the
+// order given.
+func (u *UserManager) BulkDelete(ctx context.Context, userIDs []string) (results []storage.BulkResult, err error) {
+	// Initialize contextual method logger
+	log := newLogger(ctx, u.Logger, Fields{
+		"package":    "mongo",
+		"collection": storage.EntityUsers,
+		"method":     "BulkDelete",
+	})
+
+	// Trace how long the Mongo operation takes to complete.
+	span, ctx := u.DB.traceMongoCall(ctx, DBTrace{
+		Manager:    "UserManager",
+		Method:     "BulkDelete",
+		Collection: storage.EntityUsers,
+		Operation:  "bulkWrite",
+	})
+	defer span.Finish()
+
+	collection := u.DB.Collection(storage.EntityUsers)
+	results, err = bulkDelete(ctx, collection, storage.EntityUsers, userIDs)
+	if err != nil {
+		// Log to StdOut
+		log.WithError(err).Error(logError)
+		// Log to Tracer
+		span.RecordError(err)
+		return nil, err
+	}
+
+	return results, nil
+}
+
+// BulkGrantScopes grants the scopes to the users matching the filter and
+// returns the number of users modified.
+func (u *UserManager) BulkGrantScopes(ctx context.Context, filter storage.ListUsersRequest, scopes []string) (modified int64, err error) {
+	if err = filter.ValidateBulk(); err != nil {
+		return 0, err
+	}
+	if len(scopes) == 0 {
+		return 0, nil
+	}
+
+	err = storage.ValidateScopes(ctx, u.Scopes, storage.EntityUsers, scopes)
+	if err != nil {
+		return 0, err
+	}
+
+	tenantGroups, err := u.tenantGroups(ctx, filter.AllowedTenantAccess)
+	if err != nil {
+		return 0, err
+	}
+
+	// Only match the users missing a scope, so that the update time of
+	// users that already have the scopes is retained.
+	query := bson.M{
+		"$and": []bson.M{
+			listUsersQuery(filter, tenantGroups),
+			{"scopes": bson.M{"$not": bson.M{"$all": scopes}}},
+		},
+	}
+	update := bson.M{
+		"$addToSet": bson.M{"scopes": bson.M{"$each": scopes}},
+		"$set":      bson.M{"updateTime": time.Now().Unix()},
+	}
+
+	return u.updateMany(ctx, "BulkGrantScopes", query, update)
+}
+
+// BulkRemoveScopes removes the scopes from the users matching the filter
+// and returns the number of users modified.
+func (u *UserManager) BulkRemoveScopes(ctx context.Context, filter storage.ListUsersRequest, scopes []string) (modified int64, err error) {
+	if err = filter.ValidateBulk(); err != nil {
+		return 0, err
+	}
+	if len(scopes) == 0 {
+		return 0, nil
+	}
+
+	tenantGroups, err := u.tenantGroups(ctx, filter.AllowedTenantAccess)
+	if err != nil {
+		return 0, err
+	}
+
+	query := bson.M{
+		"$and": []bson.M{
+			listUsersQuery(filter, tenantGroups),
+			{"scopes": bson.M{"$in": scopes}},
+		},
+	}
+	update := bson.M{
+		"$pull": bson.M{"scopes": bson.M{"$in": scopes}},
+		"$set":  bson.M{"updateTime": time.Now().Unix()},
+	}
+
+	return u.updateMany(ctx, "BulkRemoveScopes", query, update)
+}
+
+// validateBulk validates the tenants and scopes referenced by each user,
+// recording a validation error as the user's result.
+func (u *UserManager) validateBulk(ctx context.Context, results []storage.BulkResult, tenants [][]string, scopes [][]string) {
+	tenantErrs := validateEach(tenants, func(tenantIDs []string) error {
+		return storage.ValidateTenantReferences(ctx, u.Tenants, storage.EntityUsers, tenantIDs)
+	})
+	scopeErrs := validateEach(scopes, func(scopeNames []string) error {
+		return storage.ValidateScopes(ctx, u.Scopes, storage.EntityUsers, scopeNames)
+	})
+
+	for i := range results {
+		if results[i].Err != nil {
+			continue
+		}
+		if tenantErrs[i] != nil {
+			results[i].Err = tenantErrs[i]
+			continue
+		}
+		results[i].Err = scopeErrs[i]
+	}
+}
+
+// findByIDs returns the users that exist out of the given IDs, by ID.
+func (u *UserManager) findByIDs(ctx context.Context, userIDs []string) (map[string]storage.User, error) {
+	collection := u.DB.Collection(storage.EntityUsers)
+	cursor, err := collection.Find(ctx, bson.M{"id": bson.M{"$in": userIDs}})
+	if err != nil {
+		return nil, toStorageError(storage.EntityUsers, err)
+	}
+
+	var users []storage.User
+	if err = cursor.All(ctx, &users); err != nil {
+		return nil, toStorageError(storage.EntityUsers, err)
+	}
+
+	found := make(map[string]storage.User, len(users))
+	for _, user := range users {
+		found[user.ID] = user
+	}
+
+	return found, nil
+}
+
+// bulkWrite writes the models, keyed by the index of the user they write,
+// recording the error of each failed write as the user's result.
+func (u *UserManager) bulkWrite(ctx context.Context, method string, results []storage.BulkResult, models map[int]mongo.WriteModel) error {
+	// Initialize contextual method logger
+	log := newLogger(ctx, u.Logger, Fields{
+		"package":    "mongo",
+		"collection": storage.EntityUsers,
+		"method":     method,
+	})
+
+	// Trace how long the Mongo operation takes to complete.
+	span, ctx := u.DB.traceMongoCall(ctx, DBTrace{
+		Manager:    "UserManager",
+		Method:     method,
+		Collection: storage.EntityUsers,
+		Operation:  "bulkWrite",
+	})
+	defer span.Finish()
+
+	collection := u.DB.Collection(storage.EntityUsers)
+	err := writeIndexed(ctx, collection, storage.EntityUsers, results, models)
+	if err != nil {
+		// Log to StdOut
+		log.WithError(err).Error(logError)
+		// Log to Tracer
+		span.RecordError(err)
+		return err
+	}
+
+	return nil
+}
+
+// updateMany applies the update to the users matching the query and
+// returns the number of users modified.
+func (u *UserManager) updateMany(ctx context.Context, method string, query bson.M, update bson.M) (int64, error) {
+	// Initialize contextual method logger
+	log := newLogger(ctx, u.Logger, Fields{
+		"package":    "mongo",
+		"collection": storage.EntityUsers,
+		"method":     method,
+	})
+
+	// Trace how long the Mongo operation takes to complete.
+	span, ctx := u.DB.traceMongoCall(ctx, DBTrace{
+		Manager:    "UserManager",
+		Method:     method,
+		Collection: storage.EntityUsers,
+		Operation:  "updateMany",
+		Query:      query,
+	})
+	defer span.Finish()
+
+	collection := u.DB.Collection(storage.EntityUsers)
+	res, err := collection.UpdateMany(ctx, query, update)
+	if err != nil {
+		// Log to StdOut
+		log.WithError(err).Error(logError)
+		// Log to Tracer
+		span.RecordError(err)
+		return 0, toStorageError(storage.EntityUsers, err)
+	}
+
+	return res.ModifiedCount, nil
+}
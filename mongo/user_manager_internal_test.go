@@ -1,8 +1,11 @@
 package mongo
 
 import (
+	"reflect"
 	"testing"
 
+	"go.mongodb.org/mongo-driver/bson"
+
 	"github.com/matthewhartstonge/storage"
 )
 
@@ -41,3 +44,83 @@ func TestUserMongoManager_ImplementsStorageUserManager(t *testing.T) {
 		t.Error("UserManager does not implement interface storage.UserManager")
 	}
 }
+
+func TestUserMongoManager_ImplementsStorageUserIterator(t *testing.T) {
+	u := &UserManager{}
+
+	var i interface{} = u
+	if _, ok := i.(storage.UserIterator); !ok {
+		t.Error("UserManager does not implement interface storage.UserIterator")
+	}
+}
+
+func TestSearchUsersPipeline(t *testing.T) {
+	request, err := storage.SearchUsersRequest{
+		Query:               "Pe",
+		AllowedTenantAccess: "tenant-1",
+		Offset:              20,
+	}.Normalize()
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	pipeline := searchUsersPipeline(request, nil)
+	match := pipeline[0]["$match"].(bson.M)["$and"].([]bson.M)
+	prefix := match[0]["$or"].([]bson.M)
+	if got := prefix[0]["username"].(bson.M)["$regex"]; got != "^pe" {
+		t.Errorf("expected the username to be matched by its normalized prefix, got %v", got)
+	}
+	if match[1]["allowedTenantAccess"] != "tenant-1" {
+		t.Errorf("expected the search to be filtered by tenant, got %v", match[1])
+	}
+	if pipeline[3]["$skip"] != 20 || pipeline[4]["$limit"] != storage.DefaultUserSearchLimit {
+		t.Errorf("expected the search to be paginated, got %v, %v", pipeline[3], pipeline[4])
+	}
+
+	request.Mode = storage.UserSearchText
+	pipeline = searchUsersPipeline(request, nil)
+	match = pipeline[0]["$match"].(bson.M)["$and"].([]bson.M)
+	if _, ok := match[0]["$text"]; !ok {
+		t.Errorf("expected a text search, got %v", match[0])
+	}
+}
+
+func TestListUsersQuery_ShouldMatchTenantGroupMembers(t *testing.T) {
+	filter := storage.ListUsersRequest{AllowedTenantAccess: "tenant-1"}
+
+	query := listUsersQuery(filter, nil)
+	if query["allowedTenantAccess"] != "tenant-1" {
+		t.Errorf("expected users to be filtered by tenant, got %v", query)
+	}
+
+	query = listUsersQuery(filter, []string{"staff"})
+	access := query["$or"].([]bson.M)
+	if access[0]["allowedTenantAccess"] != "tenant-1" {
+		t.Errorf("expected users with direct access to the tenant to match, got %v", access[0])
+	}
+	if groups := access[1]["groups"].(bson.M)["$in"].([]string); len(groups) != 1 || groups[0] != "staff" {
+		t.Errorf("expected members of groups with access to the tenant to match, got %v", access[1])
+	}
+}
+
+func TestPrefixSearch_EscapesQuery(t *testing.T) {
+	match, _ := prefixSearch("a.b*")
+	prefix := match["$or"].([]bson.M)
+	if got := prefix[0]["username"].(bson.M)["$regex"]; got != `^a\.b\*` {
+		t.Errorf("expected the query to be escaped, got %v", got)
+	}
+}
+
+func TestPrefixSearch_ShouldMatchAndRankNamesAlike(t *testing.T) {
+	match, score := prefixSearch("ÉMile")
+	names := match["$or"].([]bson.M)[1]["$expr"]
+	ranked := score["$switch"].(bson.M)["branches"].([]bson.M)[2]["case"]
+	if !reflect.DeepEqual(names, ranked) {
+		t.Errorf("expected names to be matched and ranked by the same expression, got %v and %v", names, ranked)
+	}
+
+	// $toLower only lowercases ASCII letters, so neither does the query.
+	if got := toLowerASCII("ÉMile"); got != "Émile" {
+		t.Errorf("expected only ASCII letters to be lowercased, got %q", got)
+	}
+}
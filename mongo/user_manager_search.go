@@ -0,0 +1,199 @@
+package mongo
+
+import (
+	// Standard Library Imports
+	"context"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	// External Imports
+	"go.mongodb.org/mongo-driver/bson"
+
+	// Internal Imports
+	"github.com/matthewhartstonge/storage"
+)
+
+const (
+	// searchScoreExactUsername ranks users whose username matches the query.
+	searchScoreExactUsername = 4
+
+	// searchScoreUsername ranks users whose username starts with the query.
+	searchScoreUsername = 3
+
+	// searchScoreName ranks users whose first, or last, name starts with the
+	// query.
+	searchScoreName = 2
+
+	// searchScorePersonID ranks users whose person ID starts with the query.
+	searchScorePersonID = 1
+)
+
+// Search returns the users matching the query, ranked, then paginated.
+// Passwords are not returned.
+func (u *UserManager) Search(ctx context.Context, request storage.SearchUsersRequest) (results []storage.User, err error) {
+	// Initialize contextual method logger
+	log := newLogger(ctx, u.Logger, Fields{
+		"package":    "mongo",
+		"collection": storage.EntityUsers,
+		"method":     "Search",
+	})
+
+	request, err = request.Normalize()
+	if err != nil {
+		log.WithError(err).Debug(logInvalid)
+		return results, err
+	}
+	if strings.TrimSpace(request.Query) == "" {
+		return results, nil
+	}
+
+	tenantGroups, err := u.tenantGroups(ctx, request.AllowedTenantAccess)
+	if err != nil {
+		log.WithError(err).Error(logError)
+		return results, err
+	}
+
+	// Build Query
+	pipeline := searchUsersPipeline(request, tenantGroups)
+
+	// Trace how long the Mongo operation takes to complete.
+	span, ctx := u.DB.traceMongoCall(ctx, DBTrace{
+		Manager:    "UserManager",
+		Method:     "Search",
+		Collection: storage.EntityUsers,
+		Operation:  "aggregate",
+		Query:      pipeline,
+	})
+	defer span.Finish()
+
+	collection := u.DB.Collection(storage.EntityUsers)
+	cursor, err := collection.Aggregate(ctx, pipeline)
+	if err != nil {
+		// Log to StdOut
+		log.WithError(err).Error(logError)
+		// Log to Tracer
+		span.RecordError(err)
+		return results, toStorageError(storage.EntityUsers, err)
+	}
+
+	var users []storage.User
+	err = cursor.All(ctx, &users)
+	if err != nil {
+		// Log to StdOut
+		log.WithError(err).Error(logError)
+		// Log to Tracer
+		span.RecordError(err)
+		return results, toStorageError(storage.EntityUsers, err)
+	}
+
+	return users, nil
+}
+
+// searchUsersPipeline returns the aggregation pipeline ranking and paginating
+// the users that match the normalized search request. tenantGroups contains
+// the groups granting access to the filtered tenant.
+func searchUsersPipeline(request storage.SearchUsersRequest, tenantGroups []string) []bson.M {
+	var match, score bson.M
+	switch request.Mode {
+	case storage.UserSearchText:
+		match = bson.M{"$text": bson.M{"$search": request.Query}}
+		score = bson.M{"$meta": "textScore"}
+
+	default:
+		match, score = prefixSearch(request.Query)
+	}
+
+	if request.AllowedTenantAccess != "" {
+		match = bson.M{
+			"$and": []bson.M{
+				match,
+				tenantAccessQuery(request.AllowedTenantAccess, tenantGroups),
+			},
+		}
+	}
+
+	return []bson.M{
+		{"$match": match},
+		{"$addFields": bson.M{"score": score}},
+		{"$sort": bson.D{
+			{Key: "score", Value: -1},
+			{Key: "username", Value: 1},
+			{Key: "id", Value: 1},
+		}},
+		{"$skip": request.Offset},
+		{"$limit": request.Limit},
+		{"$project": bson.M{"password": 0, "score": 0}},
+	}
+}
+
+// prefixSearch returns the query matching users with a username, first name,
+// last name or person ID starting with the query, and the expression scoring
+// which field matched.
+func prefixSearch(query string) (match bson.M, score bson.M) {
+	username := storage.NormalizeUsername(query)
+
+	// Names are matched, and ranked, by the same expression, so that the
+	// query is lowercased the same way as the names it is compared with.
+	name := toLowerASCII(query)
+	namePrefix := bson.M{"$or": bson.A{
+		hasPrefix(bson.M{"$toLower": "$firstName"}, name),
+		hasPrefix(bson.M{"$toLower": "$lastName"}, name),
+	}}
+
+	match = bson.M{
+		"$or": []bson.M{
+			{"username": bson.M{"$regex": "^" + regexp.QuoteMeta(username)}},
+			{"$expr": namePrefix},
+			{"personId": bson.M{"$regex": "^" + regexp.QuoteMeta(query)}},
+		},
+	}
+
+	score = bson.M{
+		"$switch": bson.M{
+			"branches": []bson.M{
+				{
+					"case": bson.M{"$eq": bson.A{"$username", username}},
+					"then": searchScoreExactUsername,
+				},
+				{
+					"case": hasPrefix("$username", username),
+					"then": searchScoreUsername,
+				},
+				{
+					"case": namePrefix,
+					"then": searchScoreName,
+				},
+			},
+			"default": searchScorePersonID,
+		},
+	}
+
+	return match, score
+}
+
+// toLowerASCII lowercases the ASCII letters of s, leaving any other
+// characters as is, the same as mongo's $toLower.
+func toLowerASCII(s string) string {
+	return strings.Map(func(r rune) rune {
+		if 'A' <= r && r <= 'Z' {
+			return r + 'a' - 'A'
+		}
+		return r
+	}, s)
+}
+
+// hasPrefix returns an expression testing whether the string expression
+// starts with the prefix.
+func hasPrefix(expression interface{}, prefix string) bson.M {
+	return bson.M{
+		"$eq": bson.A{
+			bson.M{"$substrCP": bson.A{
+				bson.M{"$ifNull": bson.A{expression, ""}},
+				0,
+				utf8.RuneCountInString(prefix),
+			}},
+			prefix,
+		},
+	}
+}
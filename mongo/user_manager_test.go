@@ -3,6 +3,7 @@ package mongo_test
 import (
 	// Standard Library Imports
 	"context"
+	"errors"
 	"reflect"
 	"testing"
 	"time"
@@ -10,6 +11,7 @@ import (
 	// External Imports
 	"github.com/google/uuid"
 	"github.com/ory/fosite"
+	"go.mongodb.org/mongo-driver/bson"
 
 	// Internal Imports
 	"github.com/matthewhartstonge/storage"
@@ -81,7 +83,7 @@ func TestUserManager_Create_ShouldConflict(t *testing.T) {
 	if err == nil {
 		AssertError(t, err, nil, "create should return an error on conflict")
 	}
-	if err != storage.ErrResourceExists {
+	if !errors.Is(err, storage.ErrResourceExists) {
 		AssertError(t, err, nil, "create should return conflict")
 	}
 }
@@ -96,11 +98,119 @@ func TestUserManager_Create_ShouldConflictOnUsername(t *testing.T) {
 	if err == nil {
 		AssertError(t, err, nil, "create should return an error on conflict")
 	}
-	if err != storage.ErrResourceExists {
+	if !errors.Is(err, storage.ErrResourceExists) {
 		AssertError(t, err, nil, "create should return conflict")
 	}
 }
 
+func TestUserManager_Create_ShouldConflictOnUsernameCase(t *testing.T) {
+	store, ctx, teardown := setup(t)
+	defer teardown()
+
+	expected := createUser(ctx, t, store)
+	expected.ID = uuid.NewString()
+	expected.Username = "J.Doe@Example.com"
+	_, err := store.UserManager.Create(ctx, expected)
+	if !errors.Is(err, storage.ErrResourceExists) {
+		AssertError(t, err, nil, "create should return conflict on a username differing by case")
+	}
+}
+
+func TestUserManager_GetByUsername_ShouldIgnoreCase(t *testing.T) {
+	store, ctx, teardown := setup(t)
+	defer teardown()
+
+	expected := createUser(ctx, t, store)
+	got, err := store.UserManager.GetByUsername(ctx, "J.DOE@EXAMPLE.COM")
+	if err != nil {
+		AssertError(t, err, nil, "get by username should return no database errors")
+	}
+	if got.ID != expected.ID {
+		AssertError(t, got.ID, expected.ID, "get by username should ignore case")
+	}
+}
+
+func TestUserManager_GetByEmail(t *testing.T) {
+	store, ctx, teardown := setup(t)
+	defer teardown()
+
+	user := expectedUser()
+	user.Email = " J.Doe@Example.com"
+	created, err := store.UserManager.Create(ctx, user)
+	if err != nil {
+		AssertError(t, err, nil, "create should return no database errors")
+		t.FailNow()
+	}
+	if created.Email != "j.doe@example.com" {
+		AssertError(t, created.Email, "j.doe@example.com", "create should normalize the email address")
+	}
+
+	got, err := store.UserManager.GetByEmail(ctx, "J.DOE@example.com")
+	if err != nil {
+		AssertError(t, err, nil, "get by email should return no database errors")
+	}
+	if got.ID != created.ID {
+		AssertError(t, got.ID, created.ID, "get by email should ignore case")
+	}
+
+	user.ID = uuid.NewString()
+	user.Username = "someone.else@example.com"
+	_, err = store.UserManager.Create(ctx, user)
+	if !errors.Is(err, storage.ErrResourceExists) {
+		AssertError(t, err, nil, "create should return conflict on email address")
+	}
+}
+
+func TestUserManager_Update_ShouldResetEmailVerification(t *testing.T) {
+	store, ctx, teardown := setup(t)
+	defer teardown()
+
+	user := expectedUser()
+	user.Email = "j.doe@example.com"
+	user.EmailVerified = true
+	created, err := store.UserManager.Create(ctx, user)
+	if err != nil {
+		AssertError(t, err, nil, "create should return no database errors")
+		t.FailNow()
+	}
+
+	created.FirstName = "Johnny"
+	got, err := store.UserManager.Update(ctx, created.ID, created)
+	if err != nil {
+		AssertError(t, err, nil, "update should return no database errors")
+	}
+	if !got.EmailVerified {
+		AssertError(t, got.EmailVerified, true, "update should retain verification if the email address is unchanged")
+	}
+
+	created.Email = "john.doe@example.com"
+	got, err = store.UserManager.Update(ctx, created.ID, created)
+	if err != nil {
+		AssertError(t, err, nil, "update should return no database errors")
+	}
+	if got.EmailVerified {
+		AssertError(t, got.EmailVerified, false, "update should reset verification if the email address changes")
+	}
+}
+
+func TestUserManager_Search(t *testing.T) {
+	store, ctx, teardown := setup(t)
+	defer teardown()
+
+	expected := createUser(ctx, t, store)
+	got, err := store.UserManager.Search(ctx, storage.SearchUsersRequest{Query: "Do"})
+	if err != nil {
+		AssertError(t, err, nil, "search should return no database errors")
+	}
+	if len(got) != 1 || got[0].ID != expected.ID {
+		AssertError(t, got, expected, "search should match by last name prefix")
+		t.FailNow()
+	}
+	if got[0].Password != "" {
+		AssertError(t, got[0].Password, "", "search should not return passwords")
+	}
+}
+
 func TestUserManager_Get(t *testing.T) {
 	store, ctx, teardown := setup(t)
 	defer teardown()
@@ -119,9 +229,9 @@ func TestUserManager_Get_ShouldReturnNotFound(t *testing.T) {
 	store, ctx, teardown := setup(t)
 	defer teardown()
 
-	expected := fosite.ErrNotFound
+	expected := storage.ErrNotFound
 	got, err := store.UserManager.Get(ctx, "lolNotFound")
-	if err != expected {
+	if !errors.Is(err, expected) {
 		AssertError(t, got, expected, "get should return not found")
 	}
 }
@@ -233,7 +343,7 @@ func TestUserManager_Update_ShouldConflictUsername(t *testing.T) {
 	if err == nil {
 		AssertError(t, err, nil, "update should return an error on username conflict")
 	}
-	if err != storage.ErrResourceExists {
+	if !errors.Is(err, storage.ErrResourceExists) {
 		AssertError(t, err, nil, "update should return conflict on username")
 	}
 }
@@ -246,7 +356,7 @@ func TestUserManager_Update_ShouldReturnNotFound(t *testing.T) {
 	if err == nil {
 		AssertError(t, err, nil, "update should return an error on not found")
 	}
-	if err != fosite.ErrNotFound {
+	if !errors.Is(err, fosite.ErrNotFound) {
 		AssertError(t, err, nil, "update should return not found")
 	}
 }
@@ -263,9 +373,9 @@ func TestUserManager_Delete(t *testing.T) {
 	}
 
 	// Double check that the original reference was deleted
-	expectedErr := fosite.ErrNotFound
+	expectedErr := storage.ErrNotFound
 	got, err := store.UserManager.Get(ctx, expected.ID)
-	if err != expectedErr {
+	if !errors.Is(err, expectedErr) {
 		AssertError(t, got, expectedErr, "get should return not found")
 	}
 }
@@ -278,7 +388,147 @@ func TestUserManager_Delete_ShouldReturnNotFound(t *testing.T) {
 	if err == nil {
 		AssertError(t, err, nil, "delete should return an error on not found")
 	}
-	if err != fosite.ErrNotFound {
+	if !errors.Is(err, fosite.ErrNotFound) {
 		AssertError(t, err, nil, "delete should return not found")
 	}
 }
+
+func TestUserManager_BulkCreate_ShouldNotModifyInput(t *testing.T) {
+	store, ctx, teardown := setup(t)
+	defer teardown()
+
+	user := expectedUser()
+	user.ID = ""
+	user.CreateTime = 0
+	user.Username = "J.Doe@Example.com"
+	users := []storage.User{user}
+
+	results, err := store.UserManager.BulkCreate(ctx, users)
+	if err != nil {
+		AssertFatal(t, err, nil, "bulk create should return no database errors")
+	}
+	if results[0].Err != nil {
+		AssertFatal(t, results[0].Err, nil, "bulk create should create the user")
+	}
+	if !reflect.DeepEqual(users[0], user) {
+		AssertError(t, users[0], user, "bulk create should not modify the given users")
+	}
+
+	got, err := store.UserManager.Get(ctx, results[0].ID)
+	if err != nil {
+		AssertFatal(t, err, nil, "get should return no database errors")
+	}
+	if got.Password == user.Password {
+		AssertError(t, got.Password, "bcrypt encoded secret", "bulk create should hash the password")
+	}
+}
+
+func TestUserManager_BulkUpdate_ShouldNotModifyInput(t *testing.T) {
+	store, ctx, teardown := setup(t)
+	defer teardown()
+
+	expected := createUser(ctx, t, store)
+
+	update := expected
+	update.Password = "foobaz"
+	update.UpdateTime = 0
+	users := []storage.User{update}
+
+	results, err := store.UserManager.BulkUpdate(ctx, users)
+	if err != nil {
+		AssertFatal(t, err, nil, "bulk update should return no database errors")
+	}
+	if results[0].Err != nil {
+		AssertFatal(t, results[0].Err, nil, "bulk update should update the user")
+	}
+	if !reflect.DeepEqual(users[0], update) {
+		AssertError(t, users[0], update, "bulk update should not modify the given users")
+	}
+}
+
+func TestUserManager_BulkGrantScopes_ShouldRequireFilter(t *testing.T) {
+	store, ctx, teardown := setup(t)
+	defer teardown()
+
+	expected := createUser(ctx, t, store)
+	scopes := []string{"urn:test:birds:read"}
+
+	_, err := store.UserManager.BulkGrantScopes(ctx, storage.ListUsersRequest{}, scopes)
+	if !errors.Is(err, storage.ErrInvalidArgument) {
+		AssertError(t, err, storage.ErrInvalidArgument, "bulk grant should require a filter")
+	}
+
+	got, err := store.UserManager.Get(ctx, expected.ID)
+	if err != nil {
+		AssertFatal(t, err, nil, "get should return no database errors")
+	}
+	if !reflect.DeepEqual(got.Scopes, expected.Scopes) {
+		AssertError(t, got.Scopes, expected.Scopes, "rejected bulk grant should not modify users")
+	}
+
+	modified, err := store.UserManager.BulkGrantScopes(ctx, storage.ListUsersRequest{All: true}, scopes)
+	if err != nil {
+		AssertFatal(t, err, nil, "bulk grant should return no database errors")
+	}
+	if modified != 1 {
+		AssertError(t, modified, 1, "bulk grant should modify every user if all is set")
+	}
+}
+
+func TestUserManager_Configure_ShouldIndexUsernamesWithoutMigrating(t *testing.T) {
+	cfg := mongo.DefaultConfig()
+	cfg.DatabaseName = "fositeStorageTest"
+	cfg.MigrationsDryRun = true
+	store, err := mongo.New(cfg, nil)
+	if err != nil {
+		AssertFatal(t, err, nil, "mongo connection error")
+	}
+	ctx := context.Background()
+	defer func() {
+		if err := store.DB.Drop(ctx); err != nil {
+			t.Errorf("error dropping database on cleanup: %s", err)
+		}
+		store.Close()
+	}()
+
+	user := expectedUser()
+	user.Username = "Bob"
+	if _, err = store.UserManager.Create(ctx, user); err != nil {
+		AssertFatal(t, err, nil, "create should return no database errors")
+	}
+
+	// Bypass normalization, as if the username was stored before usernames
+	// were normalized.
+	_, err = store.DB.Collection(storage.EntityUsers).InsertOne(ctx, bson.M{"id": uuid.NewString(), "username": "BOB"})
+	if err == nil {
+		AssertError(t, err, "duplicate key error", "usernames should be unique regardless of case without migrating")
+	}
+}
+
+func TestUserManager_Configure_ShouldExpectUsernameIndexWhileUsernamesConflict(t *testing.T) {
+	store, ctx, teardown := setup(t)
+	defer teardown()
+
+	// Rewind the users collection to before usernames were normalized.
+	users := store.DB.Collection(storage.EntityUsers)
+	if _, err := users.Indexes().DropOne(ctx, mongo.IdxUsernameCaseInsensitive); err != nil {
+		AssertFatal(t, err, nil, "drop index should return no database errors")
+	}
+	_, err := users.InsertMany(ctx, []interface{}{
+		bson.M{"id": uuid.NewString(), "username": "Bob"},
+		bson.M{"id": uuid.NewString(), "username": "bob"},
+	})
+	if err != nil {
+		AssertFatal(t, err, nil, "insert should return no database errors")
+	}
+
+	if err = store.UserManager.Configure(ctx); err != nil {
+		AssertFatal(t, err, nil, "configure should not fail while usernames conflict")
+	}
+
+	expected := storage.EntityUsers + "." + mongo.IdxUsernameCaseInsensitive
+	health := store.Health(ctx)
+	if len(health.Indexes.Missing) != 1 || health.Indexes.Missing[0] != expected {
+		AssertError(t, health.Indexes.Missing, []string{expected}, "the username index should be reported missing")
+	}
+}
@@ -0,0 +1,52 @@
+package storage
+
+import (
+	// Standard Library Imports
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"time"
+
+	// External Imports
+	"github.com/ory/fosite"
+)
+
+const (
+	// PARRequestURIPrefix provides the URN prefix of the request_uri issued
+	// for a pushed authorization request, as defined by RFC 9126, section 2.2.
+	PARRequestURIPrefix = "urn:ietf:params:oauth:request_uri:"
+
+	// DefaultPARLifespan provides the default time a pushed authorization
+	// request can be used for after being pushed.
+	DefaultPARLifespan = time.Minute
+)
+
+// PARStorage provides storage for Pushed Authorization Requests (RFC 9126).
+//
+// PARStorage mirrors the interface expected by fosite's PAR handler, which is
+// yet to be released in the version of fosite this module depends on.
+type PARStorage interface {
+	// CreatePARSession stores the pushed authorization request under the
+	// request_uri.
+	CreatePARSession(ctx context.Context, requestURI string, request fosite.AuthorizeRequester) error
+
+	// GetPARSession returns the pushed authorization request stored under the
+	// request_uri. A pushed authorization request can only be retrieved once,
+	// and only within its lifespan.
+	GetPARSession(ctx context.Context, requestURI string) (fosite.AuthorizeRequester, error)
+
+	// DeletePARSession deletes the pushed authorization request stored under
+	// the request_uri.
+	DeletePARSession(ctx context.Context, requestURI string) error
+}
+
+// NewPARRequestURI generates a new request_uri for a pushed authorization
+// request.
+func NewPARRequestURI() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	return PARRequestURIPrefix + base64.RawURLEncoding.EncodeToString(b), nil
+}
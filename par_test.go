@@ -0,0 +1,25 @@
+package storage_test
+
+import (
+	// Standard Library Imports
+	"strings"
+	"testing"
+
+	// Internal Imports
+	"github.com/matthewhartstonge/storage"
+)
+
+func TestNewPARRequestURI(t *testing.T) {
+	first, err := storage.NewPARRequestURI()
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if !strings.HasPrefix(first, storage.PARRequestURIPrefix) {
+		t.Errorf("expected request_uri to be prefixed with %s, got %s", storage.PARRequestURIPrefix, first)
+	}
+
+	second, _ := storage.NewPARRequestURI()
+	if first == second {
+		t.Error("expected request_uris to be unique")
+	}
+}
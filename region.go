@@ -0,0 +1,84 @@
+package storage
+
+import (
+	// Standard Library Imports
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// regionContextKey provides the context key the serving region is bound to.
+type regionContextKey struct{}
+
+// RegionToContext binds the region the request is being served from to the
+// context. A region bound to the context takes precedence over the region a
+// store has been configured with.
+func RegionToContext(ctx context.Context, region string) context.Context {
+	return context.WithValue(ctx, regionContextKey{}, region)
+}
+
+// ContextToRegion returns the serving region bound to the context, if any.
+func ContextToRegion(ctx context.Context) (region string, ok bool) {
+	region, ok = ctx.Value(regionContextKey{}).(string)
+	return region, ok && region != ""
+}
+
+// ServingRegion returns the serving region bound to the context, otherwise
+// the given default region.
+func ServingRegion(ctx context.Context, defaultRegion string) string {
+	if region, ok := ContextToRegion(ctx); ok {
+		return region
+	}
+
+	return defaultRegion
+}
+
+// ErrDataResidency is wrapped by the errors returned when persisting data
+// would store it outside of the regions a tenant is pinned to.
+var ErrDataResidency = errors.New("data residency violation")
+
+// IsRegionAllowed returns whether the client is permitted to operate in the
+// given region. Clients without AllowedRegions are permitted in every region.
+func (c *Client) IsRegionAllowed(region string) bool {
+	return regionAllowed(c.AllowedRegions, region)
+}
+
+// IsRegionAllowed returns whether the tenant's data is permitted to be stored
+// in the given region. Tenants without AllowedRegions are not pinned to a
+// region.
+func (t Tenant) IsRegionAllowed(region string) bool {
+	return regionAllowed(t.AllowedRegions, region)
+}
+
+// regionAllowed returns whether the region is in the allowed regions, or if
+// there are no region restrictions.
+func regionAllowed(allowedRegions []string, region string) bool {
+	return len(allowedRegions) == 0 || contains(allowedRegions, region)
+}
+
+// CheckDataResidency returns an error wrapping ErrPreconditionFailed and
+// ErrDataResidency if any of the given tenants are pinned to regions other
+// than the serving region. Unknown tenants are not pinned to a region.
+func CheckDataResidency(ctx context.Context, tenants TenantStorer, entityName string, region string, tenantIDs []string) error {
+	if tenants == nil || len(tenantIDs) == 0 {
+		return nil
+	}
+
+	found, err := tenants.List(ctx, ListTenantsRequest{IDs: tenantIDs})
+	if err != nil {
+		return err
+	}
+
+	for _, tenant := range found {
+		if !tenant.IsRegionAllowed(region) {
+			return NewError(
+				ErrPreconditionFailed,
+				entityName,
+				fmt.Errorf("%w: tenant %s is pinned to %s", ErrDataResidency, tenant.ID, strings.Join(tenant.AllowedRegions, ", ")),
+			)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,76 @@
+package storage_test
+
+import (
+	// Standard Library Imports
+	"context"
+	"errors"
+	"testing"
+
+	// Internal Imports
+	"github.com/matthewhartstonge/storage"
+)
+
+func TestContextToRegion(t *testing.T) {
+	if _, ok := storage.ContextToRegion(context.Background()); ok {
+		t.Error("expected no region to be bound")
+	}
+
+	ctx := storage.RegionToContext(context.Background(), "eu-west-1")
+	if region, ok := storage.ContextToRegion(ctx); !ok || region != "eu-west-1" {
+		t.Errorf("expected eu-west-1, got %q", region)
+	}
+}
+
+func TestServingRegion(t *testing.T) {
+	if region := storage.ServingRegion(context.Background(), "us-east-1"); region != "us-east-1" {
+		t.Errorf("expected the default region, got %q", region)
+	}
+
+	ctx := storage.RegionToContext(context.Background(), "eu-west-1")
+	if region := storage.ServingRegion(ctx, "us-east-1"); region != "eu-west-1" {
+		t.Errorf("expected the context region to take precedence, got %q", region)
+	}
+}
+
+func TestClient_IsRegionAllowed(t *testing.T) {
+	client := storage.Client{}
+	if !client.IsRegionAllowed("eu-west-1") {
+		t.Error("expected a client without allowed regions to be allowed in every region")
+	}
+
+	client.AllowedRegions = []string{"eu-west-1"}
+	if !client.IsRegionAllowed("eu-west-1") {
+		t.Error("expected client to be allowed in eu-west-1")
+	}
+	if client.IsRegionAllowed("us-east-1") {
+		t.Error("expected client to be denied in us-east-1")
+	}
+}
+
+func TestCheckDataResidency(t *testing.T) {
+	ctx := context.Background()
+	tenants := &memoryTenants{tenants: map[string]storage.Tenant{
+		"tenant-1": {ID: "tenant-1", Name: "Tenant 1", Status: storage.TenantStatusActive},
+		"tenant-eu": {
+			ID:             "tenant-eu",
+			Name:           "Tenant EU",
+			Status:         storage.TenantStatusActive,
+			AllowedRegions: []string{"eu-west-1"},
+		},
+	}}
+
+	if err := storage.CheckDataResidency(ctx, nil, storage.EntityAccessTokens, "us-east-1", []string{"tenant-eu"}); err != nil {
+		t.Errorf("expected no check without a tenant storer, got %v", err)
+	}
+	if err := storage.CheckDataResidency(ctx, tenants, storage.EntityAccessTokens, "us-east-1", []string{"tenant-1", "tenant-unknown"}); err != nil {
+		t.Errorf("expected unpinned tenants to be allowed, got %v", err)
+	}
+	if err := storage.CheckDataResidency(ctx, tenants, storage.EntityAccessTokens, "eu-west-1", []string{"tenant-eu"}); err != nil {
+		t.Errorf("expected pinned tenant to be allowed in its region, got %v", err)
+	}
+
+	err := storage.CheckDataResidency(ctx, tenants, storage.EntityAccessTokens, "us-east-1", []string{"tenant-1", "tenant-eu"})
+	if !errors.Is(err, storage.ErrPreconditionFailed) || !errors.Is(err, storage.ErrDataResidency) {
+		t.Errorf("expected a data residency violation, got %v", err)
+	}
+}
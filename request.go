@@ -11,7 +11,6 @@ import (
 	"github.com/google/uuid"
 	"github.com/ory/fosite"
 	"github.com/pkg/errors"
-	log "github.com/sirupsen/logrus"
 )
 
 // Request is a concrete implementation of a fosite.Requester, extended to
@@ -78,8 +77,6 @@ func (r *Request) ToRequest(ctx context.Context, session fosite.Session, cm Clie
 		if err := json.Unmarshal(r.Session, session); err != nil {
 			return nil, errors.WithStack(err)
 		}
-	} else {
-		log.Debug("Got an empty session in toRequest")
 	}
 
 	client, err := cm.GetClient(ctx, r.ClientID)
@@ -8,6 +8,7 @@ import (
 	"github.com/ory/fosite/handler/oauth2"
 	"github.com/ory/fosite/handler/openid"
 	"github.com/ory/fosite/handler/pkce"
+	fositeStorage "github.com/ory/fosite/storage"
 )
 
 // RequestManager provides an interface in order to build a compliant Fosite
@@ -29,6 +30,14 @@ type RequestStorer interface {
 	// Proof Key for Code Exchange storage interfaces.
 	pkce.PKCERequestStorage
 
+	// Pushed Authorization Request storage interfaces.
+	PARStorage
+
+	// Transactional enables fosite to atomically store the requests created
+	// during a flow, for example, an access token, refresh token and OpenID
+	// Connect session.
+	fositeStorage.Transactional
+
 	// Implements the rest of oauth2.TokenRevocationStorage
 	RevokeRefreshToken(ctx context.Context, requestID string) error
 	RevokeAccessToken(ctx context.Context, requestID string) error
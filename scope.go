@@ -0,0 +1,66 @@
+package storage
+
+// Scope provides the structure of a registered OAuth 2.0 scope, which the
+// Scopes of clients and users reference by name.
+type Scope struct {
+	//// Scope Meta
+	// ID is the unique identifier of the scope.
+	ID string `bson:"id" json:"id" xml:"id"`
+
+	// CreateTime is when the resource was created in seconds from the epoch.
+	CreateTime int64 `bson:"createTime" json:"createTime" xml:"createTime"`
+
+	// UpdateTime is the last time the resource was modified in seconds from
+	// the epoch.
+	UpdateTime int64 `bson:"updateTime" json:"updateTime" xml:"updateTime"`
+
+	//// Scope Content
+	// Name is the unique name of the scope, as requested by clients, for
+	// example, cats:read.
+	Name string `bson:"name" json:"name" xml:"name"`
+
+	// Description contains a human-readable description of the scope for
+	// administrators.
+	Description string `bson:"description" json:"description" xml:"description"`
+
+	// ConsentText contains the text to display to users on the consent
+	// screen, for example, "Read your cats".
+	ConsentText string `bson:"consentText" json:"consentText" xml:"consentText"`
+
+	// Sensitive reports whether the scope grants access to sensitive data,
+	// for example, so the consent screen can highlight it.
+	Sensitive bool `bson:"sensitive" json:"sensitive" xml:"sensitive"`
+
+	// Audience contains the audiences the scope applies to. If empty, the
+	// scope applies to every audience.
+	Audience []string `bson:"audience" json:"audience,omitempty" xml:"audience,omitempty"`
+}
+
+// AppliesTo returns whether the scope applies to the given audience.
+func (s Scope) AppliesTo(audience string) bool {
+	return len(s.Audience) == 0 || contains(s.Audience, audience)
+}
+
+// Validate returns an invalid argument error if the scope is not valid.
+func (s Scope) Validate() error {
+	if s.Name == "" {
+		return NewInvalidArgumentError(EntityScopes, "name is required", "name")
+	}
+	if !isScopeToken(s.Name) {
+		return NewInvalidArgumentError(EntityScopes, "name must be a valid scope token", "name")
+	}
+
+	return nil
+}
+
+// isScopeToken returns whether the name only contains the characters allowed
+// in a scope token, as defined by RFC 6749, section 3.3.
+func isScopeToken(name string) bool {
+	for _, r := range name {
+		if r < 0x21 || r > 0x7e || r == '"' || r == '\\' {
+			return false
+		}
+	}
+
+	return true
+}
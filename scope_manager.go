@@ -0,0 +1,76 @@
+package storage
+
+import (
+	// Standard Library Imports
+	"context"
+	"fmt"
+	"strings"
+)
+
+// ScopeManager provides a generic interface to registered scopes in order to
+// build a Datastore backend.
+type ScopeManager interface {
+	Configurer
+	ScopeStorer
+}
+
+// ScopeStorer provides a definition of specific methods that are required to
+// store a Scope in a data store. Scopes are identified by their unique name.
+type ScopeStorer interface {
+	List(ctx context.Context, filter ListScopesRequest) ([]Scope, error)
+	Create(ctx context.Context, scope Scope) (Scope, error)
+	Get(ctx context.Context, scopeName string) (Scope, error)
+	Update(ctx context.Context, scopeName string, scope Scope) (Scope, error)
+	Delete(ctx context.Context, scopeName string) error
+
+	// ListUnregistered returns the scopes referenced by clients, users or
+	// groups that are not registered, for example, to audit stores that
+	// predate strict scopes.
+	ListUnregistered(ctx context.Context) ([]string, error)
+}
+
+// ListScopesRequest enables filtering stored Scope entities.
+type ListScopesRequest struct {
+	// Names filters scopes to those with one of the listed names.
+	Names []string `json:"names" xml:"names"`
+	// Audience filters scopes to those that apply to the audience, including
+	// scopes that apply to every audience.
+	Audience string `json:"audience" xml:"audience"`
+	// Sensitive filters scopes to those that are sensitive.
+	Sensitive bool `json:"sensitive" xml:"sensitive"`
+}
+
+// ValidateScopes returns an invalid argument error if any of the scopes,
+// referenced by the Scopes of the given entity, are not registered.
+func ValidateScopes(ctx context.Context, scopes ScopeStorer, entityName string, scopeNames []string) error {
+	if scopes == nil || len(scopeNames) == 0 {
+		return nil
+	}
+
+	found, err := scopes.List(ctx, ListScopesRequest{Names: scopeNames})
+	if err != nil {
+		return err
+	}
+
+	registered := make(map[string]bool, len(found))
+	for _, scope := range found {
+		registered[scope.Name] = true
+	}
+
+	var unknown []string
+	for _, scopeName := range scopeNames {
+		if !registered[scopeName] {
+			unknown = append(unknown, scopeName)
+		}
+	}
+	if len(unknown) > 0 {
+		return NewError(
+			ErrInvalidArgument,
+			entityName,
+			fmt.Errorf("unknown scopes: %s", strings.Join(unknown, ", ")),
+			"scopes",
+		)
+	}
+
+	return nil
+}
@@ -0,0 +1,116 @@
+package storage_test
+
+import (
+	// Standard Library Imports
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	// Internal Imports
+	"github.com/matthewhartstonge/storage"
+)
+
+// memoryScopes provides an in-memory scope storer, for validating scopes.
+type memoryScopes struct {
+	storage.ScopeStorer
+	scopes map[string]storage.Scope
+}
+
+func (m *memoryScopes) List(ctx context.Context, filter storage.ListScopesRequest) (results []storage.Scope, err error) {
+	for _, scopeName := range filter.Names {
+		if scope, ok := m.scopes[scopeName]; ok {
+			results = append(results, scope)
+		}
+	}
+
+	return results, nil
+}
+
+func TestScope_Validate(t *testing.T) {
+	tests := []struct {
+		name  string
+		scope storage.Scope
+		valid bool
+	}{
+		{
+			name:  "valid",
+			scope: storage.Scope{Name: "cats:read"},
+			valid: true,
+		},
+		{
+			name:  "missing name",
+			scope: storage.Scope{},
+		},
+		{
+			name:  "whitespace",
+			scope: storage.Scope{Name: "cats read"},
+		},
+		{
+			name:  "quote",
+			scope: storage.Scope{Name: `cats"read`},
+		},
+		{
+			name:  "backslash",
+			scope: storage.Scope{Name: `cats\read`},
+		},
+		{
+			name:  "non-ascii",
+			scope: storage.Scope{Name: "chats:lïre"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.scope.Validate()
+			if tt.valid {
+				if err != nil {
+					t.Errorf("expected no error, got %v", err)
+				}
+				return
+			}
+			if !errors.Is(err, storage.ErrInvalidArgument) {
+				t.Errorf("expected invalid argument error, got %v", err)
+			}
+		})
+	}
+}
+
+func TestScope_AppliesTo(t *testing.T) {
+	if !(storage.Scope{}).AppliesTo("https://cats.example.com") {
+		t.Error("expected a scope without audiences to apply to every audience")
+	}
+
+	scope := storage.Scope{Audience: []string{"https://cats.example.com"}}
+	if !scope.AppliesTo("https://cats.example.com") {
+		t.Error("expected scope to apply to its audience")
+	}
+	if scope.AppliesTo("https://dogs.example.com") {
+		t.Error("expected scope to not apply to other audiences")
+	}
+}
+
+func TestValidateScopes(t *testing.T) {
+	ctx := context.Background()
+	scopes := &memoryScopes{scopes: map[string]storage.Scope{
+		"cats:read": {Name: "cats:read"},
+	}}
+
+	if err := storage.ValidateScopes(ctx, nil, storage.EntityClients, []string{"cats:raed"}); err != nil {
+		t.Errorf("expected no validation without a scope storer, got %v", err)
+	}
+	if err := storage.ValidateScopes(ctx, scopes, storage.EntityClients, nil); err != nil {
+		t.Errorf("expected no scopes to be valid, got %v", err)
+	}
+	if err := storage.ValidateScopes(ctx, scopes, storage.EntityClients, []string{"cats:read"}); err != nil {
+		t.Errorf("expected registered scopes to be valid, got %v", err)
+	}
+
+	err := storage.ValidateScopes(ctx, scopes, storage.EntityUsers, []string{"cats:read", "cats:raed", "cats:delete"})
+	if !errors.Is(err, storage.ErrInvalidArgument) {
+		t.Fatalf("expected unregistered scopes to be invalid, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "cats:raed, cats:delete") {
+		t.Errorf("expected the unregistered scopes to be reported, got %v", err)
+	}
+}
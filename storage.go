@@ -6,8 +6,15 @@ import "context"
 // storage backend implementations
 type Store struct {
 	ClientManager
+	ConsentManager
 	DeniedJTIManager
+	DeviceCodeManager
+	GroupManager
+	IssuerTrustManager
+	LoginSessionManager
 	RequestManager
+	ScopeManager
+	TenantManager
 	UserManager
 }
 
@@ -24,6 +31,14 @@ func (s *Store) Authenticate(ctx context.Context, username string, secret string
 	return err
 }
 
+// EffectivePermissions returns the user's effective permissions, merging the
+// user's scopes and access with those inherited from their groups. The
+// effective scopes are what resource owner flows should check requested
+// scopes against, rather than User.Scopes.
+func (s *Store) EffectivePermissions(ctx context.Context, user User) (Permissions, error) {
+	return EffectivePermissions(ctx, s.GroupManager, user)
+}
+
 // AuthClientFunc enables developers to supply their own authentication
 // function, to check old hashes that need to be upgraded for clients.
 //
@@ -1,9 +1,136 @@
 package storage
 
-import "errors"
+import (
+	// Standard Library Imports
+	"errors"
+	"fmt"
+	"strings"
+
+	// External Imports
+	"github.com/ory/fosite"
+)
 
 var (
 	// ErrResourceExists provides an error for when, in most cases, a record's
 	// unique identifier already exists in the system.
 	ErrResourceExists = errors.New("resource conflict")
+
+	// ErrNotFound provides an error for when the requested resource could not
+	// be found.
+	ErrNotFound = errors.New("resource not found")
+
+	// ErrInvalidArgument provides an error for when the input provided to a
+	// storage operation is not valid.
+	ErrInvalidArgument = errors.New("invalid argument")
+
+	// ErrPreconditionFailed provides an error for when the current state of a
+	// resource, or of the datastore, does not allow the operation to proceed.
+	ErrPreconditionFailed = errors.New("precondition failed")
+
+	// ErrUnavailable provides an error for when the datastore could not be
+	// reached, or the operation timed out. Operations failing with
+	// ErrUnavailable are transient and may succeed if retried.
+	ErrUnavailable = errors.New("storage unavailable")
+
+	// ErrAuthenticationFailed provides an error for when a resource, or the
+	// datastore connection itself, could not be authenticated.
+	ErrAuthenticationFailed = errors.New("authentication failed")
 )
+
+// Error provides a backend neutral storage error.
+//
+// Error can be matched against the exported storage errors (for example,
+// ErrNotFound) with `errors.Is`, and inspected with `errors.As`. The
+// underlying cause, such as a driver error, is retained and can be obtained
+// via `errors.Unwrap`, or `errors.Cause`. Where fosite requires a specific
+// error to be returned, the fosite error is the underlying cause, so fosite's
+// error identity is preserved.
+type Error struct {
+	// Kind classifies the error as one of the exported storage errors, for
+	// example, ErrNotFound.
+	Kind error
+
+	// Entity contains the name of the entity the error occurred on, for
+	// example, EntityClients.
+	Entity string
+
+	// Fields contains the names of the fields that caused the error, if
+	// known. For example, the unique fields that conflicted on create.
+	Fields []string
+
+	// Err contains the underlying cause of the error.
+	Err error
+}
+
+// Error implements error.
+func (e *Error) Error() string {
+	var b strings.Builder
+	if e.Entity != "" {
+		b.WriteString(e.Entity)
+		b.WriteString(": ")
+	}
+
+	b.WriteString(e.Kind.Error())
+	if len(e.Fields) > 0 {
+		b.WriteString(fmt.Sprintf(" (%s)", strings.Join(e.Fields, ", ")))
+	}
+
+	if e.Err != nil && e.Err.Error() != e.Kind.Error() {
+		b.WriteString(": ")
+		b.WriteString(e.Err.Error())
+	}
+
+	return b.String()
+}
+
+// Is enables matching the error against its Kind via `errors.Is`.
+func (e *Error) Is(target error) bool {
+	return target == e.Kind
+}
+
+// Unwrap returns the underlying cause of the error.
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Cause returns the underlying cause of the error. Cause is implemented in
+// order to support `github.com/pkg/errors.Cause`, which fosite uses to inspect
+// errors returned from storage.
+func (e *Error) Cause() error {
+	return e.Err
+}
+
+// NewError returns a new storage error of the given kind, wrapping the
+// underlying cause.
+func NewError(kind error, entityName string, err error, fields ...string) *Error {
+	return &Error{
+		Kind:   kind,
+		Entity: entityName,
+		Fields: fields,
+		Err:    err,
+	}
+}
+
+// NewNotFoundError returns a new not found error for the given entity. The
+// error retains the identity of fosite.ErrNotFound.
+func NewNotFoundError(entityName string) *Error {
+	return NewError(ErrNotFound, entityName, fosite.ErrNotFound)
+}
+
+// NewConflictError returns a new conflict error for the given entity,
+// recording the conflicting fields, if known.
+func NewConflictError(entityName string, err error, fields ...string) *Error {
+	return NewError(ErrResourceExists, entityName, err, fields...)
+}
+
+// NewInvalidArgumentError returns a new invalid argument error for the given
+// entity, recording the invalid fields.
+func NewInvalidArgumentError(entityName string, reason string, fields ...string) *Error {
+	return NewError(ErrInvalidArgument, entityName, errors.New(reason), fields...)
+}
+
+// NewAuthenticationFailedError returns a new authentication failed error for
+// the given entity, wrapping the underlying cause.
+func NewAuthenticationFailedError(entityName string, err error) *Error {
+	return NewError(ErrAuthenticationFailed, entityName, err)
+}
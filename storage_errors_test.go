@@ -0,0 +1,72 @@
+package storage_test
+
+import (
+	// Standard Library Imports
+	"errors"
+	"testing"
+
+	// External Imports
+	"github.com/ory/fosite"
+	pkgErrors "github.com/pkg/errors"
+
+	// Internal Imports
+	"github.com/matthewhartstonge/storage"
+)
+
+func TestNewNotFoundError_RetainsFositeIdentity(t *testing.T) {
+	err := error(storage.NewNotFoundError(storage.EntityClients))
+
+	if !errors.Is(err, storage.ErrNotFound) {
+		t.Error("expected error to match storage.ErrNotFound")
+	}
+	if !errors.Is(err, fosite.ErrNotFound) {
+		t.Error("expected error to match fosite.ErrNotFound")
+	}
+	if pkgErrors.Cause(err) != fosite.ErrNotFound {
+		t.Error("expected error to be caused by fosite.ErrNotFound")
+	}
+	if errors.Is(err, storage.ErrResourceExists) {
+		t.Error("expected error to not match storage.ErrResourceExists")
+	}
+}
+
+func TestNewConflictError(t *testing.T) {
+	cause := errors.New("E11000 duplicate key error")
+	err := error(storage.NewConflictError(storage.EntityUsers, cause, "username"))
+
+	if !errors.Is(err, storage.ErrResourceExists) {
+		t.Error("expected error to match storage.ErrResourceExists")
+	}
+	if !errors.Is(err, cause) {
+		t.Error("expected error to wrap the underlying cause")
+	}
+
+	var storeErr *storage.Error
+	if !errors.As(err, &storeErr) {
+		t.Fatal("expected error to be a *storage.Error")
+	}
+	if storeErr.Entity != storage.EntityUsers {
+		t.Errorf("entity = %v, want %v", storeErr.Entity, storage.EntityUsers)
+	}
+	if len(storeErr.Fields) != 1 || storeErr.Fields[0] != "username" {
+		t.Errorf("fields = %v, want [username]", storeErr.Fields)
+	}
+
+	expected := "users: resource conflict (username): E11000 duplicate key error"
+	if err.Error() != expected {
+		t.Errorf("Error() = %q, want %q", err.Error(), expected)
+	}
+}
+
+func TestNewInvalidArgumentError(t *testing.T) {
+	err := error(storage.NewInvalidArgumentError(storage.EntityClients, "must not be empty", "id"))
+
+	if !errors.Is(err, storage.ErrInvalidArgument) {
+		t.Error("expected error to match storage.ErrInvalidArgument")
+	}
+
+	expected := "clients: invalid argument (id): must not be empty"
+	if err.Error() != expected {
+		t.Errorf("Error() = %q, want %q", err.Error(), expected)
+	}
+}
@@ -91,3 +91,12 @@ func TestStore_ImplementsFositePkcePKCERequestStorageInterface(t *testing.T) {
 		t.Error("Store does not implement interface pkce.PKCERequestStorage")
 	}
 }
+
+func TestStore_ImplementsRFC7523KeyStorage(t *testing.T) {
+	r := &Store{}
+
+	var i interface{} = r
+	if _, ok := i.(RFC7523KeyStorage); !ok {
+		t.Error("Store does not implement interface RFC7523KeyStorage")
+	}
+}
@@ -0,0 +1,81 @@
+package storage
+
+import (
+	// Standard Library Imports
+	"fmt"
+	"strings"
+)
+
+// TenantStatus provides the lifecycle status of a tenant.
+type TenantStatus string
+
+const (
+	// TenantStatusActive reports the tenant is in use.
+	TenantStatusActive TenantStatus = "active"
+
+	// TenantStatusSuspended reports the tenant has been temporarily
+	// suspended, for example, for non-payment.
+	TenantStatusSuspended TenantStatus = "suspended"
+
+	// TenantStatusArchived reports the tenant is no longer in use, but is
+	// retained for auditing.
+	TenantStatusArchived TenantStatus = "archived"
+)
+
+// IsValid returns whether the status is a known tenant status.
+func (s TenantStatus) IsValid() bool {
+	switch s {
+	case TenantStatusActive, TenantStatusSuspended, TenantStatusArchived:
+		return true
+	}
+
+	return false
+}
+
+// Tenant provides the structure of a tenant, which the AllowedTenantAccess of
+// clients and users reference by ID.
+type Tenant struct {
+	//// Tenant Meta
+	// ID is the unique identifier of the tenant.
+	ID string `bson:"id" json:"id" xml:"id"`
+
+	// CreateTime is when the resource was created in seconds from the epoch.
+	CreateTime int64 `bson:"createTime" json:"createTime" xml:"createTime"`
+
+	// UpdateTime is the last time the resource was modified in seconds from
+	// the epoch.
+	UpdateTime int64 `bson:"updateTime" json:"updateTime" xml:"updateTime"`
+
+	// Status contains the lifecycle status of the tenant. Defaults to
+	// TenantStatusActive on create.
+	Status TenantStatus `bson:"status" json:"status" xml:"status"`
+
+	//// Tenant Content
+	// Name contains a human-readable name of the tenant.
+	Name string `bson:"name" json:"name" xml:"name"`
+
+	// AllowedRegions contains the regions the tenant's data and clients are
+	// permitted to operate in.
+	AllowedRegions []string `bson:"allowedRegions" json:"allowedRegions,omitempty" xml:"allowedRegions,omitempty"`
+
+	// DefaultScopes contains the scopes granted by default to clients and
+	// users created within the tenant.
+	DefaultScopes []string `bson:"defaultScopes" json:"defaultScopes,omitempty" xml:"defaultScopes,omitempty"`
+}
+
+// IsActive returns whether the tenant is active.
+func (t Tenant) IsActive() bool {
+	return t.Status == TenantStatusActive
+}
+
+// Validate returns an invalid argument error if the tenant is not valid.
+func (t Tenant) Validate() error {
+	if strings.TrimSpace(t.Name) == "" {
+		return NewInvalidArgumentError(EntityTenants, "name is required", "name")
+	}
+	if !t.Status.IsValid() {
+		return NewInvalidArgumentError(EntityTenants, fmt.Sprintf("unknown status %q", t.Status), "status")
+	}
+
+	return nil
+}
@@ -0,0 +1,73 @@
+package storage
+
+import (
+	// Standard Library Imports
+	"context"
+	"fmt"
+	"strings"
+)
+
+// TenantManager provides a generic interface to tenants in order to build a
+// Datastore backend.
+type TenantManager interface {
+	Configurer
+	TenantStorer
+}
+
+// TenantStorer provides a definition of specific methods that are required to
+// store a Tenant in a data store.
+type TenantStorer interface {
+	List(ctx context.Context, filter ListTenantsRequest) ([]Tenant, error)
+	Create(ctx context.Context, tenant Tenant) (Tenant, error)
+	Get(ctx context.Context, tenantID string) (Tenant, error)
+	Update(ctx context.Context, tenantID string, tenant Tenant) (Tenant, error)
+	Delete(ctx context.Context, tenantID string) error
+}
+
+// ListTenantsRequest enables filtering stored Tenant entities.
+type ListTenantsRequest struct {
+	// IDs filters tenants to those with one of the listed IDs.
+	IDs []string `json:"ids" xml:"ids"`
+	// Name filters tenants based on name.
+	Name string `json:"name" xml:"name"`
+	// Status filters tenants based on status.
+	Status TenantStatus `json:"status" xml:"status"`
+	// AllowedRegion filters tenants based on an Allowed Region.
+	AllowedRegion string `json:"allowedRegion" xml:"allowedRegion"`
+}
+
+// ValidateTenantReferences returns an invalid argument error if any of the
+// tenant IDs, referenced by the AllowedTenantAccess of the given entity, don't
+// exist.
+func ValidateTenantReferences(ctx context.Context, tenants TenantStorer, entityName string, tenantIDs []string) error {
+	if tenants == nil || len(tenantIDs) == 0 {
+		return nil
+	}
+
+	found, err := tenants.List(ctx, ListTenantsRequest{IDs: tenantIDs})
+	if err != nil {
+		return err
+	}
+
+	exists := make(map[string]bool, len(found))
+	for _, tenant := range found {
+		exists[tenant.ID] = true
+	}
+
+	var missing []string
+	for _, tenantID := range tenantIDs {
+		if !exists[tenantID] {
+			missing = append(missing, tenantID)
+		}
+	}
+	if len(missing) > 0 {
+		return NewError(
+			ErrInvalidArgument,
+			entityName,
+			fmt.Errorf("unknown tenants: %s", strings.Join(missing, ", ")),
+			"allowedTenantAccess",
+		)
+	}
+
+	return nil
+}
@@ -0,0 +1,1165 @@
+package storage
+
+import (
+	// Standard Library Imports
+	"context"
+	"errors"
+	"time"
+
+	// External Imports
+	"github.com/ory/fosite"
+)
+
+// tenantContextKey provides the context key the current tenant is bound to.
+type tenantContextKey struct{}
+
+// TenantToContext binds the current tenant to the context, in order to scope
+// a tenant store to the tenant.
+func TenantToContext(ctx context.Context, tenantID string) context.Context {
+	return context.WithValue(ctx, tenantContextKey{}, tenantID)
+}
+
+// ContextToTenant returns the current tenant bound to the context, if any.
+func ContextToTenant(ctx context.Context) (tenantID string, ok bool) {
+	tenantID, ok = ctx.Value(tenantContextKey{}).(string)
+	return tenantID, ok && tenantID != ""
+}
+
+// ErrTenantRequired is wrapped by the errors returned from a tenant store if
+// no tenant is bound to the context.
+var ErrTenantRequired = errors.New("tenant required")
+
+// NewTenantStore wraps the store, scoping clients, groups and users to the
+// tenant bound to the context via TenantToContext:
+// - Users have access to the tenant if their AllowedTenantAccess, or that of
+//   one of their groups, includes the tenant. List and Search rely on the
+//   backend to match group members when filtering by AllowedTenantAccess.
+// - List and Search return only the clients and users with access to the
+//   tenant.
+// - Get, Update, Delete and the utility functions report clients and users
+//   without access to the tenant as not found.
+// - Create, Update and Migrate reject clients and users without access to
+//   the tenant, or whose AllowedTenantAccess includes any other tenant. Users
+//   are also rejected if their groups grant access to any other tenant.
+//   Clients and users shared between tenants must be managed via the
+//   unscoped store.
+// - Groups are only visible, and can only be created or updated, if their
+//   AllowedTenantAccess is exactly the tenant, so that groups can't be used
+//   to grant access to other tenants.
+// - GetClient and Authenticate fail for clients and users without access to
+//   the tenant, as if they didn't exist.
+// - Bulk operations apply the same rules per item. Bulk scope grants and
+//   removals only modify the clients and users with access to the tenant.
+//
+// Every call fails closed, with an error wrapping ErrPreconditionFailed and
+// ErrTenantRequired, if no tenant is bound to the context. Consents, denied
+// JTIs, device codes, issuer trusts, login sessions, requests, scopes and
+// tenants themselves are not tenant scoped.
+//
+// Backends may resolve clients and users internally, for example, when
+// loading the client of a stored request. Only calls made via the returned
+// store are scoped.
+func NewTenantStore(store Store) Store {
+	users := &tenantUserManager{
+		users:  store.UserManager,
+		groups: store.GroupManager,
+	}
+
+	return Store{
+		ClientManager:       &tenantClientManager{clients: store.ClientManager},
+		ConsentManager:      store.ConsentManager,
+		DeniedJTIManager:    store.DeniedJTIManager,
+		DeviceCodeManager:   store.DeviceCodeManager,
+		GroupManager:        &tenantGroupManager{groups: store.GroupManager},
+		IssuerTrustManager:  store.IssuerTrustManager,
+		LoginSessionManager: store.LoginSessionManager,
+		RequestManager: &tenantRequestManager{
+			requests: store.RequestManager,
+			users:    users,
+		},
+		ScopeManager:  store.ScopeManager,
+		TenantManager: store.TenantManager,
+		UserManager:   users,
+	}
+}
+
+// requireTenant returns the tenant bound to the context.
+func requireTenant(ctx context.Context, entityName string) (string, error) {
+	tenantID, ok := ContextToTenant(ctx)
+	if !ok {
+		return "", NewError(ErrPreconditionFailed, entityName, ErrTenantRequired)
+	}
+
+	return tenantID, nil
+}
+
+// onlyTenant returns true if every tenant in the list is the given tenant.
+func onlyTenant(tenants []string, tenantID string) bool {
+	for _, tenant := range tenants {
+		if tenant != tenantID {
+			return false
+		}
+	}
+
+	return true
+}
+
+// tenantClientManager scopes a client manager to the current tenant.
+type tenantClientManager struct {
+	clients ClientManager
+}
+
+// allowed returns the client, if it has access to the current tenant,
+// otherwise not found.
+func (c *tenantClientManager) allowed(ctx context.Context, clientID string) (Client, error) {
+	tenantID, err := requireTenant(ctx, EntityClients)
+	if err != nil {
+		return Client{}, err
+	}
+
+	client, err := c.clients.Get(ctx, clientID)
+	if err != nil {
+		return Client{}, err
+	}
+	if !contains(client.AllowedTenantAccess, tenantID) {
+		return Client{}, NewNotFoundError(EntityClients)
+	}
+
+	return client, nil
+}
+
+// includesTenant returns invalid argument if the client doesn't include the
+// current tenant, or includes any other tenant.
+func (c *tenantClientManager) includesTenant(ctx context.Context, client Client) error {
+	tenantID, err := requireTenant(ctx, EntityClients)
+	if err != nil {
+		return err
+	}
+	if !contains(client.AllowedTenantAccess, tenantID) {
+		return NewInvalidArgumentError(EntityClients, "allowed tenant access must include the current tenant", "allowedTenantAccess")
+	}
+	if !onlyTenant(client.AllowedTenantAccess, tenantID) {
+		return NewInvalidArgumentError(EntityClients, "allowed tenant access must not include other tenants", "allowedTenantAccess")
+	}
+
+	return nil
+}
+
+// Configure configures the underlying client manager. Configure is not tenant
+// scoped.
+func (c *tenantClientManager) Configure(ctx context.Context) error {
+	return c.clients.Configure(ctx)
+}
+
+// ClientAssertionJWTValid implements fosite.Storage. Client assertion JTIs are
+// not tenant scoped.
+func (c *tenantClientManager) ClientAssertionJWTValid(ctx context.Context, jti string) error {
+	return c.clients.ClientAssertionJWTValid(ctx, jti)
+}
+
+// SetClientAssertionJWT implements fosite.Storage. Client assertion JTIs are
+// not tenant scoped.
+func (c *tenantClientManager) SetClientAssertionJWT(ctx context.Context, jti string, exp time.Time) error {
+	return c.clients.SetClientAssertionJWT(ctx, jti, exp)
+}
+
+// List returns the clients with access to the current tenant.
+func (c *tenantClientManager) List(ctx context.Context, filter ListClientsRequest) ([]Client, error) {
+	tenantID, err := requireTenant(ctx, EntityClients)
+	if err != nil {
+		return nil, err
+	}
+	if filter.AllowedTenantAccess != "" && filter.AllowedTenantAccess != tenantID {
+		return nil, nil
+	}
+
+	filter.AllowedTenantAccess = tenantID
+	return c.clients.List(ctx, filter)
+}
+
+// Each calls fn with each client with access to the current tenant.
+func (c *tenantClientManager) Each(ctx context.Context, filter ListClientsRequest, fn func(client Client) error) error {
+	tenantID, err := requireTenant(ctx, EntityClients)
+	if err != nil {
+		return err
+	}
+	if filter.AllowedTenantAccess != "" && filter.AllowedTenantAccess != tenantID {
+		return nil
+	}
+
+	filter.AllowedTenantAccess = tenantID
+	return eachClient(ctx, c.clients, filter, fn)
+}
+
+// Create creates the client, if it has access to the current tenant.
+func (c *tenantClientManager) Create(ctx context.Context, client Client) (Client, error) {
+	if err := c.includesTenant(ctx, client); err != nil {
+		return Client{}, err
+	}
+
+	return c.clients.Create(ctx, client)
+}
+
+// Get returns the client, if it has access to the current tenant.
+func (c *tenantClientManager) Get(ctx context.Context, clientID string) (Client, error) {
+	return c.allowed(ctx, clientID)
+}
+
+// GetClient implements fosite.Storage, returning the client, if it has
+// access to the current tenant.
+func (c *tenantClientManager) GetClient(ctx context.Context, clientID string) (fosite.Client, error) {
+	if _, err := c.allowed(ctx, clientID); err != nil {
+		return nil, err
+	}
+
+	return c.clients.GetClient(ctx, clientID)
+}
+
+// Update updates the client, if it has access to the current tenant, and
+// retains access to the current tenant.
+func (c *tenantClientManager) Update(ctx context.Context, clientID string, client Client) (Client, error) {
+	if _, err := c.allowed(ctx, clientID); err != nil {
+		return Client{}, err
+	}
+	if err := c.includesTenant(ctx, client); err != nil {
+		return Client{}, err
+	}
+
+	return c.clients.Update(ctx, clientID, client)
+}
+
+// Delete deletes the client, if it has access to the current tenant.
+func (c *tenantClientManager) Delete(ctx context.Context, clientID string) error {
+	if _, err := c.allowed(ctx, clientID); err != nil {
+		return err
+	}
+
+	return c.clients.Delete(ctx, clientID)
+}
+
+// Authenticate authenticates the client, if it has access to the current
+// tenant.
+func (c *tenantClientManager) Authenticate(ctx context.Context, clientID string, secret string) (Client, error) {
+	if _, err := c.allowed(ctx, clientID); err != nil {
+		return Client{}, err
+	}
+
+	return c.clients.Authenticate(ctx, clientID, secret)
+}
+
+// AuthenticateMigration authenticates the client, if it has access to the
+// current tenant.
+func (c *tenantClientManager) AuthenticateMigration(ctx context.Context, currentAuth AuthClientFunc, clientID string, secret string) (Client, error) {
+	if _, err := c.allowed(ctx, clientID); err != nil {
+		return Client{}, err
+	}
+
+	return c.clients.AuthenticateMigration(ctx, currentAuth, clientID, secret)
+}
+
+// GrantScopes grants scopes to the client, if it has access to the current
+// tenant.
+func (c *tenantClientManager) GrantScopes(ctx context.Context, clientID string, scopes []string) (Client, error) {
+	if _, err := c.allowed(ctx, clientID); err != nil {
+		return Client{}, err
+	}
+
+	return c.clients.GrantScopes(ctx, clientID, scopes)
+}
+
+// RemoveScopes removes scopes from the client, if it has access to the
+// current tenant.
+func (c *tenantClientManager) RemoveScopes(ctx context.Context, clientID string, scopes []string) (Client, error) {
+	if _, err := c.allowed(ctx, clientID); err != nil {
+		return Client{}, err
+	}
+
+	return c.clients.RemoveScopes(ctx, clientID, scopes)
+}
+
+// BulkCreate creates the clients with access to the current tenant, reporting
+// the others as invalid.
+func (c *tenantClientManager) BulkCreate(ctx context.Context, clients []Client) ([]BulkResult, error) {
+	if _, err := requireTenant(ctx, EntityClients); err != nil {
+		return nil, err
+	}
+
+	results := make([]BulkResult, len(clients))
+	var passed []int
+	var permitted []Client
+	for i, client := range clients {
+		results[i].ID = client.ID
+		if err := c.includesTenant(ctx, client); err != nil {
+			results[i].Err = err
+			continue
+		}
+
+		passed = append(passed, i)
+		permitted = append(permitted, client)
+	}
+	if len(permitted) == 0 {
+		return results, nil
+	}
+
+	passedResults, err := c.clients.BulkCreate(ctx, permitted)
+	if err != nil {
+		return nil, err
+	}
+
+	return mergeBulkResults(results, passed, passedResults), nil
+}
+
+// BulkUpdate updates the clients with access to the current tenant, that
+// retain access to the current tenant, reporting the others as not found, or
+// invalid.
+func (c *tenantClientManager) BulkUpdate(ctx context.Context, clients []Client) ([]BulkResult, error) {
+	if _, err := requireTenant(ctx, EntityClients); err != nil {
+		return nil, err
+	}
+
+	results := make([]BulkResult, len(clients))
+	var passed []int
+	var permitted []Client
+	for i, client := range clients {
+		results[i].ID = client.ID
+		if _, err := c.allowed(ctx, client.ID); err != nil {
+			results[i].Err = err
+			continue
+		}
+		if err := c.includesTenant(ctx, client); err != nil {
+			results[i].Err = err
+			continue
+		}
+
+		passed = append(passed, i)
+		permitted = append(permitted, client)
+	}
+	if len(permitted) == 0 {
+		return results, nil
+	}
+
+	passedResults, err := c.clients.BulkUpdate(ctx, permitted)
+	if err != nil {
+		return nil, err
+	}
+
+	return mergeBulkResults(results, passed, passedResults), nil
+}
+
+// BulkDelete deletes the clients with access to the current tenant, reporting
+// the others as not found.
+func (c *tenantClientManager) BulkDelete(ctx context.Context, clientIDs []string) ([]BulkResult, error) {
+	if _, err := requireTenant(ctx, EntityClients); err != nil {
+		return nil, err
+	}
+
+	results := make([]BulkResult, len(clientIDs))
+	var passed []int
+	var permitted []string
+	for i, clientID := range clientIDs {
+		results[i].ID = clientID
+		if _, err := c.allowed(ctx, clientID); err != nil {
+			results[i].Err = err
+			continue
+		}
+
+		passed = append(passed, i)
+		permitted = append(permitted, clientID)
+	}
+	if len(permitted) == 0 {
+		return results, nil
+	}
+
+	passedResults, err := c.clients.BulkDelete(ctx, permitted)
+	if err != nil {
+		return nil, err
+	}
+
+	return mergeBulkResults(results, passed, passedResults), nil
+}
+
+// BulkGrantScopes grants scopes to the clients matching the filter, with
+// access to the current tenant.
+func (c *tenantClientManager) BulkGrantScopes(ctx context.Context, filter ListClientsRequest, scopes []string) (int64, error) {
+	tenantID, err := requireTenant(ctx, EntityClients)
+	if err != nil {
+		return 0, err
+	}
+	if err = filter.ValidateBulk(); err != nil {
+		return 0, err
+	}
+	if filter.AllowedTenantAccess != "" && filter.AllowedTenantAccess != tenantID {
+		return 0, nil
+	}
+
+	filter.AllowedTenantAccess = tenantID
+	return c.clients.BulkGrantScopes(ctx, filter, scopes)
+}
+
+// BulkRemoveScopes removes scopes from the clients matching the filter, with
+// access to the current tenant.
+func (c *tenantClientManager) BulkRemoveScopes(ctx context.Context, filter ListClientsRequest, scopes []string) (int64, error) {
+	tenantID, err := requireTenant(ctx, EntityClients)
+	if err != nil {
+		return 0, err
+	}
+	if err = filter.ValidateBulk(); err != nil {
+		return 0, err
+	}
+	if filter.AllowedTenantAccess != "" && filter.AllowedTenantAccess != tenantID {
+		return 0, nil
+	}
+
+	filter.AllowedTenantAccess = tenantID
+	return c.clients.BulkRemoveScopes(ctx, filter, scopes)
+}
+
+// Migrate stores the client, if it has access to the current tenant. An
+// existing client without access to the current tenant is reported as a
+// conflict, rather than being overwritten.
+func (c *tenantClientManager) Migrate(ctx context.Context, client Client) (Client, error) {
+	if err := c.includesTenant(ctx, client); err != nil {
+		return Client{}, err
+	}
+
+	if client.ID != "" {
+		_, err := c.allowed(ctx, client.ID)
+		if err == nil {
+			return c.clients.Migrate(ctx, client)
+		}
+		if !errors.Is(err, ErrNotFound) {
+			return Client{}, err
+		}
+
+		if _, err = c.clients.Get(ctx, client.ID); err == nil {
+			return Client{}, NewConflictError(EntityClients, ErrResourceExists, "id")
+		}
+	}
+
+	return c.clients.Migrate(ctx, client)
+}
+
+// tenantUserManager scopes a user manager to the current tenant.
+type tenantUserManager struct {
+	users UserManager
+
+	// groups, if set, resolves the tenant access users inherit from their
+	// groups.
+	groups GroupStorer
+}
+
+// hasAccess returns true if the user has access to the tenant, either
+// directly, or via their groups.
+func (u *tenantUserManager) hasAccess(ctx context.Context, user User, tenantID string) (bool, error) {
+	permissions, err := EffectivePermissions(ctx, u.groups, user)
+	if err != nil {
+		return false, err
+	}
+
+	return contains(permissions.AllowedTenantAccess, tenantID), nil
+}
+
+// permitted returns not found if the user doesn't have access to the current
+// tenant.
+func (u *tenantUserManager) permitted(ctx context.Context, user User) (User, error) {
+	tenantID, err := requireTenant(ctx, EntityUsers)
+	if err != nil {
+		return User{}, err
+	}
+
+	ok, err := u.hasAccess(ctx, user, tenantID)
+	if err != nil {
+		return User{}, err
+	}
+	if !ok {
+		return User{}, NewNotFoundError(EntityUsers)
+	}
+
+	return user, nil
+}
+
+// allowed returns the user, if they have access to the current tenant,
+// otherwise not found.
+func (u *tenantUserManager) allowed(ctx context.Context, userID string) (User, error) {
+	if _, err := requireTenant(ctx, EntityUsers); err != nil {
+		return User{}, err
+	}
+
+	user, err := u.users.Get(ctx, userID)
+	if err != nil {
+		return User{}, err
+	}
+
+	return u.permitted(ctx, user)
+}
+
+// allowedByUsername returns the user, if they have access to the current
+// tenant, otherwise not found.
+func (u *tenantUserManager) allowedByUsername(ctx context.Context, username string) (User, error) {
+	if _, err := requireTenant(ctx, EntityUsers); err != nil {
+		return User{}, err
+	}
+
+	user, err := u.users.GetByUsername(ctx, username)
+	if err != nil {
+		return User{}, err
+	}
+
+	return u.permitted(ctx, user)
+}
+
+// allowedByEmail returns the user, if they have access to the current tenant,
+// otherwise not found.
+func (u *tenantUserManager) allowedByEmail(ctx context.Context, email string) (User, error) {
+	if _, err := requireTenant(ctx, EntityUsers); err != nil {
+		return User{}, err
+	}
+
+	user, err := u.users.GetByEmail(ctx, email)
+	if err != nil {
+		return User{}, err
+	}
+
+	return u.permitted(ctx, user)
+}
+
+// includesTenant returns invalid argument if the user doesn't have access to
+// the current tenant, directly or via their groups, or has access to any
+// other tenant.
+func (u *tenantUserManager) includesTenant(ctx context.Context, user User) error {
+	tenantID, err := requireTenant(ctx, EntityUsers)
+	if err != nil {
+		return err
+	}
+
+	permissions, err := EffectivePermissions(ctx, u.groups, user)
+	if err != nil {
+		return err
+	}
+	if !contains(permissions.AllowedTenantAccess, tenantID) {
+		return NewInvalidArgumentError(EntityUsers, "allowed tenant access must include the current tenant", "allowedTenantAccess")
+	}
+	if !onlyTenant(user.AllowedTenantAccess, tenantID) {
+		return NewInvalidArgumentError(EntityUsers, "allowed tenant access must not include other tenants", "allowedTenantAccess")
+	}
+	if !onlyTenant(permissions.AllowedTenantAccess, tenantID) {
+		return NewInvalidArgumentError(EntityUsers, "groups must not grant access to other tenants", "groups")
+	}
+
+	return nil
+}
+
+// Configure configures the underlying user manager. Configure is not tenant
+// scoped.
+func (u *tenantUserManager) Configure(ctx context.Context) error {
+	return u.users.Configure(ctx)
+}
+
+// List returns the users with access to the current tenant.
+func (u *tenantUserManager) List(ctx context.Context, filter ListUsersRequest) ([]User, error) {
+	tenantID, err := requireTenant(ctx, EntityUsers)
+	if err != nil {
+		return nil, err
+	}
+	if filter.AllowedTenantAccess != "" && filter.AllowedTenantAccess != tenantID {
+		return nil, nil
+	}
+
+	filter.AllowedTenantAccess = tenantID
+	return u.users.List(ctx, filter)
+}
+
+// Each calls fn with each user with access to the current tenant.
+func (u *tenantUserManager) Each(ctx context.Context, filter ListUsersRequest, fn func(user User) error) error {
+	tenantID, err := requireTenant(ctx, EntityUsers)
+	if err != nil {
+		return err
+	}
+	if filter.AllowedTenantAccess != "" && filter.AllowedTenantAccess != tenantID {
+		return nil
+	}
+
+	filter.AllowedTenantAccess = tenantID
+	return eachUser(ctx, u.users, filter, fn)
+}
+
+// Search searches the users with access to the current tenant.
+func (u *tenantUserManager) Search(ctx context.Context, request SearchUsersRequest) ([]User, error) {
+	tenantID, err := requireTenant(ctx, EntityUsers)
+	if err != nil {
+		return nil, err
+	}
+	if request.AllowedTenantAccess != "" && request.AllowedTenantAccess != tenantID {
+		return nil, nil
+	}
+
+	request.AllowedTenantAccess = tenantID
+	return u.users.Search(ctx, request)
+}
+
+// Create creates the user, if they have access to the current tenant.
+func (u *tenantUserManager) Create(ctx context.Context, user User) (User, error) {
+	if err := u.includesTenant(ctx, user); err != nil {
+		return User{}, err
+	}
+
+	return u.users.Create(ctx, user)
+}
+
+// Get returns the user, if they have access to the current tenant.
+func (u *tenantUserManager) Get(ctx context.Context, userID string) (User, error) {
+	return u.allowed(ctx, userID)
+}
+
+// GetByUsername returns the user, if they have access to the current tenant.
+func (u *tenantUserManager) GetByUsername(ctx context.Context, username string) (User, error) {
+	return u.allowedByUsername(ctx, username)
+}
+
+// GetByEmail returns the user, if they have access to the current tenant.
+func (u *tenantUserManager) GetByEmail(ctx context.Context, email string) (User, error) {
+	return u.allowedByEmail(ctx, email)
+}
+
+// Update updates the user, if they have access to the current tenant, and
+// retain access to the current tenant.
+func (u *tenantUserManager) Update(ctx context.Context, userID string, user User) (User, error) {
+	if _, err := u.allowed(ctx, userID); err != nil {
+		return User{}, err
+	}
+	if err := u.includesTenant(ctx, user); err != nil {
+		return User{}, err
+	}
+
+	return u.users.Update(ctx, userID, user)
+}
+
+// Delete deletes the user, if they have access to the current tenant.
+func (u *tenantUserManager) Delete(ctx context.Context, userID string) error {
+	if _, err := u.allowed(ctx, userID); err != nil {
+		return err
+	}
+
+	return u.users.Delete(ctx, userID)
+}
+
+// Authenticate authenticates the user, if they have access to the current
+// tenant.
+func (u *tenantUserManager) Authenticate(ctx context.Context, username string, password string) (User, error) {
+	if _, err := u.allowedByUsername(ctx, username); err != nil {
+		return User{}, err
+	}
+
+	return u.users.Authenticate(ctx, username, password)
+}
+
+// AuthenticateByID authenticates the user, if they have access to the
+// current tenant.
+func (u *tenantUserManager) AuthenticateByID(ctx context.Context, userID string, password string) (User, error) {
+	if _, err := u.allowed(ctx, userID); err != nil {
+		return User{}, err
+	}
+
+	return u.users.AuthenticateByID(ctx, userID, password)
+}
+
+// AuthenticateByUsername authenticates the user, if they have access to the
+// current tenant.
+func (u *tenantUserManager) AuthenticateByUsername(ctx context.Context, username string, password string) (User, error) {
+	if _, err := u.allowedByUsername(ctx, username); err != nil {
+		return User{}, err
+	}
+
+	return u.users.AuthenticateByUsername(ctx, username, password)
+}
+
+// AuthenticateByEmail authenticates the user, if they have access to the
+// current tenant.
+func (u *tenantUserManager) AuthenticateByEmail(ctx context.Context, email string, password string) (User, error) {
+	if _, err := u.allowedByEmail(ctx, email); err != nil {
+		return User{}, err
+	}
+
+	return u.users.AuthenticateByEmail(ctx, email, password)
+}
+
+// AuthenticateMigration authenticates the user, if they have access to the
+// current tenant.
+func (u *tenantUserManager) AuthenticateMigration(ctx context.Context, currentAuth AuthUserFunc, userID string, password string) (User, error) {
+	if _, err := u.allowed(ctx, userID); err != nil {
+		return User{}, err
+	}
+
+	return u.users.AuthenticateMigration(ctx, currentAuth, userID, password)
+}
+
+// GrantScopes grants scopes to the user, if they have access to the current
+// tenant.
+func (u *tenantUserManager) GrantScopes(ctx context.Context, userID string, scopes []string) (User, error) {
+	if _, err := u.allowed(ctx, userID); err != nil {
+		return User{}, err
+	}
+
+	return u.users.GrantScopes(ctx, userID, scopes)
+}
+
+// RemoveScopes removes scopes from the user, if they have access to the
+// current tenant.
+func (u *tenantUserManager) RemoveScopes(ctx context.Context, userID string, scopes []string) (User, error) {
+	if _, err := u.allowed(ctx, userID); err != nil {
+		return User{}, err
+	}
+
+	return u.users.RemoveScopes(ctx, userID, scopes)
+}
+
+// BulkCreate creates the users with access to the current tenant, reporting
+// the others as invalid.
+func (u *tenantUserManager) BulkCreate(ctx context.Context, users []User) ([]BulkResult, error) {
+	if _, err := requireTenant(ctx, EntityUsers); err != nil {
+		return nil, err
+	}
+
+	results := make([]BulkResult, len(users))
+	var passed []int
+	var permitted []User
+	for i, user := range users {
+		results[i].ID = user.ID
+		if err := u.includesTenant(ctx, user); err != nil {
+			results[i].Err = err
+			continue
+		}
+
+		passed = append(passed, i)
+		permitted = append(permitted, user)
+	}
+	if len(permitted) == 0 {
+		return results, nil
+	}
+
+	passedResults, err := u.users.BulkCreate(ctx, permitted)
+	if err != nil {
+		return nil, err
+	}
+
+	return mergeBulkResults(results, passed, passedResults), nil
+}
+
+// BulkUpdate updates the users with access to the current tenant, that
+// retain access to the current tenant, reporting the others as not found, or
+// invalid.
+func (u *tenantUserManager) BulkUpdate(ctx context.Context, users []User) ([]BulkResult, error) {
+	if _, err := requireTenant(ctx, EntityUsers); err != nil {
+		return nil, err
+	}
+
+	results := make([]BulkResult, len(users))
+	var passed []int
+	var permitted []User
+	for i, user := range users {
+		results[i].ID = user.ID
+		if _, err := u.allowed(ctx, user.ID); err != nil {
+			results[i].Err = err
+			continue
+		}
+		if err := u.includesTenant(ctx, user); err != nil {
+			results[i].Err = err
+			continue
+		}
+
+		passed = append(passed, i)
+		permitted = append(permitted, user)
+	}
+	if len(permitted) == 0 {
+		return results, nil
+	}
+
+	passedResults, err := u.users.BulkUpdate(ctx, permitted)
+	if err != nil {
+		return nil, err
+	}
+
+	return mergeBulkResults(results, passed, passedResults), nil
+}
+
+// BulkDelete deletes the users with access to the current tenant, reporting
+// the others as not found.
+func (u *tenantUserManager) BulkDelete(ctx context.Context, userIDs []string) ([]BulkResult, error) {
+	if _, err := requireTenant(ctx, EntityUsers); err != nil {
+		return nil, err
+	}
+
+	results := make([]BulkResult, len(userIDs))
+	var passed []int
+	var permitted []string
+	for i, userID := range userIDs {
+		results[i].ID = userID
+		if _, err := u.allowed(ctx, userID); err != nil {
+			results[i].Err = err
+			continue
+		}
+
+		passed = append(passed, i)
+		permitted = append(permitted, userID)
+	}
+	if len(permitted) == 0 {
+		return results, nil
+	}
+
+	passedResults, err := u.users.BulkDelete(ctx, permitted)
+	if err != nil {
+		return nil, err
+	}
+
+	return mergeBulkResults(results, passed, passedResults), nil
+}
+
+// BulkGrantScopes grants scopes to the users matching the filter, with
+// access to the current tenant.
+func (u *tenantUserManager) BulkGrantScopes(ctx context.Context, filter ListUsersRequest, scopes []string) (int64, error) {
+	tenantID, err := requireTenant(ctx, EntityUsers)
+	if err != nil {
+		return 0, err
+	}
+	if err = filter.ValidateBulk(); err != nil {
+		return 0, err
+	}
+	if filter.AllowedTenantAccess != "" && filter.AllowedTenantAccess != tenantID {
+		return 0, nil
+	}
+
+	filter.AllowedTenantAccess = tenantID
+	return u.users.BulkGrantScopes(ctx, filter, scopes)
+}
+
+// BulkRemoveScopes removes scopes from the users matching the filter, with
+// access to the current tenant.
+func (u *tenantUserManager) BulkRemoveScopes(ctx context.Context, filter ListUsersRequest, scopes []string) (int64, error) {
+	tenantID, err := requireTenant(ctx, EntityUsers)
+	if err != nil {
+		return 0, err
+	}
+	if err = filter.ValidateBulk(); err != nil {
+		return 0, err
+	}
+	if filter.AllowedTenantAccess != "" && filter.AllowedTenantAccess != tenantID {
+		return 0, nil
+	}
+
+	filter.AllowedTenantAccess = tenantID
+	return u.users.BulkRemoveScopes(ctx, filter, scopes)
+}
+
+// Migrate stores the user, if they have access to the current tenant. An
+// existing user without access to the current tenant is reported as a
+// conflict, rather than being overwritten.
+func (u *tenantUserManager) Migrate(ctx context.Context, user User) (User, error) {
+	if err := u.includesTenant(ctx, user); err != nil {
+		return User{}, err
+	}
+
+	if user.ID != "" {
+		_, err := u.allowed(ctx, user.ID)
+		if err == nil {
+			return u.users.Migrate(ctx, user)
+		}
+		if !errors.Is(err, ErrNotFound) {
+			return User{}, err
+		}
+
+		if _, err = u.users.Get(ctx, user.ID); err == nil {
+			return User{}, NewConflictError(EntityUsers, ErrResourceExists, "id")
+		}
+	}
+
+	return u.users.Migrate(ctx, user)
+}
+
+// tenantGroupManager scopes a group manager to the current tenant. Only
+// groups granting access to exactly the current tenant are visible, or can be
+// stored, so that members can't be granted access to other tenants.
+type tenantGroupManager struct {
+	groups GroupManager
+}
+
+// inTenant returns true if the group grants access to exactly the tenant.
+func inTenant(group Group, tenantID string) bool {
+	return contains(group.AllowedTenantAccess, tenantID) && onlyTenant(group.AllowedTenantAccess, tenantID)
+}
+
+// allowed returns the group, if it grants access to exactly the current
+// tenant, otherwise not found.
+func (g *tenantGroupManager) allowed(ctx context.Context, groupID string) (Group, error) {
+	tenantID, err := requireTenant(ctx, EntityGroups)
+	if err != nil {
+		return Group{}, err
+	}
+
+	group, err := g.groups.Get(ctx, groupID)
+	if err != nil {
+		return Group{}, err
+	}
+	if !inTenant(group, tenantID) {
+		return Group{}, NewNotFoundError(EntityGroups)
+	}
+
+	return group, nil
+}
+
+// includesTenant returns invalid argument if the group doesn't grant access
+// to the current tenant, or grants access to any other tenant.
+func (g *tenantGroupManager) includesTenant(ctx context.Context, group Group) error {
+	tenantID, err := requireTenant(ctx, EntityGroups)
+	if err != nil {
+		return err
+	}
+	if !contains(group.AllowedTenantAccess, tenantID) {
+		return NewInvalidArgumentError(EntityGroups, "allowed tenant access must include the current tenant", "allowedTenantAccess")
+	}
+	if !onlyTenant(group.AllowedTenantAccess, tenantID) {
+		return NewInvalidArgumentError(EntityGroups, "allowed tenant access must not include other tenants", "allowedTenantAccess")
+	}
+
+	return nil
+}
+
+// Configure configures the underlying group manager. Configure is not tenant
+// scoped.
+func (g *tenantGroupManager) Configure(ctx context.Context) error {
+	return g.groups.Configure(ctx)
+}
+
+// List returns the groups granting access to exactly the current tenant.
+func (g *tenantGroupManager) List(ctx context.Context, filter ListGroupsRequest) ([]Group, error) {
+	tenantID, err := requireTenant(ctx, EntityGroups)
+	if err != nil {
+		return nil, err
+	}
+	if filter.AllowedTenantAccess != "" && filter.AllowedTenantAccess != tenantID {
+		return nil, nil
+	}
+
+	filter.AllowedTenantAccess = tenantID
+	groups, err := g.groups.List(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	var results []Group
+	for _, group := range groups {
+		if inTenant(group, tenantID) {
+			results = append(results, group)
+		}
+	}
+
+	return results, nil
+}
+
+// Create creates the group, if it grants access to exactly the current
+// tenant.
+func (g *tenantGroupManager) Create(ctx context.Context, group Group) (Group, error) {
+	if err := g.includesTenant(ctx, group); err != nil {
+		return Group{}, err
+	}
+
+	return g.groups.Create(ctx, group)
+}
+
+// Get returns the group, if it grants access to exactly the current tenant.
+func (g *tenantGroupManager) Get(ctx context.Context, groupID string) (Group, error) {
+	return g.allowed(ctx, groupID)
+}
+
+// Update updates the group, if it grants access to exactly the current
+// tenant, and continues to.
+func (g *tenantGroupManager) Update(ctx context.Context, groupID string, group Group) (Group, error) {
+	if _, err := g.allowed(ctx, groupID); err != nil {
+		return Group{}, err
+	}
+	if err := g.includesTenant(ctx, group); err != nil {
+		return Group{}, err
+	}
+
+	return g.groups.Update(ctx, groupID, group)
+}
+
+// Delete deletes the group, if it grants access to exactly the current
+// tenant.
+func (g *tenantGroupManager) Delete(ctx context.Context, groupID string) error {
+	if _, err := g.allowed(ctx, groupID); err != nil {
+		return err
+	}
+
+	return g.groups.Delete(ctx, groupID)
+}
+
+// tenantRequestManager scopes resource owner password credentials
+// authentication to the current tenant. Requests themselves are not tenant
+// scoped, so every other call is delegated as is.
+type tenantRequestManager struct {
+	requests RequestManager
+	users    UserManager
+}
+
+// Authenticate implements fosite.ResourceOwnerPasswordCredentialsGrantStorage,
+// authenticating the user, if they have access to the current tenant.
+func (r *tenantRequestManager) Authenticate(ctx context.Context, username string, secret string) error {
+	_, err := r.users.Authenticate(ctx, username, secret)
+	if errors.Is(err, ErrAuthenticationFailed) && !errors.Is(err, fosite.ErrAccessDenied) {
+		// fosite requires fosite.ErrNotFound to be returned in order to
+		// respond with an invalid grant, rather than a server error.
+		return NewError(ErrAuthenticationFailed, EntityUsers, fosite.ErrNotFound)
+	}
+
+	return err
+}
+
+// Configure implements Configurer.
+func (r *tenantRequestManager) Configure(ctx context.Context) error {
+	return r.requests.Configure(ctx)
+}
+
+// CreateAuthorizeCodeSession implements oauth2.AuthorizeCodeStorage.
+func (r *tenantRequestManager) CreateAuthorizeCodeSession(ctx context.Context, code string, request fosite.Requester) error {
+	return r.requests.CreateAuthorizeCodeSession(ctx, code, request)
+}
+
+// GetAuthorizeCodeSession implements oauth2.AuthorizeCodeStorage.
+func (r *tenantRequestManager) GetAuthorizeCodeSession(ctx context.Context, code string, session fosite.Session) (fosite.Requester, error) {
+	return r.requests.GetAuthorizeCodeSession(ctx, code, session)
+}
+
+// InvalidateAuthorizeCodeSession implements oauth2.AuthorizeCodeStorage.
+func (r *tenantRequestManager) InvalidateAuthorizeCodeSession(ctx context.Context, code string) error {
+	return r.requests.InvalidateAuthorizeCodeSession(ctx, code)
+}
+
+// CreateAccessTokenSession implements oauth2.AccessTokenStorage.
+func (r *tenantRequestManager) CreateAccessTokenSession(ctx context.Context, signature string, request fosite.Requester) error {
+	return r.requests.CreateAccessTokenSession(ctx, signature, request)
+}
+
+// GetAccessTokenSession implements oauth2.AccessTokenStorage.
+func (r *tenantRequestManager) GetAccessTokenSession(ctx context.Context, signature string, session fosite.Session) (fosite.Requester, error) {
+	return r.requests.GetAccessTokenSession(ctx, signature, session)
+}
+
+// DeleteAccessTokenSession implements oauth2.AccessTokenStorage.
+func (r *tenantRequestManager) DeleteAccessTokenSession(ctx context.Context, signature string) error {
+	return r.requests.DeleteAccessTokenSession(ctx, signature)
+}
+
+// CreateRefreshTokenSession implements oauth2.RefreshTokenStorage.
+func (r *tenantRequestManager) CreateRefreshTokenSession(ctx context.Context, signature string, request fosite.Requester) error {
+	return r.requests.CreateRefreshTokenSession(ctx, signature, request)
+}
+
+// GetRefreshTokenSession implements oauth2.RefreshTokenStorage.
+func (r *tenantRequestManager) GetRefreshTokenSession(ctx context.Context, signature string, session fosite.Session) (fosite.Requester, error) {
+	return r.requests.GetRefreshTokenSession(ctx, signature, session)
+}
+
+// DeleteRefreshTokenSession implements oauth2.RefreshTokenStorage.
+func (r *tenantRequestManager) DeleteRefreshTokenSession(ctx context.Context, signature string) error {
+	return r.requests.DeleteRefreshTokenSession(ctx, signature)
+}
+
+// CreateOpenIDConnectSession implements openid.OpenIDConnectRequestStorage.
+func (r *tenantRequestManager) CreateOpenIDConnectSession(ctx context.Context, authorizeCode string, requester fosite.Requester) error {
+	return r.requests.CreateOpenIDConnectSession(ctx, authorizeCode, requester)
+}
+
+// GetOpenIDConnectSession implements openid.OpenIDConnectRequestStorage.
+func (r *tenantRequestManager) GetOpenIDConnectSession(ctx context.Context, authorizeCode string, requester fosite.Requester) (fosite.Requester, error) {
+	return r.requests.GetOpenIDConnectSession(ctx, authorizeCode, requester)
+}
+
+// DeleteOpenIDConnectSession implements openid.OpenIDConnectRequestStorage.
+func (r *tenantRequestManager) DeleteOpenIDConnectSession(ctx context.Context, authorizeCode string) error {
+	return r.requests.DeleteOpenIDConnectSession(ctx, authorizeCode)
+}
+
+// CreatePKCERequestSession implements pkce.PKCERequestStorage.
+func (r *tenantRequestManager) CreatePKCERequestSession(ctx context.Context, signature string, requester fosite.Requester) error {
+	return r.requests.CreatePKCERequestSession(ctx, signature, requester)
+}
+
+// GetPKCERequestSession implements pkce.PKCERequestStorage.
+func (r *tenantRequestManager) GetPKCERequestSession(ctx context.Context, signature string, session fosite.Session) (fosite.Requester, error) {
+	return r.requests.GetPKCERequestSession(ctx, signature, session)
+}
+
+// DeletePKCERequestSession implements pkce.PKCERequestStorage.
+func (r *tenantRequestManager) DeletePKCERequestSession(ctx context.Context, signature string) error {
+	return r.requests.DeletePKCERequestSession(ctx, signature)
+}
+
+// CreatePARSession implements PARStorage.
+func (r *tenantRequestManager) CreatePARSession(ctx context.Context, requestURI string, request fosite.AuthorizeRequester) error {
+	return r.requests.CreatePARSession(ctx, requestURI, request)
+}
+
+// GetPARSession implements PARStorage.
+func (r *tenantRequestManager) GetPARSession(ctx context.Context, requestURI string) (fosite.AuthorizeRequester, error) {
+	return r.requests.GetPARSession(ctx, requestURI)
+}
+
+// DeletePARSession implements PARStorage.
+func (r *tenantRequestManager) DeletePARSession(ctx context.Context, requestURI string) error {
+	return r.requests.DeletePARSession(ctx, requestURI)
+}
+
+// BeginTX implements fosite's storage.Transactional.
+func (r *tenantRequestManager) BeginTX(ctx context.Context) (context.Context, error) {
+	return r.requests.BeginTX(ctx)
+}
+
+// Commit implements fosite's storage.Transactional.
+func (r *tenantRequestManager) Commit(ctx context.Context) error {
+	return r.requests.Commit(ctx)
+}
+
+// Rollback implements fosite's storage.Transactional.
+func (r *tenantRequestManager) Rollback(ctx context.Context) error {
+	return r.requests.Rollback(ctx)
+}
+
+// RevokeRefreshToken implements oauth2.TokenRevocationStorage.
+func (r *tenantRequestManager) RevokeRefreshToken(ctx context.Context, requestID string) error {
+	return r.requests.RevokeRefreshToken(ctx, requestID)
+}
+
+// RevokeAccessToken implements oauth2.TokenRevocationStorage.
+func (r *tenantRequestManager) RevokeAccessToken(ctx context.Context, requestID string) error {
+	return r.requests.RevokeAccessToken(ctx, requestID)
+}
+
+// List lists the requests stored for the entity.
+func (r *tenantRequestManager) List(ctx context.Context, entityName string, filter ListRequestsRequest) ([]Request, error) {
+	return r.requests.List(ctx, entityName, filter)
+}
+
+// Create stores the request for the entity.
+func (r *tenantRequestManager) Create(ctx context.Context, entityName string, request Request) (Request, error) {
+	return r.requests.Create(ctx, entityName, request)
+}
+
+// Get returns the request stored for the entity.
+func (r *tenantRequestManager) Get(ctx context.Context, entityName string, requestID string) (Request, error) {
+	return r.requests.Get(ctx, entityName, requestID)
+}
+
+// Update updates the request stored for the entity.
+func (r *tenantRequestManager) Update(ctx context.Context, entityName string, requestID string, request Request) (Request, error) {
+	return r.requests.Update(ctx, entityName, requestID, request)
+}
+
+// Delete deletes the request stored for the entity.
+func (r *tenantRequestManager) Delete(ctx context.Context, entityName string, requestID string) error {
+	return r.requests.Delete(ctx, entityName, requestID)
+}
+
+// DeleteBySignature deletes the request stored for the entity by signature.
+func (r *tenantRequestManager) DeleteBySignature(ctx context.Context, entityName string, signature string) error {
+	return r.requests.DeleteBySignature(ctx, entityName, signature)
+}
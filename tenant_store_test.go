@@ -0,0 +1,431 @@
+package storage_test
+
+import (
+	// Standard Library Imports
+	"context"
+	"errors"
+	"testing"
+
+	// External Imports
+	"github.com/ory/fosite"
+
+	// Internal Imports
+	"github.com/matthewhartstonge/storage"
+)
+
+func (m *memoryClients) Create(ctx context.Context, client storage.Client) (storage.Client, error) {
+	m.clients[client.ID] = client
+	return client, nil
+}
+
+func (m *memoryClients) Update(ctx context.Context, clientID string, client storage.Client) (storage.Client, error) {
+	m.clients[clientID] = client
+	return client, nil
+}
+
+func (m *memoryClients) Delete(ctx context.Context, clientID string) error {
+	delete(m.clients, clientID)
+	return nil
+}
+
+func (m *memoryClients) BulkDelete(ctx context.Context, clientIDs []string) ([]storage.BulkResult, error) {
+	results := make([]storage.BulkResult, len(clientIDs))
+	for i, clientID := range clientIDs {
+		results[i].ID = clientID
+		delete(m.clients, clientID)
+	}
+	return results, nil
+}
+
+func (m *memoryClients) BulkGrantScopes(ctx context.Context, filter storage.ListClientsRequest, scopes []string) (int64, error) {
+	m.bulkFilter = &filter
+	return 1, nil
+}
+
+func (m *memoryClients) GetClient(ctx context.Context, clientID string) (fosite.Client, error) {
+	client, err := m.Get(ctx, clientID)
+	if err != nil {
+		return nil, err
+	}
+	return &client, nil
+}
+
+func (m *memoryUsers) Search(ctx context.Context, request storage.SearchUsersRequest) ([]storage.User, error) {
+	var results []storage.User
+	for _, user := range m.users {
+		for _, tenantID := range user.AllowedTenantAccess {
+			if tenantID == request.AllowedTenantAccess {
+				results = append(results, user)
+			}
+		}
+	}
+	return results, nil
+}
+
+func (m *memoryUsers) GetByEmail(ctx context.Context, email string) (storage.User, error) {
+	for _, user := range m.users {
+		if user.Email == email {
+			return user, nil
+		}
+	}
+	return storage.User{}, storage.NewNotFoundError(storage.EntityUsers)
+}
+
+func (m *memoryUsers) Update(ctx context.Context, userID string, user storage.User) (storage.User, error) {
+	m.users[userID] = user
+	return user, nil
+}
+
+func (m *memoryUsers) Authenticate(ctx context.Context, username string, password string) (storage.User, error) {
+	user, err := m.GetByUsername(ctx, username)
+	if err != nil {
+		return user, err
+	}
+	if user.Password != password {
+		return storage.User{}, storage.NewAuthenticationFailedError(storage.EntityUsers, errors.New("password mismatch"))
+	}
+	return user, nil
+}
+
+func newTenantStore() (storage.Store, *memoryClients, *memoryUsers) {
+	clients, users := newMemoryStores()
+	store := storage.NewTenantStore(storage.Store{
+		ClientManager: clients,
+		GroupManager: &memoryGroups{groups: map[string]storage.Group{
+			"staff": {
+				ID:                  "staff",
+				Name:                "Staff",
+				AllowedTenantAccess: []string{"tenant-1"},
+			},
+			"shared": {
+				ID:                  "shared",
+				Name:                "Shared",
+				AllowedTenantAccess: []string{"tenant-1", "tenant-2"},
+			},
+		}},
+		RequestManager: &memoryRequests{},
+		UserManager:    users,
+	})
+
+	return store, clients, users
+}
+
+// memoryRequests provides a request manager, which is not tenant scoped.
+type memoryRequests struct {
+	storage.RequestManager
+}
+
+func TestContextToTenant(t *testing.T) {
+	if _, ok := storage.ContextToTenant(context.Background()); ok {
+		t.Error("expected no tenant to be bound")
+	}
+	if _, ok := storage.ContextToTenant(storage.TenantToContext(context.Background(), "")); ok {
+		t.Error("expected an empty tenant to not be bound")
+	}
+
+	tenantID, ok := storage.ContextToTenant(storage.TenantToContext(context.Background(), "tenant-1"))
+	if !ok || tenantID != "tenant-1" {
+		t.Errorf("expected tenant-1, got %q", tenantID)
+	}
+}
+
+func TestTenantStore_ShouldRequireTenant(t *testing.T) {
+	store, _, _ := newTenantStore()
+
+	_, err := store.ClientManager.Get(context.Background(), "client-1")
+	if !errors.Is(err, storage.ErrPreconditionFailed) || !errors.Is(err, storage.ErrTenantRequired) {
+		t.Errorf("expected tenant required, got %v", err)
+	}
+
+	_, err = store.UserManager.List(context.Background(), storage.ListUsersRequest{})
+	if !errors.Is(err, storage.ErrTenantRequired) {
+		t.Errorf("expected tenant required, got %v", err)
+	}
+}
+
+func TestTenantStore_Clients(t *testing.T) {
+	store, clients, _ := newTenantStore()
+	ctx := storage.TenantToContext(context.Background(), "tenant-1")
+
+	if _, err := store.ClientManager.Get(ctx, "client-1"); err != nil {
+		t.Errorf("expected client in tenant to be found, got %v", err)
+	}
+
+	_, err := store.ClientManager.Get(ctx, "client-2")
+	if !errors.Is(err, storage.ErrNotFound) || !errors.Is(err, fosite.ErrNotFound) {
+		t.Errorf("expected client in another tenant to be not found, got %v", err)
+	}
+
+	_, err = store.GetClient(ctx, "client-2")
+	if !errors.Is(err, fosite.ErrNotFound) {
+		t.Errorf("expected GetClient in another tenant to be not found, got %v", err)
+	}
+
+	_, err = store.ClientManager.Update(ctx, "client-2", storage.Client{ID: "client-2", AllowedTenantAccess: []string{"tenant-1"}})
+	if !errors.Is(err, storage.ErrNotFound) {
+		t.Errorf("expected update in another tenant to be not found, got %v", err)
+	}
+
+	_, err = store.ClientManager.Update(ctx, "client-1", storage.Client{ID: "client-1"})
+	if !errors.Is(err, storage.ErrInvalidArgument) {
+		t.Errorf("expected removing the tenant to be rejected, got %v", err)
+	}
+
+	err = store.ClientManager.Delete(ctx, "client-2")
+	if !errors.Is(err, storage.ErrNotFound) {
+		t.Errorf("expected delete in another tenant to be not found, got %v", err)
+	}
+	if _, ok := clients.clients["client-2"]; !ok {
+		t.Error("expected client in another tenant to not be deleted")
+	}
+
+	_, err = store.ClientManager.Create(ctx, storage.Client{ID: "client-3"})
+	if !errors.Is(err, storage.ErrInvalidArgument) {
+		t.Errorf("expected create without the tenant to be rejected, got %v", err)
+	}
+
+	_, err = store.ClientManager.Migrate(ctx, storage.Client{ID: "client-2", AllowedTenantAccess: []string{"tenant-1"}})
+	if !errors.Is(err, storage.ErrResourceExists) {
+		t.Errorf("expected migrating over a client in another tenant to conflict, got %v", err)
+	}
+}
+
+func TestTenantStore_ShouldRejectOtherTenants(t *testing.T) {
+	store, clients, users := newTenantStore()
+	ctx := storage.TenantToContext(context.Background(), "tenant-1")
+	tenants := []string{"tenant-1", "tenant-2"}
+
+	_, err := store.ClientManager.Create(ctx, storage.Client{ID: "client-3", AllowedTenantAccess: tenants})
+	if !errors.Is(err, storage.ErrInvalidArgument) {
+		t.Errorf("expected create granting another tenant to be rejected, got %v", err)
+	}
+
+	_, err = store.ClientManager.Update(ctx, "client-1", storage.Client{ID: "client-1", AllowedTenantAccess: tenants})
+	if !errors.Is(err, storage.ErrInvalidArgument) {
+		t.Errorf("expected update granting another tenant to be rejected, got %v", err)
+	}
+	if got := clients.clients["client-1"].AllowedTenantAccess; len(got) != 1 {
+		t.Errorf("expected client to not be updated, got %v", got)
+	}
+
+	_, err = store.ClientManager.Migrate(ctx, storage.Client{ID: "client-1", AllowedTenantAccess: tenants})
+	if !errors.Is(err, storage.ErrInvalidArgument) {
+		t.Errorf("expected migrate granting another tenant to be rejected, got %v", err)
+	}
+
+	results, err := store.ClientManager.BulkCreate(ctx, []storage.Client{{ID: "client-3", AllowedTenantAccess: tenants}})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if !errors.Is(results[0].Err, storage.ErrInvalidArgument) {
+		t.Errorf("expected bulk create granting another tenant to be rejected, got %+v", results[0])
+	}
+
+	_, err = store.UserManager.Create(ctx, storage.User{ID: "user-2", AllowedTenantAccess: tenants})
+	if !errors.Is(err, storage.ErrInvalidArgument) {
+		t.Errorf("expected user create granting another tenant to be rejected, got %v", err)
+	}
+
+	_, err = store.UserManager.Migrate(ctx, storage.User{ID: "user-1", AllowedTenantAccess: []string{"tenant-2"}})
+	if !errors.Is(err, storage.ErrInvalidArgument) {
+		t.Errorf("expected user migrate to another tenant to be rejected, got %v", err)
+	}
+	if got := users.users["user-1"].AllowedTenantAccess; len(got) != 1 || got[0] != "tenant-1" {
+		t.Errorf("expected user to not be migrated, got %v", got)
+	}
+}
+
+func TestTenantStore_Clients_List(t *testing.T) {
+	store, _, _ := newTenantStore()
+	ctx := storage.TenantToContext(context.Background(), "tenant-1")
+
+	results, err := store.ClientManager.List(ctx, storage.ListClientsRequest{AllowedTenantAccess: "tenant-2"})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(results) != 0 {
+		t.Errorf("expected no clients for another tenant, got %d", len(results))
+	}
+}
+
+func TestTenantStore_Clients_Bulk(t *testing.T) {
+	store, clients, _ := newTenantStore()
+	ctx := storage.TenantToContext(context.Background(), "tenant-1")
+
+	results, err := store.ClientManager.BulkDelete(ctx, []string{"client-2", "client-1"})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if results[0].ID != "client-2" || !errors.Is(results[0].Err, storage.ErrNotFound) {
+		t.Errorf("expected delete in another tenant to be not found, got %+v", results[0])
+	}
+	if results[1].ID != "client-1" || results[1].Err != nil {
+		t.Errorf("expected client in tenant to be deleted, got %+v", results[1])
+	}
+	if _, ok := clients.clients["client-2"]; !ok {
+		t.Error("expected client in another tenant to not be deleted")
+	}
+
+	modified, err := store.ClientManager.BulkGrantScopes(ctx, storage.ListClientsRequest{AllowedTenantAccess: "tenant-2"}, []string{"admin"})
+	if err != nil || modified != 0 || clients.bulkFilter != nil {
+		t.Errorf("expected no clients in another tenant to be modified, got %d, %v", modified, err)
+	}
+
+	_, err = store.ClientManager.BulkGrantScopes(ctx, storage.ListClientsRequest{}, []string{"admin"})
+	if !errors.Is(err, storage.ErrInvalidArgument) || clients.bulkFilter != nil {
+		t.Errorf("expected scope grants without a filter, or all, to be invalid, got %v", err)
+	}
+
+	_, err = store.ClientManager.BulkGrantScopes(ctx, storage.ListClientsRequest{All: true}, []string{"admin"})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if clients.bulkFilter == nil || clients.bulkFilter.AllowedTenantAccess != "tenant-1" {
+		t.Errorf("expected scope grants to be scoped to the tenant, got %+v", clients.bulkFilter)
+	}
+
+	results, err = store.ClientManager.BulkCreate(ctx, []storage.Client{{ID: "client-3"}})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if !errors.Is(results[0].Err, storage.ErrInvalidArgument) {
+		t.Errorf("expected create without the tenant to be rejected, got %+v", results[0])
+	}
+}
+
+func TestTenantStore_Users_Search(t *testing.T) {
+	store, _, _ := newTenantStore()
+	ctx := storage.TenantToContext(context.Background(), "tenant-1")
+
+	results, err := store.UserManager.Search(ctx, storage.SearchUsersRequest{Query: "k"})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(results) != 1 || results[0].ID != "user-1" {
+		t.Errorf("expected only the users in the tenant to be searched, got %+v", results)
+	}
+
+	results, err = store.UserManager.Search(ctx, storage.SearchUsersRequest{Query: "k", AllowedTenantAccess: "tenant-2"})
+	if err != nil || len(results) != 0 {
+		t.Errorf("expected no users for another tenant, got %+v, %v", results, err)
+	}
+}
+
+func TestTenantStore_Users_GetByEmail(t *testing.T) {
+	store, _, users := newTenantStore()
+	users.users["user-2"] = storage.User{
+		ID:                  "user-2",
+		Email:               "bob@example.com",
+		AllowedTenantAccess: []string{"tenant-2"},
+	}
+	ctx := storage.TenantToContext(context.Background(), "tenant-1")
+
+	_, err := store.UserManager.GetByEmail(ctx, "bob@example.com")
+	if !errors.Is(err, storage.ErrNotFound) {
+		t.Errorf("expected user in another tenant to be not found, got %v", err)
+	}
+
+	_, err = store.UserManager.AuthenticateByEmail(ctx, "bob@example.com", "password")
+	if !errors.Is(err, storage.ErrNotFound) {
+		t.Errorf("expected authenticating a user in another tenant to be not found, got %v", err)
+	}
+}
+
+func TestTenantStore_Authenticate(t *testing.T) {
+	store, _, _ := newTenantStore()
+
+	ctx := storage.TenantToContext(context.Background(), "tenant-1")
+	if err := store.Authenticate(ctx, "kevin", "$2a$10$userhash"); err != nil {
+		t.Errorf("expected user in tenant to authenticate, got %v", err)
+	}
+	if err := store.RequestManager.Authenticate(ctx, "kevin", "wrong"); !errors.Is(err, fosite.ErrNotFound) {
+		t.Errorf("expected a failed authentication to wrap fosite.ErrNotFound, got %v", err)
+	}
+
+	ctx = storage.TenantToContext(context.Background(), "tenant-2")
+	if err := store.RequestManager.Authenticate(ctx, "kevin", "$2a$10$userhash"); !errors.Is(err, fosite.ErrNotFound) {
+		t.Errorf("expected user in another tenant to be not found, got %v", err)
+	}
+}
+
+func TestTenantStore_Users_ShouldInheritGroupTenantAccess(t *testing.T) {
+	store, _, users := newTenantStore()
+	users.users["user-2"] = storage.User{
+		ID:       "user-2",
+		Username: "bob",
+		Groups:   []string{"staff"},
+		Password: "$2a$10$userhash",
+	}
+
+	ctx := storage.TenantToContext(context.Background(), "tenant-1")
+	user, err := store.UserManager.Get(ctx, "user-2")
+	if err != nil {
+		t.Fatalf("expected a member of a group with access to the tenant to be found, got %v", err)
+	}
+	if err := store.Authenticate(ctx, "bob", "$2a$10$userhash"); err != nil {
+		t.Errorf("expected a member of a group with access to the tenant to authenticate, got %v", err)
+	}
+
+	user.FirstName = "Bob"
+	if _, err := store.UserManager.Update(ctx, "user-2", user); err != nil {
+		t.Errorf("expected a member of a group with access to the tenant to be updated, got %v", err)
+	}
+
+	ctx = storage.TenantToContext(context.Background(), "tenant-2")
+	if _, err := store.UserManager.Get(ctx, "user-2"); !errors.Is(err, storage.ErrNotFound) {
+		t.Errorf("expected a member of a group without access to the tenant to be not found, got %v", err)
+	}
+	if err := store.RequestManager.Authenticate(ctx, "bob", "$2a$10$userhash"); !errors.Is(err, fosite.ErrNotFound) {
+		t.Errorf("expected a member of a group without access to the tenant to not authenticate, got %v", err)
+	}
+}
+
+func TestTenantStore_Groups_ShouldRejectOtherTenants(t *testing.T) {
+	store, _, _ := newTenantStore()
+	ctx := storage.TenantToContext(context.Background(), "tenant-1")
+
+	_, err := store.GroupManager.Create(ctx, storage.Group{
+		ID:                  "escalate",
+		Name:                "Escalate",
+		AllowedTenantAccess: []string{"tenant-1", "tenant-2"},
+	})
+	if !errors.Is(err, storage.ErrInvalidArgument) {
+		t.Errorf("expected a group granting another tenant to be rejected, got %v", err)
+	}
+
+	if _, err = store.GroupManager.Get(ctx, "shared"); !errors.Is(err, storage.ErrNotFound) {
+		t.Errorf("expected a group granting another tenant to be not found, got %v", err)
+	}
+
+	groups, err := store.GroupManager.List(ctx, storage.ListGroupsRequest{})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(groups) != 1 || groups[0].ID != "staff" {
+		t.Errorf("expected only the groups granting exactly the tenant to be listed, got %+v", groups)
+	}
+
+	_, err = store.GroupManager.Create(ctx, storage.Group{
+		ID:                  "cats",
+		Name:                "Cats",
+		AllowedTenantAccess: []string{"tenant-1"},
+	})
+	if err != nil {
+		t.Errorf("expected a group granting the tenant to be created, got %v", err)
+	}
+}
+
+func TestTenantStore_Users_ShouldRejectGroupsGrantingOtherTenants(t *testing.T) {
+	store, _, _ := newTenantStore()
+	ctx := storage.TenantToContext(context.Background(), "tenant-1")
+
+	_, err := store.UserManager.Create(ctx, storage.User{
+		ID:                  "user-3",
+		Username:            "mallory",
+		AllowedTenantAccess: []string{"tenant-1"},
+		Groups:              []string{"shared"},
+	})
+	if !errors.Is(err, storage.ErrInvalidArgument) {
+		t.Errorf("expected a user whose groups grant another tenant to be rejected, got %v", err)
+	}
+}
@@ -0,0 +1,94 @@
+package storage_test
+
+import (
+	// Standard Library Imports
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	// Internal Imports
+	"github.com/matthewhartstonge/storage"
+)
+
+// memoryTenants provides an in-memory tenant storer, for validating tenant
+// references.
+type memoryTenants struct {
+	storage.TenantStorer
+	tenants map[string]storage.Tenant
+}
+
+func (m *memoryTenants) List(ctx context.Context, filter storage.ListTenantsRequest) (results []storage.Tenant, err error) {
+	for _, tenantID := range filter.IDs {
+		if tenant, ok := m.tenants[tenantID]; ok {
+			results = append(results, tenant)
+		}
+	}
+
+	return results, nil
+}
+
+func TestTenantStatus_IsValid(t *testing.T) {
+	for _, status := range []storage.TenantStatus{
+		storage.TenantStatusActive,
+		storage.TenantStatusSuspended,
+		storage.TenantStatusArchived,
+	} {
+		if !status.IsValid() {
+			t.Errorf("expected %q to be valid", status)
+		}
+	}
+
+	for _, status := range []storage.TenantStatus{"", "deleted"} {
+		if status.IsValid() {
+			t.Errorf("expected %q to be invalid", status)
+		}
+	}
+}
+
+func TestTenant_Validate(t *testing.T) {
+	tenant := storage.Tenant{Name: "Kittens Inc.", Status: storage.TenantStatusActive}
+	if err := tenant.Validate(); err != nil {
+		t.Errorf("expected tenant to be valid, got %v", err)
+	}
+	if !tenant.IsActive() {
+		t.Error("expected tenant to be active")
+	}
+
+	if err := (storage.Tenant{Name: " ", Status: storage.TenantStatusActive}).Validate(); !errors.Is(err, storage.ErrInvalidArgument) {
+		t.Errorf("expected a missing name to be invalid, got %v", err)
+	}
+
+	tenant = storage.Tenant{Name: "Kittens Inc.", Status: "deleted"}
+	if err := tenant.Validate(); !errors.Is(err, storage.ErrInvalidArgument) {
+		t.Errorf("expected an unknown status to be invalid, got %v", err)
+	}
+	if tenant.IsActive() {
+		t.Error("expected tenant to not be active")
+	}
+}
+
+func TestValidateTenantReferences(t *testing.T) {
+	ctx := context.Background()
+	tenants := &memoryTenants{tenants: map[string]storage.Tenant{
+		"tenant-1": {ID: "tenant-1", Name: "Tenant 1", Status: storage.TenantStatusActive},
+	}}
+
+	if err := storage.ValidateTenantReferences(ctx, nil, storage.EntityClients, []string{"tenant-2"}); err != nil {
+		t.Errorf("expected no validation without a tenant storer, got %v", err)
+	}
+	if err := storage.ValidateTenantReferences(ctx, tenants, storage.EntityClients, nil); err != nil {
+		t.Errorf("expected no tenants to be valid, got %v", err)
+	}
+	if err := storage.ValidateTenantReferences(ctx, tenants, storage.EntityClients, []string{"tenant-1"}); err != nil {
+		t.Errorf("expected known tenants to be valid, got %v", err)
+	}
+
+	err := storage.ValidateTenantReferences(ctx, tenants, storage.EntityUsers, []string{"tenant-1", "tenant-2", "tenant-3"})
+	if !errors.Is(err, storage.ErrInvalidArgument) {
+		t.Fatalf("expected unknown tenants to be invalid, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "tenant-2, tenant-3") {
+		t.Errorf("expected the unknown tenants to be reported, got %v", err)
+	}
+}
@@ -0,0 +1,505 @@
+package storage
+
+import (
+	// Standard Library Imports
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// entityTransfer provides the name of the entity used when reporting transfer
+// errors.
+const entityTransfer = "transfer"
+
+// maxTransferLineSize provides the maximum size of a single line of NDJSON
+// read on import.
+const maxTransferLineSize = 4 * 1024 * 1024
+
+// ConflictStrategy specifies how an import handles records that already exist.
+type ConflictStrategy string
+
+const (
+	// ConflictSkip leaves existing records untouched.
+	ConflictSkip ConflictStrategy = "skip"
+
+	// ConflictOverwrite replaces existing records with the imported record.
+	ConflictOverwrite ConflictStrategy = "overwrite"
+
+	// ConflictFail stops the import on the first existing record.
+	ConflictFail ConflictStrategy = "fail"
+)
+
+// TransferRecord provides a single line of a newline-delimited JSON export.
+// Exactly one of Client or User is set, as specified by Entity.
+type TransferRecord struct {
+	// Entity contains the name of the entity, either EntityClients or
+	// EntityUsers.
+	Entity string `json:"entity"`
+
+	Client *Client `json:"client,omitempty"`
+	User   *User   `json:"user,omitempty"`
+}
+
+// TransferFilter filters the clients and users exported or imported.
+type TransferFilter struct {
+	// Entities limits the transfer to the named entities, EntityClients and
+	// EntityUsers. If empty, both are transferred.
+	Entities []string `json:"entities,omitempty"`
+
+	// Tenant filters clients and users to those with access to the tenant.
+	Tenant string `json:"tenant,omitempty"`
+
+	// Owner filters clients to those owned by the owner. Users aren't owned,
+	// so are not filtered by owner.
+	Owner string `json:"owner,omitempty"`
+
+	// Scopes filters clients and users to those that have all the listed
+	// scopes.
+	Scopes []string `json:"scopes,omitempty"`
+}
+
+// includes returns true if the filter includes the named entity.
+func (f TransferFilter) includes(entityName string) bool {
+	if len(f.Entities) == 0 {
+		return true
+	}
+
+	for _, name := range f.Entities {
+		if name == entityName {
+			return true
+		}
+	}
+
+	return false
+}
+
+// matchesClient returns true if the client passes the filter.
+func (f TransferFilter) matchesClient(client Client) bool {
+	if !f.includes(EntityClients) {
+		return false
+	}
+	if f.Tenant != "" && !contains(client.AllowedTenantAccess, f.Tenant) {
+		return false
+	}
+	if f.Owner != "" && client.Owner != f.Owner {
+		return false
+	}
+
+	return containsAll(client.Scopes, f.Scopes)
+}
+
+// matchesUser returns true if the user passes the filter.
+func (f TransferFilter) matchesUser(user User) bool {
+	if !f.includes(EntityUsers) {
+		return false
+	}
+	if f.Tenant != "" && !contains(user.AllowedTenantAccess, f.Tenant) {
+		return false
+	}
+
+	return containsAll(user.Scopes, f.Scopes)
+}
+
+// TransferSummary reports the outcome of an export or import, by entity.
+type TransferSummary struct {
+	// DryRun is true if the import was not applied.
+	DryRun bool `json:"dryRun,omitempty"`
+
+	// Exported contains the number of records written on export.
+	Exported map[string]int `json:"exported,omitempty"`
+
+	// Created contains the number of records that did not exist, and were,
+	// or on a dry run would be, created on import.
+	Created map[string]int `json:"created,omitempty"`
+
+	// Overwritten contains the number of existing records that were, or on a
+	// dry run would be, overwritten on import.
+	Overwritten map[string]int `json:"overwritten,omitempty"`
+
+	// Skipped contains the number of existing records left untouched on
+	// import.
+	Skipped map[string]int `json:"skipped,omitempty"`
+
+	// Filtered contains the number of records read, but excluded by the
+	// filter on import.
+	Filtered map[string]int `json:"filtered,omitempty"`
+}
+
+// newTransferSummary returns an empty transfer summary.
+func newTransferSummary() TransferSummary {
+	return TransferSummary{
+		Exported:    map[string]int{},
+		Created:     map[string]int{},
+		Overwritten: map[string]int{},
+		Skipped:     map[string]int{},
+		Filtered:    map[string]int{},
+	}
+}
+
+// Written returns the number of records created, or overwritten, on import.
+// On a dry run, nothing is written, so Written returns 0.
+func (s TransferSummary) Written() int {
+	if s.DryRun {
+		return 0
+	}
+
+	written := 0
+	for _, count := range s.Created {
+		written += count
+	}
+	for _, count := range s.Overwritten {
+		written += count
+	}
+
+	return written
+}
+
+// ExportOptions configures an export.
+type ExportOptions struct {
+	Filter TransferFilter
+}
+
+// ImportOptions configures an import.
+type ImportOptions struct {
+	Filter TransferFilter
+
+	// OnConflict specifies how records that already exist are handled.
+	// Defaults to ConflictFail.
+	OnConflict ConflictStrategy
+
+	// DryRun, if true, reports what would be imported without writing.
+	DryRun bool
+}
+
+// ClientIterator is implemented by client storers that can stream the clients
+// matching a filter, rather than listing them all into memory. Export uses
+// Each where it is implemented.
+type ClientIterator interface {
+	// Each calls fn with each client matching the filter, stopping at, and
+	// returning, the first error returned by fn.
+	Each(ctx context.Context, filter ListClientsRequest, fn func(client Client) error) error
+}
+
+// UserIterator is implemented by user storers that can stream the users
+// matching a filter, rather than listing them all into memory. Export uses
+// Each where it is implemented.
+type UserIterator interface {
+	// Each calls fn with each user matching the filter, stopping at, and
+	// returning, the first error returned by fn.
+	Each(ctx context.Context, filter ListUsersRequest, fn func(user User) error) error
+}
+
+// eachClient calls fn with each client matching the filter, streaming the
+// clients if the storer supports it.
+func eachClient(ctx context.Context, clients ClientStorer, filter ListClientsRequest, fn func(client Client) error) error {
+	if iterator, ok := clients.(ClientIterator); ok {
+		return iterator.Each(ctx, filter, fn)
+	}
+
+	results, err := clients.List(ctx, filter)
+	if err != nil {
+		return err
+	}
+	for i := range results {
+		if err := fn(results[i]); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// eachUser calls fn with each user matching the filter, streaming the users
+// if the storer supports it.
+func eachUser(ctx context.Context, users UserStorer, filter ListUsersRequest, fn func(user User) error) error {
+	if iterator, ok := users.(UserIterator); ok {
+		return iterator.Each(ctx, filter, fn)
+	}
+
+	results, err := users.List(ctx, filter)
+	if err != nil {
+		return err
+	}
+	for i := range results {
+		if err := fn(results[i]); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Export writes the clients and users matching the filter to w as
+// newline-delimited JSON, one TransferRecord per line. Client secrets and user
+// passwords are exported as their stored hashes.
+//
+// Records are written as they are read, so storers implementing
+// ClientIterator, or UserIterator, are exported without being held in memory.
+//
+// If clients or users is nil, that entity is not exported.
+func Export(ctx context.Context, w io.Writer, clients ClientStorer, users UserStorer, opts ExportOptions) (summary TransferSummary, err error) {
+	summary = newTransferSummary()
+	enc := json.NewEncoder(w)
+
+	if clients != nil && opts.Filter.includes(EntityClients) {
+		filter := ListClientsRequest{
+			AllowedTenantAccess: opts.Filter.Tenant,
+			ScopesIntersection:  opts.Filter.Scopes,
+		}
+		err = eachClient(ctx, clients, filter, func(client Client) error {
+			if !opts.Filter.matchesClient(client) {
+				return nil
+			}
+
+			if err := enc.Encode(TransferRecord{Entity: EntityClients, Client: &client}); err != nil {
+				return err
+			}
+			summary.Exported[EntityClients]++
+
+			return nil
+		})
+		if err != nil {
+			return summary, err
+		}
+	}
+
+	if users != nil && opts.Filter.includes(EntityUsers) {
+		filter := ListUsersRequest{
+			AllowedTenantAccess: opts.Filter.Tenant,
+			ScopesIntersection:  opts.Filter.Scopes,
+		}
+		err = eachUser(ctx, users, filter, func(user User) error {
+			if !opts.Filter.matchesUser(user) {
+				return nil
+			}
+
+			if err := enc.Encode(TransferRecord{Entity: EntityUsers, User: &user}); err != nil {
+				return err
+			}
+			summary.Exported[EntityUsers]++
+
+			return nil
+		})
+		if err != nil {
+			return summary, err
+		}
+	}
+
+	return summary, nil
+}
+
+// Import reads newline-delimited JSON, as written by Export, from r and stores
+// the clients and users matching the filter via Migrate, so stored hashes are
+// preserved verbatim rather than being hashed again.
+//
+// Records are validated and stored one at a time as they are read, so large
+// files are imported without being held in memory. Import is not atomic: if a
+// record is malformed, or conflicts under ConflictFail, the import stops
+// there, and records before it remain imported. The returned error reports
+// the failing line, and the summary reports the records written before it.
+// Use DryRun to check a file before importing it.
+//
+// If clients or users is nil, records for that entity are rejected.
+func Import(ctx context.Context, r io.Reader, clients ClientManager, users UserManager, opts ImportOptions) (summary TransferSummary, err error) {
+	summary = newTransferSummary()
+	summary.DryRun = opts.DryRun
+
+	onConflict := opts.OnConflict
+	switch onConflict {
+	case "":
+		onConflict = ConflictFail
+	case ConflictSkip, ConflictOverwrite, ConflictFail:
+	default:
+		return summary, NewInvalidArgumentError(entityTransfer, fmt.Sprintf("unknown conflict strategy %q", onConflict), "OnConflict")
+	}
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxTransferLineSize)
+
+	line := 0
+	for scanner.Scan() {
+		line++
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+
+		record, err := readTransferRecord(scanner.Bytes(), clients != nil, users != nil)
+		if err == nil {
+			err = importRecord(ctx, clients, users, record, onConflict, opts, &summary)
+		}
+		if err != nil {
+			return summary, fmt.Errorf("line %d, after %d records were written: %w", line, summary.Written(), err)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return summary, NewError(ErrInvalidArgument, entityTransfer, fmt.Errorf("line %d, after %d records were written: %w", line+1, summary.Written(), err))
+	}
+
+	return summary, nil
+}
+
+// readTransferRecord decodes and validates a single record.
+func readTransferRecord(data []byte, hasClients bool, hasUsers bool) (record TransferRecord, err error) {
+	if err := json.Unmarshal(data, &record); err != nil {
+		return record, NewError(ErrInvalidArgument, entityTransfer, err)
+	}
+
+	switch {
+	case record.Entity == EntityClients && record.Client != nil && record.User == nil:
+		if !hasClients {
+			return record, NewError(ErrInvalidArgument, entityTransfer, errors.New("clients can't be imported without a client storer"))
+		}
+		if record.Client.ID == "" {
+			return record, NewError(ErrInvalidArgument, entityTransfer, errors.New("client id is required"), "id")
+		}
+
+	case record.Entity == EntityUsers && record.User != nil && record.Client == nil:
+		if !hasUsers {
+			return record, NewError(ErrInvalidArgument, entityTransfer, errors.New("users can't be imported without a user storer"))
+		}
+		if record.User.ID == "" {
+			return record, NewError(ErrInvalidArgument, entityTransfer, errors.New("user id is required"), "id")
+		}
+
+	default:
+		return record, NewError(ErrInvalidArgument, entityTransfer, errors.New("unknown record"), "entity")
+	}
+
+	return record, nil
+}
+
+// importRecord stores the record, if it matches the filter.
+func importRecord(ctx context.Context, clients ClientManager, users UserManager, record TransferRecord, onConflict ConflictStrategy, opts ImportOptions, summary *TransferSummary) error {
+	switch record.Entity {
+	case EntityClients:
+		if !opts.Filter.matchesClient(*record.Client) {
+			summary.Filtered[EntityClients]++
+			return nil
+		}
+
+		return importClient(ctx, clients, *record.Client, onConflict, opts.DryRun, summary)
+
+	case EntityUsers:
+		if !opts.Filter.matchesUser(*record.User) {
+			summary.Filtered[EntityUsers]++
+			return nil
+		}
+
+		return importUser(ctx, users, *record.User, onConflict, opts.DryRun, summary)
+	}
+
+	return nil
+}
+
+// importClient stores the client, resolving conflicts with the existing
+// client using the given strategy.
+func importClient(ctx context.Context, clients ClientManager, client Client, onConflict ConflictStrategy, dryRun bool, summary *TransferSummary) error {
+	_, err := clients.Get(ctx, client.ID)
+	exists := err == nil
+	if err != nil && !errors.Is(err, ErrNotFound) {
+		return err
+	}
+
+	if exists {
+		switch onConflict {
+		case ConflictSkip:
+			summary.Skipped[EntityClients]++
+			return nil
+
+		case ConflictFail:
+			return NewConflictError(EntityClients, fmt.Errorf("client %q already exists", client.ID), "id")
+		}
+	}
+
+	if !dryRun {
+		if _, err := clients.Migrate(ctx, client); err != nil {
+			return err
+		}
+	}
+
+	if exists {
+		summary.Overwritten[EntityClients]++
+	} else {
+		summary.Created[EntityClients]++
+	}
+
+	return nil
+}
+
+// importUser stores the user, resolving conflicts with the existing user
+// using the given strategy. A user conflicts if either their id, or their
+// username, is already taken.
+func importUser(ctx context.Context, users UserManager, user User, onConflict ConflictStrategy, dryRun bool, summary *TransferSummary) error {
+	_, err := users.Get(ctx, user.ID)
+	exists := err == nil
+	if err != nil && !errors.Is(err, ErrNotFound) {
+		return err
+	}
+
+	if !exists && user.Username != "" {
+		// A different user holding the username can't be overwritten, as
+		// that would change their id.
+		existing, err := users.GetByUsername(ctx, user.Username)
+		if err != nil && !errors.Is(err, ErrNotFound) {
+			return err
+		}
+		if err == nil && existing.ID != user.ID {
+			if onConflict == ConflictSkip {
+				summary.Skipped[EntityUsers]++
+				return nil
+			}
+
+			return NewConflictError(EntityUsers, fmt.Errorf("username %q is taken by another user", user.Username), "username")
+		}
+	}
+
+	if exists {
+		switch onConflict {
+		case ConflictSkip:
+			summary.Skipped[EntityUsers]++
+			return nil
+
+		case ConflictFail:
+			return NewConflictError(EntityUsers, fmt.Errorf("user %q already exists", user.ID), "id")
+		}
+	}
+
+	if !dryRun {
+		if _, err := users.Migrate(ctx, user); err != nil {
+			return err
+		}
+	}
+
+	if exists {
+		summary.Overwritten[EntityUsers]++
+	} else {
+		summary.Created[EntityUsers]++
+	}
+
+	return nil
+}
+
+// contains returns true if the value is in the list.
+func contains(list []string, value string) bool {
+	for _, v := range list {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
+}
+
+// containsAll returns true if all the values are in the list.
+func containsAll(list []string, values []string) bool {
+	for _, value := range values {
+		if !contains(list, value) {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,361 @@
+package storage_test
+
+import (
+	// Standard Library Imports
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	// Internal Imports
+	"github.com/matthewhartstonge/storage"
+)
+
+// memoryClients provides an in-memory client manager, implementing the
+// methods used by export and import.
+type memoryClients struct {
+	storage.ClientManager
+	clients  map[string]storage.Client
+	migrated int
+
+	bulkFilter *storage.ListClientsRequest
+}
+
+func (m *memoryClients) List(ctx context.Context, filter storage.ListClientsRequest) (results []storage.Client, err error) {
+	for _, client := range m.clients {
+		results = append(results, client)
+	}
+	return results, nil
+}
+
+func (m *memoryClients) Get(ctx context.Context, clientID string) (storage.Client, error) {
+	client, ok := m.clients[clientID]
+	if !ok {
+		return storage.Client{}, storage.NewNotFoundError(storage.EntityClients)
+	}
+	return client, nil
+}
+
+func (m *memoryClients) Migrate(ctx context.Context, client storage.Client) (storage.Client, error) {
+	m.clients[client.ID] = client
+	m.migrated++
+	return client, nil
+}
+
+// memoryUsers provides an in-memory user manager, implementing the methods
+// used by export and import.
+type memoryUsers struct {
+	storage.UserManager
+	users    map[string]storage.User
+	migrated int
+}
+
+func (m *memoryUsers) List(ctx context.Context, filter storage.ListUsersRequest) (results []storage.User, err error) {
+	for _, user := range m.users {
+		results = append(results, user)
+	}
+	return results, nil
+}
+
+func (m *memoryUsers) Get(ctx context.Context, userID string) (storage.User, error) {
+	user, ok := m.users[userID]
+	if !ok {
+		return storage.User{}, storage.NewNotFoundError(storage.EntityUsers)
+	}
+	return user, nil
+}
+
+func (m *memoryUsers) GetByUsername(ctx context.Context, username string) (storage.User, error) {
+	for _, user := range m.users {
+		if user.Username == username {
+			return user, nil
+		}
+	}
+	return storage.User{}, storage.NewNotFoundError(storage.EntityUsers)
+}
+
+func (m *memoryUsers) Migrate(ctx context.Context, user storage.User) (storage.User, error) {
+	m.users[user.ID] = user
+	m.migrated++
+	return user, nil
+}
+
+func newMemoryStores() (*memoryClients, *memoryUsers) {
+	clients := &memoryClients{
+		clients: map[string]storage.Client{
+			"client-1": {
+				ID:                  "client-1",
+				Owner:               "owner-1",
+				AllowedTenantAccess: []string{"tenant-1"},
+				Scopes:              []string{"read", "write"},
+				Secret:              "$2a$10$clienthash",
+			},
+			"client-2": {
+				ID:                  "client-2",
+				Owner:               "owner-2",
+				AllowedTenantAccess: []string{"tenant-2"},
+				Scopes:              []string{"read"},
+			},
+		},
+	}
+	users := &memoryUsers{
+		users: map[string]storage.User{
+			"user-1": {
+				ID:                  "user-1",
+				Username:            "kevin",
+				AllowedTenantAccess: []string{"tenant-1"},
+				Scopes:              []string{"read"},
+				Password:            "$2a$10$userhash",
+			},
+		},
+	}
+
+	return clients, users
+}
+
+func TestExport_Import_RoundTrip(t *testing.T) {
+	ctx := context.Background()
+	clients, users := newMemoryStores()
+
+	var buf bytes.Buffer
+	summary, err := storage.Export(ctx, &buf, clients, users, storage.ExportOptions{})
+	if err != nil {
+		t.Fatalf("export should not error, got %v", err)
+	}
+	if summary.Exported[storage.EntityClients] != 2 || summary.Exported[storage.EntityUsers] != 1 {
+		t.Errorf("expected 2 clients and 1 user exported, got %v", summary.Exported)
+	}
+	if lines := strings.Count(buf.String(), "\n"); lines != 3 {
+		t.Errorf("expected 3 lines, got %d", lines)
+	}
+
+	target := &memoryClients{clients: map[string]storage.Client{}}
+	targetUsers := &memoryUsers{users: map[string]storage.User{}}
+	summary, err = storage.Import(ctx, &buf, target, targetUsers, storage.ImportOptions{})
+	if err != nil {
+		t.Fatalf("import should not error, got %v", err)
+	}
+	if summary.Created[storage.EntityClients] != 2 || summary.Created[storage.EntityUsers] != 1 {
+		t.Errorf("expected 2 clients and 1 user created, got %v", summary.Created)
+	}
+
+	if got := target.clients["client-1"].Secret; got != "$2a$10$clienthash" {
+		t.Errorf("expected client secret hash to be preserved, got %q", got)
+	}
+	if got := targetUsers.users["user-1"].Password; got != "$2a$10$userhash" {
+		t.Errorf("expected user password hash to be preserved, got %q", got)
+	}
+}
+
+func TestExport_Filter(t *testing.T) {
+	tests := []struct {
+		name            string
+		filter          storage.TransferFilter
+		expectedClients int
+		expectedUsers   int
+	}{
+		{
+			name:            "tenant",
+			filter:          storage.TransferFilter{Tenant: "tenant-1"},
+			expectedClients: 1,
+			expectedUsers:   1,
+		},
+		{
+			name:            "owner",
+			filter:          storage.TransferFilter{Owner: "owner-2"},
+			expectedClients: 1,
+			expectedUsers:   1,
+		},
+		{
+			name:            "scopes",
+			filter:          storage.TransferFilter{Scopes: []string{"write"}},
+			expectedClients: 1,
+			expectedUsers:   0,
+		},
+		{
+			name:            "entities",
+			filter:          storage.TransferFilter{Entities: []string{storage.EntityUsers}},
+			expectedClients: 0,
+			expectedUsers:   1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clients, users := newMemoryStores()
+
+			var buf bytes.Buffer
+			summary, err := storage.Export(context.Background(), &buf, clients, users, storage.ExportOptions{Filter: tt.filter})
+			if err != nil {
+				t.Fatalf("export should not error, got %v", err)
+			}
+			if got := summary.Exported[storage.EntityClients]; got != tt.expectedClients {
+				t.Errorf("expected %d clients, got %d", tt.expectedClients, got)
+			}
+			if got := summary.Exported[storage.EntityUsers]; got != tt.expectedUsers {
+				t.Errorf("expected %d users, got %d", tt.expectedUsers, got)
+			}
+		})
+	}
+}
+
+func TestImport_OnConflict(t *testing.T) {
+	input := `{"entity":"clients","client":{"id":"client-1","name":"imported"}}
+{"entity":"clients","client":{"id":"client-3","name":"imported"}}
+`
+
+	tests := []struct {
+		name        string
+		onConflict  storage.ConflictStrategy
+		dryRun      bool
+		expectedErr error
+		created     int
+		overwritten int
+		skipped     int
+		migrated    int
+	}{
+		{
+			name:       "skip",
+			onConflict: storage.ConflictSkip,
+			created:    1,
+			skipped:    1,
+			migrated:   1,
+		},
+		{
+			name:        "overwrite",
+			onConflict:  storage.ConflictOverwrite,
+			created:     1,
+			overwritten: 1,
+			migrated:    2,
+		},
+		{
+			name:        "overwrite dry run",
+			onConflict:  storage.ConflictOverwrite,
+			dryRun:      true,
+			created:     1,
+			overwritten: 1,
+			migrated:    0,
+		},
+		{
+			name:        "fail",
+			onConflict:  storage.ConflictFail,
+			expectedErr: storage.ErrResourceExists,
+		},
+		{
+			name:        "unknown",
+			onConflict:  "merge",
+			expectedErr: storage.ErrInvalidArgument,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clients, users := newMemoryStores()
+
+			summary, err := storage.Import(context.Background(), strings.NewReader(input), clients, users, storage.ImportOptions{
+				OnConflict: tt.onConflict,
+				DryRun:     tt.dryRun,
+			})
+			if tt.expectedErr != nil {
+				if !errors.Is(err, tt.expectedErr) {
+					t.Errorf("expected error %v, got %v", tt.expectedErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("import should not error, got %v", err)
+			}
+
+			if got := summary.Created[storage.EntityClients]; got != tt.created {
+				t.Errorf("expected %d created, got %d", tt.created, got)
+			}
+			if got := summary.Overwritten[storage.EntityClients]; got != tt.overwritten {
+				t.Errorf("expected %d overwritten, got %d", tt.overwritten, got)
+			}
+			if got := summary.Skipped[storage.EntityClients]; got != tt.skipped {
+				t.Errorf("expected %d skipped, got %d", tt.skipped, got)
+			}
+			if clients.migrated != tt.migrated {
+				t.Errorf("expected %d migrated, got %d", tt.migrated, clients.migrated)
+			}
+		})
+	}
+}
+
+func TestImport_UsernameConflict(t *testing.T) {
+	clients, users := newMemoryStores()
+	input := `{"entity":"users","user":{"id":"user-2","username":"kevin"}}`
+
+	_, err := storage.Import(context.Background(), strings.NewReader(input), clients, users, storage.ImportOptions{
+		OnConflict: storage.ConflictOverwrite,
+	})
+	if !errors.Is(err, storage.ErrResourceExists) {
+		t.Errorf("expected conflict on username, got %v", err)
+	}
+	if users.migrated != 0 {
+		t.Errorf("expected no users to be migrated, got %d", users.migrated)
+	}
+}
+
+func TestImport_ShouldReportRecordsWrittenBeforeFailure(t *testing.T) {
+	clients, users := newMemoryStores()
+	input := `{"entity":"clients","client":{"id":"client-3"}}
+{"entity":"tenants"}
+{"entity":"clients","client":{"id":"client-4"}}
+`
+
+	summary, err := storage.Import(context.Background(), strings.NewReader(input), clients, users, storage.ImportOptions{})
+	if !errors.Is(err, storage.ErrInvalidArgument) {
+		t.Errorf("expected invalid argument, got %v", err)
+	}
+	if err != nil && !strings.Contains(err.Error(), "line 2, after 1 records were written") {
+		t.Errorf("expected error to report the line and records written, got %v", err)
+	}
+	if clients.migrated != 1 {
+		t.Errorf("expected records before the failure to be migrated, got %d", clients.migrated)
+	}
+	if got := summary.Written(); got != 1 {
+		t.Errorf("expected 1 written, got %d", got)
+	}
+}
+
+// iteratingClients provides an in-memory client manager that streams clients.
+type iteratingClients struct {
+	*memoryClients
+	listed bool
+}
+
+func (m *iteratingClients) List(ctx context.Context, filter storage.ListClientsRequest) ([]storage.Client, error) {
+	m.listed = true
+	return m.memoryClients.List(ctx, filter)
+}
+
+func (m *iteratingClients) Each(ctx context.Context, filter storage.ListClientsRequest, fn func(client storage.Client) error) error {
+	for _, client := range m.clients {
+		if err := fn(client); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func TestExport_ShouldStreamFromIterator(t *testing.T) {
+	memory, users := newMemoryStores()
+	clients := &iteratingClients{memoryClients: memory}
+
+	var buf bytes.Buffer
+	summary, err := storage.Export(context.Background(), &buf, clients, users, storage.ExportOptions{})
+	if err != nil {
+		t.Fatalf("export should not error, got %v", err)
+	}
+	if clients.listed {
+		t.Error("expected clients to be streamed, not listed")
+	}
+	if got := summary.Exported[storage.EntityClients]; got != 2 {
+		t.Errorf("expected 2 clients, got %d", got)
+	}
+	if got := summary.Exported[storage.EntityUsers]; got != 1 {
+		t.Errorf("expected 1 user, got %d", got)
+	}
+}
@@ -39,6 +39,11 @@ type User struct {
 	// Scopes contains the permissions that the user is entitled to request.
 	Scopes []string `bson:"scopes" json:"scopes" xml:"scopes"`
 
+	// Groups contains the IDs of the groups the user is a member of. The user
+	// inherits the scopes, tenant access and person access of each group, as
+	// calculated by EffectivePermissions.
+	Groups []string `bson:"groups" json:"groups,omitempty" xml:"groups,omitempty"`
+
 	// PersonID is a uniquely assigned id that references a person within the
 	// system.
 	// This enables applications where an external person data store is present.
@@ -53,6 +58,14 @@ type User struct {
 	// Username is used to authenticate a user
 	Username string `bson:"username" json:"username" xml:"username"`
 
+	// Email contains the user's email address. Email addresses are unique and
+	// stored normalized, see NormalizeEmail.
+	Email string `bson:"email,omitempty" json:"email,omitempty" xml:"email,omitempty"`
+
+	// EmailVerified specifies whether the user has verified they own their
+	// email address. Changing the email address resets verification.
+	EmailVerified bool `bson:"emailVerified" json:"emailVerified" xml:"emailVerified"`
+
 	// Password of the user - will be a hash based on your fosite selected
 	// hasher.
 	// If using this model directly in an API, be sure to clear the password
@@ -186,6 +199,36 @@ func (u *User) DisableScopeAccess(scopes ...string) {
 	}
 }
 
+// EnableGroupMembership adds the user to one or many groups.
+func (u *User) EnableGroupMembership(groupIDs ...string) {
+	for i := range groupIDs {
+		found := false
+		for j := range u.Groups {
+			if groupIDs[i] == u.Groups[j] {
+				found = true
+				break
+			}
+		}
+		if !found {
+			u.Groups = append(u.Groups, groupIDs[i])
+		}
+	}
+}
+
+// DisableGroupMembership removes the user from one or many groups.
+func (u *User) DisableGroupMembership(groupIDs ...string) {
+	for i := range groupIDs {
+		for j := range u.Groups {
+			if groupIDs[i] == u.Groups[j] {
+				copy(u.Groups[j:], u.Groups[j+1:])
+				u.Groups[len(u.Groups)-1] = ""
+				u.Groups = u.Groups[:len(u.Groups)-1]
+				break
+			}
+		}
+	}
+}
+
 // Equal enables checking equality as having a byte array in a struct stops
 // allowing direct equality checks.
 func (u User) Equal(x User) bool {
@@ -213,6 +256,10 @@ func (u User) Equal(x User) bool {
 		return false
 	}
 
+	if !stringArrayEquals(u.Groups, x.Groups) {
+		return false
+	}
+
 	if u.PersonID != x.PersonID {
 		return false
 	}
@@ -225,6 +272,14 @@ func (u User) Equal(x User) bool {
 		return false
 	}
 
+	if u.Email != x.Email {
+		return false
+	}
+
+	if u.EmailVerified != x.EmailVerified {
+		return false
+	}
+
 	if u.Password != x.Password {
 		return false
 	}
@@ -1,6 +1,10 @@
 package storage
 
-import "context"
+import (
+	// Standard Library Imports
+	"context"
+	"fmt"
+)
 
 // UserManager provides a generic interface to users in order to build a DataStore
 type UserManager interface {
@@ -12,9 +16,11 @@ type UserManager interface {
 // UserStorer provides a definition of specific methods that are required to store a User in a data store.
 type UserStorer interface {
 	List(ctx context.Context, filter ListUsersRequest) ([]User, error)
+	Search(ctx context.Context, request SearchUsersRequest) ([]User, error)
 	Create(ctx context.Context, user User) (User, error)
 	Get(ctx context.Context, userID string) (User, error)
 	GetByUsername(ctx context.Context, username string) (User, error)
+	GetByEmail(ctx context.Context, email string) (User, error)
 	Update(ctx context.Context, userID string, user User) (User, error)
 	Delete(ctx context.Context, userID string) error
 
@@ -22,20 +28,39 @@ type UserStorer interface {
 	Authenticate(ctx context.Context, username string, password string) (User, error)
 	AuthenticateByID(ctx context.Context, userID string, password string) (User, error)
 	AuthenticateByUsername(ctx context.Context, username string, password string) (User, error)
+	AuthenticateByEmail(ctx context.Context, email string, password string) (User, error)
 	GrantScopes(ctx context.Context, userID string, scopes []string) (User, error)
 	RemoveScopes(ctx context.Context, userID string, scopes []string) (User, error)
+
+	// Bulk Functions
+	// BulkCreate, BulkUpdate and BulkDelete return a result per user, in the
+	// order given. An error is only returned if the batch as a whole failed.
+	BulkCreate(ctx context.Context, users []User) ([]BulkResult, error)
+	BulkUpdate(ctx context.Context, users []User) ([]BulkResult, error)
+	BulkDelete(ctx context.Context, userIDs []string) ([]BulkResult, error)
+
+	// BulkGrantScopes and BulkRemoveScopes grant, or remove, scopes for the
+	// users matching the filter, returning the number of users modified.
+	// An empty filter is rejected, unless the filter sets All.
+	BulkGrantScopes(ctx context.Context, filter ListUsersRequest, scopes []string) (int64, error)
+	BulkRemoveScopes(ctx context.Context, filter ListUsersRequest, scopes []string) (int64, error)
 }
 
 // ListUsersRequest enables filtering stored User entities.
 type ListUsersRequest struct {
-	// AllowedTenantAccess filters users based on an Allowed Tenant Access.
+	// AllowedTenantAccess filters users based on an Allowed Tenant Access,
+	// including the access users inherit from their groups.
 	AllowedTenantAccess string `json:"allowedTenantAccess" xml:"allowedTenantAccess"`
 	// AllowedPersonAccess filters users based on Allowed Person Access.
 	AllowedPersonAccess string `json:"allowedPersonAccess" xml:"allowedPersonAccess"`
 	// AllowedPersonAccess filters users based on Person Access.
 	PersonID string `json:"personId" xml:"personId"`
+	// Group filters users to the members of the group, by group ID.
+	Group string `json:"group" xml:"group"`
 	// Username filters users based on username.
 	Username string `json:"username" xml:"username"`
+	// Email filters users based on email address.
+	Email string `json:"email" xml:"email"`
 	// ScopesUnion filters users that have at least one of of the listed scopes.
 	// ScopesUnion performs an OR operation.
 	// If ScopesUnion is provided, a union operation will be performed as it
@@ -50,4 +75,101 @@ type ListUsersRequest struct {
 	LastName string `json:"lastName" xml:"lastName"`
 	// Disabled filters users to those with disabled accounts.
 	Disabled bool `json:"disabled" xml:"disabled"`
+	// All must be set in order to bulk grant, or remove, scopes for every
+	// user, if no other filter is set. All is ignored when listing.
+	All bool `json:"all" xml:"all"`
+}
+
+// IsZero returns true if the request doesn't filter users.
+func (r ListUsersRequest) IsZero() bool {
+	return r.AllowedTenantAccess == "" &&
+		r.AllowedPersonAccess == "" &&
+		r.PersonID == "" &&
+		r.Group == "" &&
+		r.Username == "" &&
+		r.Email == "" &&
+		len(r.ScopesUnion) == 0 &&
+		len(r.ScopesIntersection) == 0 &&
+		r.FirstName == "" &&
+		r.LastName == "" &&
+		!r.Disabled
+}
+
+// ValidateBulk returns an invalid argument error if the request doesn't
+// filter users and doesn't set All, so that a zero value request can't modify
+// every user.
+func (r ListUsersRequest) ValidateBulk() error {
+	if r.IsZero() && !r.All {
+		return NewInvalidArgumentError(EntityUsers, "a filter, or all, is required to modify users in bulk", "all")
+	}
+
+	return nil
+}
+
+// UserSearchMode specifies how a user search query is matched.
+type UserSearchMode string
+
+const (
+	// UserSearchPrefix matches users whose username, first name, last name
+	// or person ID start with the query. Matches are ranked by username
+	// first, then by name, then by person ID.
+	UserSearchPrefix UserSearchMode = "prefix"
+
+	// UserSearchText matches users by the words in the query, ranked by
+	// relevance.
+	UserSearchText UserSearchMode = "text"
+)
+
+const (
+	// DefaultUserSearchLimit provides the number of users returned by a
+	// search if no limit is requested.
+	DefaultUserSearchLimit = 20
+
+	// MaxUserSearchLimit provides the maximum number of users returned by a
+	// search.
+	MaxUserSearchLimit = 100
+)
+
+// SearchUsersRequest enables searching for stored User entities, for
+// example, to power a typeahead. Results are ranked, then paginated.
+type SearchUsersRequest struct {
+	// Query contains the text to search for.
+	Query string `json:"query" xml:"query"`
+	// Mode specifies how the query is matched. Defaults to UserSearchPrefix.
+	Mode UserSearchMode `json:"mode" xml:"mode"`
+	// AllowedTenantAccess filters users based on an Allowed Tenant Access,
+	// including the access users inherit from their groups.
+	AllowedTenantAccess string `json:"allowedTenantAccess" xml:"allowedTenantAccess"`
+	// Limit specifies the maximum number of users to return. Defaults to
+	// DefaultUserSearchLimit and is capped at MaxUserSearchLimit.
+	Limit int `json:"limit" xml:"limit"`
+	// Offset specifies the number of ranked users to skip.
+	Offset int `json:"offset" xml:"offset"`
+}
+
+// Normalize returns the request with its mode and pagination defaulted, or an
+// error if the request is invalid.
+func (r SearchUsersRequest) Normalize() (SearchUsersRequest, error) {
+	switch r.Mode {
+	case "":
+		r.Mode = UserSearchPrefix
+	case UserSearchPrefix, UserSearchText:
+	default:
+		return r, NewInvalidArgumentError(EntityUsers, fmt.Sprintf("unsupported search mode %q", r.Mode), "mode")
+	}
+
+	if r.Offset < 0 {
+		return r, NewInvalidArgumentError(EntityUsers, "offset must not be negative", "offset")
+	}
+
+	switch {
+	case r.Limit < 0:
+		return r, NewInvalidArgumentError(EntityUsers, "limit must not be negative", "limit")
+	case r.Limit == 0:
+		r.Limit = DefaultUserSearchLimit
+	case r.Limit > MaxUserSearchLimit:
+		r.Limit = MaxUserSearchLimit
+	}
+
+	return r, nil
 }
@@ -0,0 +1,36 @@
+package storage_test
+
+import (
+	// Standard Library Imports
+	"errors"
+	"testing"
+
+	// Internal Imports
+	"github.com/matthewhartstonge/storage"
+)
+
+func TestSearchUsersRequest_Normalize(t *testing.T) {
+	got, err := storage.SearchUsersRequest{Query: "pe"}.Normalize()
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if got.Mode != storage.UserSearchPrefix || got.Limit != storage.DefaultUserSearchLimit {
+		t.Errorf("expected the mode and limit to be defaulted, got %+v", got)
+	}
+
+	got, err = storage.SearchUsersRequest{Query: "pe", Limit: 1000}.Normalize()
+	if err != nil || got.Limit != storage.MaxUserSearchLimit {
+		t.Errorf("expected the limit to be capped, got %d, %v", got.Limit, err)
+	}
+
+	invalid := []storage.SearchUsersRequest{
+		{Query: "pe", Mode: "fuzzy"},
+		{Query: "pe", Limit: -1},
+		{Query: "pe", Offset: -1},
+	}
+	for _, request := range invalid {
+		if _, err := request.Normalize(); !errors.Is(err, storage.ErrInvalidArgument) {
+			t.Errorf("expected %+v to be invalid, got %v", request, err)
+		}
+	}
+}
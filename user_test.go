@@ -341,6 +341,37 @@ func TestUser_Equal(t *testing.T) {
 			},
 			expected: false,
 		},
+		{
+			description: "email should be equal",
+			x: User{
+				Email: "timmy@example.com",
+			},
+			y: User{
+				Email: "timmy@example.com",
+			},
+			expected: true,
+		},
+		{
+			description: "email should not be equal",
+			x: User{
+				Email: "timmy@example.com",
+			},
+			y: User{
+				Email: "jimmy@example.com",
+			},
+			expected: false,
+		},
+		{
+			description: "email verification should not be equal",
+			x: User{
+				Email:         "timmy@example.com",
+				EmailVerified: true,
+			},
+			y: User{
+				Email: "timmy@example.com",
+			},
+			expected: false,
+		},
 		{
 			description: "password should be equal",
 			x: User{
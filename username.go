@@ -0,0 +1,18 @@
+package storage
+
+import (
+	// External Imports
+	"golang.org/x/text/cases"
+	"golang.org/x/text/unicode/norm"
+)
+
+// NormalizeUsername returns the form usernames are stored and compared in.
+// The username is Unicode NFKC normalized and case folded, so that
+// "Peter", "peter" and "ｐｅｔｅｒ" all refer to the same user.
+func NormalizeUsername(username string) string {
+	// A caser may be stateful, so can't be shared between goroutines.
+	folded := cases.Fold().String(norm.NFKC.String(username))
+
+	// Folding can denormalize the username, so normalize it again.
+	return norm.NFKC.String(folded)
+}
@@ -0,0 +1,30 @@
+package storage_test
+
+import (
+	// Standard Library Imports
+	"testing"
+
+	// Internal Imports
+	"github.com/matthewhartstonge/storage"
+)
+
+func TestNormalizeUsername(t *testing.T) {
+	tests := []struct {
+		username string
+		expected string
+	}{
+		{username: "peter", expected: "peter"},
+		{username: "Peter", expected: "peter"},
+		{username: "PETER@Example.com", expected: "peter@example.com"},
+		{username: "ｐｅｔｅｒ", expected: "peter"},
+		{username: "Straße", expected: "strasse"},
+		{username: "ﬁona", expected: "fiona"},
+		{username: "", expected: ""},
+	}
+
+	for _, tt := range tests {
+		if got := storage.NormalizeUsername(tt.username); got != tt.expected {
+			t.Errorf("NormalizeUsername(%q): expected %q, got %q", tt.username, tt.expected, got)
+		}
+	}
+}